@@ -6,6 +6,7 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/api/extensions/v1beta1"
 	apiextv1beta1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1beta1"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/intstr"
 )
@@ -54,6 +55,43 @@ type AvailabilityPolicySpec struct {
 
 	// The microservice's scheduling constraints.
 	Affinity *corev1.Affinity `json:"affinity,omitempty"`
+
+	// Autoscaling scales the pods of a release through a
+	// HorizontalPodAutoscaler. When it's set, Replicas is ignored.
+	Autoscaling *AutoscalingPolicy `json:"autoscaling,omitempty"`
+}
+
+// AutoscalingPolicy describes the bounds and targets of a
+// HorizontalPodAutoscaler.
+type AutoscalingPolicy struct {
+	// MinReplicas is the lower limit of the number of replicas, it defaults
+	// to 1.
+	MinReplicas *int32 `json:"minReplicas,omitempty"`
+
+	// MaxReplicas is the upper limit of the number of replicas.
+	MaxReplicas int32 `json:"maxReplicas"`
+
+	// TargetCPUUtilization is the average CPU utilization of the pods, as a
+	// percentage of their requested CPU.
+	TargetCPUUtilization *int32 `json:"targetCPUUtilization,omitempty"`
+
+	// TargetMemoryUtilization is the average memory utilization of the
+	// pods, as a percentage of their requested memory.
+	TargetMemoryUtilization *int32 `json:"targetMemoryUtilization,omitempty"`
+
+	// Metrics are custom metrics of the pods to scale on.
+	Metrics []AutoscalingMetric `json:"metrics,omitempty"`
+}
+
+// AutoscalingMetric is a custom metric describing the pods of a release, like
+// requests per second.
+type AutoscalingMetric struct {
+	// Name is the name of the metric.
+	Name string `json:"name"`
+
+	// TargetAverageValue is the value of the metric to aim for, averaged
+	// across all pods.
+	TargetAverageValue resource.Quantity `json:"targetAverageValue"`
 }
 
 // DefaultAvailabilityPolicySpec is the default availability spec that will be
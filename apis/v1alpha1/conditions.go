@@ -0,0 +1,112 @@
+package v1alpha1
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// ConditionType is the type of a Condition.
+type ConditionType string
+
+const (
+	// ConditionReady indicates that the resource is fully reconciled and
+	// doing what its spec asks for.
+	ConditionReady ConditionType = "Ready"
+
+	// ConditionProgressing indicates that the resource is being reconciled,
+	// like a rollout which is still in progress.
+	ConditionProgressing ConditionType = "Progressing"
+
+	// ConditionDegraded indicates that the resource can't be reconciled, like
+	// when a policy it references is missing.
+	ConditionDegraded ConditionType = "Degraded"
+)
+
+// Condition describes an aspect of the state of a resource, like the
+// conditions of core Kubernetes resources.
+type Condition struct {
+	Type   ConditionType          `json:"type"`
+	Status corev1.ConditionStatus `json:"status"`
+
+	// LastTransitionTime is the last time the status changed.
+	LastTransitionTime metav1.Time `json:"lastTransitionTime,omitempty"`
+
+	// Reason is a CamelCase reason for the last transition.
+	Reason string `json:"reason,omitempty"`
+
+	// Message is a human readable description of the last transition.
+	Message string `json:"message,omitempty"`
+}
+
+// Conditions is the list of conditions of a resource.
+type Conditions []Condition
+
+// Get returns the condition of the given type, if any.
+func (c Conditions) Get(t ConditionType) *Condition {
+	for i := range c {
+		if c[i].Type == t {
+			return &c[i]
+		}
+	}
+
+	return nil
+}
+
+// IsTrue reports whether the condition of the given type is true.
+func (c Conditions) IsTrue(t ConditionType) bool {
+	cond := c.Get(t)
+	return cond != nil && cond.Status == corev1.ConditionTrue
+}
+
+// Set adds or updates the condition of the given type. The transition time is
+// only updated when the status changes, so conditions are stable across
+// syncs.
+func (c *Conditions) Set(t ConditionType, status corev1.ConditionStatus, reason, message string) {
+	cond := c.Get(t)
+	if cond == nil {
+		*c = append(*c, Condition{Type: t})
+		cond = &(*c)[len(*c)-1]
+	}
+
+	if cond.Status != status {
+		cond.Status = status
+		cond.LastTransitionTime = metav1.Now()
+	}
+
+	cond.Reason = reason
+	cond.Message = message
+}
+
+// Mark marks the resource as ready, progressing or degraded, depending on the
+// given condition type.
+func (c *Conditions) Mark(t ConditionType, reason, message string) {
+	switch t {
+	case ConditionReady:
+		c.MarkReady(reason, message)
+	case ConditionProgressing:
+		c.MarkProgressing(reason, message)
+	case ConditionDegraded:
+		c.MarkDegraded(reason, message)
+	}
+}
+
+// MarkReady marks the resource as ready.
+func (c *Conditions) MarkReady(reason, message string) {
+	c.Set(ConditionReady, corev1.ConditionTrue, reason, message)
+	c.Set(ConditionProgressing, corev1.ConditionFalse, reason, "")
+	c.Set(ConditionDegraded, corev1.ConditionFalse, reason, "")
+}
+
+// MarkProgressing marks the resource as being reconciled.
+func (c *Conditions) MarkProgressing(reason, message string) {
+	c.Set(ConditionReady, corev1.ConditionFalse, reason, message)
+	c.Set(ConditionProgressing, corev1.ConditionTrue, reason, message)
+	c.Set(ConditionDegraded, corev1.ConditionFalse, reason, "")
+}
+
+// MarkDegraded marks the resource as failing to reconcile.
+func (c *Conditions) MarkDegraded(reason, message string) {
+	c.Set(ConditionReady, corev1.ConditionFalse, reason, message)
+	c.Set(ConditionProgressing, corev1.ConditionFalse, reason, "")
+	c.Set(ConditionDegraded, corev1.ConditionTrue, reason, message)
+}
@@ -0,0 +1,39 @@
+package v1alpha1
+
+import (
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestConditions(t *testing.T) {
+	t.Run("marks the resource as degraded", func(t *testing.T) {
+		var c Conditions
+		c.MarkDegraded("ImagePolicyError", "not found")
+
+		if c.IsTrue(ConditionReady) || c.IsTrue(ConditionProgressing) || !c.IsTrue(ConditionDegraded) {
+			t.Errorf("Expected only Degraded to be true, got %+v", c)
+		}
+
+		if msg := c.Get(ConditionDegraded).Message; msg != "not found" {
+			t.Errorf("Expected the message to be set, got %q", msg)
+		}
+	})
+
+	t.Run("keeps the transition time while the status is unchanged", func(t *testing.T) {
+		then := metav1.NewTime(time.Now().Add(-time.Hour))
+		c := Conditions{{Type: ConditionReady, Status: corev1.ConditionTrue, LastTransitionTime: then, Reason: "Synced"}}
+
+		c.Set(ConditionReady, corev1.ConditionTrue, "Deployed", "")
+		if cond := c.Get(ConditionReady); !cond.LastTransitionTime.Equal(&then) || cond.Reason != "Deployed" {
+			t.Errorf("Expected only the reason to change, got %+v", cond)
+		}
+
+		c.Set(ConditionReady, corev1.ConditionFalse, "RollingOut", "")
+		if cond := c.Get(ConditionReady); cond.LastTransitionTime.Equal(&then) {
+			t.Errorf("Expected the transition time to change, got %+v", cond)
+		}
+	})
+}
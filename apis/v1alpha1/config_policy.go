@@ -38,6 +38,13 @@ type ConfigPolicySpec struct {
 type ConfigPolicyStatus struct {
 	LastUpdatedTime metav1.Time `json:"lastUpdatedTime"`
 	Hashed          string      `json:"hashed"`
+
+	// ObservedGeneration is the generation of the ConfigPolicy which was last
+	// reconciled.
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
+	// Conditions describe whether the ConfigPolicy is ready.
+	Conditions Conditions `json:"conditions,omitempty"`
 }
 
 // ConfigPolicyValidationSchema represents the OpenAPIV3Schema validation for
@@ -0,0 +1,30 @@
+package v1alpha1
+
+// DeletionStatus describes the progress of the cleanup of a resource which is
+// being deleted. The resource is removed once everything is cleaned up, or
+// once the deletion timed out.
+type DeletionStatus struct {
+	// Pending lists what still has to be cleaned up.
+	Pending []string `json:"pending,omitempty"`
+
+	// Message describes why the cleanup isn't finished yet.
+	Message string `json:"message,omitempty"`
+
+	// TimedOut is set when the cleanup was given up on.
+	TimedOut bool `json:"timedOut,omitempty"`
+}
+
+// NewDeletionStatus returns the DeletionStatus for a cleanup which left the
+// given things pending because of err.
+func NewDeletionStatus(pending []string, err error, timedOut bool) *DeletionStatus {
+	status := &DeletionStatus{
+		Pending:  pending,
+		TimedOut: timedOut && len(pending) > 0,
+	}
+
+	if err != nil {
+		status.Message = err.Error()
+	}
+
+	return status
+}
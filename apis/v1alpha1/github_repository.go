@@ -62,6 +62,17 @@ type GitHubRepositoryStatus struct {
 
 	// Reconciliation represents the status of the repository reconciliation.
 	Reconciliation GitHubReconciliation `json:"reconciliation"`
+
+	// Deletion shows the progress of the cleanup once the GitHubRepository
+	// is deleted.
+	Deletion *DeletionStatus `json:"deletion,omitempty"`
+
+	// ObservedGeneration is the generation of the GitHubRepository which was last
+	// reconciled.
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
+	// Conditions describe whether the GitHubRepository is ready.
+	Conditions Conditions `json:"conditions,omitempty"`
 }
 
 // GitHubHook represents the status object for a GiHub Webhook for the CRD.
@@ -81,6 +92,22 @@ type GitHubRelease struct {
 	Level       SemVerLevel `json:"level"`
 	ReleaseTime metav1.Time `json:"releaseTime"`
 	Deployment  *Deployment `json:"deployment,omitempty"`
+
+	// SHA is the commit the release points to. It's resolved through the
+	// GitHub API after the release is found.
+	SHA string `json:"sha,omitempty"`
+
+	// CommitTime is when the commit of the release was made.
+	CommitTime metav1.Time `json:"commitTime,omitempty"`
+
+	// PullRequest is the number of the pull request for preview releases.
+	PullRequest int `json:"pullRequest,omitempty"`
+
+	// OpenedTime is when the pull request was opened for preview releases.
+	OpenedTime metav1.Time `json:"openedTime,omitempty"`
+
+	// RevivedTime is when an expired preview release was last revived.
+	RevivedTime *metav1.Time `json:"revivedTime,omitempty"`
 }
 
 // GitHubReconciliation represents the status of the repository reconciliation.
@@ -95,6 +122,7 @@ type Deployment struct {
 	NetworkPolicy corev1.ObjectReference `json:"networkPolicy"`
 	State         string                 `json:"state"`
 	URL           *string                `json:"url,omitempty"`
+	Description   string                 `json:"description,omitempty"`
 }
 
 // GitHubRepositoryValidationSchema represents the OpenAPIV3Schema
@@ -62,6 +62,13 @@ type ImagePolicySpec struct {
 // Deployment.
 type ImagePolicyStatus struct {
 	Releases []Release `json:"releases"`
+
+	// ObservedGeneration is the generation of the ImagePolicy which was last
+	// reconciled.
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
+	// Conditions describe whether the ImagePolicy is ready.
+	Conditions Conditions `json:"conditions,omitempty"`
 }
 
 // ImagePolicyMatch defines how a release is matched to an image tag.
@@ -36,11 +36,192 @@ type MicroserviceSpec struct {
 	AvailabilityPolicy v1.ObjectReference `json:"availabilityPolicy,omitempty"`
 	SecurityPolicy     v1.ObjectReference `json:"securityPolicy,omitempty"`
 	HealthPolicy       v1.ObjectReference `json:"healthPolicy,omitempty"`
+	ResourcePolicy     v1.ObjectReference `json:"resourcePolicy,omitempty"`
+
+	// Sidecars are containers which run next to the container of the
+	// Microservice, like log shippers or proxies.
+	Sidecars []Container `json:"sidecars,omitempty"`
+
+	// InitContainers run to completion, in order, before the containers of
+	// the Microservice are started.
+	InitContainers []Container `json:"initContainers,omitempty"`
+
+	// Hooks are Jobs which run for every release, before and after it's
+	// deployed.
+	Hooks *Hooks `json:"hooks,omitempty"`
+
+	// Overrides reference other policies for the releases of a level, like
+	// a smaller AvailabilityPolicy and a staging ConfigPolicy for previews.
+	Overrides map[SemVerLevel]PolicyOverrides `json:"overrides,omitempty"`
+}
+
+// PolicyOverrides are the policies which replace the policies of a
+// Microservice for the releases of a level. Policies which aren't set are
+// taken from the Microservice.
+type PolicyOverrides struct {
+	ConfigPolicy       *v1.LocalObjectReference `json:"configPolicy,omitempty"`
+	AvailabilityPolicy *v1.ObjectReference      `json:"availabilityPolicy,omitempty"`
+	SecurityPolicy     *v1.ObjectReference      `json:"securityPolicy,omitempty"`
+	HealthPolicy       *v1.ObjectReference      `json:"healthPolicy,omitempty"`
+	ResourcePolicy     *v1.ObjectReference      `json:"resourcePolicy,omitempty"`
+}
+
+// ForLevel returns the MicroserviceSpec for the releases of the given level,
+// with the policies of its overrides.
+func (s *MicroserviceSpec) ForLevel(level SemVerLevel) *MicroserviceSpec {
+	spec := s.DeepCopy()
+
+	o, ok := spec.Overrides[level]
+	if !ok {
+		return spec
+	}
+
+	if o.ConfigPolicy != nil {
+		spec.ConfigPolicy = *o.ConfigPolicy
+	}
+
+	if o.AvailabilityPolicy != nil {
+		spec.AvailabilityPolicy = *o.AvailabilityPolicy
+	}
+
+	if o.SecurityPolicy != nil {
+		spec.SecurityPolicy = *o.SecurityPolicy
+	}
+
+	if o.HealthPolicy != nil {
+		spec.HealthPolicy = *o.HealthPolicy
+	}
+
+	if o.ResourcePolicy != nil {
+		spec.ResourcePolicy = *o.ResourcePolicy
+	}
+
+	return spec
+}
+
+// Hooks describe the Jobs which run when a release is deployed. They use the
+// image and ConfigPolicy of the release. Hooks run once per release, a
+// failing hook blocks the rollout of the release.
+type Hooks struct {
+	// PreDeploy hooks run before the Deployment of a release is created, like
+	// database migrations. The Deployment is only created once all of them
+	// succeeded.
+	PreDeploy []Hook `json:"preDeploy,omitempty"`
+
+	// PostDeploy hooks run once all pods of a release are available, like
+	// smoke tests. The release only receives traffic from its release stream
+	// once all of them succeeded.
+	PostDeploy []Hook `json:"postDeploy,omitempty"`
+}
+
+// Hook is a Job which runs the image of a release.
+type Hook struct {
+	// Name is the name of the hook, which is unique within its phase.
+	Name string `json:"name"`
+
+	// Command overrides the command of the ConfigPolicy.
+	Command []string `json:"command,omitempty"`
+
+	// Args overrides the arguments of the ConfigPolicy.
+	Args []string `json:"args,omitempty"`
+
+	// BackoffLimit is the number of retries before the hook fails, defaults
+	// to `0`.
+	BackoffLimit *int32 `json:"backoffLimit,omitempty"`
+
+	// ActiveDeadlineSeconds is how long the hook may run before it fails.
+	ActiveDeadlineSeconds *int64 `json:"activeDeadlineSeconds,omitempty"`
+}
+
+// HookPhase describes when a hook runs.
+type HookPhase string
+
+// The available HookPhases.
+const (
+	HookPhasePreDeploy  HookPhase = "PreDeploy"
+	HookPhasePostDeploy HookPhase = "PostDeploy"
+)
+
+// HookState describes the progress of a hook.
+type HookState string
+
+// The available HookStates.
+const (
+	// HookStatePending is used for hooks which are waiting for the previous
+	// phase to finish.
+	HookStatePending   HookState = "Pending"
+	HookStateRunning   HookState = "Running"
+	HookStateSucceeded HookState = "Succeeded"
+	HookStateFailed    HookState = "Failed"
+)
+
+// HookStatus describes the progress of a hook of a release.
+type HookStatus struct {
+	Name  string    `json:"name"`
+	Phase HookPhase `json:"phase"`
+	State HookState `json:"state"`
+
+	// Job is the name of the Job which runs the hook.
+	Job string `json:"job,omitempty"`
+
+	// Message describes why a hook failed.
+	Message string `json:"message,omitempty"`
+}
+
+// Container describes a sidecar or init container of a Microservice. The
+// policies of a Container only apply to the Container itself.
+type Container struct {
+	// Name is the name of the container. It can't be the name of the
+	// Microservice, which is used by its main container.
+	Name string `json:"name"`
+
+	// Image is a fixed image to run. Either Image or ImagePolicy needs to be
+	// provided.
+	Image string `json:"image,omitempty"`
+
+	// ImagePolicy runs the image of the latest release of an ImagePolicy.
+	ImagePolicy *v1.LocalObjectReference `json:"imagePolicy,omitempty"`
+
+	// ImagePullPolicy defaults to the pull policy of the ImagePolicy, or
+	// `IfNotPresent`.
+	ImagePullPolicy v1.PullPolicy `json:"imagePullPolicy,omitempty"`
+
+	// ConfigPolicy provides the arguments, environment and volumes of the
+	// container.
+	ConfigPolicy *v1.LocalObjectReference `json:"configPolicy,omitempty"`
+
+	// HealthPolicy provides the probes of the container. It's ignored for
+	// init containers.
+	HealthPolicy *v1.ObjectReference `json:"healthPolicy,omitempty"`
+
+	// Ports are the ports the container exposes.
+	Ports []v1.ContainerPort `json:"ports,omitempty"`
+
+	// Resources are the compute resources the container needs.
+	Resources v1.ResourceRequirements `json:"resources,omitempty"`
 }
 
 // MicroserviceStatus represents the status a specific Microservice is in.
 type MicroserviceStatus struct {
 	Releases []Release `json:"releases"`
+
+	// Ready is the number of ready pods out of the desired pods of all
+	// releases, like "2/3".
+	Ready string `json:"ready,omitempty"`
+
+	// Restarts is the total number of container restarts of all releases.
+	Restarts int32 `json:"restarts,omitempty"`
+
+	// Deletion shows the progress of the cleanup once the Microservice is
+	// deleted.
+	Deletion *DeletionStatus `json:"deletion,omitempty"`
+
+	// ObservedGeneration is the generation of the Microservice which was last
+	// reconciled.
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
+	// Conditions describe whether the Microservice is ready.
+	Conditions Conditions `json:"conditions,omitempty"`
 }
 
 // MicroserviceValidationSchema represents the OpenAPIV3Scheme which
@@ -51,7 +232,15 @@ var MicroserviceValidationSchema = &v1beta1.CustomResourceValidation{
 			"spec": {
 				Required: []string{"imagePolicy"},
 				Properties: map[string]v1beta1.JSONSchemaProps{
-					"imagePolicy": requiredObjectReference,
+					"imagePolicy":    requiredObjectReference,
+					"sidecars":       containersValidation,
+					"initContainers": containersValidation,
+					"hooks": {
+						Properties: map[string]v1beta1.JSONSchemaProps{
+							"preDeploy":  hooksValidation,
+							"postDeploy": hooksValidation,
+						},
+					},
 				},
 			},
 			"status": ReleaseValidationSchema,
@@ -63,3 +252,28 @@ var MicroserviceValidationSchema = &v1beta1.CustomResourceValidation{
 var requiredObjectReference = v1beta1.JSONSchemaProps{
 	Required: []string{"name"},
 }
+
+var containersValidation = v1beta1.JSONSchemaProps{
+	Items: &v1beta1.JSONSchemaPropsOrArray{
+		Schema: &v1beta1.JSONSchemaProps{
+			Required: []string{"name"},
+			OneOf: []v1beta1.JSONSchemaProps{
+				{Required: []string{"image"}},
+				{Required: []string{"imagePolicy"}},
+			},
+			Properties: map[string]v1beta1.JSONSchemaProps{
+				"imagePolicy":  requiredObjectReference,
+				"configPolicy": requiredObjectReference,
+				"healthPolicy": requiredObjectReference,
+			},
+		},
+	},
+}
+
+var hooksValidation = v1beta1.JSONSchemaProps{
+	Items: &v1beta1.JSONSchemaPropsOrArray{
+		Schema: &v1beta1.JSONSchemaProps{
+			Required: []string{"name"},
+		},
+	},
+}
@@ -0,0 +1,47 @@
+package v1alpha1
+
+import (
+	"testing"
+
+	v1 "k8s.io/api/core/v1"
+)
+
+func TestMicroserviceSpec_ForLevel(t *testing.T) {
+	spec := &MicroserviceSpec{
+		ConfigPolicy:       v1.LocalObjectReference{Name: "production"},
+		AvailabilityPolicy: v1.ObjectReference{Name: "high-availability"},
+		HealthPolicy:       v1.ObjectReference{Name: "node-apps"},
+		Overrides: map[SemVerLevel]PolicyOverrides{
+			SemVerLevelPreview: {
+				ConfigPolicy:       &v1.LocalObjectReference{Name: "staging"},
+				AvailabilityPolicy: &v1.ObjectReference{Name: "single", Namespace: "shared"},
+			},
+		},
+	}
+
+	t.Run("without overrides", func(t *testing.T) {
+		s := spec.ForLevel(SemVerLevelRelease)
+		if s.ConfigPolicy.Name != "production" || s.AvailabilityPolicy.Name != "high-availability" {
+			t.Errorf("Expected the policies of the Microservice, got %+v", s)
+		}
+	})
+
+	t.Run("with overrides", func(t *testing.T) {
+		s := spec.ForLevel(SemVerLevelPreview)
+		if s.ConfigPolicy.Name != "staging" {
+			t.Errorf("Expected the staging ConfigPolicy, got %s", s.ConfigPolicy.Name)
+		}
+
+		if s.AvailabilityPolicy.Name != "single" || s.AvailabilityPolicy.Namespace != "shared" {
+			t.Errorf("Expected the shared single AvailabilityPolicy, got %+v", s.AvailabilityPolicy)
+		}
+
+		if s.HealthPolicy.Name != "node-apps" {
+			t.Errorf("Expected the HealthPolicy of the Microservice, got %s", s.HealthPolicy.Name)
+		}
+	})
+
+	if spec.ConfigPolicy.Name != "production" {
+		t.Errorf("Expected the spec to be left untouched")
+	}
+}
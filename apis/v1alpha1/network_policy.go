@@ -1,9 +1,13 @@
 package v1alpha1
 
 import (
+	"time"
+
+	batchv1 "k8s.io/api/batch/v1"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1beta1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
 	"k8s.io/kube-openapi/pkg/util/proto"
 )
 
@@ -40,6 +44,17 @@ type NetworkPolicySpec struct {
 	// Ports which we want to be accessible for the associated Microservice.
 	Ports []NetworkPort `json:"ports"`
 
+	// ServiceType is the type of the Services of the Microservice. It's
+	// either `ClusterIP`, `NodePort` or `LoadBalancer`, defaults to
+	// `NodePort`. Only the Service of a release stream becomes a
+	// LoadBalancer, the Services of individual releases use `NodePort`.
+	ServiceType corev1.ServiceType `json:"serviceType,omitempty"`
+
+	// ExternalTrafficPolicy is the externalTrafficPolicy of NodePort and
+	// LoadBalancer Services. It's either `Cluster` or `Local`, defaults to
+	// `Cluster`.
+	ExternalTrafficPolicy corev1.ServiceExternalTrafficPolicyType `json:"externalTrafficPolicy,omitempty"`
+
 	// ExternalDNS represents the domain specification for a Microservice
 	// externally.
 	ExternalDNS []ExternalDNS `json:"externalDNS"`
@@ -47,6 +62,234 @@ type NetworkPolicySpec struct {
 	// UpdateStrategy defines how Heighliner will transition DNS from one
 	// version to another.
 	UpdateStrategy UpdateStrategy `json:"updateStrategy"`
+
+	// ScaleToZero scales preview releases down to zero replicas when they
+	// haven't received any traffic for a while. Traffic for these releases is
+	// routed through the Heighliner activator, which scales them back up on
+	// demand. It requires an Ingress routing backend.
+	ScaleToZero *ScaleToZero `json:"scaleToZero,omitempty"`
+
+	// Routing configures which API is used to route external traffic to the
+	// releases of the Microservice. When not provided, the API is detected
+	// based on what the cluster supports.
+	Routing *Routing `json:"routing,omitempty"`
+
+	// CertManager lets the NetworkPolicy manage the cert-manager Certificates
+	// for the TLS groups of its ExternalDNS records. When not provided, the
+	// TLS secrets are expected to be provisioned by something else.
+	CertManager *CertManager `json:"certManager,omitempty"`
+
+	// Firewall restricts the traffic to and from the pods of the
+	// Microservice. When not provided, pods can be reached from anywhere in
+	// the cluster.
+	Firewall *Firewall `json:"firewall,omitempty"`
+
+	// DNSProvider lets the NetworkPolicy manage the DNS records for its
+	// ExternalDNS records directly. When provided, the external-dns
+	// annotations aren't set on the Ingresses.
+	DNSProvider *DNSProvider `json:"dnsProvider,omitempty"`
+}
+
+// DNSProvider describes where the DNS records of a NetworkPolicy are managed
+// and what they point to. Records are owned through TXT records, names which
+// are in use by someone else are left untouched.
+type DNSProvider struct {
+	// Target is the hostname or IP address the records point to, like the
+	// load balancer of the Ingress controller. Hostnames result in CNAME
+	// records, IP addresses in A or AAAA records.
+	Target string `json:"target"`
+
+	// RFC2136 manages the records through dynamic updates.
+	RFC2136 *RFC2136Provider `json:"rfc2136,omitempty"`
+}
+
+// RFC2136Provider manages records on a DNS server which accepts RFC2136
+// dynamic updates.
+type RFC2136Provider struct {
+	// Server is the address of the DNS server, like `ns1.hlnr.io:53`.
+	Server string `json:"server"`
+
+	// Zone is the zone the records are updated in.
+	Zone string `json:"zone"`
+
+	// TSIG is the key the updates are signed with. When not provided, the
+	// updates are sent unsigned.
+	TSIG *TSIGKey `json:"tsig,omitempty"`
+}
+
+// TSIGKey refers to the secret of a TSIG key.
+type TSIGKey struct {
+	// KeyName is the name of the key, as known by the DNS server.
+	KeyName string `json:"keyName"`
+
+	// Algorithm is either `hmac-sha1`, `hmac-sha256` or `hmac-sha512`,
+	// defaults to `hmac-sha256`.
+	Algorithm string `json:"algorithm,omitempty"`
+
+	// SecretRef refers to the key in a secret which holds the base64 encoded
+	// secret of the key.
+	SecretRef corev1.SecretKeySelector `json:"secretRef"`
+}
+
+// Firewall describes which traffic is allowed to and from the pods of a
+// Microservice. It is translated into networking.k8s.io/v1 NetworkPolicies.
+type Firewall struct {
+	// Ingress lists the traffic the pods accept. When not provided, all
+	// incoming traffic is allowed.
+	Ingress []FirewallRule `json:"ingress,omitempty"`
+
+	// Egress lists the traffic the pods are allowed to send. When not
+	// provided, all outgoing traffic is allowed.
+	Egress []FirewallRule `json:"egress,omitempty"`
+
+	// AllowPreviewTraffic allows traffic between preview releases and the
+	// other releases of Microservices. By default, preview releases can only
+	// reach, and be reached by, the preview releases of other Microservices.
+	// Defaults to `false`.
+	AllowPreviewTraffic bool `json:"allowPreviewTraffic,omitempty"`
+}
+
+// FirewallRule allows traffic from or to a set of peers on a set of ports.
+type FirewallRule struct {
+	// Peers the traffic is allowed from or to. When not provided, traffic
+	// from or to anywhere is allowed.
+	Peers []FirewallPeer `json:"peers,omitempty"`
+
+	// Ports the traffic is allowed on. Named ports which match a NetworkPort
+	// refer to its target port. When not provided, all ports are allowed.
+	Ports []FirewallPort `json:"ports,omitempty"`
+}
+
+// FirewallPeer is a Microservice, namespace or CIDR traffic is allowed from or
+// to.
+type FirewallPeer struct {
+	// Microservice is the name of a Microservice whose pods are allowed.
+	Microservice string `json:"microservice,omitempty"`
+
+	// Namespace is the namespace of the Microservice. Without a Microservice,
+	// all pods in the namespace are allowed. Defaults to the namespace of the
+	// NetworkPolicy when a Microservice is provided.
+	Namespace string `json:"namespace,omitempty"`
+
+	// CIDR is an IP range which is allowed. It can't be combined with a
+	// Microservice or Namespace.
+	CIDR string `json:"cidr,omitempty"`
+
+	// Except lists the IP ranges within the CIDR which aren't allowed.
+	Except []string `json:"except,omitempty"`
+}
+
+// FirewallPort is a port traffic is allowed on.
+type FirewallPort struct {
+	Port intstr.IntOrString `json:"port"`
+
+	// Protocol is either `TCP` or `UDP`, defaults to `TCP`.
+	Protocol corev1.Protocol `json:"protocol,omitempty"`
+}
+
+// CertManager configures how the cert-manager Certificates of a NetworkPolicy
+// are issued.
+type CertManager struct {
+	// IssuerRef is the Issuer or ClusterIssuer which issues the certificates.
+	IssuerRef CertIssuerReference `json:"issuerRef"`
+
+	// DisableWildcards disables wildcard certificates for templated domains.
+	// Wildcard certificates require an Issuer with a DNS01 solver.
+	// Defaults to `false`.
+	DisableWildcards bool `json:"disableWildcards,omitempty"`
+}
+
+// CertIssuerReference refers to a cert-manager Issuer or ClusterIssuer.
+type CertIssuerReference struct {
+	Name string `json:"name"`
+
+	// Kind is either `Issuer` or `ClusterIssuer`, defaults to `Issuer`.
+	Kind string `json:"kind,omitempty"`
+}
+
+// RoutingBackend is an API which can route external traffic to a release.
+type RoutingBackend string
+
+const (
+	// RoutingExtensionsIngress uses extensions/v1beta1 Ingresses.
+	RoutingExtensionsIngress RoutingBackend = "ExtensionsIngress"
+
+	// RoutingIngress uses networking.k8s.io/v1 Ingresses.
+	RoutingIngress RoutingBackend = "Ingress"
+
+	// RoutingHTTPRoute uses Gateway API HTTPRoutes.
+	RoutingHTTPRoute RoutingBackend = "HTTPRoute"
+
+	// RoutingIstio uses an Istio VirtualService and DestinationRule.
+	RoutingIstio RoutingBackend = "Istio"
+)
+
+// Routing describes how external traffic is routed to the releases of a
+// Microservice.
+type Routing struct {
+	// Backend is the API used for routing. When not provided, Istio is used
+	// if it's configured and supported by the cluster, followed by HTTPRoutes
+	// if a Gateway is configured and supported by the cluster,
+	// networking.k8s.io/v1 Ingresses and extensions/v1beta1 Ingresses.
+	Backend RoutingBackend `json:"backend,omitempty"`
+
+	// Gateway is the Gateway HTTPRoutes are attached to.
+	Gateway *GatewayReference `json:"gateway,omitempty"`
+
+	// Istio configures the Istio VirtualService.
+	Istio *IstioRouting `json:"istio,omitempty"`
+
+	// Rules route requests with a matching header or cookie to preview
+	// releases, on the domains of the release stream. Rules require the
+	// HTTPRoute or Istio backend.
+	Rules []RoutingRule `json:"rules,omitempty"`
+}
+
+// RoutingRule routes requests to a preview release based on a header or a
+// cookie. Exactly one of Header and Cookie should be set.
+type RoutingRule struct {
+	// Header is the name of the request header to match.
+	Header string `json:"header,omitempty"`
+
+	// Cookie is the name of the cookie to match.
+	Cookie string `json:"cookie,omitempty"`
+
+	// Value is the value the header or cookie should have for a request to be
+	// routed to a preview release. Values can be templated with the data of
+	// the Release, possible values are `Name`, `StreamName` and `FullName`.
+	// Defaults to `{{.Name}}`.
+	Value string `json:"value,omitempty"`
+}
+
+// IstioRouting configures how releases are exposed through Istio.
+type IstioRouting struct {
+	// Gateways are the Istio Gateways the VirtualService is bound to, which
+	// expose the external domains. The VirtualService is always bound to the
+	// mesh.
+	Gateways []string `json:"gateways,omitempty"`
+}
+
+// GatewayReference refers to a Gateway API Gateway.
+type GatewayReference struct {
+	Name string `json:"name"`
+
+	// Namespace defaults to the namespace of the NetworkPolicy.
+	Namespace string `json:"namespace,omitempty"`
+
+	// SectionName is the name of the listener of the Gateway to attach to.
+	SectionName string `json:"sectionName,omitempty"`
+}
+
+// ScaleToZero describes when preview releases are scaled down to zero replicas
+// and how traffic reaches them.
+type ScaleToZero struct {
+	// IdleTimeout is the amount of time without traffic after which a preview
+	// release is scaled down to zero replicas.
+	IdleTimeout metav1.Duration `json:"idleTimeout"`
+
+	// Activator is the fully qualified hostname of the activator Service.
+	// Defaults to `activator.hlnr-system.svc.cluster.local`.
+	Activator string `json:"activator,omitempty"`
 }
 
 // NetworkPort describes a port that is exposed for a given service.
@@ -60,18 +303,49 @@ type NetworkPort struct {
 
 	// The port this service will be available on from within the cluster.
 	Port int32 `json:"port"`
+
+	// Protocol is either `TCP`, `UDP` or `SCTP`, defaults to `TCP`.
+	Protocol corev1.Protocol `json:"protocol,omitempty"`
+
+	// AppProtocol is the application protocol of the port, like `http`,
+	// `h2c` or `grpc`. It's a hint for load balancers and service meshes.
+	AppProtocol string `json:"appProtocol,omitempty"`
 }
 
+// IngressController is an Ingress controller which Heighliner knows the
+// annotations of.
+type IngressController string
+
+// The supported Ingress controllers.
+const (
+	IngressControllerNginx   IngressController = "nginx"
+	IngressControllerTraefik IngressController = "traefik"
+	IngressControllerContour IngressController = "contour"
+	IngressControllerHAProxy IngressController = "haproxy"
+)
+
 // ExternalDNS describes a DNS entry for a given service, allowing external
 // access to the service.
 // If no port is provided but a DNS entry is provided, a default headless port
 // will be created with the internalPort `8080`.
+// Records with a different IngressClass, TTL, Controller or Annotations are
+// exposed through separate Ingresses.
 type ExternalDNS struct {
 	// IngressClass represents the class that is given to the Ingress controller
 	// to handle DNS entries. This defaults to the default at the controller
 	// configuration level.
 	IngressClass string `json:"ingressClass"`
 
+	// Controller is the Ingress controller which handles the IngressClass,
+	// used to set its TLS annotations. Possible values are `nginx`,
+	// `traefik`, `contour` and `haproxy`. Defaults to the IngressClass when
+	// it's one of these, and `nginx` otherwise.
+	Controller IngressController `json:"controller,omitempty"`
+
+	// Annotations are added to the Ingress of this record, and take
+	// precedence over the annotations Heighliner sets.
+	Annotations map[string]string `json:"annotations,omitempty"`
+
 	// The domain name that will be linked to the service. This can be a full
 	// fledged domain like `dashboard.heighliner.com` or it could be a templated
 	// domain like `{.StreamName}.pr.heighliner.com`. Templated domains get
@@ -80,8 +354,6 @@ type ExternalDNS struct {
 	Domain string `json:"domain"`
 
 	// TTL in seconds for the DNS entry, defaults to `300`.
-	// Note: if multiple DNS entries are provided, the TTL of the first record
-	// will be used.
 	TTL int32 `json:"ttl"`
 
 	// By default, TLS will be enabled for external access to a service.
@@ -91,12 +363,54 @@ type ExternalDNS struct {
 	// TLSGroup specifies the certificate group in which we'll store the SSL
 	// Certificates. This defaults to "heighliner-components". It is recommended
 	// to set this up per group of applications, this way the certificates will
-	// be stored together.
+	// be stored together. With CertManager configured, the NetworkPolicy
+	// manages a Certificate for each TLS group.
 	TLSGroup string `json:"tlsGroup"`
 
 	// Port links back to a NetworkPort and will be used to guide traffic for
 	// this hostname through the specified port. Defaults to `headless`.
 	Port string `json:"port"`
+
+	// Paths route parts of the domain to different ports or Microservices.
+	// Defaults to routing `/` to Port.
+	Paths []HTTPPath `json:"paths,omitempty"`
+}
+
+// PathType describes how the path of a request is matched.
+type PathType string
+
+// The supported PathTypes.
+const (
+	PathTypePrefix                 PathType = "Prefix"
+	PathTypeExact                  PathType = "Exact"
+	PathTypeImplementationSpecific PathType = "ImplementationSpecific"
+)
+
+// HTTPPath routes the requests for a path of a domain.
+type HTTPPath struct {
+	// Path is the path requests are matched against. Defaults to `/`.
+	Path string `json:"path,omitempty"`
+
+	// PathType describes how the path is matched, defaults to `Prefix`.
+	// extensions/v1beta1 Ingresses don't support path types.
+	PathType PathType `json:"pathType,omitempty"`
+
+	// Port is the name or number of the port requests are sent to. Defaults
+	// to the Port of the record. Paths routed to another Microservice through
+	// HTTPRoutes or Istio need a port number.
+	Port *intstr.IntOrString `json:"port,omitempty"`
+
+	// Microservice routes the path to another Microservice in the same
+	// namespace. Requests are sent to its release stream which matches the
+	// release, so a preview release is routed to the preview release of the
+	// other Microservice with the same name. When the other Microservice has
+	// no release in that stream, requests are sent to its release stream.
+	Microservice *corev1.LocalObjectReference `json:"microservice,omitempty"`
+
+	// Rewrite replaces the matched path prefix before the request is sent to
+	// the backend. It's only supported by the HTTPRoute and Istio routing
+	// backends, the other backends refuse paths with a rewrite.
+	Rewrite string `json:"rewrite,omitempty"`
 }
 
 // UpdateStrategy allows a strategy to be defined which will allow the
@@ -105,17 +419,47 @@ type ExternalDNS struct {
 // The fields defined on each strategy will be used as label selectors to select
 // the correct VersionedMicroservice.
 type UpdateStrategy struct {
-	Manual *ManualUpdateStrategy `json:"manual"`
-	Latest *LatestUpdateStrategy `json:"latest"`
+	Manual    *ManualUpdateStrategy    `json:"manual"`
+	Latest    *LatestUpdateStrategy    `json:"latest"`
+	Canary    *CanaryUpdateStrategy    `json:"canary,omitempty"`
+	BlueGreen *BlueGreenUpdateStrategy `json:"blueGreen,omitempty"`
 }
 
 // ManualUpdateStrategy is an UpdateStrategy that is purely manual. The
-// Controller will put in the labels as provided and won't take any other action
-// to detect possible versions.
+// Controller will pin the stream of the provided release to that release and
+// won't take any other action to detect possible versions. Other streams
+// follow the latest release.
+// Exactly one release should be provided, matching the versioning scheme of
+// the Microservice.
 type ManualUpdateStrategy struct {
 	// SemVer is the SemVer annotation of the specific release we want to use
 	// for this Microservice.
-	SemVer *SemVerRelease `json:"semVer"`
+	SemVer *SemVerRelease `json:"semVer,omitempty"`
+
+	// CalVer is the CalVer annotation of the specific release we want to use
+	// for this Microservice.
+	CalVer *CalVerRelease `json:"calVer,omitempty"`
+
+	// Commit is the Commit annotation of the specific release we want to use
+	// for this Microservice.
+	Commit *CommitRelease `json:"commit,omitempty"`
+
+	// Regex is the Regex annotation of the specific release we want to use
+	// for this Microservice.
+	Regex *RegexRelease `json:"regex,omitempty"`
+}
+
+// Release returns the versioning information of the pinned release. It
+// returns false if no release is pinned.
+func (s *ManualUpdateStrategy) Release() (name, version string, ok bool) {
+	d := Domain{
+		SemVer: s.SemVer,
+		CalVer: s.CalVer,
+		Commit: s.Commit,
+		Regex:  s.Regex,
+	}
+
+	return d.Release()
 }
 
 // LatestUpdateStrategy will monitor the available release for a given
@@ -123,9 +467,299 @@ type ManualUpdateStrategy struct {
 // external DNS.
 type LatestUpdateStrategy struct{}
 
+// CanaryUpdateStrategy will gradually shift traffic from the previous release
+// to the latest release of a stream. A share of the traffic is sent to the
+// latest release, which steps up on a schedule until the latest release is
+// promoted and receives all traffic.
+type CanaryUpdateStrategy struct {
+	// Steps are the percentages of traffic sent to the latest release, in
+	// order. Defaults to 10, 25 and 50 percent.
+	Steps []int32 `json:"steps,omitempty"`
+
+	// StepDuration is how long each step lasts before moving on to the next
+	// one. Defaults to 5 minutes.
+	StepDuration *metav1.Duration `json:"stepDuration,omitempty"`
+
+	// Analysis judges the latest release before moving on to the next step.
+	// When the analysis fails, the latest release is rolled back.
+	Analysis *CanaryAnalysis `json:"analysis,omitempty"`
+}
+
+// CanaryAnalysis compares metrics of the canary release with the metrics of
+// the baseline release, which is the release that was live before.
+type CanaryAnalysis struct {
+	// Address is the address of the Prometheus server to query, like
+	// http://prometheus.monitoring.svc.cluster.local:9090.
+	Address string `json:"address"`
+
+	// Metrics are the metrics which all need to pass for the canary release
+	// to move on to the next step.
+	Metrics []CanaryMetric `json:"metrics"`
+}
+
+// CanaryMetric is a PromQL query which is run for both the canary and the
+// baseline release. The query is a template which has access to the
+// Namespace, Name, Release, Version, FullName and StreamName of the release
+// being queried. The query should result in a single value.
+type CanaryMetric struct {
+	Name  string `json:"name"`
+	Query string `json:"query"`
+
+	// Max is the highest value the canary release is allowed to have.
+	Max string `json:"max,omitempty"`
+
+	// MaxBaselineRatio is the highest value the canary release is allowed to
+	// have compared to the baseline release. A ratio of 1.1 allows the
+	// canary release to be 10 percent higher than the baseline release.
+	MaxBaselineRatio string `json:"maxBaselineRatio,omitempty"`
+}
+
+var (
+	// DefaultCanarySteps are the canary steps used when none are provided.
+	DefaultCanarySteps = []int32{10, 25, 50}
+
+	// DefaultCanaryStepDuration is the canary step duration used when none
+	// is provided.
+	DefaultCanaryStepDuration = 5 * time.Minute
+)
+
+// Schedule returns the steps and step duration of the strategy, taking
+// defaults into account.
+func (s *CanaryUpdateStrategy) Schedule() ([]int32, time.Duration) {
+	steps := s.Steps
+	if len(steps) == 0 {
+		steps = DefaultCanarySteps
+	}
+
+	duration := DefaultCanaryStepDuration
+	if s.StepDuration != nil {
+		duration = s.StepDuration.Duration
+	}
+
+	return steps, duration
+}
+
+// BlueGreenUpdateStrategy keeps the previous release of a stream live until
+// the latest release has been verified, after which all traffic switches over
+// at once. The previous release stays deployed, so it's possible to roll back
+// quickly.
+type BlueGreenUpdateStrategy struct {
+	// Verification checks the latest release before it receives traffic.
+	// Without a verification, the latest release needs to be ready.
+	Verification *Verification `json:"verification,omitempty"`
+
+	// Timeout is how long the latest release has to pass its verification.
+	// Defaults to 10 minutes.
+	Timeout *metav1.Duration `json:"timeout,omitempty"`
+}
+
+// DefaultVerificationTimeout is the verification timeout used when none is
+// provided.
+var DefaultVerificationTimeout = 10 * time.Minute
+
+// VerificationTimeout returns the timeout of the strategy, taking defaults
+// into account.
+func (s *BlueGreenUpdateStrategy) VerificationTimeout() time.Duration {
+	if s.Timeout == nil {
+		return DefaultVerificationTimeout
+	}
+
+	return s.Timeout.Duration
+}
+
+// Verification describes how a release is verified before it receives
+// traffic. Only one of HTTP and Job can be used.
+type Verification struct {
+	HTTP *HTTPVerification `json:"http,omitempty"`
+
+	// Job is run against the Service of the release. The URL of the Service
+	// is available in the HLNR_SERVICE_URL environment variable.
+	Job *batchv1.JobSpec `json:"job,omitempty"`
+}
+
+// HTTPVerification verifies a release by sending a GET request to its
+// Service.
+type HTTPVerification struct {
+	// Path is the path to request. Defaults to /.
+	Path string `json:"path,omitempty"`
+
+	// Port is the name of the NetworkPort to send the request to. Defaults
+	// to headless.
+	Port string `json:"port,omitempty"`
+
+	// Status is the expected status code. Defaults to 200.
+	Status int32 `json:"status,omitempty"`
+}
+
 // NetworkPolicyStatus provides external domains and associated SemVer from the release
 type NetworkPolicyStatus struct {
 	Domains []Domain `json:"domains"`
+
+	// Streams describes which release is live for each release stream and
+	// why it was selected.
+	Streams []StreamStatus `json:"streams,omitempty"`
+
+	// Error describes why the NetworkPolicy could not be applied. When set,
+	// the live releases are left untouched.
+	Error string `json:"error,omitempty"`
+
+	// Certificates describes the cert-manager Certificates managed for the
+	// TLS groups of the NetworkPolicy.
+	Certificates []CertificateStatus `json:"certificates,omitempty"`
+
+	// DNSRecords lists the records managed through the DNSProvider.
+	DNSRecords []DNSRecordStatus `json:"dnsRecords,omitempty"`
+
+	// DNSProvider is the provider the DNSRecords were created with. It's kept
+	// once the DNSProvider is removed from the spec, until the records are
+	// deleted.
+	DNSProvider *DNSProvider `json:"dnsProvider,omitempty"`
+
+	// Deletion shows the progress of the cleanup once the NetworkPolicy is
+	// deleted.
+	Deletion *DeletionStatus `json:"deletion,omitempty"`
+
+	// ObservedGeneration is the generation of the NetworkPolicy which was last
+	// reconciled.
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
+	// Conditions describe whether the NetworkPolicy is ready.
+	Conditions Conditions `json:"conditions,omitempty"`
+}
+
+// DNSRecordStatus describes a record managed through the DNSProvider.
+type DNSRecordStatus struct {
+	// Name is the domain of the record.
+	Name string `json:"name"`
+
+	// Type is either `A`, `AAAA` or `CNAME`.
+	Type string `json:"type"`
+
+	// Target is what the record points to.
+	Target string `json:"target"`
+
+	// Stream is the release stream the record was created for.
+	Stream string `json:"stream"`
+}
+
+// CertificateStatus describes the readiness of a cert-manager Certificate.
+type CertificateStatus struct {
+	// Name is the name of the Certificate and the secret it's stored in.
+	Name string `json:"name"`
+
+	// TLSGroup is the TLS group the Certificate was issued for.
+	TLSGroup string `json:"tlsGroup"`
+
+	// DNSNames are the domains the Certificate is valid for.
+	DNSNames []string `json:"dnsNames"`
+
+	// Ready is set once the Certificate has been issued.
+	Ready bool `json:"ready"`
+
+	// Message is the message of the Ready condition of the Certificate.
+	Message string `json:"message,omitempty"`
+}
+
+// StreamStatus describes the live release of a release stream.
+type StreamStatus struct {
+	// Name is the name of the release stream.
+	Name string `json:"name"`
+
+	// Release is the name of the live release.
+	Release string `json:"release"`
+
+	// Version is the version of the live release.
+	Version string `json:"version"`
+
+	// Reason describes why the release was selected by the UpdateStrategy.
+	Reason string `json:"reason"`
+
+	// Canary describes the progress of the canary release of the stream, if
+	// any.
+	Canary *CanaryStatus `json:"canary,omitempty"`
+
+	// Verification describes the progress of the verification of the latest
+	// release of the stream, if any.
+	Verification *VerificationStatus `json:"verification,omitempty"`
+
+	// Error describes why the stream could not be synced. The rest of the
+	// status is left as it was before the failure.
+	Error string `json:"error,omitempty"`
+}
+
+// VerificationStatus describes the progress of the verification of a release.
+type VerificationStatus struct {
+	// Release is the name of the release being verified.
+	Release string `json:"release"`
+
+	// Version is the version of the release being verified.
+	Version string `json:"version"`
+
+	// Phase is the phase of the verification.
+	Phase VerificationPhase `json:"phase"`
+
+	// StartTime is when the verification started.
+	StartTime metav1.Time `json:"startTime"`
+
+	// Message describes why the verification failed.
+	Message string `json:"message,omitempty"`
+}
+
+// VerificationPhase describes the phase of a verification.
+type VerificationPhase string
+
+const (
+	// VerificationRunning is used while a release is being verified.
+	VerificationRunning VerificationPhase = "Running"
+
+	// VerificationFailed is used when a release failed its verification.
+	VerificationFailed VerificationPhase = "Failed"
+)
+
+// CanaryStatus describes the progress of a canary release.
+type CanaryStatus struct {
+	// Release is the name of the canary release.
+	Release string `json:"release"`
+
+	// Version is the version of the canary release.
+	Version string `json:"version"`
+
+	// Phase is the phase of the canary release.
+	Phase CanaryPhase `json:"phase"`
+
+	// Step is the index of the current canary step.
+	Step int32 `json:"step"`
+
+	// Weight is the percentage of traffic sent to the canary release.
+	Weight int32 `json:"weight"`
+
+	// StartTime is when the canary release started receiving traffic.
+	StartTime metav1.Time `json:"startTime"`
+
+	// Analysis are the results of the last canary analysis.
+	Analysis []CanaryMetricResult `json:"analysis,omitempty"`
+}
+
+// CanaryPhase describes the phase of a canary release.
+type CanaryPhase string
+
+const (
+	// CanaryProgressing is used when the canary release receives a share of
+	// the traffic.
+	CanaryProgressing CanaryPhase = "Progressing"
+
+	// CanaryRolledBack is used when the canary release failed its analysis
+	// and no longer receives traffic.
+	CanaryRolledBack CanaryPhase = "RolledBack"
+)
+
+// CanaryMetricResult is the result of a single CanaryMetric.
+type CanaryMetricResult struct {
+	Name     string `json:"name"`
+	Canary   string `json:"canary,omitempty"`
+	Baseline string `json:"baseline,omitempty"`
+	Passed   bool   `json:"passed"`
+	Message  string `json:"message,omitempty"`
 }
 
 // Domain is represents a url associated with the NetworkPolicy and the associated SemVer
@@ -136,6 +770,46 @@ type Domain struct {
 	// SemVer is the SemVer release object linked to this NetworkPolicyStatus if the
 	// VersioningPolicy associated with it is SemVer.
 	SemVer *SemVerRelease `json:"semVer,omitempty"`
+
+	// CalVer is the CalVer release object linked to this NetworkPolicyStatus
+	// if the VersioningPolicy associated with it is CalVer.
+	CalVer *CalVerRelease `json:"calVer,omitempty"`
+
+	// Commit is the Commit release object linked to this NetworkPolicyStatus
+	// if the VersioningPolicy associated with it is Commit.
+	Commit *CommitRelease `json:"commit,omitempty"`
+
+	// Regex is the Regex release object linked to this NetworkPolicyStatus if
+	// the VersioningPolicy associated with it is Regex.
+	Regex *RegexRelease `json:"regex,omitempty"`
+}
+
+// NewDomain creates a Domain for the given URL, linked to the given release.
+func NewDomain(url string, release *Release) Domain {
+	return Domain{
+		URL:    url,
+		SemVer: release.SemVer,
+		CalVer: release.CalVer,
+		Commit: release.Commit,
+		Regex:  release.Regex,
+	}
+}
+
+// Release returns the versioning information of the release this Domain is
+// linked to. It returns false if the Domain isn't linked to any release.
+func (d Domain) Release() (name, version string, ok bool) {
+	if d.SemVer == nil && d.CalVer == nil && d.Commit == nil && d.Regex == nil {
+		return "", "", false
+	}
+
+	r := Release{
+		SemVer: d.SemVer,
+		CalVer: d.CalVer,
+		Commit: d.Commit,
+		Regex:  d.Regex,
+	}
+
+	return r.Name(), r.Version(), true
 }
 
 // NetworkPolicyValidationSchema represents the OpenAPIV3Schema validation for
@@ -154,9 +828,37 @@ var NetworkPolicyValidationSchema = &v1beta1.CustomResourceValidation{
 						Items: &v1beta1.JSONSchemaPropsOrArray{
 							Schema: &v1beta1.JSONSchemaProps{
 								Required: []string{"name", "targetPort", "port"},
+								Properties: map[string]v1beta1.JSONSchemaProps{
+									"protocol": {
+										Type: proto.String,
+										Enum: []v1beta1.JSON{
+											{Raw: []byte(`"TCP"`)},
+											{Raw: []byte(`"UDP"`)},
+											{Raw: []byte(`"SCTP"`)},
+										},
+									},
+									"appProtocol": {
+										Type: proto.String,
+									},
+								},
 							},
 						},
 					},
+					"serviceType": {
+						Type: proto.String,
+						Enum: []v1beta1.JSON{
+							{Raw: []byte(`"ClusterIP"`)},
+							{Raw: []byte(`"NodePort"`)},
+							{Raw: []byte(`"LoadBalancer"`)},
+						},
+					},
+					"externalTrafficPolicy": {
+						Type: proto.String,
+						Enum: []v1beta1.JSON{
+							{Raw: []byte(`"Cluster"`)},
+							{Raw: []byte(`"Local"`)},
+						},
+					},
 					"externalDNS": {
 						Items: &v1beta1.JSONSchemaPropsOrArray{
 							Schema: &v1beta1.JSONSchemaProps{
@@ -174,12 +876,220 @@ var NetworkPolicyValidationSchema = &v1beta1.CustomResourceValidation{
 									"port": {
 										Type: proto.String,
 									},
+									"paths": {
+										Items: &v1beta1.JSONSchemaPropsOrArray{
+											Schema: &v1beta1.JSONSchemaProps{
+												Properties: map[string]v1beta1.JSONSchemaProps{
+													"pathType": {
+														Type: proto.String,
+														Enum: []v1beta1.JSON{
+															{Raw: []byte(`"Prefix"`)},
+															{Raw: []byte(`"Exact"`)},
+															{Raw: []byte(`"ImplementationSpecific"`)},
+														},
+													},
+												},
+											},
+										},
+									},
+									"controller": {
+										Type: proto.String,
+										Enum: []v1beta1.JSON{
+											{Raw: []byte(`"nginx"`)},
+											{Raw: []byte(`"traefik"`)},
+											{Raw: []byte(`"contour"`)},
+											{Raw: []byte(`"haproxy"`)},
+										},
+									},
+								},
+							},
+						},
+					},
+					"updateStrategy": {
+						Properties: map[string]v1beta1.JSONSchemaProps{
+							"canary": {
+								Properties: map[string]v1beta1.JSONSchemaProps{
+									"steps": {
+										Items: &v1beta1.JSONSchemaPropsOrArray{
+											Schema: &v1beta1.JSONSchemaProps{
+												Type:    proto.Integer,
+												Minimum: &canaryMinWeight,
+												Maximum: &canaryMaxWeight,
+											},
+										},
+									},
+									"stepDuration": {
+										Type: proto.String,
+									},
+									"analysis": {
+										Required: []string{"address", "metrics"},
+										Properties: map[string]v1beta1.JSONSchemaProps{
+											"address": {
+												Type: proto.String,
+											},
+											"metrics": {
+												Items: &v1beta1.JSONSchemaPropsOrArray{
+													Schema: &v1beta1.JSONSchemaProps{
+														Required: []string{"name", "query"},
+													},
+												},
+											},
+										},
+									},
+								},
+							},
+							"blueGreen": {
+								Properties: map[string]v1beta1.JSONSchemaProps{
+									"timeout": {
+										Type: proto.String,
+									},
+									"verification": {
+										Properties: map[string]v1beta1.JSONSchemaProps{
+											"http": {
+												Properties: map[string]v1beta1.JSONSchemaProps{
+													"path": {
+														Type: proto.String,
+													},
+													"port": {
+														Type: proto.String,
+													},
+													"status": {
+														Type: proto.Integer,
+													},
+												},
+											},
+										},
+									},
+								},
+							},
+						},
+					},
+					"routing": {
+						Properties: map[string]v1beta1.JSONSchemaProps{
+							"backend": {
+								Type: proto.String,
+								Enum: []v1beta1.JSON{
+									{Raw: []byte(`"ExtensionsIngress"`)},
+									{Raw: []byte(`"Ingress"`)},
+									{Raw: []byte(`"HTTPRoute"`)},
+									{Raw: []byte(`"Istio"`)},
+								},
+							},
+							"gateway": {
+								Required: []string{"name"},
+							},
+							"rules": {
+								Items: &v1beta1.JSONSchemaPropsOrArray{
+									Schema: &v1beta1.JSONSchemaProps{
+										OneOf: []v1beta1.JSONSchemaProps{
+											{Required: []string{"header"}},
+											{Required: []string{"cookie"}},
+										},
+									},
+								},
+							},
+						},
+					},
+					"certManager": {
+						Required: []string{"issuerRef"},
+						Properties: map[string]v1beta1.JSONSchemaProps{
+							"issuerRef": {
+								Required: []string{"name"},
+								Properties: map[string]v1beta1.JSONSchemaProps{
+									"kind": {
+										Type: proto.String,
+										Enum: []v1beta1.JSON{
+											{Raw: []byte(`"Issuer"`)},
+											{Raw: []byte(`"ClusterIssuer"`)},
+										},
+									},
 								},
 							},
 						},
 					},
+					"firewall": {
+						Properties: map[string]v1beta1.JSONSchemaProps{
+							"ingress": firewallRulesValidation,
+							"egress":  firewallRulesValidation,
+						},
+					},
+					"dnsProvider": {
+						Required: []string{"target"},
+						Properties: map[string]v1beta1.JSONSchemaProps{
+							"rfc2136": {
+								Required: []string{"server", "zone"},
+								Properties: map[string]v1beta1.JSONSchemaProps{
+									"tsig": {
+										Required: []string{"keyName", "secretRef"},
+										Properties: map[string]v1beta1.JSONSchemaProps{
+											"algorithm": {
+												Type: proto.String,
+												Enum: []v1beta1.JSON{
+													{Raw: []byte(`"hmac-sha1"`)},
+													{Raw: []byte(`"hmac-sha256"`)},
+													{Raw: []byte(`"hmac-sha512"`)},
+												},
+											},
+										},
+									},
+								},
+							},
+						},
+					},
+					"scaleToZero": {
+						Required: []string{"idleTimeout"},
+						Properties: map[string]v1beta1.JSONSchemaProps{
+							"idleTimeout": {
+								Type: proto.String,
+							},
+							"activator": {
+								Type: proto.String,
+							},
+						},
+					},
 				},
 			},
 		},
 	},
 }
+
+var firewallRulesValidation = v1beta1.JSONSchemaProps{
+	Items: &v1beta1.JSONSchemaPropsOrArray{
+		Schema: &v1beta1.JSONSchemaProps{
+			Properties: map[string]v1beta1.JSONSchemaProps{
+				"peers": {
+					Items: &v1beta1.JSONSchemaPropsOrArray{
+						Schema: &v1beta1.JSONSchemaProps{
+							AnyOf: []v1beta1.JSONSchemaProps{
+								{Required: []string{"microservice"}},
+								{Required: []string{"namespace"}},
+								{Required: []string{"cidr"}},
+							},
+						},
+					},
+				},
+				"ports": {
+					Items: &v1beta1.JSONSchemaPropsOrArray{
+						Schema: &v1beta1.JSONSchemaProps{
+							Required: []string{"port"},
+							Properties: map[string]v1beta1.JSONSchemaProps{
+								"protocol": {
+									Type: proto.String,
+									Enum: []v1beta1.JSON{
+										{Raw: []byte(`"TCP"`)},
+										{Raw: []byte(`"UDP"`)},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	},
+}
+
+var (
+	canaryMinWeight float64 = 1
+	canaryMaxWeight float64 = 99
+)
@@ -2,6 +2,8 @@ package v1alpha1
 
 import (
 	"fmt"
+	"strconv"
+	"strings"
 
 	"github.com/manifoldco/heighliner/internal/k8sutils"
 	"k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1beta1"
@@ -26,13 +28,115 @@ type Release struct {
 	// VersioningPolicy associated with it is SemVer.
 	SemVer *SemVerRelease `json:"semVer,omitempty"`
 
+	// CalVer is the CalVer release object linked to this Release if the
+	// VersioningPolicy associated with it is CalVer.
+	CalVer *CalVerRelease `json:"calVer,omitempty"`
+
+	// Commit is the Commit release object linked to this Release if the
+	// VersioningPolicy associated with it is Commit.
+	Commit *CommitRelease `json:"commit,omitempty"`
+
+	// Regex is the Regex release object linked to this Release if the
+	// VersioningPolicy associated with it is Regex.
+	Regex *RegexRelease `json:"regex,omitempty"`
+
 	// Level is the detected maturity level for this release
 	Level SemVerLevel `json:"level"`
+
+	// Expired is set when this preview release has expired according to the
+	// PreviewExpiry of its VersioningPolicy.
+	Expired *ReleaseExpiry `json:"expired,omitempty"`
+
+	// PullRequest is the number of the pull request of a preview release.
+	PullRequest int `json:"pullRequest,omitempty"`
+
+	// SHA is the commit the release was built from, when it's known.
+	SHA string `json:"sha,omitempty"`
+
+	// Hooks describes the progress of the hooks of the release. This will be
+	// set by the Microservice controller.
+	Hooks []HookStatus `json:"hooks,omitempty"`
+
+	// Status describes the rollout and health of the pods of the release.
+	// This will be set by the Microservice controller.
+	Status *ReleaseStatus `json:"status,omitempty"`
+}
+
+// ReleaseStatus describes the rollout and health of the pods of a release.
+type ReleaseStatus struct {
+	// Replicas is the desired number of pods.
+	Replicas int32 `json:"replicas"`
+
+	// ReadyReplicas is the number of pods which are ready.
+	ReadyReplicas int32 `json:"readyReplicas"`
+
+	// UpdatedReplicas is the number of pods which run the latest spec of the
+	// release.
+	UpdatedReplicas int32 `json:"updatedReplicas"`
+
+	// Rollout is the progress of the rollout of the release.
+	Rollout RolloutState `json:"rollout"`
+
+	// Restarts is the total number of container restarts of the pods.
+	Restarts int32 `json:"restarts"`
+
+	// LastFailure describes why a pod of the release last failed, like a
+	// container which was OOMKilled or can't pull its image.
+	LastFailure string `json:"lastFailure,omitempty"`
+
+	// URLs are the live URLs of the release, as reported by its
+	// NetworkPolicy.
+	URLs []string `json:"urls,omitempty"`
+}
+
+// RolloutState describes the progress of the rollout of a release.
+type RolloutState string
+
+const (
+	// RolloutPending is used when the Deployment of a release doesn't exist
+	// yet, like while pre-deploy hooks are running.
+	RolloutPending RolloutState = "Pending"
+
+	// RolloutProgressing is used while the pods of a release are being
+	// updated.
+	RolloutProgressing RolloutState = "Progressing"
+
+	// RolloutComplete is used when all pods of a release are updated and
+	// available.
+	RolloutComplete RolloutState = "Complete"
+
+	// RolloutFailed is used when the Deployment of a release exceeded its
+	// progress deadline.
+	RolloutFailed RolloutState = "Failed"
+)
+
+// ReleaseExpiry describes why and when a release expired.
+type ReleaseExpiry struct {
+	// Time is when the release expired.
+	Time metav1.Time `json:"time"`
+
+	// Reason is a human readable explanation of the expiry.
+	Reason string `json:"reason"`
+
+	// Action is what should happen with the expired release.
+	Action ExpiryAction `json:"action"`
+}
+
+// HooksSucceeded reports whether all hooks of the release succeeded. Releases
+// without hooks have nothing to wait for.
+func (r Release) HooksSucceeded() bool {
+	for _, hook := range r.Hooks {
+		if hook.State != HookStateSucceeded {
+			return false
+		}
+	}
+
+	return true
 }
 
 // String concatenates the Release values into a single unique string.
 func (r Release) String() string {
-	return fmt.Sprintf("%s-%s", r.SemVer.String(), r.ReleaseTime)
+	return fmt.Sprintf("%s-%s-%s", r.Name(), r.Version(), r.ReleaseTime)
 }
 
 // StreamName creates the release stream name for a release. It takes the name
@@ -43,37 +147,38 @@ func (r Release) String() string {
 //     candidate - `<prefix>-rc`
 //     preview - `<prefix>-pr-<hash of branch/release name>
 func (r Release) StreamName(prefix string) string {
-	if r.SemVer != nil {
-		switch r.Level {
-		case SemVerLevelRelease:
-			return prefix
-		case SemVerLevelReleaseCandidate:
-			return fmt.Sprintf("%s-rc", prefix)
-		case SemVerLevelPreview:
-			return fmt.Sprintf("%s-pr-%s", prefix, k8sutils.ShortHash(r.SemVer.Name, 8))
-		default:
-			panic("Unknown SemVerLevel")
-		}
-	}
+	name := r.Name()
 
-	panic("No release type specified")
+	switch r.Level {
+	case SemVerLevelRelease:
+		return prefix
+	case SemVerLevelReleaseCandidate:
+		return fmt.Sprintf("%s-rc", prefix)
+	case SemVerLevelPreview:
+		return fmt.Sprintf("%s-pr-%s", prefix, k8sutils.ShortHash(name, 8))
+	default:
+		panic("Unknown SemVerLevel")
+	}
 }
 
 // FullName creates the full name for a release. This is the stream name
 // suffixed by a version derived hash.
 // Microservice as a prefix.
 func (r Release) FullName(prefix string) string {
-	if r.SemVer != nil {
-		return fmt.Sprintf("%s-%s", r.StreamName(prefix), k8sutils.ShortHash(r.SemVer.Version, 8))
-	}
-
-	panic("No release type specified")
+	return fmt.Sprintf("%s-%s", r.StreamName(prefix), k8sutils.ShortHash(r.Version(), 8))
 }
 
 // Name returns the name of the actual version.
 func (r Release) Name() string {
-	if r.SemVer != nil {
+	switch {
+	case r.SemVer != nil:
 		return r.SemVer.Name
+	case r.CalVer != nil:
+		return r.CalVer.Name
+	case r.Commit != nil:
+		return r.Commit.Name
+	case r.Regex != nil:
+		return r.Regex.Name
 	}
 
 	panic("No release type specified")
@@ -81,13 +186,43 @@ func (r Release) Name() string {
 
 // Version returns the version of the release.
 func (r Release) Version() string {
-	if r.SemVer != nil {
+	switch {
+	case r.SemVer != nil:
 		return r.SemVer.Version
+	case r.CalVer != nil:
+		return r.CalVer.Version
+	case r.Commit != nil:
+		return r.Commit.SHA
+	case r.Regex != nil:
+		return r.Regex.Version
 	}
 
 	panic("No release type specified")
 }
 
+// Before reports whether this release was released before the other release.
+// CalVer and Regex releases are compared by their version and Commit releases
+// by the time of their commit. All other releases and versions which can't be
+// compared are ordered by their ReleaseTime.
+func (r Release) Before(o Release) bool {
+	switch {
+	case r.CalVer != nil && o.CalVer != nil:
+		if cmp, ok := compareCalVer(r.CalVer.Version, o.CalVer.Version); ok && cmp != 0 {
+			return cmp < 0
+		}
+	case r.Regex != nil && o.Regex != nil:
+		if cmp := compareKeys(r.Regex.Key, o.Regex.Key); cmp != 0 {
+			return cmp < 0
+		}
+	case r.Commit != nil && o.Commit != nil:
+		if !r.Commit.Time.IsZero() && !o.Commit.Time.IsZero() && !r.Commit.Time.Equal(&o.Commit.Time) {
+			return r.Commit.Time.Before(&o.Commit.Time)
+		}
+	}
+
+	return r.ReleaseTime.Before(&o.ReleaseTime)
+}
+
 // SemVerRelease represents a release which is linked to a SemVer
 // VersioningPolicy.
 type SemVerRelease struct {
@@ -105,14 +240,119 @@ func (r *SemVerRelease) String() string {
 	return fmt.Sprintf("%s-%s", r.Name, r.Version)
 }
 
+// CalVerRelease represents a release which is linked to a CalVer
+// VersioningPolicy.
+type CalVerRelease struct {
+	// Name represents the name of the service to be released. For releases and
+	// release candidates, this will be the name of the application, for
+	// previews this will be the preview tag (generally the branch name).
+	Name string `json:"name"`
+
+	// Version is the specific version for this release in a CalVer
+	// annotation.
+	Version string `json:"version"`
+}
+
+// CommitRelease represents a release which is linked to a Commit
+// VersioningPolicy.
+type CommitRelease struct {
+	// Name represents the name of the service to be released. For releases and
+	// release candidates, this will be the name of the application, for
+	// previews this will be the preview tag (generally the branch name).
+	Name string `json:"name"`
+
+	// SHA is the commit this release was built from.
+	SHA string `json:"sha"`
+
+	// Time is when the commit was made. It's used to order commit releases.
+	Time metav1.Time `json:"time,omitempty"`
+}
+
+// RegexRelease represents a release which is linked to a Regex
+// VersioningPolicy.
+type RegexRelease struct {
+	// Name represents the name of the service to be released. For releases and
+	// release candidates, this will be the name of the application, for
+	// previews this will be the preview tag (generally the branch name).
+	Name string `json:"name"`
+
+	// Version is the full version for this release.
+	Version string `json:"version"`
+
+	// Key is the ordering key extracted from the Version.
+	Key string `json:"key"`
+}
+
+// compareCalVer compares two calendar versions by their numeric components.
+// The boolean indicates if both versions could be parsed.
+func compareCalVer(a, b string) (int, bool) {
+	ap, aok := calVerParts(a)
+	bp, bok := calVerParts(b)
+	if !aok || !bok {
+		return 0, false
+	}
+
+	for i := 0; i < len(ap) && i < len(bp); i++ {
+		if ap[i] != bp[i] {
+			if ap[i] < bp[i] {
+				return -1, true
+			}
+			return 1, true
+		}
+	}
+
+	return len(ap) - len(bp), true
+}
+
+func calVerParts(version string) ([]int, bool) {
+	fields := strings.FieldsFunc(strings.TrimPrefix(version, "v"), func(c rune) bool {
+		return c == '.' || c == '-' || c == '_'
+	})
+	if len(fields) < 2 {
+		return nil, false
+	}
+
+	parts := make([]int, len(fields))
+	for i, f := range fields {
+		p, err := strconv.Atoi(f)
+		if err != nil {
+			return nil, false
+		}
+		parts[i] = p
+	}
+
+	return parts, true
+}
+
+// compareKeys compares two ordering keys, numerically if both are integers
+// and lexically otherwise.
+func compareKeys(a, b string) int {
+	ai, aerr := strconv.ParseInt(a, 10, 64)
+	bi, berr := strconv.ParseInt(b, 10, 64)
+	if aerr == nil && berr == nil {
+		switch {
+		case ai < bi:
+			return -1
+		case ai > bi:
+			return 1
+		}
+		return 0
+	}
+
+	return strings.Compare(a, b)
+}
+
 // ReleaseValidationSchema represents the OpenAPIv3 validation schema for a
 // release object.
 var ReleaseValidationSchema = v1beta1.JSONSchemaProps{
 	Properties: map[string]v1beta1.JSONSchemaProps{
 		"releases": {
-			Required: []string{"semVer", "image", "releaseTime"},
+			Required: []string{"image", "releaseTime"},
 			Properties: map[string]v1beta1.JSONSchemaProps{
 				"semVer": semVerReleaseValidation,
+				"calVer": semVerReleaseValidation,
+				"commit": commitReleaseValidation,
+				"regex":  regexReleaseValidation,
 			},
 		},
 	},
@@ -121,3 +361,11 @@ var ReleaseValidationSchema = v1beta1.JSONSchemaProps{
 var semVerReleaseValidation = v1beta1.JSONSchemaProps{
 	Required: []string{"version"},
 }
+
+var commitReleaseValidation = v1beta1.JSONSchemaProps{
+	Required: []string{"sha"},
+}
+
+var regexReleaseValidation = v1beta1.JSONSchemaProps{
+	Required: []string{"version", "key"},
+}
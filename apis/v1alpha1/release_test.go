@@ -2,6 +2,9 @@ package v1alpha1
 
 import (
 	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
 func TestReleaseNaming(t *testing.T) {
@@ -51,3 +54,100 @@ func TestReleaseNaming(t *testing.T) {
 		}
 	})
 }
+
+func TestReleaseNaming_VersioningSchemes(t *testing.T) {
+	tcs := []struct {
+		name    string
+		release Release
+	}{
+		{"calver", Release{CalVer: &CalVerRelease{Name: "hello-world", Version: "v1.2.3"}}},
+		{"commit", Release{Commit: &CommitRelease{Name: "hello-world", SHA: "v1.2.3"}}},
+		{"regex", Release{Regex: &RegexRelease{Name: "hello-world", Version: "v1.2.3", Key: "3"}}},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			release := tc.release
+			release.Level = SemVerLevelPreview
+
+			// names should match the semver equivalent
+			if name := release.StreamName("hello-world"); name != "hello-world-pr-cmqolv9f" {
+				t.Errorf("Expected stream name 'hello-world-pr-cmqolv9f', got '%s'", name)
+			}
+
+			if name := release.FullName("hello-world"); name != "hello-world-pr-cmqolv9f-hqo6t73v" {
+				t.Errorf("Expected full name 'hello-world-pr-cmqolv9f-hqo6t73v', got '%s'", name)
+			}
+
+			if version := release.Version(); version != "v1.2.3" {
+				t.Errorf("Expected version 'v1.2.3', got '%s'", version)
+			}
+		})
+	}
+}
+
+func TestReleaseBefore(t *testing.T) {
+	early := metav1.Date(2018, time.April, 28, 13, 42, 01, 0, time.UTC)
+	late := metav1.Date(2018, time.April, 29, 13, 42, 01, 0, time.UTC)
+
+	tcs := []struct {
+		name     string
+		a        Release
+		b        Release
+		expected bool
+	}{
+		{"semver uses release time",
+			Release{SemVer: &SemVerRelease{Version: "v2.0.0"}, ReleaseTime: early},
+			Release{SemVer: &SemVerRelease{Version: "v1.0.0"}, ReleaseTime: late},
+			true,
+		},
+		{"commit uses commit time",
+			Release{Commit: &CommitRelease{SHA: "b", Time: early}, ReleaseTime: late},
+			Release{Commit: &CommitRelease{SHA: "a", Time: late}, ReleaseTime: early},
+			true,
+		},
+		{"unresolved commit uses release time",
+			Release{Commit: &CommitRelease{SHA: "b", Time: early}, ReleaseTime: late},
+			Release{Commit: &CommitRelease{SHA: "a"}, ReleaseTime: early},
+			false,
+		},
+		{"calver uses version",
+			Release{CalVer: &CalVerRelease{Version: "2018.10.2"}, ReleaseTime: early},
+			Release{CalVer: &CalVerRelease{Version: "2018.9.5"}, ReleaseTime: late},
+			false,
+		},
+		{"calver with more components is newer",
+			Release{CalVer: &CalVerRelease{Version: "2018.10"}, ReleaseTime: late},
+			Release{CalVer: &CalVerRelease{Version: "2018.10.1"}, ReleaseTime: early},
+			true,
+		},
+		{"unparsable calver uses release time",
+			Release{CalVer: &CalVerRelease{Version: "2018.10.2"}, ReleaseTime: early},
+			Release{CalVer: &CalVerRelease{Version: "8d2ab3f"}, ReleaseTime: late},
+			true,
+		},
+		{"numeric regex keys",
+			Release{Regex: &RegexRelease{Key: "10"}, ReleaseTime: early},
+			Release{Regex: &RegexRelease{Key: "9"}, ReleaseTime: late},
+			false,
+		},
+		{"lexical regex keys",
+			Release{Regex: &RegexRelease{Key: "alpha"}, ReleaseTime: late},
+			Release{Regex: &RegexRelease{Key: "beta"}, ReleaseTime: early},
+			true,
+		},
+		{"equal regex keys use release time",
+			Release{Regex: &RegexRelease{Key: "10"}, ReleaseTime: early},
+			Release{Regex: &RegexRelease{Key: "10"}, ReleaseTime: late},
+			true,
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			if actual := tc.a.Before(tc.b); actual != tc.expected {
+				t.Errorf("Expected %t, got %t", tc.expected, actual)
+			}
+		})
+	}
+}
@@ -0,0 +1,132 @@
+package v1alpha1
+
+import (
+	"github.com/manifoldco/heighliner/internal/k8sutils"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1beta1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// ResourcePolicy describes the configuration options for the ResourcePolicy.
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+type ResourcePolicy struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata"`
+
+	Spec ResourcePolicySpec `json:"spec"`
+}
+
+// ResourcePolicyList is a list of ResourcePolicy CRDs.
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+type ResourcePolicyList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata"`
+	Items           []ResourcePolicy `json:"items"`
+}
+
+// ResourcePolicySpec describes the compute resources of the container of a
+// Microservice.
+type ResourcePolicySpec struct {
+	Resources `json:",inline"`
+
+	// Levels override the resources for the releases of a specific level,
+	// like smaller footprints for previews. Only the given resources are
+	// overridden.
+	Levels []LevelResources `json:"levels,omitempty"`
+}
+
+// Resources describes the requests and limits of a container.
+type Resources struct {
+	// Requests describes the minimum amount of compute resources required.
+	Requests v1.ResourceList `json:"requests,omitempty"`
+
+	// Limits describes the maximum amount of compute resources allowed.
+	Limits v1.ResourceList `json:"limits,omitempty"`
+
+	// EphemeralStorage is the amount of local storage which is both requested
+	// and the limit.
+	EphemeralStorage *resource.Quantity `json:"ephemeralStorage,omitempty"`
+}
+
+// LevelResources are the resources for the releases of a level.
+type LevelResources struct {
+	Level     SemVerLevel `json:"level"`
+	Resources `json:",inline"`
+}
+
+// ForLevel returns the resource requirements for a release of the given
+// level.
+func (s *ResourcePolicySpec) ForLevel(level SemVerLevel) v1.ResourceRequirements {
+	req := v1.ResourceRequirements{
+		Requests: v1.ResourceList{},
+		Limits:   v1.ResourceList{},
+	}
+
+	s.Resources.apply(&req)
+	for _, l := range s.Levels {
+		if l.Level == level {
+			l.Resources.apply(&req)
+		}
+	}
+
+	if len(req.Requests) == 0 {
+		req.Requests = nil
+	}
+
+	if len(req.Limits) == 0 {
+		req.Limits = nil
+	}
+
+	return req
+}
+
+func (r *Resources) apply(req *v1.ResourceRequirements) {
+	for name, q := range r.Requests {
+		req.Requests[name] = q.DeepCopy()
+	}
+
+	for name, q := range r.Limits {
+		req.Limits[name] = q.DeepCopy()
+	}
+
+	if r.EphemeralStorage != nil {
+		req.Requests[v1.ResourceEphemeralStorage] = r.EphemeralStorage.DeepCopy()
+		req.Limits[v1.ResourceEphemeralStorage] = r.EphemeralStorage.DeepCopy()
+	}
+}
+
+var levelResourcesValidation = v1beta1.JSONSchemaProps{
+	Type: "array",
+	Items: &v1beta1.JSONSchemaPropsOrArray{
+		Schema: &v1beta1.JSONSchemaProps{
+			Required: []string{"level"},
+			Properties: map[string]v1beta1.JSONSchemaProps{
+				"level": {
+					Type: "string",
+					Enum: []v1beta1.JSON{
+						{Raw: k8sutils.JSONBytes("release")},
+						{Raw: k8sutils.JSONBytes("candidate")},
+						{Raw: k8sutils.JSONBytes("preview")},
+					},
+				},
+			},
+		},
+	},
+}
+
+// ResourcePolicyValidationSchema represents the OpenAPIV3Schema validation for
+// the ResourcePolicy CRD.
+var ResourcePolicyValidationSchema = &v1beta1.CustomResourceValidation{
+	OpenAPIV3Schema: &v1beta1.JSONSchemaProps{
+		Required: []string{"spec"},
+		Properties: map[string]v1beta1.JSONSchemaProps{
+			"spec": {
+				Properties: map[string]v1beta1.JSONSchemaProps{
+					"levels": levelResourcesValidation,
+				},
+			},
+		},
+	},
+}
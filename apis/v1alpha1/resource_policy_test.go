@@ -0,0 +1,63 @@
+package v1alpha1
+
+import (
+	"testing"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+func TestResourcePolicySpec_ForLevel(t *testing.T) {
+	storage := resource.MustParse("1Gi")
+	spec := &ResourcePolicySpec{
+		Resources: Resources{
+			Requests: v1.ResourceList{
+				v1.ResourceCPU:    resource.MustParse("500m"),
+				v1.ResourceMemory: resource.MustParse("512Mi"),
+			},
+			Limits: v1.ResourceList{
+				v1.ResourceMemory: resource.MustParse("1Gi"),
+			},
+			EphemeralStorage: &storage,
+		},
+		Levels: []LevelResources{
+			{
+				Level: SemVerLevelPreview,
+				Resources: Resources{
+					Requests: v1.ResourceList{v1.ResourceCPU: resource.MustParse("50m")},
+				},
+			},
+		},
+	}
+
+	tcs := []struct {
+		level  SemVerLevel
+		cpu    string
+		memory string
+	}{
+		{SemVerLevelRelease, "500m", "512Mi"},
+		{SemVerLevelPreview, "50m", "512Mi"},
+	}
+
+	for _, tc := range tcs {
+		t.Run(string(tc.level), func(t *testing.T) {
+			req := spec.ForLevel(tc.level)
+
+			if q := req.Requests[v1.ResourceCPU]; q.String() != tc.cpu {
+				t.Errorf("Expected a CPU request of %s, got %s", tc.cpu, q.String())
+			}
+
+			if q := req.Requests[v1.ResourceMemory]; q.String() != tc.memory {
+				t.Errorf("Expected a memory request of %s, got %s", tc.memory, q.String())
+			}
+
+			if q := req.Limits[v1.ResourceEphemeralStorage]; q.String() != "1Gi" {
+				t.Errorf("Expected an ephemeral storage limit of 1Gi, got %s", q.String())
+			}
+		})
+	}
+
+	if req := (&ResourcePolicySpec{}).ForLevel(SemVerLevelRelease); req.Requests != nil || req.Limits != nil {
+		t.Errorf("Expected no resources for an empty policy, got %+v", req)
+	}
+}
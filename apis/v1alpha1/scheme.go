@@ -50,6 +50,8 @@ func addKnownTypes(scheme *runtime.Scheme) error {
 		&GitHubRepositoryList{},
 		&HealthPolicy{},
 		&HealthPolicyList{},
+		&ResourcePolicy{},
+		&ResourcePolicyList{},
 	)
 
 	v1.AddToGroupVersion(scheme, v1alpha1.SchemeGroupVersion)
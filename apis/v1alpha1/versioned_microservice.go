@@ -15,7 +15,8 @@ type VersionedMicroservice struct {
 	metav1.TypeMeta   `json:",inline"`
 	metav1.ObjectMeta `json:"metadata"`
 
-	Spec VersionedMicroserviceSpec `json:"spec"`
+	Spec   VersionedMicroserviceSpec   `json:"spec"`
+	Status VersionedMicroserviceStatus `json:"status"`
 }
 
 // VersionedMicroserviceList is a list of VersionedMicroservices.
@@ -29,11 +30,39 @@ type VersionedMicroserviceList struct {
 // VersionedMicroserviceSpec represents the specification for a
 // VersionedMicroservice.
 type VersionedMicroserviceSpec struct {
-	Availability     *AvailabilityPolicySpec       `json:"availability,omitempty"`
-	Config           *ConfigPolicySpec             `json:"config,omitempty"`
-	Security         *SecurityPolicySpec           `json:"security,omitempty"`
+	Availability *AvailabilityPolicySpec `json:"availability,omitempty"`
+
+	// Config is applied to the first container, which is the container of
+	// the Microservice. Sidecars and init containers are configured through
+	// their own ConfigPolicy.
+	Config   *ConfigPolicySpec   `json:"config,omitempty"`
+	Security *SecurityPolicySpec `json:"security,omitempty"`
+
 	Containers       []corev1.Container            `json:"containers"`
+	InitContainers   []corev1.Container            `json:"initContainers,omitempty"`
 	ImagePullSecrets []corev1.LocalObjectReference `json:"imagePullSecrets"`
+
+	// Volumes are the volumes of the ConfigPolicies of sidecars and init
+	// containers.
+	Volumes []corev1.Volume `json:"volumes,omitempty"`
+
+	// Hooks are the Jobs which run before and after the Deployment is
+	// applied.
+	Hooks *Hooks `json:"hooks,omitempty"`
+}
+
+// VersionedMicroserviceStatus represents the status of the deployment of a
+// VersionedMicroservice.
+type VersionedMicroserviceStatus struct {
+	// Hooks describes the progress of the hooks of the release.
+	Hooks []HookStatus `json:"hooks,omitempty"`
+
+	// ObservedGeneration is the generation of the VersionedMicroservice which was last
+	// reconciled.
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
+	// Conditions describe whether the VersionedMicroservice is ready.
+	Conditions Conditions `json:"conditions,omitempty"`
 }
 
 // VersionedMicroserviceValidationSchema represents the OpenAPIV3Scheme which
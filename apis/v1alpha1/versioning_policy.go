@@ -1,6 +1,12 @@
 package v1alpha1
 
 import (
+	"errors"
+	"fmt"
+	"regexp"
+	"sync"
+	"time"
+
 	"github.com/manifoldco/heighliner/internal/k8sutils"
 	apiextv1beta1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1beta1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -24,9 +30,75 @@ type VersioningPolicyList struct {
 	Items           []VersioningPolicy `json:"items"`
 }
 
-// VersioningPolicySpec describes the specification for Versioning.
+// VersioningPolicySpec describes the specification for Versioning. Exactly
+// one versioning scheme should be provided.
 type VersioningPolicySpec struct {
-	SemVer *SemVerSource `json:"semVer"`
+	SemVer *SemVerSource `json:"semVer,omitempty"`
+	CalVer *CalVerSource `json:"calVer,omitempty"`
+	Commit *CommitSource `json:"commit,omitempty"`
+	Regex  *RegexSource  `json:"regex,omitempty"`
+
+	// PreviewExpiry configures when preview releases should be considered
+	// expired. It is ignored for all other levels.
+	PreviewExpiry *PreviewExpiry `json:"previewExpiry,omitempty"`
+}
+
+var (
+	// ErrNoVersioningScheme is used when a VersioningPolicy doesn't have any
+	// versioning scheme configured.
+	ErrNoVersioningScheme = errors.New("no versioning scheme configured")
+
+	// ErrNoRegexMatch is used when a version doesn't match the pattern of a
+	// Regex versioning scheme.
+	ErrNoRegexMatch = errors.New("version does not match the regex pattern")
+)
+
+// Level returns the release level this VersioningPolicy tracks, regardless of
+// the versioning scheme used. It returns an empty level when no scheme is
+// configured.
+func (s *VersioningPolicySpec) Level() SemVerLevel {
+	switch {
+	case s.SemVer != nil:
+		return s.SemVer.Level
+	case s.CalVer != nil:
+		return s.CalVer.Level
+	case s.Commit != nil:
+		return s.Commit.Level
+	case s.Regex != nil:
+		return s.Regex.Level
+	}
+
+	return ""
+}
+
+// NewRelease creates a Release for the given name and version following the
+// configured versioning scheme. For the commit scheme, the version is the SHA
+// of the commit. The Image, ReleaseTime and the time of the commit are left
+// for the caller to fill in.
+func (s *VersioningPolicySpec) NewRelease(name, version string) (*Release, error) {
+	release := &Release{
+		Level: s.Level(),
+	}
+
+	switch {
+	case s.SemVer != nil:
+		release.SemVer = &SemVerRelease{Name: name, Version: version}
+	case s.CalVer != nil:
+		release.CalVer = &CalVerRelease{Name: name, Version: version}
+	case s.Commit != nil:
+		release.Commit = &CommitRelease{Name: name, SHA: version}
+	case s.Regex != nil:
+		key, err := s.Regex.Key(version)
+		if err != nil {
+			return nil, err
+		}
+
+		release.Regex = &RegexRelease{Name: name, Version: version, Key: key}
+	default:
+		return nil, ErrNoVersioningScheme
+	}
+
+	return release, nil
 }
 
 type (
@@ -81,6 +153,155 @@ type SemVerSource struct {
 	MinVersion string `json:"minVersion"`
 }
 
+// CalVerSource is a versioning policy based on calendar versioning, like
+// `2018.06.1` or `18.06.1`. Versions are ordered by their numeric components,
+// falling back to the release time for versions that can't be parsed.
+type CalVerSource struct {
+	// Level is the level we want to fetch images for this Microservice for.
+	Level SemVerLevel `json:"level"`
+}
+
+// CommitSource is a versioning policy based on raw commit SHAs. Versions are
+// ordered by the time of the commit, falling back to the release time for
+// commits which haven't been resolved.
+type CommitSource struct {
+	// Level is the level we want to fetch images for this Microservice for.
+	Level SemVerLevel `json:"level"`
+}
+
+// RegexSource is a versioning policy which extracts an ordering key from the
+// version with a regular expression.
+type RegexSource struct {
+	// Level is the level we want to fetch images for this Microservice for.
+	Level SemVerLevel `json:"level"`
+
+	// Pattern is the regular expression used to extract the ordering key from
+	// a version. The value of the capture group named `key` is used, or the
+	// first capture group if there is no such group, or the full match if
+	// there are no capture groups at all.
+	// Versions that don't match the pattern are ignored.
+	// Keys that are integers are compared numerically, all other keys are
+	// compared lexically.
+	Pattern string `json:"pattern"`
+}
+
+// regexps caches the compiled patterns of RegexSources, as Key is called for
+// every release.
+var regexps sync.Map
+
+// Key extracts the ordering key from the given version.
+func (s *RegexSource) Key(version string) (string, error) {
+	re, err := s.regexp()
+	if err != nil {
+		return "", fmt.Errorf("invalid regex pattern: %s", err)
+	}
+
+	matches := re.FindStringSubmatch(version)
+	if matches == nil {
+		return "", ErrNoRegexMatch
+	}
+
+	for i, name := range re.SubexpNames() {
+		if name == "key" {
+			return matches[i], nil
+		}
+	}
+
+	if len(matches) > 1 {
+		return matches[1], nil
+	}
+
+	return matches[0], nil
+}
+
+// regexp returns the compiled pattern, compiling it only once.
+func (s *RegexSource) regexp() (*regexp.Regexp, error) {
+	if re, ok := regexps.Load(s.Pattern); ok {
+		return re.(*regexp.Regexp), nil
+	}
+
+	re, err := regexp.Compile(s.Pattern)
+	if err != nil {
+		return nil, err
+	}
+
+	regexps.Store(s.Pattern, re)
+	return re, nil
+}
+
+// ExpiryAction describes what happens to a preview release once it expired.
+type ExpiryAction string
+
+var (
+	// ExpiryActionScaleDown scales the preview release down to zero replicas,
+	// keeping all other resources in place.
+	ExpiryActionScaleDown ExpiryAction = "ScaleDown"
+
+	// ExpiryActionTearDown removes the preview release and all its resources.
+	ExpiryActionTearDown ExpiryAction = "TearDown"
+)
+
+// PreviewExpiry describes when preview releases expire. An expired preview
+// can be revived by pushing to the pull request or by commenting
+// `/hlnr revive` on it.
+type PreviewExpiry struct {
+	// TTL is the maximum amount of time a preview release lives, measured
+	// from when the pull request was opened or last revived.
+	TTL *metav1.Duration `json:"ttl,omitempty"`
+
+	// IdleTimeout is the amount of time a preview release lives without
+	// activity, measured from the head commit of the pull request or the last
+	// revive.
+	IdleTimeout *metav1.Duration `json:"idleTimeout,omitempty"`
+
+	// Action is what happens to an expired preview release. Defaults to
+	// ScaleDown.
+	Action ExpiryAction `json:"action,omitempty"`
+}
+
+// Check verifies if the given GitHubRelease is expired at the given time. It
+// returns nil if the release is still valid.
+func (e *PreviewExpiry) Check(gh GitHubRelease, now time.Time) *ReleaseExpiry {
+	if e == nil || gh.Level != SemVerLevelPreview {
+		return nil
+	}
+
+	action := e.Action
+	if action == "" {
+		action = ExpiryActionScaleDown
+	}
+
+	var expiry *ReleaseExpiry
+	expire := func(since metav1.Time, d *metav1.Duration, reason string) {
+		if d == nil || since.IsZero() {
+			return
+		}
+
+		if gh.RevivedTime != nil && gh.RevivedTime.After(since.Time) {
+			since = *gh.RevivedTime
+		}
+
+		at := since.Add(d.Duration)
+		if now.Before(at) {
+			return
+		}
+
+		if expiry == nil || at.Before(expiry.Time.Time) {
+			expiry = &ReleaseExpiry{
+				Time:   metav1.NewTime(at),
+				Reason: fmt.Sprintf(reason, d.Duration),
+				Action: action,
+			}
+		}
+	}
+
+	expire(gh.OpenedTime, e.TTL, "preview expired after its TTL of %s")
+	// previews aren't considered idle until their head commit is resolved.
+	expire(gh.CommitTime, e.IdleTimeout, "preview expired after being idle for %s")
+
+	return expiry
+}
+
 // VersioningPolicyValidationSchema represents the OpenAPIV3Schema validation for
 // the NetworkPolicy CRD.
 var VersioningPolicyValidationSchema = apiextv1beta1.JSONSchemaProps{
@@ -95,16 +316,56 @@ var VersioningPolicyValidationSchema = apiextv1beta1.JSONSchemaProps{
 						{Raw: k8sutils.JSONBytes(SemVerVersionPatch)},
 					},
 				},
-				"level": {
+				"level": semVerLevelValidation,
+			},
+			Required: []string{"version", "level"},
+		},
+		"calVer": {
+			Properties: map[string]apiextv1beta1.JSONSchemaProps{
+				"level": semVerLevelValidation,
+			},
+			Required: []string{"level"},
+		},
+		"commit": {
+			Properties: map[string]apiextv1beta1.JSONSchemaProps{
+				"level": semVerLevelValidation,
+			},
+			Required: []string{"level"},
+		},
+		"regex": {
+			Properties: map[string]apiextv1beta1.JSONSchemaProps{
+				"level": semVerLevelValidation,
+				"pattern": {
+					Type: proto.String,
+				},
+			},
+			Required: []string{"level", "pattern"},
+		},
+		"previewExpiry": {
+			Properties: map[string]apiextv1beta1.JSONSchemaProps{
+				"ttl": {
+					Type: proto.String,
+				},
+				"idleTimeout": {
+					Type: proto.String,
+				},
+				"action": {
 					Type: proto.String,
 					Enum: []apiextv1beta1.JSON{
-						{Raw: k8sutils.JSONBytes(SemVerLevelRelease)},
-						{Raw: k8sutils.JSONBytes(SemVerLevelReleaseCandidate)},
-						{Raw: k8sutils.JSONBytes(SemVerLevelPreview)},
+						{Raw: k8sutils.JSONBytes(ExpiryActionScaleDown)},
+						{Raw: k8sutils.JSONBytes(ExpiryActionTearDown)},
 					},
 				},
 			},
-			Required: []string{"version", "level"},
 		},
 	},
 }
+
+var semVerLevelValidation = apiextv1beta1.JSONSchemaProps{
+	Type: proto.String,
+	Enum: []apiextv1beta1.JSON{
+		{Raw: k8sutils.JSONBytes(SemVerLevelRelease)},
+		{Raw: k8sutils.JSONBytes(SemVerLevelReleaseCandidate)},
+		{Raw: k8sutils.JSONBytes(SemVerLevelPreview)},
+	},
+}
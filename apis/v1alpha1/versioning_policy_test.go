@@ -0,0 +1,158 @@
+package v1alpha1
+
+import (
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestVersioningPolicySpecNewRelease(t *testing.T) {
+	t.Run("without a versioning scheme", func(t *testing.T) {
+		spec := &VersioningPolicySpec{}
+		if _, err := spec.NewRelease("hello-world", "v1.2.3"); err != ErrNoVersioningScheme {
+			t.Errorf("Expected '%s', got '%v'", ErrNoVersioningScheme, err)
+		}
+	})
+
+	tcs := []struct {
+		name    string
+		spec    VersioningPolicySpec
+		version string
+		check   func(*Release) bool
+	}{
+		{"semver", VersioningPolicySpec{SemVer: &SemVerSource{Level: SemVerLevelRelease}}, "v1.2.3",
+			func(r *Release) bool { return r.SemVer != nil && r.SemVer.Version == "v1.2.3" }},
+		{"calver", VersioningPolicySpec{CalVer: &CalVerSource{Level: SemVerLevelRelease}}, "2018.10.1",
+			func(r *Release) bool { return r.CalVer != nil && r.CalVer.Version == "2018.10.1" }},
+		{"commit", VersioningPolicySpec{Commit: &CommitSource{Level: SemVerLevelRelease}}, "8d2ab3f",
+			func(r *Release) bool { return r.Commit != nil && r.Commit.SHA == "8d2ab3f" }},
+		{"regex with named group", VersioningPolicySpec{Regex: &RegexSource{Level: SemVerLevelRelease, Pattern: `^(build)-(?P<key>\d+)$`}}, "build-42",
+			func(r *Release) bool { return r.Regex != nil && r.Regex.Key == "42" }},
+		{"regex with capture group", VersioningPolicySpec{Regex: &RegexSource{Level: SemVerLevelRelease, Pattern: `^build-(\d+)$`}}, "build-42",
+			func(r *Release) bool { return r.Regex != nil && r.Regex.Key == "42" }},
+		{"regex without groups", VersioningPolicySpec{Regex: &RegexSource{Level: SemVerLevelRelease, Pattern: `\d+`}}, "build-42",
+			func(r *Release) bool { return r.Regex != nil && r.Regex.Key == "42" }},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			release, err := tc.spec.NewRelease("hello-world", tc.version)
+			if err != nil {
+				t.Fatalf("Expected no error, got '%s'", err)
+			}
+
+			if release.Level != SemVerLevelRelease {
+				t.Errorf("Expected level '%s', got '%s'", SemVerLevelRelease, release.Level)
+			}
+
+			if release.Name() != "hello-world" {
+				t.Errorf("Expected name 'hello-world', got '%s'", release.Name())
+			}
+
+			if !tc.check(release) {
+				t.Errorf("Unexpected release %#v", release)
+			}
+		})
+	}
+
+	t.Run("regex without a match", func(t *testing.T) {
+		spec := &VersioningPolicySpec{Regex: &RegexSource{Pattern: `^build-(\d+)$`}}
+		if _, err := spec.NewRelease("hello-world", "v1.2.3"); err != ErrNoRegexMatch {
+			t.Errorf("Expected '%s', got '%v'", ErrNoRegexMatch, err)
+		}
+	})
+
+	t.Run("regex compiled once", func(t *testing.T) {
+		source := &RegexSource{Pattern: `^compiled-(\d+)$`}
+		if _, err := source.Key("compiled-1"); err != nil {
+			t.Fatalf("Expected no error, got '%s'", err)
+		}
+
+		first, _ := regexps.Load(source.Pattern)
+		if _, err := source.Key("compiled-2"); err != nil {
+			t.Fatalf("Expected no error, got '%s'", err)
+		}
+
+		if second, _ := regexps.Load(source.Pattern); first == nil || first != second {
+			t.Errorf("Expected the pattern to be compiled once")
+		}
+	})
+
+	t.Run("invalid regex", func(t *testing.T) {
+		source := &RegexSource{Pattern: `^build-(\d+$`}
+		if _, err := source.Key("build-1"); err == nil {
+			t.Errorf("Expected an error for the invalid pattern")
+		}
+	})
+}
+
+func TestPreviewExpiryCheck(t *testing.T) {
+	opened := time.Date(2018, 6, 1, 0, 0, 0, 0, time.UTC)
+	pushed := opened.Add(48 * time.Hour)
+	day := &metav1.Duration{Duration: 24 * time.Hour}
+	week := &metav1.Duration{Duration: 7 * 24 * time.Hour}
+
+	preview := GitHubRelease{
+		Level:       SemVerLevelPreview,
+		OpenedTime:  metav1.NewTime(opened),
+		ReleaseTime: metav1.NewTime(pushed.Add(96 * time.Hour)),
+		CommitTime:  metav1.NewTime(pushed),
+	}
+
+	unresolved := preview
+	unresolved.CommitTime = metav1.Time{}
+
+	revived := preview
+	revived.RevivedTime = &metav1.Time{Time: pushed.Add(72 * time.Hour)}
+
+	candidate := preview
+	candidate.Level = SemVerLevelReleaseCandidate
+
+	tcs := []struct {
+		name    string
+		expiry  *PreviewExpiry
+		release GitHubRelease
+		now     time.Time
+		at      time.Time
+		action  ExpiryAction
+	}{
+		{"without expiry", nil, preview, pushed.Add(365 * 24 * time.Hour), time.Time{}, ""},
+		{"not a preview", &PreviewExpiry{IdleTimeout: day}, candidate, pushed.Add(48 * time.Hour), time.Time{}, ""},
+		{"idle", &PreviewExpiry{IdleTimeout: day}, preview, pushed.Add(48 * time.Hour), pushed.Add(24 * time.Hour), ExpiryActionScaleDown},
+		{"not idle yet", &PreviewExpiry{IdleTimeout: day}, preview, pushed.Add(time.Hour), time.Time{}, ""},
+		{"unresolved commit", &PreviewExpiry{IdleTimeout: day}, unresolved, pushed.Add(48 * time.Hour), time.Time{}, ""},
+		{"ttl", &PreviewExpiry{TTL: week, Action: ExpiryActionTearDown}, preview, opened.Add(8 * 24 * time.Hour), opened.Add(7 * 24 * time.Hour), ExpiryActionTearDown},
+		{"earliest expiry wins", &PreviewExpiry{TTL: day, IdleTimeout: day}, preview, pushed.Add(48 * time.Hour), opened.Add(24 * time.Hour), ExpiryActionScaleDown},
+		{"revived", &PreviewExpiry{IdleTimeout: day}, revived, pushed.Add(84 * time.Hour), time.Time{}, ""},
+		{"expired after revival", &PreviewExpiry{IdleTimeout: day}, revived, pushed.Add(120 * time.Hour), pushed.Add(96 * time.Hour), ExpiryActionScaleDown},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			expiry := tc.expiry.Check(tc.release, tc.now)
+			if tc.action == "" {
+				if expiry != nil {
+					t.Errorf("Expected release not to be expired, got %+v", expiry)
+				}
+				return
+			}
+
+			if expiry == nil {
+				t.Fatal("Expected release to be expired")
+			}
+
+			if !expiry.Time.Time.Equal(tc.at) {
+				t.Errorf("Expected release to expire at %s, got %s", tc.at, expiry.Time)
+			}
+
+			if expiry.Action != tc.action {
+				t.Errorf("Expected action '%s', got '%s'", tc.action, expiry.Action)
+			}
+
+			if expiry.Reason == "" {
+				t.Error("Expected a reason to be set")
+			}
+		})
+	}
+}
@@ -0,0 +1,70 @@
+package main
+
+import (
+	"log"
+	"os"
+	"time"
+
+	"github.com/jelmersnoeck/kubekit"
+	flags "github.com/jessevdk/go-flags"
+	"github.com/manifoldco/heighliner/internal/activator"
+	"github.com/spf13/cobra"
+)
+
+var (
+	activatorCmd = &cobra.Command{
+		Use:   "activator",
+		Short: "Run the Activator which scales idle preview releases up on demand",
+		RunE:  activatorCommand,
+	}
+
+	activatorFlags struct {
+		Namespace     string `long:"namespace" env:"NAMESPACE" description:"The namespace we'll watch for activator Services. By default we'll watch all namespaces."`
+		Address       string `long:"address" env:"ADDRESS" description:"The address to serve traffic on" default:":8080"`
+		ReadyTimeout  string `long:"ready-timeout" env:"READY_TIMEOUT" description:"How long a request is held while waiting for a release to become available" default:"2m"`
+		RefreshPeriod string `long:"refresh-period" env:"REFRESH_PERIOD" description:"How often routes are refreshed and idle releases are scaled down" default:"30s"`
+	}
+)
+
+func activatorCommand(cmd *cobra.Command, args []string) error {
+	if _, err := flags.ParseArgs(&activatorFlags, append(args, os.Args...)); err != nil {
+		log.Printf("Could not parse flags: %s", err)
+		return err
+	}
+
+	_, cs, _, err := kubekit.InClusterClientsets()
+	if err != nil {
+		log.Printf("Could not get Clientset: %s\n", err)
+		return err
+	}
+
+	readyTimeout, err := time.ParseDuration(activatorFlags.ReadyTimeout)
+	if err != nil {
+		log.Printf("Could not parse Ready Timeout duration %s: %s\n", activatorFlags.ReadyTimeout, err)
+		return err
+	}
+
+	refreshPeriod, err := time.ParseDuration(activatorFlags.RefreshPeriod)
+	if err != nil {
+		log.Printf("Could not parse Refresh Period duration %s: %s\n", activatorFlags.RefreshPeriod, err)
+		return err
+	}
+
+	cfg := activator.Config{
+		Address:       activatorFlags.Address,
+		Namespace:     activatorFlags.Namespace,
+		ReadyTimeout:  readyTimeout,
+		RefreshPeriod: refreshPeriod,
+	}
+
+	if err := activator.New(cs, cfg).Run(); err != nil {
+		log.Printf("Error running activator: %s\n", err)
+		return err
+	}
+
+	return nil
+}
+
+func init() {
+	rootCmd.AddCommand(activatorCmd)
+}
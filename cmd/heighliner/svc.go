@@ -4,6 +4,7 @@ import (
 	"log"
 	"os"
 
+	"github.com/manifoldco/heighliner/internal/k8sutils"
 	"github.com/manifoldco/heighliner/internal/svc"
 
 	"github.com/jelmersnoeck/kubekit"
@@ -41,6 +42,11 @@ func svcCommand(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
+	if err := k8sutils.SetPrinterColumns(acs, svc.CustomResource.FullName(), svc.PrinterColumns); err != nil {
+		log.Printf("Could not set printer columns for Microservice CRD: %s\n", err)
+		return err
+	}
+
 	if err := kubekit.CreateCRD(acs, svc.AvailabilityPolicyResource); err != nil {
 		log.Printf("Could not create AvailabilityPolicy CRD: %s\n", err)
 		return err
@@ -51,6 +57,11 @@ func svcCommand(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
+	if err := kubekit.CreateCRD(acs, svc.ResourcePolicyResource); err != nil {
+		log.Printf("Could not create ResourcePolicy CRD: %s\n", err)
+		return err
+	}
+
 	if err := kubekit.CreateCRD(acs, svc.SecurityPolicyResource); err != nil {
 		log.Printf("Could not create SecurityPolicy CRD: %s\n", err)
 		return err
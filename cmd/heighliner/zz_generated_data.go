@@ -1,6 +1,7 @@
 // Code generated by go-bindata.
 // sources:
 // docs/kube/00-heighliner-namespace.yaml
+// docs/kube/activator.yaml
 // docs/kube/config-policy.yaml
 // docs/kube/github-policy.yaml
 // docs/kube/image-policy.yaml
@@ -94,6 +95,26 @@ func docsKube00HeighlinerNamespaceYaml() (*asset, error) {
 	return a, nil
 }
 
+var _docsKubeActivatorYaml = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\xc5\x55\x4d\x6f\xdb\x30\x0c\xbd\xfb\x57\x08\x39\xcf\xf9\xc0\x30\x20\xf0\x6d\x6b\x81\x62\x87\x15\x41\x0b\xec\x32\x0c\x03\x6d\xb3\xb6\x56\xd9\xd2\x24\xca\x58\x5a\xf4\xbf\x8f\x72\x92\xc5\x56\xdc\xac\x43\x0f\xf5\xc9\x7e\x8f\xe2\xc7\x23\x29\x83\x91\x5f\xd1\x3a\xa9\xdb\x4c\xd8\x1c\x8a\x39\x78\xaa\xb5\x95\x0f\x40\x8c\xcd\xef\xd7\x6e\x2e\xf5\xa2\x5b\xe5\x48\xb0\x4a\xee\x65\x5b\x66\xe2\x42\x79\x47\x68\x6f\xb4\xc2\xa4\x61\xbc\x04\x82\x2c\x11\xa2\x85\x06\x33\x51\xa3\xac\x6a\x25\x5b\xb4\x19\x14\x24\x3b\x20\x6d\x13\xeb\x15\xba\x60\x93\x0a\x30\xf2\xca\x6a\x6f\x5c\x26\xbe\xcd\x66\xdf\x19\x13\xc2\xa2\xd3\xde\x16\xd8\x63\x0e\x6d\x27\xf9\x7d\xcf\x75\x68\xf3\x1e\xaf\x90\x66\xef\xc4\x4c\x49\x47\x3d\x15\xb9\xc2\xdf\x84\x6d\x28\xc4\x4d\x39\x2d\xd1\x28\xbd\x6d\xb0\xa5\xe7\xfc\x7a\xc3\x75\x20\x93\x49\x9a\xa6\x49\x02\xaf\x13\xe6\x13\x03\xb2\xad\x5e\xac\x0f\x1f\xb9\xc1\xbb\x60\x75\x28\xea\x4c\x58\xb6\x3a\xed\xc4\x59\xff\xce\xe7\x3f\xb1\xa0\x7d\x0b\x62\xc3\xf4\x68\x18\x94\x09\xb4\x33\x50\x04\x1b\xd5\xda\xd4\x6d\x39\x48\xd3\x53\xbb\xb0\xb7\xbb\x0e\x7d\x2c\x0a\xed\x5b\x9a\x10\xac\x3b\x28\x12\x59\x9e\x53\x63\x94\xc4\x33\x29\x9c\x46\x3a\x76\x3d\xea\xc5\xe5\xdf\x86\x4f\x44\x7d\x41\x28\x67\xb0\x08\x07\x2c\xfb\x91\x05\xf0\xa4\xac\xf8\x8b\x19\xa3\x78\x4c\xb2\x5e\x8d\xa1\xe3\xf0\x28\xc8\x51\xb9\xc3\x57\x68\xa5\x19\xc7\x12\xe2\xe0\xb6\x7f\x1f\x89\x73\x7d\xbe\x29\x42\x14\xba\x25\x08\xcc\x20\x42\x3a\x51\xd0\xe1\x91\x0d\x54\x81\xb2\xb2\x62\x66\x31\x98\x8b\xc7\xc7\xf9\x5e\xc0\xa7\xa7\xf8\xc0\xc6\x2b\xb5\xd1\x5c\xf2\x36\x13\x9f\xef\xae\x35\x6d\x78\x8f\x82\x8a\x47\x3b\xb0\xd5\x20\x85\x7e\x11\x27\xc2\x1f\xd7\x6f\x00\x06\xf8\x97\x47\x47\x11\xca\xd5\x19\xcf\x12\x2f\x97\x4d\x84\x37\xd8\x68\xbb\x0d\xd4\x17\x39\xf2\x0e\xbc\x5f\xe8\xdc\xc6\xea\x1c\xc7\xce\x6a\x22\x73\x85\x14\x47\x30\x40\x75\x26\x16\x3f\x6a\x04\x45\xf5\x43\xcc\x6a\x4b\x99\x58\x2f\xd7\xcb\x11\x21\x5b\x49\x12\xd4\x25\x2a\xd8\xde\x22\xb7\xa0\xe4\x49\x78\x3f\x32\x31\x68\xa5\x2e\x27\x49\x25\x3b\x7c\xab\x24\x3f\x9c\x4f\xf2\xdf\x4b\x3b\xda\x9b\xe1\x68\xef\xe7\xf6\x74\x8d\xfe\x6b\xb1\x42\x2d\xd1\x7d\x04\x25\xff\x25\x5c\x32\x51\x29\xf1\xcc\x21\x6d\x86\xa0\x43\xc5\x37\x9a\xb6\xbb\x9c\xa2\x55\xfb\x03\xe9\x35\x1d\x2b\xd7\x06\x00\x00")
+
+func docsKubeActivatorYamlBytes() ([]byte, error) {
+	return bindataRead(
+		_docsKubeActivatorYaml,
+		"docs/kube/activator.yaml",
+	)
+}
+
+func docsKubeActivatorYaml() (*asset, error) {
+	bytes, err := docsKubeActivatorYamlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "docs/kube/activator.yaml", size: 1751, mode: os.FileMode(420), modTime: time.Unix(1792392513, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
 var _docsKubeConfigPolicyYaml = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\xa4\x53\xcd\x6e\xdb\x3c\x10\xbc\xf3\x29\x16\x3e\x7e\xf8\xe4\xc4\xb7\x82\xb7\xfe\x00\x45\x0f\x0d\x8c\x14\xe8\xa5\xe8\x61\x4d\xaf\xe5\x6d\xf8\xd7\x25\xe9\xd6\x0d\xf2\xee\x05\x55\x09\x56\x64\xc5\x46\x51\x9e\xc4\xdd\xe1\xcc\x72\x86\xc2\xc8\x9f\x49\x12\x07\xaf\x41\x36\x68\x96\x58\xf2\x3e\x08\xff\xc2\xcc\xc1\x2f\x1f\x5e\xa5\x25\x87\x9b\xc3\x6a\x43\x19\x57\xea\x81\xfd\x56\xc3\x5b\x5b\x52\x26\xb9\x0f\x96\x94\xa3\x8c\x5b\xcc\xa8\x15\x80\x47\x47\x1a\xf6\xc4\xed\xde\xb2\x27\xd1\x26\xf8\x1d\xb7\x31\x58\x36\x47\x25\xc5\x52\xaa\xb0\x06\x30\xf2\x7b\x09\x25\x26\x0d\x5f\x16\x7b\xeb\x65\xc9\x61\xf1\x55\x01\x00\x08\xa5\x50\xc4\x50\xd7\x1a\x9d\x67\x4a\x3d\xe2\x40\xb2\xe9\xba\xff\x75\x85\x09\xdb\x61\x35\x47\x94\xc8\x08\xe5\xb4\xf8\x1f\x7a\x4e\x87\xf1\x8c\xaf\xa5\x3c\xc7\x88\x91\xe9\x67\x26\x5f\x3d\x4a\xbd\x21\xb3\xc3\x96\x94\x83\x1b\x4a\x5b\xda\xb1\xe7\xea\xe1\xec\xdc\xaa\x69\x1a\xa5\xfe\xd1\xfc\x37\xec\xb7\xec\xdb\xbf\xc9\x20\x58\xba\xa7\x5d\x05\x0e\x77\xbc\xa0\xac\x00\xce\x03\xbf\x26\x91\xca\xe6\x1b\x99\xdc\x27\x3d\xc5\x36\xcf\xb0\xd5\x96\x8a\x48\x11\x4d\x85\x59\x2f\x4d\x3a\xa6\x4c\xae\x6b\xfd\x11\xff\x44\x72\x60\x43\xaf\x8d\x09\xc5\xe7\x19\xe7\x0e\x83\x35\x13\xe4\x25\x5b\xa6\x73\xbc\x30\xc5\xb9\xd8\xe9\x29\x4c\x72\x79\x47\xd1\x86\xa3\xa3\x59\xe1\xb1\x5a\x95\xce\x12\xac\x25\x79\x59\x38\x45\x32\xf5\xb8\x50\xb4\x6c\x30\x69\x58\x29\x80\x4c\x2e\x5a\xcc\xa4\x3b\x7b\xc6\x32\x75\x59\xdc\x90\x4d\xc3\xae\x26\x1c\x2f\x29\x03\x0c\x22\xdd\xf7\x33\xef\xee\xae\xc6\x06\x95\x39\x63\x6d\x8e\x24\x9b\xab\xf7\x1d\x16\x3b\x6c\x49\x03\x0a\xb7\x98\xc3\xcd\xe8\x35\x3d\x3e\x2e\x7b\xb7\x9f\x9e\xa6\x07\xd6\xc5\xda\x75\xc7\xaa\xe1\xc3\xee\x2e\xe4\xb5\x50\xaa\x96\x9f\x70\x28\xed\x68\xa0\x3a\x92\x89\x3f\x46\xfb\xd3\x3f\x3b\x2a\xd6\xf2\xf7\x42\x29\x4f\xaa\x00\x26\x16\x0d\xab\xdb\x5b\x37\xa9\x3b\x72\x41\x8e\xb5\xf5\x91\xd5\xef\x00\x00\x00\xff\xff\xdc\x56\x9b\xec\x45\x05\x00\x00")
 
 func docsKubeConfigPolicyYamlBytes() ([]byte, error) {
@@ -114,7 +135,7 @@ func docsKubeConfigPolicyYaml() (*asset, error) {
 	return a, nil
 }
 
-var _docsKubeGithubPolicyYaml = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\xc4\x56\x4d\x6f\xdb\x46\x10\xbd\xf3\x57\x0c\x78\x09\x50\x94\xb4\x8c\xa0\x80\xc1\x5b\x12\x03\xae\x0f\x51\x85\x18\xe8\xc5\x08\x8a\xd1\x72\x4c\x6e\xbd\xdc\x65\x67\x87\x4a\x14\xc1\xff\xbd\x58\x7e\x48\xa4\xac\x2f\x23\x05\xaa\x8b\xcd\xdd\x99\x79\x6f\x67\x67\xde\x2c\xd6\xfa\x4f\x62\xaf\x9d\xcd\x80\x97\xa8\x52\x6c\xa4\x74\xac\x7f\xa0\x68\x67\xd3\xe7\x1b\x9f\x6a\x77\xb5\xba\x5e\x92\xe0\x75\xf4\xac\x6d\x9e\xc1\x27\xd3\x78\x21\xfe\xe2\x0c\x45\x15\x09\xe6\x28\x98\x45\x00\x16\x2b\xca\xa0\x24\x5d\x94\x46\x5b\xe2\xac\xd0\x52\x36\xcb\x84\xa9\x76\x5e\x8b\xe3\x75\xc4\x8d\x21\x1f\x6c\x13\xc0\x5a\xdf\xb1\x6b\x6a\x9f\xc1\x63\x5c\x1a\xcb\xa9\x76\xf1\xd7\x08\x00\x80\xc9\xbb\x86\x55\x67\x19\x6c\xe3\x2e\xd2\x36\x90\x26\x1f\xb7\x5b\x2b\xe2\x65\x1b\xe0\x97\xd6\xf5\x8d\x51\x2b\xad\xd8\x79\xe2\x95\x56\x43\xc0\x04\x62\x4b\xf2\xcd\xf1\x73\xed\x8c\x56\x7a\xb4\xae\x2b\x2c\x68\xba\xba\x85\x2f\x48\xe2\x5f\x21\x36\xda\xb7\x7f\xbf\xa1\xa8\xf2\x10\x23\xac\x35\x7d\x17\xb2\x21\xe1\xbe\xcf\xee\x2b\x7a\xf0\x18\xab\xc6\x8b\xab\x86\xa5\x9c\x9e\xb4\xd5\xe1\x42\x7c\x6f\x7c\xea\xdc\xf0\x18\x1f\x3d\xb1\x27\xc5\x24\xa7\xd8\x7f\x8d\xa2\x24\x49\xa2\xe8\x27\x0b\xe3\xa3\xb6\xb9\xb6\xc5\x9b\xeb\xc3\x19\xfa\x42\x4f\xc1\x7a\x38\xd1\x09\xf8\x08\xe0\x75\x45\x5e\x84\xe3\x9b\xe5\xdf\xa4\xa4\x2f\xc5\x7d\x87\xe4\xb5\x43\xc8\x57\x30\xf3\x35\xaa\x60\x6b\x2c\x27\x7e\xed\x85\xaa\x76\xab\xa3\xf1\xd0\x95\xd2\x07\xa5\x5c\x63\xe5\x40\x22\x57\x43\xa6\xf6\x2c\x4f\x65\xe9\x20\x99\x23\x54\x5e\x23\xee\xaa\x6d\xef\xae\x6e\xa9\x36\x6e\x5d\xd1\x41\xf4\x57\x90\x89\x72\x56\xd8\x19\x43\x7c\x1c\xdd\xd7\xa4\x42\x0c\xa6\xda\x68\x85\x3e\x83\xeb\x08\x40\xa8\xaa\x0d\x0a\x75\x35\x38\xc6\x0a\x3f\x83\x4b\x32\x7e\xf8\x0a\xb7\x5e\x9f\x85\x07\x18\x90\xda\xff\x27\xa9\x9c\x5f\x76\x95\x00\x21\x22\x06\x8b\x11\x78\x72\xd9\xf1\x87\x5f\x2b\x08\x19\x20\xeb\x02\xc5\x5d\x8d\x0a\x6e\xb3\x49\xfb\x1b\x78\x79\xd9\x77\x58\x34\xc6\x2c\x82\x8a\xac\x33\x98\xd3\x6a\x12\x11\xb9\x18\xd1\x09\x84\x2e\xa4\x42\x76\x35\xf6\xdb\x1d\xe5\xf6\x8f\xcf\x1f\xee\xe7\x93\x2d\x80\x15\x9a\x86\x32\xd8\x6c\xd2\x3b\x2d\xbf\x37\xcb\x4f\x68\xcc\x12\xd5\xf3\xad\xab\x50\x4f\x29\xef\x49\xc8\x6e\xf9\x9f\x86\xbc\xec\xad\x02\xa8\xba\xc9\xe0\x7a\x36\xab\xf6\xd6\x2b\xaa\x1c\xaf\xc3\xd6\x67\x3d\x89\x8e\xb9\xb6\xe4\xfd\x82\xdd\x92\xa6\xc1\x4a\x91\xfa\x8e\x64\x1f\xa1\x46\x29\x33\xb8\xfa\xab\x24\x34\x52\xfe\xd8\xdf\x75\x2c\x19\xdc\xcc\x6e\x66\x93\x8d\x56\x3d\xd1\xdc\x92\xc1\xf5\x03\x29\x67\x73\x9f\xc1\xfb\x89\x49\x4d\xac\x5d\x7e\x70\xd3\xe8\x15\xfd\x5f\x24\x7f\x3b\x4d\xf2\xbc\xc4\x4c\xba\x7b\xdc\x6d\x7d\xdf\x5c\xd4\xec\x3f\xab\x08\xb2\xae\x29\x83\xb9\xcb\x69\xe1\x58\xa2\x2e\x05\x7b\xe2\x8b\xb9\x21\xef\xa3\x71\x82\xda\x0f\x41\x2e\x48\x16\xe3\x9c\x79\x32\xa4\xc4\x71\x77\x90\xf3\x92\xe1\x05\xa5\x69\xe1\x8c\xc3\xfc\x23\x1a\xb4\x8a\x38\x83\xcd\xcb\x81\x04\x2a\x62\xa9\xd0\x62\x41\xbc\x9b\x72\x68\xea\x72\x37\xe6\x88\x45\x3f\x69\x85\x72\xe8\xfd\xd3\x33\x51\x7d\x4f\x25\x62\xfc\xf9\x04\x75\x73\x79\x7e\x22\x80\xf6\xbe\x21\xee\x67\xe3\x80\x65\x48\x3c\x59\xc5\xeb\x5a\x92\x9a\x5d\x3e\x9a\x45\xfd\x48\xbc\x6f\xdd\xa2\xa0\x77\x55\xe5\x6c\x87\xf0\xee\x68\xe7\xbf\x0b\x83\x57\x55\x7d\x95\x2b\x67\x9f\x74\x31\x3c\x1e\x72\xeb\x67\xd7\xbb\x4a\xaf\xd9\xad\x74\xde\xa6\x71\x93\xde\xce\x1f\x16\xfd\xf7\x56\x3e\xf2\x36\xe6\x56\x22\x92\x93\xb0\x6f\x98\x5c\xf7\xb6\xe0\x50\x29\xff\xd5\xd8\x02\x40\x6b\x9d\xb4\x0f\x8b\x9e\xed\x73\xb3\x24\xb6\x24\xd4\x5e\xbf\xee\x00\x53\x65\xd0\xfb\x0c\x62\x5b\x68\xfb\xbd\x7b\x42\x05\x92\x6c\xd1\x24\xb9\xf5\x69\x5b\x24\xe9\xd4\xb7\x74\x5e\x3a\x72\x47\x0f\x9f\x5e\x16\x49\xc4\x64\x10\xbf\x9f\xcd\xe2\x5d\x5b\x99\xbe\x87\x02\xca\xf6\x91\x77\x5a\xd6\xcf\x57\xda\xe8\x8d\x1e\xe2\x9e\x9b\x13\x41\x02\x87\x3b\x0e\xba\x37\xba\xf0\x5e\x06\xb7\x25\x13\x7c\xc9\xe6\x63\xb5\xec\x55\x68\xfe\x96\xb9\xdb\xfb\x0c\x92\x10\xfd\x1b\x00\x00\xff\xff\x68\xc2\xba\xe2\xc4\x0c\x00\x00")
+var _docsKubeGithubPolicyYaml = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\xc5\x57\x4b\x6f\xdb\x38\x10\xbe\xeb\x57\x10\xbe\x14\x58\x54\x8a\x83\x62\x81\x40\xb7\xb6\x06\xb2\x39\xd4\x6b\x34\x40\x2f\x45\xb1\xa0\xa9\x89\xc4\x35\x45\x72\xc9\x91\x5b\x37\xc8\x7f\xef\x50\x0f\x5b\x92\xdf\xdb\x02\xf5\x25\x22\x67\x38\xdf\x70\x1e\xdf\x30\xdc\xca\x4f\xe0\xbc\x34\x3a\x65\x6e\xc9\x45\xc2\x2b\x2c\x8c\x93\xdf\x39\xd2\x5e\xb2\xba\xf3\x89\x34\x37\xeb\xdb\x25\x20\xbf\x8d\x56\x52\x67\x29\x7b\xaf\x2a\x8f\xe0\x3e\x1a\x05\x51\x49\xfb\x19\x47\x9e\x46\x8c\x69\x5e\x42\xca\x0a\x90\x79\xa1\xa4\x06\x97\xe6\x12\x8b\x6a\x19\x3b\xb0\xc6\x4b\x34\x6e\x13\xb9\x4a\x81\x0f\xba\x31\xe3\x56\xde\x3b\x53\x59\x9f\xb2\xcf\x93\x42\x69\x47\x40\x93\x2f\x24\x62\xcc\x81\x37\x95\x13\x8d\x66\xd0\x9d\x34\x96\xb6\x86\x24\xf8\x49\x2d\x5a\x83\x5b\xd6\x06\xfe\xa8\x8f\x5e\x69\xb5\x94\xc2\x19\x0f\x6e\x2d\x45\x67\x90\x76\x65\xc9\x73\x02\x52\x52\xec\xc3\xe4\x80\x93\xd7\x6c\xa2\xa4\xaf\xff\x7e\xe5\x28\x8a\xff\x83\xac\x01\xbf\x1a\xb7\xba\x06\x85\x3e\x2a\x4b\xa1\x86\xf0\x65\x8f\x01\xd3\x02\xbe\x21\xe8\x90\x51\xdf\xa6\x6f\xcf\x0b\xd2\x13\x94\x42\x53\x76\x5b\x19\x3c\x49\x2d\x43\xc6\x7d\xab\x7c\x2a\xb0\xb4\x7b\xf4\x62\x1e\x84\x03\x3c\x75\xa1\x2f\x51\x14\xc7\x71\x14\xf1\x9f\xab\xbc\x77\xb4\x21\x75\x7e\x75\x01\xd2\xd1\x8f\xf0\x14\xb4\xbb\x1b\x9d\x80\x27\xad\xfd\x92\xbf\x08\xc7\x57\xcb\x7f\x41\x60\x5b\xeb\xe3\x03\xf1\xfe\x81\x10\xaf\xa0\xe6\x2d\x17\x41\x97\x0a\x28\xf6\x1b\x02\x2d\x6b\x51\xe3\xc6\x63\x53\xab\x6f\x85\x30\x95\xc6\x03\x81\x5c\x77\x91\x1a\x69\x9e\x8a\xd2\x41\x67\x8e\xb8\xb2\x8f\xb8\xab\xb6\x51\xae\x66\x60\x95\xd9\x94\x70\x10\x7d\x0f\x32\x16\x46\x23\x25\x47\x81\x3b\x8e\xee\x2d\x88\x60\x83\x8e\x51\xdf\x70\x2a\xad\x5b\x5a\x91\xc4\x2a\xea\x8b\xa6\x06\xfb\x58\xe1\xa7\xf8\x12\x94\xef\x56\x21\xeb\xf6\x2c\x3c\x63\x1d\x52\xfd\x3d\x08\xe5\xfc\xb2\x54\x32\x16\x2c\xf2\xa0\xd1\x03\x8f\x2f\xbb\x7e\xf7\xab\x99\x28\x65\xdc\xc9\x9c\xa3\xb9\xe9\x15\xdc\xf3\x73\xd2\x66\xe0\xe5\x65\x7c\x60\x51\x29\xb5\x08\xc4\xb2\x49\xd9\x1c\xd6\x03\x8b\xdc\xe5\x3d\x77\x82\x43\x17\xba\x02\x7a\xdd\x3f\xb7\xbb\xca\xec\xef\x0f\x6f\x1f\xe6\x03\x11\x35\x3e\x57\x15\xc9\xc8\xcb\x7b\x89\x7f\x55\xcb\xf7\x5c\x29\xea\xb1\xd5\xcc\x94\x14\x92\x81\xcb\x23\x0a\xd9\x6d\xff\x57\x81\xc7\xd1\x2e\x45\xd5\x56\x94\xf5\xe9\xb4\x1c\xed\x97\x50\x92\xef\x41\xf4\x41\x0e\xac\x73\x62\x09\xf0\x7e\xe1\xcc\x12\x86\xc6\x0a\x44\x7b\x0f\x38\x46\x20\x6a\x2d\x52\x76\xf3\x4f\x01\x5c\x61\xf1\x7d\x2c\x35\x0e\x53\x76\x37\xbd\x9b\x0e\x04\x35\x7b\x72\x35\x03\xc5\x37\x8f\x40\x11\xcc\xa8\x38\xdf\x0c\x54\x2c\x38\x69\xb2\x83\x42\x25\xd7\xf0\xbb\x9c\xfc\xf3\xb4\x93\xe7\x29\x66\xd0\xdd\xfd\x6e\x6b\xfb\xe6\xa2\x66\xff\x59\x46\xc0\x8d\x25\xc9\xdc\x64\xb0\xa0\xbb\x47\x4d\x08\x46\xe4\xcb\x33\x7a\x7b\xf8\xa8\x1f\xa0\x7a\x81\xd4\x14\x80\x8b\x7e\xcc\x3c\x28\x22\x6f\xe3\x9a\x8b\x9c\xa7\x0c\x8f\x1c\xab\x1a\x4e\x19\x9e\xbd\xe3\x8a\x6b\x41\x5d\xca\x9e\x5f\x0e\x04\x90\x24\x58\x72\x4d\x9d\xea\x76\x53\x8e\x2b\x5b\xec\xc6\x1c\x69\xc8\x27\x62\x38\x84\xe3\xdc\x29\xda\x9e\x8a\x51\xf9\xf3\x01\x6a\xe6\xf2\xfc\x84\x01\xe9\x7d\x45\x13\xae\x99\x8d\x1d\x96\xa2\x59\x0e\x5a\xb8\x8d\xc5\xd8\x3a\x93\xf5\x66\x51\x3b\x12\x1f\xea\x63\x51\xe0\xbb\xb2\x34\xba\x41\x78\x75\xb4\xf3\x5f\x85\xc1\x2b\xca\xb6\xca\x29\x84\x4f\x32\xef\x1e\x0f\x99\xf6\xd3\xdb\x5d\xa5\x13\xde\x5a\x66\x75\x18\x9f\x93\xd9\xfc\x71\xd1\xae\xb7\xf4\x91\xd5\x36\xb7\x14\x11\x9f\x84\xbd\x62\x72\x3d\xe8\xdc\x85\x4a\xf9\x55\x63\x8b\x6e\xac\xb5\xc1\xfa\x61\xd1\x7a\xbb\xaa\x96\xe0\xe8\x11\x08\x75\xfa\x65\x03\x98\x08\xc5\x3d\x75\xdd\x44\xe7\x52\x7f\x6b\x9e\x50\xc1\x49\xa7\xb9\x8a\x29\x38\x49\x5d\x24\xc9\xf0\x6c\x61\x3c\x36\xce\x1d\xbd\x7c\x72\x99\x25\x44\x45\xd8\x6f\xa6\xd3\xc9\xae\xad\x54\xdb\x43\x01\x65\xfb\xc8\x3b\x4d\xeb\xe7\x2b\xad\xf7\x4f\x40\xb0\x7b\x6e\x4e\x04\x0a\xec\x72\x1c\x78\xaf\x97\xf0\x96\x06\xb7\x25\x13\xce\x02\xa5\xb0\xc7\x69\x2d\x0b\xcd\xaf\x99\xbb\xed\x99\x8e\x12\xa2\x1f\x60\x0a\x66\xef\x25\x0d\x00\x00")
 
 func docsKubeGithubPolicyYamlBytes() ([]byte, error) {
 	return bindataRead(
@@ -129,7 +150,7 @@ func docsKubeGithubPolicyYaml() (*asset, error) {
 		return nil, err
 	}
 
-	info := bindataFileInfo{name: "docs/kube/github-policy.yaml", size: 3268, mode: os.FileMode(420), modTime: time.Unix(1536676566, 0)}
+	info := bindataFileInfo{name: "docs/kube/github-policy.yaml", size: 3365, mode: os.FileMode(420), modTime: time.Unix(1792399777, 0)}
 	a := &asset{bytes: bytes, info: info}
 	return a, nil
 }
@@ -154,7 +175,7 @@ func docsKubeImagePolicyYaml() (*asset, error) {
 	return a, nil
 }
 
-var _docsKubeMicroserviceYaml = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\xa4\x54\x4d\x6f\xdb\x30\x0c\xbd\xeb\x57\x10\x3e\x0e\x73\xda\xdc\x06\xdf\xf6\x01\xec\xb4\xa2\xe8\x80\x5d\x86\x1d\x68\x99\x75\xb8\xea\x6b\xa2\x64\x2c\x2b\xfa\xdf\x07\x39\x31\xea\x38\x69\xb2\x62\x3e\x49\x8f\x4f\x7a\xe4\x23\x65\x0c\xfc\x8d\xa2\xb0\x77\x0d\xc4\x16\xf5\x0a\x73\xda\xf8\xc8\x7f\x30\xb1\x77\xab\x87\x77\xb2\x62\x7f\x35\xac\x5b\x4a\xb8\x56\x0f\xec\xba\x06\x3e\x9a\x2c\x89\xe2\x9d\x37\xa4\x2c\x25\xec\x30\x61\xa3\x00\x1c\x5a\x6a\x60\x43\xdc\x6f\x0c\x3b\x8a\x8d\x65\x1d\xbd\x50\x1c\x58\x93\x8a\xd9\x90\x14\x5a\x0d\x18\xf8\x73\xf4\x39\x48\x03\xdf\xab\x8d\x71\x71\xc5\xbe\xfa\xa1\x00\x00\x22\x89\xcf\x51\xd3\x18\x9a\x9f\x97\x3d\x61\xa0\xd8\x8e\xc1\x37\x23\xf0\xef\x97\x0d\xbb\x32\xa9\x3b\x7b\x6b\x4f\xa9\x7a\x0b\x55\x0e\x1d\x26\x2a\x2b\x1d\x69\xbf\xea\xc8\xd0\x6e\x15\x30\xe9\xcd\x6b\xe4\xc7\x6d\x0d\x15\x5b\xec\x29\x78\xc3\x9a\x49\xaa\x09\xc5\x01\xd9\x60\xcb\x86\xd3\xf6\x28\xa8\xbd\xbb\xe7\xfe\x08\x16\xd2\x39\x1e\xf1\x97\x65\x18\x96\x74\x2a\x4f\x0c\x4c\xbf\x13\xb9\x62\x88\xec\x9b\x7c\xca\x33\x9d\x25\x79\x3b\x41\x1d\xdd\xb3\xe3\x32\x17\x27\x9b\xa1\xea\xba\x56\xea\x3f\x07\xea\x03\xbb\x8e\x5d\xff\x9a\xb9\xf2\x86\xee\xe8\xbe\x10\xa7\x1a\xcf\x28\x2b\x80\xe3\x21\xbe\x24\x21\xb9\xfd\x49\x3a\xed\xa7\x77\xc9\xad\x0f\xb8\xc5\x96\xc2\x90\x80\xba\xd0\x8c\x8b\xb5\x6c\x25\x91\x1d\x43\x3b\xf1\xaf\x3b\xf2\x7b\xad\x7d\x76\xe9\x84\x73\xc3\x64\xcd\x82\x79\xce\x96\x65\x1e\x2f\x64\x71\x2c\xf6\x3c\x0a\x8b\xbe\x7c\xa2\x60\xfc\xd6\xd2\x49\xe1\xb9\x5a\xad\xbd\x4b\xd1\x1b\x43\xf1\x65\x61\x09\xa4\xcb\xf1\x48\xc1\xb0\x46\x69\x60\xad\x00\x12\xd9\x60\x30\xd1\xee\x8d\xcc\x65\xca\x67\xb0\x25\x23\xd3\xae\x74\x38\x9c\x53\x06\x98\x44\xc6\xf5\x81\x77\x37\x17\xdb\x06\x50\x2e\xc3\x12\x9c\x49\xd6\x17\xeb\x9d\xbe\xf1\x75\x37\x80\x91\x7b\x4c\xfe\x6a\x36\x4d\x8f\x8f\xab\xbd\xdb\x4f\x4f\xcb\x03\xb7\xd9\x98\xdb\xf2\x8a\xb7\x0d\xdc\xd0\x70\x70\x23\xc6\x7e\x96\x49\xc9\xc5\xca\xa0\x67\xc0\xe2\x17\xf3\x0c\xff\xca\x24\x69\x81\x02\xe8\x90\x1b\x58\x5f\x5f\xdb\x05\x6e\xc9\xfa\xb8\x2d\xa1\x2f\xac\xfe\x06\x00\x00\xff\xff\x70\x81\x54\x61\x13\x06\x00\x00")
+var _docsKubeMicroserviceYaml = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\xa5\x54\x4d\x6f\xdb\x30\x0c\xbd\xfb\x57\x08\x39\x0e\x73\xda\xdc\x06\xdf\xf6\x01\xec\xb4\xa2\xe8\x80\x5d\x86\x1e\x18\x99\xb5\xb9\xe8\x6b\x92\xec\x2e\x2b\xfa\xdf\x47\x39\x71\xe3\x38\x4e\xd2\x74\xbe\x58\x7a\xa4\xde\x23\x29\x8a\xe0\xe8\x07\xfa\x40\xd6\x14\xc2\x2f\x41\xce\xa1\x89\xb5\xf5\xf4\x17\x22\x63\xf3\xd5\x87\x30\x27\x7b\xd5\x2e\x96\x18\x61\x91\xad\xc8\x94\x85\xf8\xac\x9a\x10\xd1\xdf\x59\x85\x99\x66\xbc\x84\x08\x45\x26\x84\x01\x8d\x85\xa8\x91\xaa\x5a\x91\x41\x5f\x68\x92\xde\x06\xf4\x2d\x49\xcc\x7c\xa3\x30\x24\xb7\x5c\x80\xa3\xaf\xde\x36\x2e\x14\xe2\xe7\xac\x56\xc6\xb3\xc6\xec\x9e\x4d\x42\x78\x0c\xb6\xf1\x12\x3b\xd3\xf0\x7c\xd8\x3a\xb4\xe8\x97\x9d\xf1\x5d\x07\xbc\x9e\xac\xdd\xa4\x89\xe5\x49\xd6\x0a\xe3\xec\xbd\x98\x35\x8e\x93\xc2\xb4\x92\x1e\xb7\xab\x12\x15\x6e\x56\x0e\xa2\xac\x2f\x91\xef\xb6\xb9\x98\x91\x86\x0a\x9d\x55\x24\x89\xa5\x7b\x14\x5a\x20\x05\x4b\x52\x14\xd7\x07\x46\x69\xcd\x03\x55\x07\x70\x40\xd9\xf8\x29\xff\x1a\x41\xc5\xfa\x00\xee\x63\xd9\x37\x8c\xd3\x56\x14\xe2\x85\x65\x35\x18\x1f\xad\x5f\xbd\xf0\xde\x1f\x27\xe6\xff\xe3\xb1\xca\x4d\x51\x2b\xd2\x14\x3d\x98\xea\x34\xed\x2b\xd9\x9c\x2d\xdf\x1c\x1d\xfe\x89\x68\x52\xf7\x84\x29\xe6\x12\x9d\xb2\x6b\x8d\x26\xbe\x59\x80\x37\x3b\x8d\xed\xab\x9b\x92\x92\xfc\xf2\xac\xee\xa1\x12\x1f\xc8\x50\x1c\xc4\xb5\xf7\x3a\xb2\x3c\xcf\xb3\x0c\xfe\xef\x85\x7f\x62\x80\x4c\x75\xc9\x43\xe7\x53\x77\xf8\x90\x1c\xfb\x1c\x4f\x28\xb3\xd7\xe1\x54\x39\x27\x11\x9a\xe5\x2f\x94\x71\x3b\x4e\xc6\xbe\xf9\x9e\x6f\x2a\x4b\xf2\x08\x0e\x64\x72\xe3\x56\xce\xc3\x9a\xa5\x74\x67\xda\x88\x7f\xdf\x38\x7f\x94\xd2\x36\x26\x4e\x54\xae\xed\x4b\x33\xf2\x3c\x55\x96\x71\x1c\x47\xa2\x38\x14\xdb\xb5\xc2\xe8\x5e\xbe\xbc\x74\xda\x84\xf0\x50\x2d\xe7\xb1\x11\xf9\x22\x14\xfa\xe3\xc2\xc1\xa1\x4c\xc7\x3d\xb3\x92\x04\xee\x9b\x05\xef\xd8\xe2\x14\x8f\xbc\xcd\xd0\x1a\xca\xa4\x8f\xe7\x14\xaa\xd0\xef\xd2\x0d\xbb\x53\xca\x42\xf4\x22\xdd\x7a\xaf\x76\x37\x67\xaf\x4d\x88\x44\x06\xc9\x38\x90\xcc\xcf\xe6\xdb\x7f\xdd\xb8\x2d\x04\x78\xaa\x20\xda\xab\x41\x37\x3d\x3d\xcd\xb7\xd5\x7e\x7e\x1e\x1f\xb8\x6d\x94\xba\x4d\xe3\x6c\x5d\x88\x1b\x6c\xf7\x18\xc1\x57\x83\x48\x52\x2c\x3a\xb4\x72\x00\x8c\x66\xfe\x0e\xfe\xdd\x60\x88\x23\x94\xf3\x73\x0d\x57\xfd\xfa\x5a\x8f\x70\x8d\xda\xfa\x75\x32\x7d\xa3\xec\x1f\x28\xa5\x99\xb3\xa4\x07\x00\x00")
 
 func docsKubeMicroserviceYamlBytes() ([]byte, error) {
 	return bindataRead(
@@ -169,12 +190,12 @@ func docsKubeMicroserviceYaml() (*asset, error) {
 		return nil, err
 	}
 
-	info := bindataFileInfo{name: "docs/kube/microservice.yaml", size: 1555, mode: os.FileMode(420), modTime: time.Unix(1535043176, 0)}
+	info := bindataFileInfo{name: "docs/kube/microservice.yaml", size: 1956, mode: os.FileMode(420), modTime: time.Unix(1792401103, 0)}
 	a := &asset{bytes: bytes, info: info}
 	return a, nil
 }
 
-var _docsKubeNetworkPolicyYaml = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\xa4\x53\x4d\x6f\x13\x31\x10\xbd\xfb\x57\x8c\x72\x44\x6c\xda\xdc\x90\x6f\x7c\x48\x88\x03\x55\x55\x24\x2e\x88\x83\xe3\x4c\x37\x43\xfd\xc5\x8c\x9d\x12\xaa\xfe\x77\xe4\x6d\x56\xd9\x6c\x36\x21\x08\x9f\xe2\x99\x97\xf7\x9e\xdf\xcc\x9a\x44\x5f\x91\x85\x62\xd0\xc0\x4b\x63\xe7\xa6\xe4\x75\x64\xfa\x6d\x32\xc5\x30\x7f\x78\x23\x73\x8a\x57\x9b\xc5\x12\xb3\x59\xa8\x07\x0a\x2b\x0d\xef\x5d\x91\x8c\x7c\x17\x1d\x2a\x8f\xd9\xac\x4c\x36\x5a\x01\x04\xe3\x51\xc3\x1a\xa9\x5d\x3b\x0a\xc8\x3a\x60\x7e\x8c\xfc\xd0\xa4\xe8\xc8\x6e\x15\x17\x87\x52\x81\x0d\x98\x44\x1f\x39\x96\x24\x1a\xbe\xcd\xd6\x2e\xf0\x9c\xe2\xec\xbb\x02\x00\x60\x94\x58\xd8\x62\xd7\xda\x31\x74\x04\x84\xb2\x83\x6c\x90\x97\x5d\xfb\x55\x57\xb8\x9c\xce\x93\xe5\x28\xc8\x1b\xb2\xc7\x64\x2d\xe6\xd9\x6b\x98\x39\x92\x3c\x45\x8b\xbf\x32\x86\x1a\x94\x4c\x31\x53\x68\x19\x45\x2e\xb4\x38\xc5\x70\xca\xd6\x24\x81\x49\xb4\xf7\xb3\x9b\xd2\x14\xa9\x2d\x92\xa3\xef\x4b\x2b\xbc\xa7\x40\x79\xf0\x86\x03\x15\xd5\x34\x8d\x52\xff\xb9\x11\xef\x28\xac\x28\xb4\xff\xb6\x18\xd1\xe1\x1d\xde\x57\x68\xff\xca\x33\xda\x0a\xe0\x78\x0f\xff\x2e\x22\x65\xf9\x03\x6d\xde\x2d\xe0\x18\xdd\x8c\xd0\x35\x9c\x8a\x91\x64\x6c\x05\xba\xc0\x8d\x6c\x25\xa3\xef\x5a\x2f\x06\xbe\xbc\x8c\xec\xad\xb5\xb1\x84\x3c\x91\xdf\xa6\x0f\x68\x84\x3c\x17\xce\xb1\x93\x13\x3e\x8e\xe5\xf6\x2b\x31\x9a\xcf\x07\x4c\x2e\x6e\x3d\x4e\x4a\x1f\xea\x35\x36\x86\xcc\xd1\x39\xe4\xd3\xd2\x92\xd0\x56\x02\xc6\xe4\xc8\x1a\xd1\xb0\x50\x00\x19\x7d\x72\x26\xa3\xee\x22\x1a\x0a\xd5\xe3\xcc\x12\x9d\xf4\xb7\x3a\xe9\x74\x5e\x1b\xa0\x97\xe9\x7e\x1f\x24\x78\x73\xc1\xf8\x00\x2a\x9d\xa9\xed\x81\x6c\x73\xc1\xab\xfb\x43\xde\xb4\xa8\xc1\x30\xb5\x26\xc7\xab\xc1\x6e\x3d\x3d\xcd\x77\xa9\x3f\x3f\x8f\xff\x70\x5b\x9c\xbb\xed\x58\x35\x7c\xba\xbf\x89\xf9\x96\x51\x6a\xf4\x7b\x9c\xe1\x76\x60\xa9\x33\x95\x1e\x07\xf7\xfd\x37\x3c\x28\xd6\xf2\xcf\x82\x92\x47\x55\x00\x9b\x8a\x86\xc5\xf5\xb5\x1f\xd5\x3d\xfa\xc8\xdb\xda\xfa\x4c\xea\x4f\x00\x00\x00\xff\xff\x25\xdb\xfa\x7b\xea\x05\x00\x00")
+var _docsKubeNetworkPolicyYaml = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\xa5\x54\x4d\x6f\xdb\x30\x0c\xbd\xfb\x57\x08\x39\x0e\x73\xda\xdc\x06\xdf\xf6\x01\x0c\x3b\xac\x08\x3a\x60\x97\x61\x07\x59\x61\x6c\x36\xb2\xa4\xe9\x23\x59\x56\xf4\xbf\x8f\x52\xed\xc5\x71\xec\xcc\xdd\x7c\xb2\x48\xea\x3d\xf2\x91\x22\x37\xf8\x15\xac\x43\xad\x0a\x66\x4b\x2e\x96\x3c\xf8\x5a\x5b\xfc\xc5\x3d\xd9\x96\xbb\x37\x6e\x89\xfa\x66\xbf\x2a\xc1\xf3\x55\xb6\x43\xb5\x29\xd8\x7b\x19\x9c\x07\x7b\xaf\x25\x64\x0d\xd9\x37\xdc\xf3\x22\x63\x4c\xf1\x06\x0a\x56\x03\x56\xb5\x44\x05\xb6\x50\xe0\x0f\xda\xee\x72\xa3\x25\x8a\x63\x66\x83\x04\x17\x03\x73\xc6\x0d\x7e\xb4\x3a\x18\x57\xb0\x6f\x8b\x5a\x2a\x4b\x2c\x8b\xef\xe4\x62\xcc\x82\xd3\xc1\x0a\x48\xae\x16\x21\x01\x20\xb8\x36\x64\x0f\xb6\x4c\xee\x57\xc9\x30\x1f\xae\x41\x61\xb5\x03\xbb\x47\x71\x09\x56\x81\x5f\xbc\x66\x0b\x89\xce\x8f\xc1\xc2\x4f\x0f\x2a\x0a\xe5\xc6\x90\x51\x55\x74\x76\x33\x53\x6c\xcb\xa2\x4b\xad\xc2\xd7\x21\x29\xab\x7f\x11\xa2\xe2\x1e\x0e\xfc\xb8\x9c\xc5\x56\x7b\x6f\xe8\xa6\x7f\x79\x05\xa4\x17\xea\x09\xd4\x3d\x5a\x1f\xb8\xfc\x23\x39\x55\xb2\x01\x8a\x57\x69\xbc\xd2\x40\xcc\xa2\x13\x60\x7d\xde\x70\xc5\x2b\x98\xea\x6d\x0c\xc1\x2d\x0a\x3e\xb7\x84\x31\x90\xa9\xd9\x98\x0d\x00\x6a\x63\x34\x2a\x3f\x3a\x5d\xb3\x93\x10\x16\x66\x23\x94\xdc\x8b\x7a\x0c\xe6\x41\x97\xf3\xea\xa0\xc3\x69\xb8\xaf\x8c\x88\xa0\x67\xaf\x9b\xce\xb4\x81\x2d\x2a\xf4\xbd\x07\x71\xc6\x92\xe5\x79\x9e\x65\xfc\xff\xd6\xcb\x3b\x32\xd0\x88\xbd\x6c\xcb\xd0\xbd\x7b\xd8\xc6\xd0\xae\xca\x2b\xdc\x14\x75\xb9\xd4\xfe\x4e\xe2\x42\xf9\x00\xc2\xb7\xdb\x6c\x18\x9d\x0f\xa2\xa3\x38\x31\xc6\x19\x2e\x62\x20\x2d\xa8\xdc\x1d\x89\xae\x49\xae\xe7\x04\xbe\x3c\x8f\xde\x5b\x21\x74\x50\x7e\x44\xbf\x7d\x27\xd0\x20\xf2\x9a\x38\x97\x99\x4c\xe4\x71\x49\x77\x1a\x89\x41\x7f\x3e\x80\x91\xfa\xd8\xc0\x28\xf5\x39\x5f\x2e\xb4\xf2\xd4\x10\x09\x76\x9a\xda\x19\x10\x11\xc0\x12\x2e\x3d\x5f\x9a\xa0\x15\x9d\xc8\x63\x24\xbd\xe5\x22\x49\xd4\x27\x8a\x9f\xe4\x25\x48\xd7\x9d\x62\xa7\xcd\x75\x6e\xc6\x3a\x9a\xf4\x7f\xa6\xe0\xdd\x8c\xf6\x31\x16\xe1\x78\x74\xf7\x68\xf3\x19\x55\x77\x1f\x36\xb4\xbc\x0a\xc6\x2d\xd2\x5e\xd6\x37\xbd\xd9\x7a\x7c\x5c\xb6\xaa\x3f\x3d\x0d\x2f\xac\x83\x94\xeb\x84\x5a\xb0\x4f\xdb\x3b\xed\xd7\xf4\xfe\xa2\xf4\xa7\x38\x6e\xab\x5e\x4a\x29\x29\x73\xe8\x9d\x4f\x6f\xb8\x67\x8c\xe6\x1f\x81\x56\xf1\xc0\x4a\x75\x9a\x40\x1d\xb8\xbd\x6d\x06\xf6\x06\x1a\x6d\x8f\xd1\xf5\x19\xb3\xdf\x62\x8b\x6a\xb5\x37\x08\x00\x00")
 
 func docsKubeNetworkPolicyYamlBytes() ([]byte, error) {
 	return bindataRead(
@@ -189,12 +210,12 @@ func docsKubeNetworkPolicyYaml() (*asset, error) {
 		return nil, err
 	}
 
-	info := bindataFileInfo{name: "docs/kube/network-policy.yaml", size: 1514, mode: os.FileMode(420), modTime: time.Unix(1535043176, 0)}
+	info := bindataFileInfo{name: "docs/kube/network-policy.yaml", size: 2103, mode: os.FileMode(420), modTime: time.Unix(1792397384, 0)}
 	a := &asset{bytes: bytes, info: info}
 	return a, nil
 }
 
-var _docsKubeVersionedMicroserviceYaml = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\xa4\x54\xcd\x6e\xdb\x3c\x10\xbc\xeb\x29\x16\x39\x7e\x80\x9c\xf8\xf6\x81\xb7\xfe\x00\x45\x0f\x0d\x8c\x14\xe8\xa5\xe8\x81\xa2\xd6\xf2\x36\xfc\xeb\x2e\x29\xd4\x0d\xf2\xee\x05\x65\x2b\x56\x14\x39\x70\x5b\x9e\xec\xe5\xec\xcc\x70\x76\x6d\x1d\xe9\x0b\xb2\x50\xf0\x0a\xb8\xd1\x66\xa5\x73\xda\x05\xa6\x5f\x3a\x51\xf0\xab\xfb\xff\x65\x45\xe1\xba\x5f\x37\x98\xf4\xba\xba\x27\xdf\x2a\x78\x67\xb3\x24\xe4\xbb\x60\xb1\x72\x98\x74\xab\x93\x56\x15\x80\xd7\x0e\x15\xec\x90\xba\x9d\x25\x8f\xac\xfa\x03\x33\xb6\x8e\x0c\x07\x41\xee\xc9\x60\xc5\xd9\xa2\x14\x7c\x0d\x3a\xd2\x07\x0e\x39\x8a\x82\xaf\x57\x3b\xeb\x79\x45\xe1\xea\x5b\x05\x00\xc0\x28\x21\xb3\xc1\xe1\x6a\x91\x48\x8e\xc8\x1e\xb9\x19\x50\xff\x0d\x85\x19\x2b\xfe\x4c\xe8\x4b\xb3\x2c\x11\xb7\x18\x6d\xd8\x3b\xf4\xe9\x32\xb6\x18\x2c\x99\xfd\x12\x53\x0c\x6d\x4b\xc2\x39\x96\xdc\x9a\xdc\x76\x78\x21\xa5\x8e\x74\xf2\x78\x0c\x7c\x49\xc0\x64\x49\xc1\x8d\xa5\x16\xb7\xe4\x29\x4d\xde\xf5\x4c\xa5\xaa\xeb\xba\xaa\xfe\x71\xb8\x6f\xc9\xb7\xe4\xbb\xbf\x9a\x71\xb0\x78\x87\xdb\xd2\x31\x3e\xf6\x15\x0b\x15\xc0\xcb\xcd\xba\x58\x4b\x72\xf3\x1d\x4d\x3a\xae\xd4\xbc\xa9\x5e\x6e\x2a\x89\x15\xa8\x44\x6d\x0a\xde\x7a\xae\x65\x2f\x09\xdd\x70\x75\xb0\xf3\xf9\x00\x7e\x63\x4c\xc8\x3e\x2d\x84\xda\x8f\xa9\xcd\x90\xaf\x25\x76\xd6\xd0\x19\x3b\x2f\x55\x4f\xeb\x32\x9b\xdd\xfb\xa7\x6d\x5e\x70\xf0\x24\x5b\x4f\x75\x6b\x13\x7c\xe2\x60\x2d\xf2\x79\x0b\x12\xd1\x14\x22\xc6\x68\xc9\x68\x51\xb0\xae\x00\x12\xba\x68\x75\x42\x35\x24\x36\x15\x2c\xc7\xea\x06\xad\x8c\xdf\xca\x1a\xc4\xcb\x3c\x00\x8c\x72\xc3\xe7\x67\xc1\xde\x5e\x3e\x5c\x80\xc2\xaa\x0b\x6a\xe2\xa2\xfe\x83\x30\xc6\x43\x4e\x77\xa8\x40\x33\x75\x3a\x85\xeb\xc9\x3e\x3e\x3c\xac\x8e\x43\x79\x7c\x9c\x37\x6c\xb2\xb5\x9b\xe1\xdf\x42\xc1\xc7\xed\x6d\x48\x1b\x46\x29\x93\x39\xe1\x34\x77\x13\x6b\xc5\x5c\x2f\xbd\x99\x14\x4e\xbf\xff\x49\xb1\x94\x7f\x64\x94\x34\xab\x02\x98\x98\x15\xac\x6f\x6e\xdc\xac\xee\xd0\x05\xde\x97\xab\x4f\x54\xfd\x0e\x00\x00\xff\xff\x58\x0b\xb8\x08\xf1\x05\x00\x00")
+var _docsKubeVersionedMicroserviceYaml = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\xa5\x54\xb9\x6e\xdb\x40\x10\xed\xf9\x15\x0b\x97\x01\x28\x5b\x5d\xc0\x2e\x07\x10\xa4\xb0\x21\x38\x40\x9a\x20\xc5\x70\x39\xa2\xc6\xde\x2b\x7b\x10\x51\x0c\xff\x7b\x66\x29\xd1\xa2\x69\xca\x60\x62\x56\xe4\x1c\x6f\xde\xbe\x79\x4b\x70\xf4\x1d\x7d\x20\x6b\x2a\xe1\x6b\x90\x2b\x48\x71\x67\x3d\xfd\x81\xc8\xb1\xd5\xfd\xfb\xb0\x22\x7b\xd9\xad\x6b\x8c\xb0\x2e\xee\xc9\x34\x95\xf8\xa4\x52\x88\xe8\x6f\xad\xc2\x42\x73\xbc\x81\x08\x55\x21\x84\x01\x8d\x95\xd8\x21\xb5\x3b\x45\x06\x7d\xd5\x1d\x90\xb1\xd1\x24\xbd\x0d\xe8\x3b\x92\x58\xf8\xa4\x30\xe4\xfa\x52\x80\xa3\x2f\xde\x26\x17\x2a\xf1\xe3\x62\xa7\x8c\xe7\x61\x17\x3f\x39\x25\x84\xc7\x60\x93\x97\xd8\xa7\x66\x81\xc2\xb1\x92\x93\x75\x5f\xf5\xae\x0f\x4c\x50\xf1\x77\x44\x93\x9b\xc3\x1c\x70\x83\x4e\xd9\xbd\x46\x13\x97\xa1\x39\xab\x48\xee\xe7\x90\x9c\x6d\x1a\x0a\x3e\xb9\xac\x5b\x9d\x9a\x16\x17\x42\xd6\x10\xe5\x6e\x0e\xf1\xce\xd6\xcb\x10\x78\x65\x36\x48\x60\xcd\xdb\x39\x9c\x7e\x9d\xd6\x44\x50\xcc\x71\xa8\x65\x41\x97\x61\x3b\x3a\x29\x78\xb4\xc3\xdc\x10\xc9\x96\xb0\x7a\x08\x35\xb8\x25\x43\x71\xa4\xfa\xb3\x29\x45\x59\x96\x45\x01\x6f\xb3\xde\x47\x0e\xf0\x89\xff\xcb\x81\xdc\x7e\x8b\xdb\xdc\x31\x1c\xf6\x15\x0a\x5c\xf5\xd2\xf7\x8b\x67\x85\x54\xdf\xa1\x8c\x47\xc3\x4f\x9b\xca\xf9\xa6\xac\x58\x2e\x0d\x0e\x64\xae\xe7\x9b\x51\x86\x3d\x0f\xd7\x7d\xea\x40\xe7\xdb\xa1\xf8\x83\x94\x36\x99\x38\x23\x6a\x37\xa8\x36\xa9\x7c\x4d\xb1\xb3\x84\xce\xd0\x79\x39\xf5\x64\x97\xc9\xee\x3e\x3f\xdd\xb5\x19\x06\x4f\x63\xcb\xf1\xdc\x52\xb2\x6f\x79\x5b\x6c\xd7\xf3\x14\x82\x43\x99\x81\x3c\xe3\x93\x04\x76\xd9\x9a\xbf\x38\xe3\x14\x44\xac\x7a\xc5\xc6\x03\xf3\xa3\xa0\x46\x15\x86\xaf\x6c\x03\xb7\x8c\x83\x10\xc3\xb8\xfe\xfd\x99\xb0\x37\xcb\x97\x2b\x44\x46\x85\x5c\x35\x62\x51\xfe\x83\x18\xc3\x43\x1a\x5a\x6e\x01\x4f\x2d\x44\x7b\x39\xf2\xe3\xc3\xc3\xea\xb8\x94\xc7\xc7\x69\xc3\x26\x29\xb5\xe9\xff\x65\x95\xf8\xba\xbd\xb1\x71\xc3\x57\x37\x6f\xe6\x54\x07\xbe\x1d\x51\xcb\xe4\xba\xd0\xc9\x51\xe0\x74\xff\x47\xc1\x1c\xfe\x95\x30\xc4\x49\x94\x0f\xec\x12\x6f\xe6\xea\x4a\x4f\xe2\x1a\xb5\xf5\xfb\x9c\xba\xa6\xe2\x2f\x8c\xa7\x96\xbd\x8f\x06\x00\x00")
 
 func docsKubeVersionedMicroserviceYamlBytes() ([]byte, error) {
 	return bindataRead(
@@ -209,7 +230,7 @@ func docsKubeVersionedMicroserviceYaml() (*asset, error) {
 		return nil, err
 	}
 
-	info := bindataFileInfo{name: "docs/kube/versioned-microservice.yaml", size: 1521, mode: os.FileMode(420), modTime: time.Unix(1535043176, 0)}
+	info := bindataFileInfo{name: "docs/kube/versioned-microservice.yaml", size: 1679, mode: os.FileMode(420), modTime: time.Unix(1792398530, 0)}
 	a := &asset{bytes: bytes, info: info}
 	return a, nil
 }
@@ -267,6 +288,7 @@ func AssetNames() []string {
 // _bindata is a table, holding each asset generator, mapped to its name.
 var _bindata = map[string]func() (*asset, error){
 	"docs/kube/00-heighliner-namespace.yaml": docsKube00HeighlinerNamespaceYaml,
+	"docs/kube/activator.yaml": docsKubeActivatorYaml,
 	"docs/kube/config-policy.yaml": docsKubeConfigPolicyYaml,
 	"docs/kube/github-policy.yaml": docsKubeGithubPolicyYaml,
 	"docs/kube/image-policy.yaml": docsKubeImagePolicyYaml,
@@ -318,6 +340,7 @@ var _bintree = &bintree{nil, map[string]*bintree{
 	"docs": &bintree{nil, map[string]*bintree{
 		"kube": &bintree{nil, map[string]*bintree{
 			"00-heighliner-namespace.yaml": &bintree{docsKube00HeighlinerNamespaceYaml, map[string]*bintree{}},
+			"activator.yaml": &bintree{docsKubeActivatorYaml, map[string]*bintree{}},
 			"config-policy.yaml": &bintree{docsKubeConfigPolicyYaml, map[string]*bintree{}},
 			"github-policy.yaml": &bintree{docsKubeGithubPolicyYaml, map[string]*bintree{}},
 			"image-policy.yaml": &bintree{docsKubeImagePolicyYaml, map[string]*bintree{}},
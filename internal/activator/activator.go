@@ -0,0 +1,319 @@
+package activator
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net"
+	"net/http"
+	"net/http/httputil"
+	"net/url"
+	"os"
+	"os/signal"
+	"strconv"
+	"sync"
+	"syscall"
+	"time"
+
+	"k8s.io/api/extensions/v1beta1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+// ErrNotReady is used when a Deployment didn't become available in time.
+var ErrNotReady = errors.New("deployment did not become available in time")
+
+// Config is the configuration required to start the Activator.
+type Config struct {
+	Address       string
+	Namespace     string
+	ReadyTimeout  time.Duration
+	RefreshPeriod time.Duration
+}
+
+// Activator proxies traffic to Deployments which are scaled down to zero
+// replicas when they're idle. When a request comes in for a Deployment
+// without replicas, the Deployment is scaled back up and the request is held
+// until the Deployment is available.
+type Activator struct {
+	cs  kubernetes.Interface
+	cfg Config
+
+	// pollInterval is how often we check if a Deployment became available.
+	pollInterval time.Duration
+
+	// targetURL returns the URL we proxy traffic for a route to.
+	targetURL func(Route) *url.URL
+
+	mu          sync.RWMutex
+	routes      map[string]Route
+	lastRequest map[string]time.Time
+}
+
+// New returns a new Activator.
+func New(cs kubernetes.Interface, cfg Config) *Activator {
+	return &Activator{
+		cs:           cs,
+		cfg:          cfg,
+		pollInterval: time.Second,
+		targetURL:    serviceURL,
+		routes:       map[string]Route{},
+		lastRequest:  map[string]time.Time{},
+	}
+}
+
+// Run runs the Activator until a shutdown is requested.
+func (a *Activator) Run() error {
+	log.Printf("Starting activator...")
+	ctx, cancel := context.WithCancel(context.Background())
+
+	if err := a.refreshRoutes(); err != nil {
+		log.Printf("Could not load routes: %s", err)
+	}
+
+	go a.run(ctx)
+
+	srv := &http.Server{
+		Handler: a,
+		Addr:    a.cfg.Address,
+	}
+
+	go func() {
+		log.Printf("Listening on %s", a.cfg.Address)
+		if err := srv.ListenAndServe(); err != http.ErrServerClosed {
+			log.Fatal(err)
+		}
+	}()
+
+	quit := make(chan os.Signal, 1)
+	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
+
+	<-quit
+	log.Printf("Shutdown requested...")
+	cancel()
+
+	log.Printf("Shutting down activator server...")
+	if err := srv.Shutdown(context.Background()); err != nil {
+		log.Printf("Error shutting down activator server: %s", err)
+	}
+
+	log.Printf("Shutting down...")
+	return nil
+}
+
+func (a *Activator) run(ctx context.Context) {
+	ticker := time.NewTicker(a.cfg.RefreshPeriod)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := a.refreshRoutes(); err != nil {
+				log.Printf("Could not refresh routes: %s", err)
+			}
+
+			a.scaleDownIdle(time.Now())
+		}
+	}
+}
+
+// ServeHTTP proxies the request to the Deployment linked to its host, scaling
+// it up when needed.
+func (a *Activator) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	host := r.Host
+	if h, _, err := net.SplitHostPort(host); err == nil {
+		host = h
+	}
+
+	a.mu.Lock()
+	route, ok := a.routes[host]
+	if ok {
+		a.lastRequest[route.Key()] = time.Now()
+	}
+	a.mu.Unlock()
+
+	switch {
+	case !ok && r.URL.Path == "/_healthz":
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte("OK!"))
+		return
+	case !ok:
+		http.Error(w, "404 Not Found", http.StatusNotFound)
+		return
+	}
+
+	if err := a.activate(route.Target); err != nil {
+		log.Printf("Could not activate %s: %s", route.Key(), err)
+		http.Error(w, "503 Service Unavailable", http.StatusServiceUnavailable)
+		return
+	}
+
+	httputil.NewSingleHostReverseProxy(a.targetURL(route)).ServeHTTP(w, r)
+}
+
+// activate scales the Deployment of the target up if it has no replicas and
+// waits for it to become available.
+func (a *Activator) activate(target Target) error {
+	deployments := a.cs.ExtensionsV1beta1().Deployments(target.Namespace)
+
+	dpl, err := deployments.Get(target.Deployment, metav1.GetOptions{})
+	if err != nil {
+		return err
+	}
+
+	if dpl.Status.AvailableReplicas > 0 {
+		return nil
+	}
+
+	if dpl.Spec.Replicas != nil && *dpl.Spec.Replicas == 0 {
+		replicas := scaledUpReplicas(dpl)
+		dpl.Spec.Replicas = &replicas
+
+		// when a concurrent request scaled it up already, we only need
+		// to wait for it.
+		if _, err := deployments.Update(dpl); err != nil && !kerrors.IsConflict(err) {
+			return err
+		}
+
+		log.Printf("Scaled up %s to %d replicas", target.Key(), replicas)
+	}
+
+	deadline := time.Now().Add(a.cfg.ReadyTimeout)
+	for time.Now().Before(deadline) {
+		time.Sleep(a.pollInterval)
+
+		dpl, err := deployments.Get(target.Deployment, metav1.GetOptions{})
+		if err != nil {
+			return err
+		}
+
+		if dpl.Status.AvailableReplicas > 0 {
+			return nil
+		}
+	}
+
+	return ErrNotReady
+}
+
+// scaleDownIdle scales all Deployments down to zero replicas which haven't
+// received traffic within their idle timeout.
+func (a *Activator) scaleDownIdle(now time.Time) {
+	a.mu.RLock()
+	var idle []Target
+	seen := map[string]bool{}
+	for _, route := range a.routes {
+		key := route.Key()
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+
+		if now.Sub(a.lastRequest[key]) >= route.IdleTimeout {
+			idle = append(idle, route.Target)
+		}
+	}
+	a.mu.RUnlock()
+
+	for _, target := range idle {
+		if err := a.scaleDown(target); err != nil {
+			log.Printf("Could not scale down %s: %s", target.Key(), err)
+		}
+	}
+}
+
+func (a *Activator) scaleDown(target Target) error {
+	deployments := a.cs.ExtensionsV1beta1().Deployments(target.Namespace)
+
+	dpl, err := deployments.Get(target.Deployment, metav1.GetOptions{})
+	if err != nil {
+		return err
+	}
+
+	if dpl.Spec.Replicas != nil && *dpl.Spec.Replicas == 0 {
+		return nil
+	}
+
+	replicas := int32(1)
+	if dpl.Spec.Replicas != nil {
+		replicas = *dpl.Spec.Replicas
+	}
+
+	if dpl.Annotations == nil {
+		dpl.Annotations = map[string]string{}
+	}
+	dpl.Annotations[AnnotationReplicas] = strconv.Itoa(int(replicas))
+	dpl.Spec.Replicas = func(i int32) *int32 { return &i }(0)
+
+	if _, err := deployments.Update(dpl); err != nil {
+		return err
+	}
+
+	log.Printf("Scaled down idle %s", target.Key())
+	return nil
+}
+
+// refreshRoutes loads the routes from all activator Services.
+func (a *Activator) refreshRoutes() error {
+	list, err := a.cs.CoreV1().Services(a.cfg.Namespace).List(metav1.ListOptions{
+		LabelSelector: LabelActivator + "=true",
+	})
+	if err != nil {
+		return err
+	}
+
+	routes := map[string]Route{}
+	for i := range list.Items {
+		srvRoutes, err := RoutesFromService(&list.Items[i])
+		if err != nil {
+			log.Printf("Skipping activator Service: %s", err)
+			continue
+		}
+
+		for host, route := range srvRoutes {
+			routes[host] = route
+		}
+	}
+
+	now := time.Now()
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	lastRequest := make(map[string]time.Time, len(routes))
+	for _, route := range routes {
+		key := route.Key()
+		if t, ok := a.lastRequest[key]; ok {
+			lastRequest[key] = t
+		} else {
+			// give new targets a full idle period before scaling them down.
+			lastRequest[key] = now
+		}
+	}
+
+	a.routes = routes
+	a.lastRequest = lastRequest
+
+	return nil
+}
+
+// scaledUpReplicas returns the amount of replicas a Deployment had before it
+// was scaled down, defaulting to a single replica.
+func scaledUpReplicas(dpl *v1beta1.Deployment) int32 {
+	replicas, err := strconv.Atoi(dpl.Annotations[AnnotationReplicas])
+	if err != nil || replicas < 1 {
+		return 1
+	}
+
+	return int32(replicas)
+}
+
+func serviceURL(route Route) *url.URL {
+	return &url.URL{
+		Scheme: "http",
+		Host:   fmt.Sprintf("%s.%s.svc.cluster.local:%d", route.Service, route.Namespace, route.Port),
+	}
+}
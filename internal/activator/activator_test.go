@@ -0,0 +1,188 @@
+package activator
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/api/extensions/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+	ktesting "k8s.io/client-go/testing"
+)
+
+func TestRoutesFromService(t *testing.T) {
+	srv := &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "hello-world-pr-abc-activator",
+			Namespace: "testing",
+			Annotations: map[string]string{
+				AnnotationDeployment:  "hello-world-pr-abc-def",
+				AnnotationService:     "hello-world-pr-abc",
+				AnnotationIdleTimeout: "30m0s",
+				AnnotationRoutes:      `{"pr.hlnr.io":80}`,
+			},
+		},
+	}
+
+	routes, err := RoutesFromService(srv)
+	if err != nil {
+		t.Fatalf("Expected no error, got %s", err)
+	}
+
+	expected := Route{
+		Target: Target{
+			Namespace:   "testing",
+			Deployment:  "hello-world-pr-abc-def",
+			Service:     "hello-world-pr-abc",
+			IdleTimeout: 30 * time.Minute,
+		},
+		Port: 80,
+	}
+
+	if routes["pr.hlnr.io"] != expected {
+		t.Errorf("Expected route %+v, got %+v", expected, routes["pr.hlnr.io"])
+	}
+
+	delete(srv.Annotations, AnnotationDeployment)
+	if _, err := RoutesFromService(srv); err == nil {
+		t.Error("Expected an error for a Service without target")
+	}
+}
+
+func TestActivator(t *testing.T) {
+	backend := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("hello from " + r.Host))
+	}))
+	defer backend.Close()
+
+	target := Target{
+		Namespace:   "testing",
+		Deployment:  "hello-world-pr-abc-def",
+		Service:     "hello-world-pr-abc",
+		IdleTimeout: time.Minute,
+	}
+
+	newActivator := func(replicas, available int32) (*Activator, *fake.Clientset) {
+		cs := fake.NewSimpleClientset(&v1beta1.Deployment{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        target.Deployment,
+				Namespace:   target.Namespace,
+				Annotations: map[string]string{AnnotationReplicas: "3"},
+			},
+			Spec:   v1beta1.DeploymentSpec{Replicas: &replicas},
+			Status: v1beta1.DeploymentStatus{AvailableReplicas: available},
+		})
+
+		// mimic the Deployment becoming available once it's scaled up.
+		cs.PrependReactor("update", "deployments", func(action ktesting.Action) (bool, runtime.Object, error) {
+			dpl := action.(ktesting.UpdateAction).GetObject().(*v1beta1.Deployment)
+			dpl.Status.AvailableReplicas = *dpl.Spec.Replicas
+			return false, nil, nil
+		})
+
+		a := New(cs, Config{ReadyTimeout: time.Second})
+		a.pollInterval = time.Millisecond
+		a.targetURL = func(Route) *url.URL {
+			u, _ := url.Parse(backend.URL)
+			return u
+		}
+		a.routes = map[string]Route{"pr.hlnr.io": {Target: target, Port: 80}}
+
+		return a, cs
+	}
+
+	get := func(a *Activator, host, path string) (int, string) {
+		req := httptest.NewRequest("GET", "http://"+host+path, nil)
+		rec := httptest.NewRecorder()
+		a.ServeHTTP(rec, req)
+
+		body, _ := ioutil.ReadAll(rec.Body)
+		return rec.Code, string(body)
+	}
+
+	getReplicas := func(cs *fake.Clientset) (int32, string) {
+		dpl, err := cs.ExtensionsV1beta1().Deployments(target.Namespace).Get(target.Deployment, metav1.GetOptions{})
+		if err != nil {
+			t.Fatalf("Expected no error, got %s", err)
+		}
+
+		return *dpl.Spec.Replicas, dpl.Annotations[AnnotationReplicas]
+	}
+
+	t.Run("with an unknown host", func(t *testing.T) {
+		a, _ := newActivator(1, 1)
+		if code, _ := get(a, "unknown.hlnr.io", "/"); code != http.StatusNotFound {
+			t.Errorf("Expected status %d, got %d", http.StatusNotFound, code)
+		}
+
+		if code, _ := get(a, "10.0.0.1:8080", "/_healthz"); code != http.StatusOK {
+			t.Errorf("Expected status %d, got %d", http.StatusOK, code)
+		}
+	})
+
+	t.Run("with an available release", func(t *testing.T) {
+		a, cs := newActivator(1, 1)
+
+		code, body := get(a, "pr.hlnr.io", "/")
+		if code != http.StatusOK || body != "hello from pr.hlnr.io" {
+			t.Errorf("Expected request to be proxied, got %d: %s", code, body)
+		}
+
+		if replicas, _ := getReplicas(cs); replicas != 1 {
+			t.Errorf("Expected 1 replica, got %d", replicas)
+		}
+	})
+
+	t.Run("with a release scaled to zero", func(t *testing.T) {
+		a, cs := newActivator(0, 0)
+
+		code, body := get(a, "pr.hlnr.io:443", "/")
+		if code != http.StatusOK || body != "hello from pr.hlnr.io:443" {
+			t.Errorf("Expected request to be proxied, got %d: %s", code, body)
+		}
+
+		if replicas, _ := getReplicas(cs); replicas != 3 {
+			t.Errorf("Expected release to be scaled up to 3 replicas, got %d", replicas)
+		}
+	})
+
+	t.Run("with a release that doesn't become available", func(t *testing.T) {
+		a, cs := newActivator(1, 0)
+		a.cfg.ReadyTimeout = 10 * time.Millisecond
+		cs.PrependReactor("update", "deployments", func(ktesting.Action) (bool, runtime.Object, error) {
+			return false, nil, nil
+		})
+
+		if code, _ := get(a, "pr.hlnr.io", "/"); code != http.StatusServiceUnavailable {
+			t.Errorf("Expected status %d, got %d", http.StatusServiceUnavailable, code)
+		}
+	})
+
+	t.Run("scaling down idle releases", func(t *testing.T) {
+		a, cs := newActivator(2, 2)
+		now := time.Now()
+
+		a.lastRequest[target.Key()] = now.Add(-30 * time.Second)
+		a.scaleDownIdle(now)
+		if replicas, _ := getReplicas(cs); replicas != 2 {
+			t.Errorf("Expected active release to keep 2 replicas, got %d", replicas)
+		}
+
+		a.lastRequest[target.Key()] = now.Add(-2 * time.Minute)
+		a.scaleDownIdle(now)
+		replicas, previous := getReplicas(cs)
+		if replicas != 0 {
+			t.Errorf("Expected idle release to be scaled down, got %d replicas", replicas)
+		}
+
+		if previous != "2" {
+			t.Errorf("Expected previous replicas to be stored, got '%s'", previous)
+		}
+	})
+}
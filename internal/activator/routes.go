@@ -0,0 +1,101 @@
+// Package activator serves traffic for releases which are scaled down to zero
+// replicas, scaling them back up on demand.
+package activator
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+const (
+	// LabelActivator is the label set on Services which route traffic through
+	// the activator.
+	LabelActivator = "hlnr.io/activator"
+
+	// AnnotationDeployment is the annotation on an activator Service which
+	// holds the name of the Deployment traffic is destined for.
+	AnnotationDeployment = "hlnr.io/activator.deployment"
+
+	// AnnotationService is the annotation on an activator Service which holds
+	// the name of the Service traffic should be proxied to.
+	AnnotationService = "hlnr.io/activator.service"
+
+	// AnnotationIdleTimeout is the annotation on an activator Service which
+	// holds the time without traffic after which the Deployment is scaled down
+	// to zero replicas.
+	AnnotationIdleTimeout = "hlnr.io/activator.idle-timeout"
+
+	// AnnotationRoutes is the annotation on an activator Service which holds a
+	// JSON object mapping hostnames to the port of the Service traffic for
+	// that hostname should be proxied to.
+	AnnotationRoutes = "hlnr.io/activator.routes"
+
+	// AnnotationReplicas is the annotation on a Deployment which holds the
+	// amount of replicas it had before it was scaled down to zero.
+	AnnotationReplicas = "hlnr.io/activator.replicas"
+
+	// DefaultHost is the hostname of the activator Service installed with
+	// Heighliner.
+	DefaultHost = "activator.hlnr-system.svc.cluster.local"
+
+	// Port is the port the activator serves traffic on.
+	Port = 8080
+)
+
+// Target describes the Deployment and Service traffic is destined for.
+type Target struct {
+	Namespace   string
+	Deployment  string
+	Service     string
+	IdleTimeout time.Duration
+}
+
+// Key returns a unique key for the Target.
+func (t Target) Key() string {
+	return t.Namespace + "/" + t.Deployment
+}
+
+// Route links a hostname to a Target.
+type Route struct {
+	Target
+
+	// Port is the port on the Service traffic is proxied to.
+	Port int32
+}
+
+// RoutesFromService returns the routes described by the annotations of an
+// activator Service, keyed by hostname.
+func RoutesFromService(srv *corev1.Service) (map[string]Route, error) {
+	ann := srv.Annotations
+
+	idleTimeout, err := time.ParseDuration(ann[AnnotationIdleTimeout])
+	if err != nil {
+		return nil, fmt.Errorf("invalid idle timeout for %s/%s: %s", srv.Namespace, srv.Name, err)
+	}
+
+	target := Target{
+		Namespace:   srv.Namespace,
+		Deployment:  ann[AnnotationDeployment],
+		Service:     ann[AnnotationService],
+		IdleTimeout: idleTimeout,
+	}
+
+	if target.Deployment == "" || target.Service == "" {
+		return nil, fmt.Errorf("missing target for %s/%s", srv.Namespace, srv.Name)
+	}
+
+	ports := map[string]int32{}
+	if err := json.Unmarshal([]byte(ann[AnnotationRoutes]), &ports); err != nil {
+		return nil, fmt.Errorf("invalid routes for %s/%s: %s", srv.Namespace, srv.Name, err)
+	}
+
+	routes := make(map[string]Route, len(ports))
+	for host, port := range ports {
+		routes[host] = Route{Target: target, Port: port}
+	}
+
+	return routes, nil
+}
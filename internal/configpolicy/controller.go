@@ -6,6 +6,7 @@ import (
 	"log"
 	"os"
 	"os/signal"
+	"reflect"
 	"syscall"
 
 	"github.com/manifoldco/heighliner/apis/v1alpha1"
@@ -90,36 +91,45 @@ func (c *Controller) run(ctx context.Context) {
 
 func (c *Controller) hashConfigValues(obj interface{}) error {
 	cp := obj.(*v1alpha1.ConfigPolicy).DeepCopy()
+	status := cp.Status.DeepCopy()
 
 	hashedConfig, err := c.getHashedConfig(cp)
 	if err != nil {
 		log.Printf("Error getting hashed configuration for %s: %s", cp.Name, err)
-		return err
+		cp.Status.Conditions.MarkDegraded("ConfigError", err.Error())
+	} else {
+		hashedString := fmt.Sprintf("%x", hashedConfig)
+
+		// some values of our config have changed, update the CRD status so
+		// depending resources get notified.
+		if hashedString != cp.Status.Hashed {
+			cp.Status.LastUpdatedTime = metav1.Now()
+			cp.Status.Hashed = hashedString
+		}
+
+		cp.Status.Conditions.MarkReady("Hashed", "")
 	}
-	hashedString := fmt.Sprintf("%x", hashedConfig)
+	cp.Status.ObservedGeneration = cp.Generation
 
-	// some values of our config have changed, update the CRD status so
-	// depending resources get notified.
-	if hashedString != cp.Status.Hashed {
-		cp.Status.LastUpdatedTime = metav1.Now()
-		cp.Status.Hashed = hashedString
+	if reflect.DeepEqual(status, &cp.Status) {
+		return err
+	}
 
-		cp.TypeMeta = metav1.TypeMeta{
-			Kind:       "ConfigPolicy",
-			APIVersion: "hlnr.io/v1alpha1",
-		}
-		patch, err := c.patcher.Apply(cp)
-		if err != nil {
-			log.Printf("Could not update ConfigPolicy %s: %s", cp.Name, err)
-		}
+	cp.TypeMeta = metav1.TypeMeta{
+		Kind:       "ConfigPolicy",
+		APIVersion: "hlnr.io/v1alpha1",
+	}
+	patch, applyErr := c.patcher.Apply(cp)
+	if applyErr != nil {
+		log.Printf("Could not update ConfigPolicy %s: %s", cp.Name, applyErr)
+	}
 
-		patch, err = k8sutils.CleanupPatchAnnotations(patch, "hlnr-configpolicy")
-		if err == nil && !patcher.IsEmptyPatch(patch) {
-			log.Printf("Updated ConfigPolicy %s", cp.Name)
-		}
+	patch, applyErr = k8sutils.CleanupPatchAnnotations(patch, "hlnr-configpolicy")
+	if applyErr == nil && !patcher.IsEmptyPatch(patch) {
+		log.Printf("Updated ConfigPolicy %s", cp.Name)
 	}
 
-	return nil
+	return err
 }
 
 func (c *Controller) getHashedConfig(crd *v1alpha1.ConfigPolicy) ([]byte, error) {
@@ -0,0 +1,72 @@
+package dns
+
+import (
+	"errors"
+	"net"
+	"strings"
+)
+
+// The record types a Provider manages.
+const (
+	TypeA     = "A"
+	TypeAAAA  = "AAAA"
+	TypeCNAME = "CNAME"
+)
+
+// ErrNotOwned is used when a record exists, but isn't owned by the owner
+// trying to change it.
+var ErrNotOwned = errors.New("the record is owned by someone else")
+
+// Provider represents the interface any DNS provider needs to implement to
+// manage records directly.
+type Provider interface {
+	// Apply creates or updates a record and marks it as owned by the given
+	// owner. It returns ErrNotOwned when the name is already in use by
+	// records of someone else.
+	Apply(Record, string) error
+
+	// Delete removes a record and its ownership marker. Records which aren't
+	// owned by the given owner are left untouched.
+	Delete(Record, string) error
+}
+
+// Record is a DNS record pointing a name to a target.
+type Record struct {
+	Name   string
+	Type   string
+	Target string
+	TTL    int32
+}
+
+// NewRecord returns the record which points a name to a target. IP addresses
+// result in A or AAAA records, hostnames in CNAME records.
+func NewRecord(name, target string, ttl int32) Record {
+	rec := Record{
+		Name:   strings.TrimSuffix(name, "."),
+		Type:   TypeCNAME,
+		Target: strings.TrimSuffix(target, "."),
+		TTL:    ttl,
+	}
+
+	if ip := net.ParseIP(target); ip != nil {
+		rec.Type = TypeAAAA
+		if ip.To4() != nil {
+			rec.Type = TypeA
+		}
+	}
+
+	return rec
+}
+
+// OwnerName returns the name of the TXT record which marks the owner of a
+// record. It's a name of its own, as CNAME records can't be combined with
+// other records.
+func OwnerName(name string) string {
+	return "_hlnr-owner." + name
+}
+
+// OwnerValue returns the value of the TXT record which marks a record as owned
+// by the given owner.
+func OwnerValue(owner string) string {
+	return "heritage=heighliner,hlnr.io/owner=" + owner
+}
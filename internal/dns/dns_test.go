@@ -0,0 +1,26 @@
+package dns
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestNewRecord(t *testing.T) {
+	tcs := []struct {
+		target   string
+		expected Record
+	}{
+		{"lb.hlnr.io.", Record{Name: "hlnr.io", Type: TypeCNAME, Target: "lb.hlnr.io", TTL: 300}},
+		{"10.0.0.1", Record{Name: "hlnr.io", Type: TypeA, Target: "10.0.0.1", TTL: 300}},
+		{"2001:db8::1", Record{Name: "hlnr.io", Type: TypeAAAA, Target: "2001:db8::1", TTL: 300}},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.target, func(t *testing.T) {
+			rec := NewRecord("hlnr.io.", tc.target, 300)
+			if !reflect.DeepEqual(rec, tc.expected) {
+				t.Errorf("Expected %+v, got %+v", tc.expected, rec)
+			}
+		})
+	}
+}
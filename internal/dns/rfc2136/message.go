@@ -0,0 +1,223 @@
+package rfc2136
+
+import (
+	"crypto/hmac"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/binary"
+	"fmt"
+	"hash"
+	"net"
+	"strings"
+	"time"
+)
+
+// DNS constants used in update messages, see RFC 1035 and RFC 2136.
+const (
+	opcodeUpdate = 5
+
+	classINET = 1
+	classNONE = 254
+	classANY  = 255
+
+	typeA     = 1
+	typeCNAME = 5
+	typeSOA   = 6
+	typeTXT   = 16
+	typeAAAA  = 28
+	typeTSIG  = 250
+	typeANY   = 255
+
+	rcodeSuccess  = 0
+	rcodeYXDomain = 6
+	rcodeYXRRSet  = 7
+	rcodeNXRRSet  = 8
+
+	tsigFudge = 300
+)
+
+var recordTypes = map[string]uint16{
+	"A":     typeA,
+	"AAAA":  typeAAAA,
+	"CNAME": typeCNAME,
+}
+
+var rcodeNames = map[int]string{
+	1:  "FORMERR",
+	2:  "SERVFAIL",
+	3:  "NXDOMAIN",
+	4:  "NOTIMP",
+	5:  "REFUSED",
+	6:  "YXDOMAIN",
+	7:  "YXRRSET",
+	8:  "NXRRSET",
+	9:  "NOTAUTH",
+	10: "NOTZONE",
+}
+
+// Algorithms lists the supported TSIG algorithms, by the name of their key
+// algorithm.
+var Algorithms = map[string]func() hash.Hash{
+	"hmac-sha1":   sha1.New,
+	"hmac-sha256": sha256.New,
+	"hmac-sha512": sha512.New,
+}
+
+// resource is a resource record in an update message.
+type resource struct {
+	name  string
+	typ   uint16
+	class uint16
+	ttl   uint32
+	data  []byte
+}
+
+// message is a DNS update message. The zone section takes the place of the
+// question section, prerequisites and updates take the place of the answer
+// and authority sections.
+type message struct {
+	id            uint16
+	zone          string
+	prerequisites []resource
+	updates       []resource
+}
+
+// pack encodes the message in the wire format, without name compression.
+func (m *message) pack() []byte {
+	buf := make([]byte, 12)
+	binary.BigEndian.PutUint16(buf[0:], m.id)
+	binary.BigEndian.PutUint16(buf[2:], opcodeUpdate<<11)
+	binary.BigEndian.PutUint16(buf[4:], 1)
+	binary.BigEndian.PutUint16(buf[6:], uint16(len(m.prerequisites)))
+	binary.BigEndian.PutUint16(buf[8:], uint16(len(m.updates)))
+
+	buf = appendName(buf, m.zone)
+	buf = appendUint16(buf, typeSOA)
+	buf = appendUint16(buf, classINET)
+
+	for _, rr := range m.prerequisites {
+		buf = appendResource(buf, rr)
+	}
+
+	for _, rr := range m.updates {
+		buf = appendResource(buf, rr)
+	}
+
+	return buf
+}
+
+// sign adds a TSIG record to a packed message, see RFC 8945.
+func sign(msg []byte, key *TSIG, now time.Time) ([]byte, error) {
+	newHash, ok := Algorithms[key.Algorithm]
+	if !ok {
+		return nil, fmt.Errorf("unsupported TSIG algorithm '%s'", key.Algorithm)
+	}
+
+	timeSigned := make([]byte, 6)
+	signed := uint64(now.Unix())
+	binary.BigEndian.PutUint16(timeSigned[0:], uint16(signed>>32))
+	binary.BigEndian.PutUint32(timeSigned[2:], uint32(signed))
+
+	algorithm := appendName(nil, key.Algorithm)
+
+	variables := appendName(nil, strings.ToLower(key.Name))
+	variables = appendUint16(variables, classANY)
+	variables = appendUint32(variables, 0)
+	variables = append(variables, algorithm...)
+	variables = append(variables, timeSigned...)
+	variables = appendUint16(variables, tsigFudge)
+	variables = appendUint16(variables, 0) // error
+	variables = appendUint16(variables, 0) // other length
+
+	mac := hmac.New(newHash, key.Secret)
+	mac.Write(msg)
+	mac.Write(variables)
+	sum := mac.Sum(nil)
+
+	data := append([]byte{}, algorithm...)
+	data = append(data, timeSigned...)
+	data = appendUint16(data, tsigFudge)
+	data = appendUint16(data, uint16(len(sum)))
+	data = append(data, sum...)
+	data = append(data, msg[0:2]...) // original ID
+	data = appendUint16(data, 0)     // error
+	data = appendUint16(data, 0)     // other length
+
+	signedMsg := append([]byte{}, msg...)
+	binary.BigEndian.PutUint16(signedMsg[10:], binary.BigEndian.Uint16(msg[10:])+1)
+
+	return appendResource(signedMsg, resource{
+		name:  strings.ToLower(key.Name),
+		typ:   typeTSIG,
+		class: classANY,
+		data:  data,
+	}), nil
+}
+
+// recordData encodes the data of a record.
+func recordData(typ uint16, target string) ([]byte, error) {
+	switch typ {
+	case typeA:
+		ip := net.ParseIP(target).To4()
+		if ip == nil {
+			return nil, fmt.Errorf("'%s' is not an IPv4 address", target)
+		}
+		return ip, nil
+	case typeAAAA:
+		ip := net.ParseIP(target).To16()
+		if ip == nil {
+			return nil, fmt.Errorf("'%s' is not an IPv6 address", target)
+		}
+		return ip, nil
+	case typeCNAME:
+		return appendName(nil, target), nil
+	}
+
+	return nil, fmt.Errorf("unsupported record type %d", typ)
+}
+
+// txtData encodes the data of a TXT record, splitting the value in strings of
+// at most 255 characters.
+func txtData(value string) []byte {
+	var data []byte
+	for len(value) > 255 {
+		data = append(data, 255)
+		data = append(data, value[:255]...)
+		value = value[255:]
+	}
+
+	data = append(data, byte(len(value)))
+	return append(data, value...)
+}
+
+func appendResource(buf []byte, rr resource) []byte {
+	buf = appendName(buf, rr.name)
+	buf = appendUint16(buf, rr.typ)
+	buf = appendUint16(buf, rr.class)
+	buf = appendUint32(buf, rr.ttl)
+	buf = appendUint16(buf, uint16(len(rr.data)))
+	return append(buf, rr.data...)
+}
+
+// appendName encodes a domain name as a sequence of labels.
+func appendName(buf []byte, name string) []byte {
+	for _, label := range strings.Split(strings.Trim(name, "."), ".") {
+		if label == "" {
+			continue
+		}
+
+		buf = append(buf, byte(len(label)))
+		buf = append(buf, label...)
+	}
+
+	return append(buf, 0)
+}
+
+func appendUint16(buf []byte, v uint16) []byte {
+	return append(buf, byte(v>>8), byte(v))
+}
+
+func appendUint32(buf []byte, v uint32) []byte {
+	return append(buf, byte(v>>24), byte(v>>16), byte(v>>8), byte(v))
+}
@@ -0,0 +1,210 @@
+package rfc2136
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math/rand"
+	"net"
+	"strings"
+	"time"
+
+	"github.com/manifoldco/heighliner/internal/dns"
+)
+
+const defaultTimeout = 10 * time.Second
+
+// TSIG is a shared secret which is used to sign updates.
+type TSIG struct {
+	// Name is the name of the key, as known by the DNS server.
+	Name string
+
+	// Algorithm is one of the Algorithms.
+	Algorithm string
+
+	// Secret is the decoded secret of the key.
+	Secret []byte
+}
+
+// Provider manages records through RFC2136 dynamic updates.
+type Provider struct {
+	// Server is the address of the DNS server, like `ns1.hlnr.io:53`.
+	Server string
+
+	// Zone is the zone records are updated in.
+	Zone string
+
+	// TSIG is the key updates are signed with. Updates are sent unsigned when
+	// no key is given.
+	TSIG *TSIG
+
+	// Timeout is how long to wait for a response of the server, defaults to
+	// 10 seconds.
+	Timeout time.Duration
+}
+
+// rcodeError is used when the server doesn't apply an update.
+type rcodeError int
+
+func (e rcodeError) Error() string {
+	name, ok := rcodeNames[int(e)]
+	if !ok {
+		name = fmt.Sprintf("RCODE %d", int(e))
+	}
+
+	return "the DNS server refused the update: " + name
+}
+
+// Apply creates or updates a record. Records which are already owned by the
+// given owner are replaced. Otherwise, the record is only created if the name
+// isn't in use yet, together with the TXT record which marks its owner.
+func (p *Provider) Apply(rec dns.Record, owner string) error {
+	typ, ok := recordTypes[rec.Type]
+	if !ok {
+		return fmt.Errorf("unsupported record type '%s'", rec.Type)
+	}
+
+	data, err := recordData(typ, rec.Target)
+	if err != nil {
+		return err
+	}
+
+	record := resource{name: rec.Name, typ: typ, class: classINET, ttl: uint32(rec.TTL), data: data}
+	marker := resource{name: dns.OwnerName(rec.Name), typ: typeTXT, class: classINET, data: txtData(dns.OwnerValue(owner))}
+
+	// replace the records when they're ours.
+	err = p.update(&message{
+		prerequisites: []resource{marker},
+		updates:       append(deleteRecords(rec.Name), record),
+	})
+	if err != rcodeError(rcodeNXRRSet) {
+		return err
+	}
+
+	// otherwise, claim the name when nobody else uses it.
+	ownerMarker := marker
+	ownerMarker.ttl = uint32(rec.TTL)
+
+	err = p.update(&message{
+		prerequisites: []resource{
+			{name: rec.Name, typ: typeANY, class: classNONE},
+			{name: marker.name, typ: typeANY, class: classNONE},
+		},
+		updates: []resource{ownerMarker, record},
+	})
+	if err == rcodeError(rcodeYXDomain) || err == rcodeError(rcodeYXRRSet) {
+		return dns.ErrNotOwned
+	}
+
+	return err
+}
+
+// Delete removes a record and the TXT record which marks its owner, if they're
+// owned by the given owner.
+func (p *Provider) Delete(rec dns.Record, owner string) error {
+	marker := resource{name: dns.OwnerName(rec.Name), typ: typeTXT, class: classINET, data: txtData(dns.OwnerValue(owner))}
+
+	err := p.update(&message{
+		prerequisites: []resource{marker},
+		updates: append(deleteRecords(rec.Name), resource{
+			name:  marker.name,
+			typ:   typeTXT,
+			class: classANY,
+		}),
+	})
+
+	// someone else owns the name by now, so there's nothing left for us.
+	if err == rcodeError(rcodeNXRRSet) {
+		return nil
+	}
+
+	return err
+}
+
+// deleteRecords returns the updates which delete all records a name can point
+// to a target with.
+func deleteRecords(name string) []resource {
+	var updates []resource
+	for _, typ := range []uint16{typeA, typeAAAA, typeCNAME} {
+		updates = append(updates, resource{name: name, typ: typ, class: classANY})
+	}
+
+	return updates
+}
+
+// update sends an update message to the server and waits for its response.
+func (p *Provider) update(msg *message) error {
+	msg.id = uint16(rand.Intn(1 << 16))
+	msg.zone = p.Zone
+
+	for i, rr := range msg.prerequisites {
+		msg.prerequisites[i].name = p.fqdn(rr.name)
+	}
+
+	for i, rr := range msg.updates {
+		msg.updates[i].name = p.fqdn(rr.name)
+	}
+
+	req := msg.pack()
+	if p.TSIG != nil {
+		var err error
+		if req, err = sign(req, p.TSIG, time.Now()); err != nil {
+			return err
+		}
+	}
+
+	timeout := p.Timeout
+	if timeout == 0 {
+		timeout = defaultTimeout
+	}
+
+	conn, err := net.DialTimeout("udp", p.Server, timeout)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	if err := conn.SetDeadline(time.Now().Add(timeout)); err != nil {
+		return err
+	}
+
+	if _, err := conn.Write(req); err != nil {
+		return err
+	}
+
+	resp := make([]byte, 512)
+	for {
+		n, err := conn.Read(resp)
+		if err != nil {
+			return err
+		}
+
+		if n < 12 {
+			return errors.New("the DNS server sent an invalid response")
+		}
+
+		// ignore responses to other requests. The TSIG record of the response
+		// isn't verified, only the result of the update is used.
+		if binary.BigEndian.Uint16(resp[0:]) != msg.id {
+			continue
+		}
+
+		rcode := int(binary.BigEndian.Uint16(resp[2:]) & 0xF)
+		if rcode != rcodeSuccess {
+			return rcodeError(rcode)
+		}
+
+		return nil
+	}
+}
+
+// fqdn makes sure a name is within the zone of the Provider.
+func (p *Provider) fqdn(name string) string {
+	zone := strings.Trim(p.Zone, ".")
+	name = strings.Trim(name, ".")
+	if name == zone || strings.HasSuffix(name, "."+zone) {
+		return name
+	}
+
+	return name + "." + zone
+}
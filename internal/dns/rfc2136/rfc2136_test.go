@@ -0,0 +1,243 @@
+package rfc2136
+
+import (
+	"encoding/binary"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/manifoldco/heighliner/internal/dns"
+)
+
+// request is the part of an update message the tests look at.
+type request struct {
+	prerequisites []resource
+	updates       []resource
+	signed        bool
+}
+
+// server is a DNS server which answers every update with the next rcode.
+func server(t *testing.T, rcodes ...int) (string, <-chan request) {
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Expected no error, got %s", err)
+	}
+
+	requests := make(chan request, len(rcodes))
+	go func() {
+		defer conn.Close()
+		defer close(requests)
+
+		buf := make([]byte, 4096)
+		for _, rcode := range rcodes {
+			conn.SetDeadline(time.Now().Add(5 * time.Second))
+			n, addr, err := conn.ReadFrom(buf)
+			if err != nil {
+				return
+			}
+
+			requests <- parseRequest(buf[:n])
+
+			resp := make([]byte, 12)
+			copy(resp, buf[:2])
+			binary.BigEndian.PutUint16(resp[2:], 1<<15|opcodeUpdate<<11|uint16(rcode))
+			conn.WriteTo(resp, addr)
+		}
+	}()
+
+	return conn.LocalAddr().String(), requests
+}
+
+func parseRequest(msg []byte) request {
+	counts := []int{
+		int(binary.BigEndian.Uint16(msg[4:])),
+		int(binary.BigEndian.Uint16(msg[6:])),
+		int(binary.BigEndian.Uint16(msg[8:])),
+		int(binary.BigEndian.Uint16(msg[10:])),
+	}
+
+	// skip the zone section
+	off := 12
+	_, off = parseName(msg, off)
+	off += 4
+
+	var sections [3][]resource
+	for i := 1; i < 4; i++ {
+		for j := 0; j < counts[i]; j++ {
+			var rr resource
+			rr.name, off = parseName(msg, off)
+			rr.typ = binary.BigEndian.Uint16(msg[off:])
+			rr.class = binary.BigEndian.Uint16(msg[off+2:])
+			rr.ttl = binary.BigEndian.Uint32(msg[off+4:])
+			length := int(binary.BigEndian.Uint16(msg[off+8:]))
+			rr.data = msg[off+10 : off+10+length]
+			off += 10 + length
+
+			sections[i-1] = append(sections[i-1], rr)
+		}
+	}
+
+	return request{
+		prerequisites: sections[0],
+		updates:       sections[1],
+		signed:        len(sections[2]) == 1 && sections[2][0].typ == typeTSIG,
+	}
+}
+
+func parseName(msg []byte, off int) (string, int) {
+	var labels []string
+	for msg[off] != 0 {
+		length := int(msg[off])
+		labels = append(labels, string(msg[off+1:off+1+length]))
+		off += 1 + length
+	}
+
+	return strings.Join(labels, "."), off + 1
+}
+
+func TestProvider_Apply(t *testing.T) {
+	rec := dns.NewRecord("pr-12.hlnr.io", "lb.hlnr.io", 300)
+
+	t.Run("with an owned record", func(t *testing.T) {
+		addr, requests := server(t, rcodeSuccess)
+		p := &Provider{Server: addr, Zone: "hlnr.io."}
+
+		if err := p.Apply(rec, "testing/hello-world"); err != nil {
+			t.Fatalf("Expected no error, got %s", err)
+		}
+
+		req := <-requests
+		if len(req.prerequisites) != 1 {
+			t.Fatalf("Expected the owner to be a prerequisite, got %d prerequisites", len(req.prerequisites))
+		}
+
+		marker := req.prerequisites[0]
+		if marker.name != "_hlnr-owner.pr-12.hlnr.io" || marker.typ != typeTXT {
+			t.Errorf("Expected the owner TXT record, got %s %d", marker.name, marker.typ)
+		}
+
+		if !strings.Contains(string(marker.data), "hlnr.io/owner=testing/hello-world") {
+			t.Errorf("Expected the owner in the TXT record, got %q", marker.data)
+		}
+
+		if len(req.updates) != 4 {
+			t.Fatalf("Expected 3 deletes and an add, got %d updates", len(req.updates))
+		}
+
+		add := req.updates[3]
+		if add.name != "pr-12.hlnr.io" || add.typ != typeCNAME || add.class != classINET || add.ttl != 300 {
+			t.Errorf("Expected a CNAME record for pr-12.hlnr.io, got %+v", add)
+		}
+	})
+
+	t.Run("with an unclaimed name", func(t *testing.T) {
+		addr, requests := server(t, rcodeNXRRSet, rcodeSuccess)
+		p := &Provider{Server: addr, Zone: "hlnr.io"}
+
+		if err := p.Apply(rec, "testing/hello-world"); err != nil {
+			t.Fatalf("Expected no error, got %s", err)
+		}
+
+		<-requests
+		req := <-requests
+		for _, rr := range req.prerequisites {
+			if rr.class != classNONE || rr.typ != typeANY {
+				t.Errorf("Expected the name %s not to be in use, got %+v", rr.name, rr)
+			}
+		}
+
+		if len(req.updates) != 2 || req.updates[0].typ != typeTXT || req.updates[1].typ != typeCNAME {
+			t.Errorf("Expected the owner and the record to be added, got %+v", req.updates)
+		}
+	})
+
+	t.Run("with a name owned by someone else", func(t *testing.T) {
+		addr, _ := server(t, rcodeNXRRSet, rcodeYXDomain)
+		p := &Provider{Server: addr, Zone: "hlnr.io"}
+
+		if err := p.Apply(rec, "testing/hello-world"); err != dns.ErrNotOwned {
+			t.Errorf("Expected %s, got %v", dns.ErrNotOwned, err)
+		}
+	})
+
+	t.Run("with a refused update", func(t *testing.T) {
+		addr, _ := server(t, 5)
+		p := &Provider{Server: addr, Zone: "hlnr.io"}
+
+		err := p.Apply(rec, "testing/hello-world")
+		if err == nil || !strings.Contains(err.Error(), "REFUSED") {
+			t.Errorf("Expected the update to be refused, got %v", err)
+		}
+	})
+
+	t.Run("with a TSIG key", func(t *testing.T) {
+		addr, requests := server(t, rcodeSuccess)
+		p := &Provider{
+			Server: addr,
+			Zone:   "hlnr.io",
+			TSIG:   &TSIG{Name: "hlnr", Algorithm: "hmac-sha256", Secret: []byte("secret")},
+		}
+
+		if err := p.Apply(dns.NewRecord("pr-12", "10.0.0.1", 60), "testing/hello-world"); err != nil {
+			t.Fatalf("Expected no error, got %s", err)
+		}
+
+		req := <-requests
+		if !req.signed {
+			t.Errorf("Expected the update to be signed")
+		}
+
+		add := req.updates[3]
+		if add.name != "pr-12.hlnr.io" || add.typ != typeA || !net.IP(add.data).Equal(net.ParseIP("10.0.0.1")) {
+			t.Errorf("Expected an A record for pr-12.hlnr.io, got %+v", add)
+		}
+	})
+
+	t.Run("with an unknown TSIG algorithm", func(t *testing.T) {
+		p := &Provider{
+			Server: "127.0.0.1:53",
+			Zone:   "hlnr.io",
+			TSIG:   &TSIG{Name: "hlnr", Algorithm: "hmac-md5", Secret: []byte("secret")},
+		}
+
+		if err := p.Apply(rec, "testing/hello-world"); err == nil {
+			t.Errorf("Expected an error for an unsupported algorithm")
+		}
+	})
+}
+
+func TestProvider_Delete(t *testing.T) {
+	rec := dns.NewRecord("pr-12.hlnr.io", "lb.hlnr.io", 300)
+
+	t.Run("with an owned record", func(t *testing.T) {
+		addr, requests := server(t, rcodeSuccess)
+		p := &Provider{Server: addr, Zone: "hlnr.io"}
+
+		if err := p.Delete(rec, "testing/hello-world"); err != nil {
+			t.Fatalf("Expected no error, got %s", err)
+		}
+
+		req := <-requests
+		last := req.updates[len(req.updates)-1]
+		if last.name != "_hlnr-owner.pr-12.hlnr.io" || last.class != classANY {
+			t.Errorf("Expected the owner TXT record to be deleted, got %+v", last)
+		}
+	})
+
+	t.Run("with a record owned by someone else", func(t *testing.T) {
+		addr, _ := server(t, rcodeNXRRSet)
+		p := &Provider{Server: addr, Zone: "hlnr.io"}
+
+		if err := p.Delete(rec, "testing/hello-world"); err != nil {
+			t.Errorf("Expected no error, got %s", err)
+		}
+	})
+}
+
+func TestTXTData(t *testing.T) {
+	data := txtData(strings.Repeat("a", 300))
+	if data[0] != 255 || data[256] != 45 || len(data) != 302 {
+		t.Errorf("Expected strings of 255 and 45 characters, got %d bytes", len(data))
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"log"
 	"net/http"
+	"strings"
 	"sync"
 	"time"
 
@@ -40,6 +41,10 @@ type callbackServer struct {
 	// to the callback payloads.
 	patcher patchClient
 
+	// failures are the commits the Controller couldn't resolve. They're
+	// retried once a webhook comes in for their repository.
+	failures *commitFailures
+
 	// hooks is a list of hooks which know about the CRD id, repo and installed
 	// hooks. This will be used to set up correct endpoints and validate
 	// payloads as well as making sure we update the correct CRD.
@@ -111,6 +116,8 @@ func (s *callbackServer) payloadHandler(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
+	s.failures.reset(repositoryKey(cbHook.crdNamespace, cbHook.crdName))
+
 	var release *v1alpha1.GitHubRelease
 	var active bool
 	switch r.Header.Get("X-GitHub-Event") {
@@ -122,6 +129,18 @@ func (s *callbackServer) payloadHandler(w http.ResponseWriter, r *http.Request)
 		release, active, err = getPullRequestRelease(payload)
 	case "release":
 		release, active, err = getOfficialRelease(payload)
+	case "issue_comment":
+		var number int
+		if number, err = getReviveCommand(payload); err == nil && number != 0 {
+			err = s.reviveRelease(&cbHook, number, time.Now())
+		}
+	}
+
+	if err != nil {
+		log.Printf("Could not handle payload for %s/%s: %s", vars["owner"], vars["name"], err)
+		w.WriteHeader(http.StatusInternalServerError)
+		w.Write([]byte(err.Error()))
+		return
 	}
 
 	if err := s.storeRelease(&cbHook, release, active); err != nil {
@@ -160,6 +179,17 @@ func (s *callbackServer) storeRelease(hook *callbackHook, release *v1alpha1.GitH
 			if !active {
 				continue
 			}
+
+			// Keep track of what we already know about this release, the
+			// payload doesn't contain this information.
+			if release.Deployment == nil && release.Tag == r.Tag {
+				release.Deployment = r.Deployment
+			}
+			if release.RevivedTime == nil {
+				release.RevivedTime = r.RevivedTime
+			}
+			preserveCommit(release, r)
+
 			release.DeepCopyInto(&r)
 		}
 
@@ -180,6 +210,42 @@ func (s *callbackServer) storeRelease(hook *callbackHook, release *v1alpha1.GitH
 	return nil
 }
 
+// reviveRelease marks the preview release for the given pull request number
+// as revived, resetting its expiry.
+func (s *callbackServer) reviveRelease(hook *callbackHook, number int, now time.Time) error {
+	ghr := v1alpha1.GitHubRepository{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       "GitHubRepository",
+			APIVersion: "hlnr.io/v1alpha1",
+		},
+	}
+
+	if err := s.patcher.Get(&ghr, hook.crdNamespace, hook.crdName); err != nil {
+		log.Printf("Could not find GitHubRepository: %s", err)
+		return err
+	}
+
+	found := false
+	for i, r := range ghr.Status.Releases {
+		if r.Level == v1alpha1.SemVerLevelPreview && r.PullRequest == number {
+			ghr.Status.Releases[i].RevivedTime = metaTime(now)
+			found = true
+		}
+	}
+
+	if !found {
+		log.Printf("No preview release found to revive for pull request #%d", number)
+		return nil
+	}
+
+	if _, err := s.patcher.Apply(&ghr); err != nil {
+		log.Printf("Could not update GitHubRepository: %s", err)
+		return err
+	}
+
+	return nil
+}
+
 func (s *callbackServer) hookForRepo(owner, name string) (callbackHook, bool) {
 	repo := v1alpha1.GitHubRepositorySpec{
 		Repo:  name,
@@ -244,6 +310,29 @@ func getPullRequestRelease(payload []byte) (*v1alpha1.GitHubRelease, bool, error
 	return release, active, nil
 }
 
+// reviveCommand is the pull request comment which revives an expired preview
+// release.
+const reviveCommand = "/hlnr revive"
+
+// getReviveCommand returns the pull request number a revive command was given
+// for. It returns 0 when the comment isn't a revive command on a pull request.
+func getReviveCommand(payload []byte) (int, error) {
+	ice := &github.IssueCommentEvent{}
+	if err := json.Unmarshal(payload, ice); err != nil {
+		return 0, err
+	}
+
+	if ice.GetAction() != "created" || ice.Issue == nil || !ice.Issue.IsPullRequest() {
+		return 0, nil
+	}
+
+	if strings.TrimSpace(ice.GetComment().GetBody()) != reviveCommand {
+		return 0, nil
+	}
+
+	return ice.Issue.GetNumber(), nil
+}
+
 func getOfficialRelease(payload []byte) (*v1alpha1.GitHubRelease, bool, error) {
 	re := &github.ReleaseEvent{}
 	if err := json.Unmarshal(payload, re); err != nil {
@@ -282,8 +371,11 @@ func convertPullRequest(pr *github.PullRequest) (*v1alpha1.GitHubRelease, bool)
 	return &v1alpha1.GitHubRelease{
 		Name:        *pr.Head.Ref,
 		Tag:         *pr.Head.SHA,
+		SHA:         *pr.Head.SHA,
 		Level:       v1alpha1.SemVerLevelPreview,
 		ReleaseTime: releaseTimeFromGitHubTimestamp(pr.Head.Repo.UpdatedAt),
+		PullRequest: pr.GetNumber(),
+		OpenedTime:  metav1.NewTime(pr.GetCreatedAt()),
 	}, *pr.State != "closed"
 }
 
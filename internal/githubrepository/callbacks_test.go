@@ -94,6 +94,117 @@ func TestTestStoreRelease(t *testing.T) {
 			t.Error("Did not expect a release, got some")
 		}
 	})
+
+	t.Run("updating a release", func(t *testing.T) {
+		revived := metav1.NewTime(time.Date(2018, 6, 1, 0, 0, 0, 0, time.UTC))
+
+		var applied *v1alpha1.GitHubRepository
+		s := &callbackServer{
+			patcher: &mockPatcher{
+				getFn: func(obj interface{}, ns, name string) error {
+					getter := obj.(*v1alpha1.GitHubRepository)
+					getter.Status.Releases = []v1alpha1.GitHubRelease{
+						{
+							Name:        "update-release",
+							Tag:         "old",
+							RevivedTime: &revived,
+							Deployment:  &v1alpha1.Deployment{State: "success"},
+						},
+					}
+					return nil
+				},
+				applyFn: func(obj runtime.Object, opt ...patcher.OptionFunc) ([]byte, error) {
+					applied = obj.(*v1alpha1.GitHubRepository)
+					return nil, nil
+				},
+			},
+		}
+		release := &v1alpha1.GitHubRelease{
+			Name: "update-release",
+			Tag:  "new",
+		}
+
+		if err := s.storeRelease(hook, release, true); err != nil {
+			t.Errorf("Did not expect an error, got '%s'", err)
+		}
+
+		r := applied.Status.Releases[0]
+		if r.Tag != "new" {
+			t.Errorf("Expected tag to be updated, got '%s'", r.Tag)
+		}
+
+		if r.RevivedTime == nil || !r.RevivedTime.Equal(&revived) {
+			t.Errorf("Expected revived time to be kept, got %v", r.RevivedTime)
+		}
+
+		if r.Deployment != nil {
+			t.Error("Did not expect the deployment of the previous tag to be kept")
+		}
+	})
+}
+
+func TestReviveRelease(t *testing.T) {
+	hook := &callbackHook{
+		crdName:      "my-ghr",
+		crdNamespace: "test-ns",
+	}
+	now := time.Date(2018, 6, 1, 0, 0, 0, 0, time.UTC)
+
+	var applied *v1alpha1.GitHubRepository
+	s := &callbackServer{
+		patcher: &mockPatcher{
+			getFn: func(obj interface{}, ns, name string) error {
+				getter := obj.(*v1alpha1.GitHubRepository)
+				getter.Status.Releases = []v1alpha1.GitHubRelease{
+					{Name: "other", Level: v1alpha1.SemVerLevelPreview, PullRequest: 2},
+					{Name: "changes", Level: v1alpha1.SemVerLevelPreview, PullRequest: 1},
+				}
+				return nil
+			},
+			applyFn: func(obj runtime.Object, opt ...patcher.OptionFunc) ([]byte, error) {
+				applied = obj.(*v1alpha1.GitHubRepository)
+				return nil, nil
+			},
+		},
+	}
+
+	if err := s.reviveRelease(hook, 1, now); err != nil {
+		t.Errorf("Did not expect an error, got '%s'", err)
+	}
+
+	if r := applied.Status.Releases[0]; r.RevivedTime != nil {
+		t.Errorf("Did not expect release '%s' to be revived", r.Name)
+	}
+
+	if r := applied.Status.Releases[1]; r.RevivedTime == nil || !r.RevivedTime.Time.Equal(now) {
+		t.Errorf("Expected release '%s' to be revived at %s, got %v", r.Name, now, r.RevivedTime)
+	}
+}
+
+func TestGetReviveCommand(t *testing.T) {
+	tcs := []struct {
+		name    string
+		payload string
+		number  int
+	}{
+		{"revive command", `{"action": "created", "issue": {"number": 1, "pull_request": {}}, "comment": {"body": " /hlnr revive\n"}}`, 1},
+		{"other comment", `{"action": "created", "issue": {"number": 1, "pull_request": {}}, "comment": {"body": "LGTM"}}`, 0},
+		{"edited comment", `{"action": "edited", "issue": {"number": 1, "pull_request": {}}, "comment": {"body": "/hlnr revive"}}`, 0},
+		{"issue comment", `{"action": "created", "issue": {"number": 1}, "comment": {"body": "/hlnr revive"}}`, 0},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			number, err := getReviveCommand([]byte(tc.payload))
+			if err != nil {
+				t.Fatalf("Did not expect an error, got '%s'", err)
+			}
+
+			if number != tc.number {
+				t.Errorf("Expected pull request %d, got %d", tc.number, number)
+			}
+		})
+	}
 }
 
 func TestGetPullRequestRelease(t *testing.T) {
@@ -118,6 +229,10 @@ func TestGetPullRequestRelease(t *testing.T) {
 		t.Errorf("Expected level to be '%s', got '%s'", v1alpha1.SemVerLevelPreview, release.Level)
 	}
 
+	if release.PullRequest != 1 {
+		t.Errorf("Expected pull request 1, got %d", release.PullRequest)
+	}
+
 	//2015-05-05T23:40:27Z
 	prDate := metav1.NewTime(time.Date(2015, 05, 05, 23, 40, 12, 0, time.UTC))
 	if !release.ReleaseTime.Equal(&prDate) {
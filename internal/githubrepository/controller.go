@@ -14,6 +14,7 @@ import (
 	"github.com/jelmersnoeck/kubekit"
 	"github.com/jelmersnoeck/kubekit/patcher"
 	"github.com/manifoldco/heighliner/apis/v1alpha1"
+	"github.com/manifoldco/heighliner/internal/imagepolicy"
 	"github.com/manifoldco/heighliner/internal/k8sutils"
 	"github.com/manifoldco/heighliner/internal/networkpolicy"
 	"golang.org/x/oauth2"
@@ -34,6 +35,10 @@ type Controller struct {
 	namespace string
 	cfg       Config
 
+	// failures are the commits which couldn't be resolved. They're shared with
+	// the callback server, which resets them when a webhook comes in.
+	failures *commitFailures
+
 	// we'll be sharing data between several goroutines - the controller and
 	// callback server. This channel is to share information between the two.
 	hooksChan chan callbackHook
@@ -69,6 +74,7 @@ func NewController(rcfg *rest.Config, cs kubernetes.Interface, namespace string,
 		patcher:   patcher.New("hlnr-github-policy", cmdutil.NewFactory(nil)),
 		namespace: namespace,
 		cfg:       cfg,
+		failures:  newCommitFailures(),
 		hooksChan: make(chan callbackHook),
 	}, nil
 }
@@ -81,6 +87,7 @@ func (c *Controller) Run() error {
 	log.Printf("Starting WebHooks server...")
 	srv := &callbackServer{
 		patcher:   c.patcher,
+		failures:  c.failures,
 		hooksChan: c.hooksChan,
 	}
 	go srv.start(c.cfg.CallbackPort)
@@ -162,7 +169,9 @@ func (c *Controller) deleteHooks(obj interface{}) error {
 		return err
 	}
 
-	if _, err := client.Repositories.DeleteHook(ctx, repo.Owner, repo.Repo, *ghp.Status.Webhook.ID); err != nil {
+	// hooks which were removed from GitHub don't need to be deleted.
+	rsp, err := client.Repositories.DeleteHook(ctx, repo.Owner, repo.Repo, *ghp.Status.Webhook.ID)
+	if err != nil && (rsp == nil || rsp.StatusCode != http.StatusNotFound) {
 		log.Printf("Could not delete hook from GitHub for %s (%s): %s", ghp.Name, ghp.Namespace, err)
 		return err
 	}
@@ -184,32 +193,74 @@ func (c *Controller) deleteHooks(obj interface{}) error {
 func (c *Controller) syncPolicy(obj interface{}) error {
 	ghp := obj.(*v1alpha1.GitHubRepository).DeepCopy()
 
+	if ghp.DeletionTimestamp != nil {
+		return c.finalizeRepository(ghp)
+	}
+
 	ctx := context.Background()
+	ghp.Status.ObservedGeneration = ghp.Generation
 
 	ghClient, err := getGitHubClient(ctx, c.patcher, ghp.Namespace, ghp.Spec.ConfigSecret.Name)
 	if err != nil {
 		log.Printf("Could not create GitHub cleint for %s (%s): %s", ghp.Spec.Slug(), ghp.Namespace, err)
-		return err
+		return c.markDegraded(ghp, "ClientError", err)
 	}
 
 	hook, err := c.ensureHooks(c.patcher, ghp, c.cfg)
 	if err != nil {
 		log.Printf("Could not ensure GitHub hooks for %s (%s): %s", ghp.Spec.Slug(), ghp.Namespace, err)
-		return err
+		return c.markDegraded(ghp, "WebhookError", err)
+	}
+
+	ghp.Status.Webhook = &v1alpha1.GitHubHook{
+		ID:     hook.ID,
+		Secret: hook.Secret,
 	}
 
 	rc := githubReconciliationClient{Client: ghClient}
 	err = reconciliateRepository(ctx, &rc, ghp, c.cfg.ReconciliationPeriod)
 	if err != nil {
 		log.Printf("Could sync GitHub repo for %s (%s): %s", ghp.Spec.Slug(), ghp.Namespace, err)
-		return err
+		return c.markDegraded(ghp, "ReconciliationError", err)
 	}
 
-	ghp.Status.Webhook = &v1alpha1.GitHubHook{
-		ID:     hook.ID,
-		Secret: hook.Secret,
+	// releases which came in through the webhook are resolved here as well.
+	ips, err := c.listImagePolicies()
+	if err != nil {
+		log.Printf("Could not list ImagePolicies for %s (%s): %s", ghp.Spec.Slug(), ghp.Namespace, err)
+	} else if needsCommits(c.patcher, ghp, ips) {
+		resolveCommits(ctx, &rc, ghp, c.failures, c.cfg.ReconciliationPeriod)
 	}
 
+	ghp.Status.Conditions.MarkReady("Synced", "")
+	return c.applyRepository(ghp)
+}
+
+// listImagePolicies lists the ImagePolicies in the namespace the Controller
+// watches.
+func (c *Controller) listImagePolicies() ([]v1alpha1.ImagePolicy, error) {
+	list := &v1alpha1.ImagePolicyList{}
+	err := c.rc.Get().
+		Namespace(c.namespace).
+		Resource(imagepolicy.ImagePolicyResource.Plural).
+		Do().
+		Into(list)
+
+	return list.Items, err
+}
+
+// markDegraded records why a GitHubRepository couldn't be synced in its
+// status, and returns the error.
+func (c *Controller) markDegraded(ghp *v1alpha1.GitHubRepository, reason string, err error) error {
+	ghp.Status.Conditions.MarkDegraded(reason, err.Error())
+	c.applyRepository(ghp)
+	return err
+}
+
+func (c *Controller) applyRepository(ghp *v1alpha1.GitHubRepository) error {
+	// make sure the webhook is removed when the GitHubRepository is deleted.
+	k8sutils.AddFinalizer(ghp, Finalizer)
+
 	// need to specify types again until we resolve the mapping issue
 	ghp.TypeMeta = metav1.TypeMeta{
 		Kind:       "GitHubRepository",
@@ -308,6 +359,7 @@ func newGHHook(cfg webhookConfig, secret string) *github.Hook {
 		Name:   k8sutils.PtrString("web"),
 		Active: k8sutils.PtrBool(true),
 		Events: []string{
+			"issue_comment",
 			"pull_request",
 			"release",
 		},
@@ -369,9 +421,14 @@ func (c *Controller) propagateHook(cfg webhookConfig, hook *v1alpha1.GitHubHook,
 func (c *Controller) syncDeployment(obj interface{}, deleted bool) {
 	np := obj.(*v1alpha1.NetworkPolicy)
 
+	if np.DeletionTimestamp != nil && !deleted {
+		c.finalizeNetworkPolicy(np.DeepCopy())
+		return
+	}
+
 	// Find the microservice referenced by this network policy
 	msvcName := np.Name
-	if np.Spec.Microservice == nil {
+	if np.Spec.Microservice != nil {
 		msvcName = np.Spec.Microservice.Name
 	}
 
@@ -420,9 +477,24 @@ func (c *Controller) syncDeployment(obj interface{}, deleted bool) {
 		return
 	}
 
+	if !deleted {
+		if err := c.ensureNetworkPolicyFinalizer(np, &ghr); err != nil {
+			log.Printf("Error adding finalizer to NetworkPolicy %s: %s", np.Name, err)
+		}
+	}
+
+	c.updateDeployments(np, &ghr, ip.Status.Releases, deleted)
+}
+
+// updateDeployments creates the GitHub deployments and deployment statuses
+// for the releases of a NetworkPolicy, and stores them on the
+// GitHubRepository.
+func (c *Controller) updateDeployments(np *v1alpha1.NetworkPolicy, ghr *v1alpha1.GitHubRepository, deployed []v1alpha1.Release, deleted bool) error {
 	changed, newReleases := reconcileDeployments(np.Status.Domains, deleted, ghr.Status.Releases)
+	expired, newReleases := expireDeployments(deployed, newReleases)
+	changed = mergeChanged(changed, expired)
 	if len(changed) == 0 {
-		return
+		return nil
 	}
 
 	// Fix the network policy reference. reconciliation doesn't care about it.
@@ -441,26 +513,31 @@ func (c *Controller) syncDeployment(obj interface{}, deleted bool) {
 	ghClient, err := getGitHubClient(ctx, c.patcher, ghr.Namespace, ghr.Spec.ConfigSecret.Name)
 	if err != nil {
 		log.Printf("Could not fetch client: %s", err)
-		return
+		return err
 	}
 
 	// Create deployment / status in github
+	var deployErr error
 	for _, idx := range changed {
-		id, err := createGitHubDeployment(ctx, ghClient.Repositories, &ghr, newReleases[idx])
+		id, err := createGitHubDeployment(ctx, ghClient.Repositories, ghr, newReleases[idx])
 		if id != nil {
 			newReleases[idx].Deployment.ID = id
 		}
 
 		if err != nil {
 			log.Print("Error creating GitHub deployment:", err)
+			deployErr = err
 			continue // try the rest of the changes
 		}
 	}
 
 	// persist state back to k8s
-	if _, err := c.patcher.Apply(&ghr); err != nil {
+	if _, err := c.patcher.Apply(ghr); err != nil {
 		log.Printf("Error syncing GitHubRepository %s (%s): %s", ghr.Name, ghr.Namespace, err)
+		return err
 	}
+
+	return deployErr
 }
 
 func createGitHubDeployment(ctx context.Context, cl deploymentClient, repo *v1alpha1.GitHubRepository, release v1alpha1.GitHubRelease) (*int64, error) {
@@ -489,6 +566,10 @@ func createGitHubDeployment(ctx context.Context, cl deploymentClient, repo *v1al
 		EnvironmentURL: release.Deployment.URL,
 	}
 
+	if release.Deployment.Description != "" {
+		status.Description = k8sutils.PtrString(release.Deployment.Description)
+	}
+
 	// Check the last status to see if we need to create a new one.
 	opt := &github.ListOptions{PerPage: 10}
 	var prevStatus *github.DeploymentStatus
@@ -531,7 +612,8 @@ func reconcileDeployments(domains []v1alpha1.Domain, deleted bool, releases []v1
 
 	for i, r := range releases {
 		for _, d := range domains {
-			if d.SemVer.Name != r.Name || d.SemVer.Version != r.Tag {
+			name, version, ok := d.Release()
+			if !ok || !isRelease(r, name, version, d.Commit != nil) {
 				continue
 			}
 
@@ -558,6 +640,70 @@ func reconcileDeployments(domains []v1alpha1.Domain, deleted bool, releases []v1
 	return changed, newReleases
 }
 
+// expireDeployments marks the deployments of expired releases as inactive,
+// explaining why they expired. Deployments which were marked inactive because
+// they expired are marked as successful again once they're revived.
+func expireDeployments(deployed []v1alpha1.Release, releases []v1alpha1.GitHubRelease) ([]int, []v1alpha1.GitHubRelease) {
+	changed := make([]int, 0, len(releases))
+	newReleases := make([]v1alpha1.GitHubRelease, 0, len(releases))
+
+	for i, r := range releases {
+		for _, d := range deployed {
+			if r.Deployment == nil || !isRelease(r, d.Name(), d.Version(), d.Commit != nil) {
+				continue
+			}
+
+			if d.Expired != nil && r.Deployment.State != "inactive" {
+				r.Deployment.State = "inactive"
+				r.Deployment.Description = d.Expired.Reason
+				changed = append(changed, i)
+			}
+
+			// deployments without a URL were deactivated because their
+			// NetworkPolicy was removed, don't revive them.
+			revived := d.Expired == nil && r.Deployment.Description != ""
+			if revived && r.Deployment.State == "inactive" && r.Deployment.URL != nil {
+				r.Deployment.State = "success"
+				r.Deployment.Description = ""
+				changed = append(changed, i)
+			}
+
+			break
+		}
+
+		newReleases = append(newReleases, r)
+	}
+
+	return changed, newReleases
+}
+
+// isRelease returns whether the GitHub release is the release with the given
+// name and version. Releases following the commit scheme are versioned by the
+// SHA of their commit instead of their tag.
+func isRelease(r v1alpha1.GitHubRelease, name, version string, commit bool) bool {
+	if commit {
+		return name == r.Name && r.SHA != "" && version == r.SHA
+	}
+
+	return name == r.Name && version == r.Tag
+}
+
+// mergeChanged merges two lists of changed release indexes, omitting
+// duplicates.
+func mergeChanged(a, b []int) []int {
+	seen := make(map[int]bool, len(a)+len(b))
+	merged := make([]int, 0, len(a)+len(b))
+
+	for _, i := range append(a, b...) {
+		if !seen[i] {
+			seen[i] = true
+			merged = append(merged, i)
+		}
+	}
+
+	return merged
+}
+
 func getGitHubClient(ctx context.Context, cl getClient, namespace, name string) (*github.Client, error) {
 	authToken, err := getSecretAuthToken(cl, namespace, name)
 	if err != nil {
@@ -6,10 +6,16 @@ import (
 	"testing"
 
 	"k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 
 	"github.com/google/go-github/github"
+	"github.com/jelmersnoeck/kubekit/patcher"
 	"github.com/manifoldco/heighliner/apis/v1alpha1"
 	"github.com/manifoldco/heighliner/internal/k8sutils"
+	"github.com/manifoldco/heighliner/internal/tester"
 )
 
 func TestGetSecretAuthToken(t *testing.T) {
@@ -51,6 +57,41 @@ func TestGetSecretAuthToken(t *testing.T) {
 	})
 }
 
+func TestController_SyncPolicy_Degraded(t *testing.T) {
+	var applied *v1alpha1.GitHubRepository
+	cl := &tester.PatchClient{
+		GetFunc: func(obj interface{}, namespace, name string) error {
+			return kerrors.NewNotFound(schema.GroupResource{Resource: "secrets"}, name)
+		},
+		ApplyFunc: func(obj runtime.Object, _ ...patcher.OptionFunc) ([]byte, error) {
+			applied = obj.(*v1alpha1.GitHubRepository)
+			return nil, nil
+		},
+	}
+
+	ghr := &v1alpha1.GitHubRepository{
+		ObjectMeta: metav1.ObjectMeta{Name: "hello-world", Namespace: "testing", Generation: 3},
+		Spec: v1alpha1.GitHubRepositorySpec{
+			Owner:        "manifoldco",
+			Repo:         "hello-world",
+			ConfigSecret: v1.LocalObjectReference{Name: "github"},
+		},
+	}
+
+	if err := (&Controller{patcher: cl}).syncPolicy(ghr); err == nil {
+		t.Fatalf("Expected an error for the missing secret")
+	}
+
+	cond := applied.Status.Conditions.Get(v1alpha1.ConditionDegraded)
+	if cond == nil || cond.Status != v1.ConditionTrue || cond.Reason != "ClientError" {
+		t.Errorf("Expected the GitHubRepository to be degraded, got %+v", applied.Status.Conditions)
+	}
+
+	if applied.Status.ObservedGeneration != 3 {
+		t.Errorf("Expected the observed generation to be 3, got %d", applied.Status.ObservedGeneration)
+	}
+}
+
 func TestCreateDeployment(t *testing.T) {
 	repo := &v1alpha1.GitHubRepository{
 		Spec: v1alpha1.GitHubRepositorySpec{
@@ -197,6 +238,15 @@ func TestReconcileDeployments(t *testing.T) {
 			[]int{0},
 		},
 
+		{
+			"New domain of a commit release",
+			[]v1alpha1.Domain{{URL: fakeURL, Commit: &v1alpha1.CommitRelease{Name: "foo", SHA: "abc123"}}},
+			false,
+			[]v1alpha1.GitHubRelease{{Name: "foo", Tag: "1", SHA: "abc123"}},
+			[]v1alpha1.GitHubRelease{{Name: "foo", Tag: "1", SHA: "abc123", Deployment: &v1alpha1.Deployment{State: "success", URL: &fakeURL}}},
+			[]int{0},
+		},
+
 		{
 			"Unknown releases are kept the same",
 			[]v1alpha1.Domain{{URL: fakeURL, SemVer: &v1alpha1.SemVerRelease{Name: "bar", Version: "1"}}},
@@ -225,3 +275,83 @@ func TestReconcileDeployments(t *testing.T) {
 		})
 	}
 }
+
+func TestExpireDeployments(t *testing.T) {
+	fakeURL := "https://www.fake.com"
+	release := v1alpha1.Release{SemVer: &v1alpha1.SemVerRelease{Name: "foo", Version: "1"}}
+	expired := release
+	expired.Expired = &v1alpha1.ReleaseExpiry{Reason: "idle", Action: v1alpha1.ExpiryActionScaleDown}
+
+	tcs := []struct {
+		name     string
+		deployed []v1alpha1.Release
+		releases []v1alpha1.GitHubRelease
+		out      []v1alpha1.GitHubRelease
+		changed  []int
+	}{
+		{
+			"Without deployment",
+			[]v1alpha1.Release{expired},
+			[]v1alpha1.GitHubRelease{{Name: "foo", Tag: "1"}},
+			[]v1alpha1.GitHubRelease{{Name: "foo", Tag: "1"}},
+			[]int{},
+		},
+
+		{
+			"Expired release",
+			[]v1alpha1.Release{expired},
+			[]v1alpha1.GitHubRelease{{Name: "foo", Tag: "1", Deployment: &v1alpha1.Deployment{State: "success", URL: &fakeURL}}},
+			[]v1alpha1.GitHubRelease{{Name: "foo", Tag: "1", Deployment: &v1alpha1.Deployment{State: "inactive", URL: &fakeURL, Description: "idle"}}},
+			[]int{0},
+		},
+
+		{
+			"Already expired",
+			[]v1alpha1.Release{expired},
+			[]v1alpha1.GitHubRelease{{Name: "foo", Tag: "1", Deployment: &v1alpha1.Deployment{State: "inactive", URL: &fakeURL, Description: "idle"}}},
+			[]v1alpha1.GitHubRelease{{Name: "foo", Tag: "1", Deployment: &v1alpha1.Deployment{State: "inactive", URL: &fakeURL, Description: "idle"}}},
+			[]int{},
+		},
+
+		{
+			"Revived release",
+			[]v1alpha1.Release{release},
+			[]v1alpha1.GitHubRelease{{Name: "foo", Tag: "1", Deployment: &v1alpha1.Deployment{State: "inactive", URL: &fakeURL, Description: "idle"}}},
+			[]v1alpha1.GitHubRelease{{Name: "foo", Tag: "1", Deployment: &v1alpha1.Deployment{State: "success", URL: &fakeURL}}},
+			[]int{0},
+		},
+
+		{
+			"Expired commit release",
+			[]v1alpha1.Release{{
+				Commit:  &v1alpha1.CommitRelease{Name: "foo", SHA: "abc123"},
+				Expired: expired.Expired,
+			}},
+			[]v1alpha1.GitHubRelease{{Name: "foo", Tag: "1", SHA: "abc123", Deployment: &v1alpha1.Deployment{State: "success", URL: &fakeURL}}},
+			[]v1alpha1.GitHubRelease{{Name: "foo", Tag: "1", SHA: "abc123", Deployment: &v1alpha1.Deployment{State: "inactive", URL: &fakeURL, Description: "idle"}}},
+			[]int{0},
+		},
+
+		{
+			"Removed domains aren't revived",
+			[]v1alpha1.Release{release},
+			[]v1alpha1.GitHubRelease{{Name: "foo", Tag: "1", Deployment: &v1alpha1.Deployment{State: "inactive", Description: "idle"}}},
+			[]v1alpha1.GitHubRelease{{Name: "foo", Tag: "1", Deployment: &v1alpha1.Deployment{State: "inactive", Description: "idle"}}},
+			[]int{},
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			changed, newReleases := expireDeployments(tc.deployed, tc.releases)
+
+			if !reflect.DeepEqual(changed, tc.changed) {
+				t.Error("bad result for changed. got:", changed, "wanted:", tc.changed)
+			}
+
+			if !reflect.DeepEqual(newReleases, tc.out) {
+				t.Error("releases did not match! got:", newReleases, "expected:", tc.out)
+			}
+		})
+	}
+}
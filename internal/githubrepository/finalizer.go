@@ -0,0 +1,194 @@
+package githubrepository
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/manifoldco/heighliner/apis/v1alpha1"
+	"github.com/manifoldco/heighliner/internal/k8sutils"
+
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// Finalizer makes sure the webhook of a GitHubRepository is removed and
+	// its deployments are marked inactive before the GitHubRepository is
+	// deleted.
+	Finalizer = "hlnr.io/github-repository"
+
+	// DeploymentsFinalizer makes sure the GitHub deployments of a
+	// NetworkPolicy are marked inactive before the NetworkPolicy is deleted.
+	DeploymentsFinalizer = "hlnr.io/github-deployments"
+
+	// AnnotationRepository is the annotation on a NetworkPolicy which refers
+	// to the GitHubRepository its deployments are stored on, so they can be
+	// found once the Microservice is gone.
+	AnnotationRepository = "hlnr.io/github-repository"
+)
+
+// finalizeRepository removes the webhook of a GitHubRepository which is being
+// deleted and marks its deployments inactive. The finalizer is removed once
+// that's done, or once the deletion timed out.
+func (c *Controller) finalizeRepository(ghr *v1alpha1.GitHubRepository) error {
+	if !k8sutils.HasFinalizer(ghr, Finalizer) {
+		return nil
+	}
+
+	pending, err := c.deactivateRepository(ghr)
+	if err != nil {
+		log.Printf("Could not clean up GitHubRepository %s (%s): %s", ghr.Name, ghr.Namespace, err)
+	}
+
+	timedOut := k8sutils.DeletionTimedOut(ghr, time.Now())
+	if len(pending) == 0 || timedOut {
+		if len(pending) > 0 {
+			log.Printf("Deletion of GitHubRepository %s (%s) timed out, leaving %v behind", ghr.Name, ghr.Namespace, pending)
+		}
+
+		k8sutils.RemoveFinalizer(ghr, Finalizer)
+	}
+
+	ghr.Status.Deletion = v1alpha1.NewDeletionStatus(pending, err, timedOut)
+
+	// need to specify types again until we resolve the mapping issue
+	ghr.TypeMeta = metav1.TypeMeta{
+		Kind:       "GitHubRepository",
+		APIVersion: "hlnr.io/v1alpha1",
+	}
+
+	if _, err := c.patcher.Apply(ghr); err != nil && !kerrors.IsNotFound(err) {
+		log.Printf("Error finalizing GitHubRepository %s (%s): %s", ghr.Name, ghr.Namespace, err)
+		return err
+	}
+
+	return err
+}
+
+// deactivateRepository marks the active deployments of a GitHubRepository
+// inactive and deletes its webhook. It returns what couldn't be cleaned up.
+func (c *Controller) deactivateRepository(ghr *v1alpha1.GitHubRepository) ([]string, error) {
+	var pending []string
+	var lastErr error
+
+	ctx := context.Background()
+	ghClient, err := getGitHubClient(ctx, c.patcher, ghr.Namespace, ghr.Spec.ConfigSecret.Name)
+	if err != nil {
+		pending = append(pending, "Webhook")
+		return pending, err
+	}
+
+	for i, release := range ghr.Status.Releases {
+		dpl := release.Deployment
+		if dpl == nil || dpl.ID == nil || dpl.State == "inactive" {
+			continue
+		}
+
+		inactive := *dpl
+		inactive.State = "inactive"
+		inactive.URL = nil
+		release.Deployment = &inactive
+
+		if _, err := createGitHubDeployment(ctx, ghClient.Repositories, ghr, release); err != nil {
+			pending = append(pending, fmt.Sprintf("Deployment/%s@%s", release.Name, release.Tag))
+			lastErr = err
+			continue
+		}
+
+		ghr.Status.Releases[i].Deployment = &inactive
+	}
+
+	if err := c.deleteHooks(ghr); err != nil {
+		pending = append(pending, "Webhook")
+		return pending, err
+	}
+	ghr.Status.Webhook = nil
+
+	return pending, lastErr
+}
+
+// ensureNetworkPolicyFinalizer makes sure the GitHub deployments of a
+// NetworkPolicy are marked inactive when it's deleted.
+func (c *Controller) ensureNetworkPolicyFinalizer(np *v1alpha1.NetworkPolicy, ghr *v1alpha1.GitHubRepository) error {
+	ref := ghr.Namespace + "/" + ghr.Name
+	if k8sutils.HasFinalizer(np, DeploymentsFinalizer) && np.Annotations[AnnotationRepository] == ref {
+		return nil
+	}
+
+	np = np.DeepCopy()
+	k8sutils.AddFinalizer(np, DeploymentsFinalizer)
+	if np.Annotations == nil {
+		np.Annotations = map[string]string{}
+	}
+	np.Annotations[AnnotationRepository] = ref
+
+	np.TypeMeta = metav1.TypeMeta{
+		Kind:       "NetworkPolicy",
+		APIVersion: "hlnr.io/v1alpha1",
+	}
+
+	_, err := c.patcher.Apply(np)
+	return err
+}
+
+// finalizeNetworkPolicy marks the GitHub deployments of a NetworkPolicy which
+// is being deleted inactive. The finalizer is removed once that's done, or once
+// the deletion timed out.
+func (c *Controller) finalizeNetworkPolicy(np *v1alpha1.NetworkPolicy) error {
+	if !k8sutils.HasFinalizer(np, DeploymentsFinalizer) {
+		return nil
+	}
+
+	err := c.deactivateNetworkPolicy(np)
+	if err != nil {
+		log.Printf("Could not mark deployments of NetworkPolicy %s inactive: %s", np.Name, err)
+
+		if !k8sutils.DeletionTimedOut(np, time.Now()) {
+			return err
+		}
+
+		log.Printf("Deletion of NetworkPolicy %s timed out, leaving its deployments active", np.Name)
+	}
+
+	k8sutils.RemoveFinalizer(np, DeploymentsFinalizer)
+
+	np.TypeMeta = metav1.TypeMeta{
+		Kind:       "NetworkPolicy",
+		APIVersion: "hlnr.io/v1alpha1",
+	}
+
+	if _, err := c.patcher.Apply(np); err != nil && !kerrors.IsNotFound(err) {
+		log.Printf("Error finalizing NetworkPolicy %s: %s", np.Name, err)
+		return err
+	}
+
+	return nil
+}
+
+func (c *Controller) deactivateNetworkPolicy(np *v1alpha1.NetworkPolicy) error {
+	parts := strings.SplitN(np.Annotations[AnnotationRepository], "/", 2)
+	if len(parts) != 2 {
+		return nil
+	}
+
+	ghr := &v1alpha1.GitHubRepository{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       "GitHubRepository",
+			APIVersion: "hlnr.io/v1alpha1",
+		},
+	}
+
+	if err := c.patcher.Get(ghr, parts[0], parts[1]); err != nil {
+		// the GitHubRepository deactivates its deployments itself.
+		if kerrors.IsNotFound(err) {
+			return nil
+		}
+
+		return err
+	}
+
+	return c.updateDeployments(np, ghr, nil, true)
+}
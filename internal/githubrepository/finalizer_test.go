@@ -0,0 +1,82 @@
+package githubrepository
+
+import (
+	"testing"
+	"time"
+
+	"github.com/manifoldco/heighliner/apis/v1alpha1"
+	"github.com/manifoldco/heighliner/internal/k8sutils"
+	"github.com/manifoldco/heighliner/internal/tester"
+
+	"github.com/jelmersnoeck/kubekit/patcher"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+func TestNetworkPolicyFinalizer(t *testing.T) {
+	ghr := &v1alpha1.GitHubRepository{
+		ObjectMeta: metav1.ObjectMeta{Name: "hello-world", Namespace: "github"},
+	}
+
+	var applied *v1alpha1.NetworkPolicy
+	cl := &tester.PatchClient{
+		ApplyFunc: func(obj runtime.Object, _ ...patcher.OptionFunc) ([]byte, error) {
+			applied = obj.(*v1alpha1.NetworkPolicy)
+			return nil, nil
+		},
+		GetFunc: func(obj interface{}, namespace, name string) error {
+			return kerrors.NewNotFound(schema.GroupResource{}, name)
+		},
+	}
+	c := &Controller{patcher: cl}
+
+	t.Run("adds the finalizer", func(t *testing.T) {
+		np := &v1alpha1.NetworkPolicy{ObjectMeta: metav1.ObjectMeta{Name: "hello-world", Namespace: "testing"}}
+		if err := c.ensureNetworkPolicyFinalizer(np, ghr); err != nil {
+			t.Fatalf("Expected no error, got %s", err)
+		}
+
+		if !k8sutils.HasFinalizer(applied, DeploymentsFinalizer) {
+			t.Errorf("Expected the finalizer to be added, got %v", applied.Finalizers)
+		}
+
+		if ref := applied.Annotations[AnnotationRepository]; ref != "github/hello-world" {
+			t.Errorf("Expected a reference to the GitHubRepository, got %q", ref)
+		}
+
+		if len(np.Finalizers) != 0 {
+			t.Errorf("Expected the NetworkPolicy to be left untouched")
+		}
+
+		applied = nil
+		if err := c.ensureNetworkPolicyFinalizer(withDeploymentsFinalizer(np, ghr), ghr); err != nil || applied != nil {
+			t.Errorf("Expected a NetworkPolicy with the finalizer not to be applied, got %v", err)
+		}
+	})
+
+	t.Run("removes the finalizer once the GitHubRepository is gone", func(t *testing.T) {
+		now := metav1.NewTime(time.Now())
+		np := withDeploymentsFinalizer(&v1alpha1.NetworkPolicy{
+			ObjectMeta: metav1.ObjectMeta{Name: "hello-world", Namespace: "testing", DeletionTimestamp: &now},
+		}, ghr)
+
+		if err := c.finalizeNetworkPolicy(np); err != nil {
+			t.Fatalf("Expected no error, got %s", err)
+		}
+
+		if k8sutils.HasFinalizer(applied, DeploymentsFinalizer) {
+			t.Errorf("Expected the finalizer to be removed, got %v", applied.Finalizers)
+		}
+	})
+}
+
+// withDeploymentsFinalizer returns a copy of the NetworkPolicy as it looks once the finalizer
+// was added for the given GitHubRepository.
+func withDeploymentsFinalizer(np *v1alpha1.NetworkPolicy, ghr *v1alpha1.GitHubRepository) *v1alpha1.NetworkPolicy {
+	np = np.DeepCopy()
+	np.Finalizers = []string{DeploymentsFinalizer}
+	np.Annotations = map[string]string{AnnotationRepository: ghr.Namespace + "/" + ghr.Name}
+	return np
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"log"
 	"net/http"
+	"sync"
 	"time"
 
 	"github.com/google/go-github/github"
@@ -103,9 +104,16 @@ func reconciliateRepository(ctx context.Context, ghClient reconcilationClient,
 
 	for _, p := range prs {
 		pr, _ := convertPullRequest(p)
+		preservePreviewState(pr, ghp.Status.Releases)
 		releases = append(releases, *pr)
 	}
 
+	for i := range releases {
+		for _, known := range ghp.Status.Releases {
+			preserveCommit(&releases[i], known)
+		}
+	}
+
 	diffReleases(ghp.Status.Releases, releases)
 
 	ghp.Status.Releases = releases
@@ -115,6 +123,165 @@ func reconciliateRepository(ctx context.Context, ghClient reconcilationClient,
 	return nil
 }
 
+// preservePreviewState copies the state we keep track of for a preview release
+// but which isn't available on GitHub from the known releases.
+func preservePreviewState(pr *v1alpha1.GitHubRelease, known []v1alpha1.GitHubRelease) {
+	for _, r := range known {
+		if r.Level != v1alpha1.SemVerLevelPreview || r.Name != pr.Name {
+			continue
+		}
+
+		pr.RevivedTime = r.RevivedTime
+		if r.Tag == pr.Tag {
+			pr.Deployment = r.Deployment
+		}
+
+		return
+	}
+}
+
+// preserveCommit copies the resolved commit of a known release with the same
+// tag, so it doesn't have to be resolved again.
+func preserveCommit(release *v1alpha1.GitHubRelease, known v1alpha1.GitHubRelease) {
+	if !release.CommitTime.IsZero() || known.CommitTime.IsZero() || release.Tag != known.Tag {
+		return
+	}
+
+	release.SHA = known.SHA
+	release.CommitTime = known.CommitTime
+}
+
+// resolveCommits looks up the commit of releases which don't know it yet.
+// Releases only carry their tag or head SHA, which isn't enough to order
+// commit releases. Releases which can't be resolved are recorded in failures
+// and only retried after the given period, or once a webhook comes in for the
+// repository.
+func resolveCommits(ctx context.Context, ghClient reconcilationClient, ghp *v1alpha1.GitHubRepository,
+	failures *commitFailures, period time.Duration) {
+
+	repo := repositoryKey(ghp.Namespace, ghp.Name)
+	now := time.Now()
+
+	for i := range ghp.Status.Releases {
+		release := &ghp.Status.Releases[i]
+		if !release.CommitTime.IsZero() {
+			continue
+		}
+
+		ref := release.SHA
+		if ref == "" {
+			ref = release.Tag
+		}
+
+		if !failures.retry(repo, ref, now, period) {
+			continue
+		}
+
+		commit, _, err := ghClient.GetCommit(ctx, ghp.Spec.Owner, ghp.Spec.Repo, ref)
+		if err != nil {
+			log.Printf("Could not resolve the commit of release %s (%s): %s", release.Name, ref, err)
+			failures.add(repo, ref, now)
+			continue
+		}
+
+		release.SHA = commit.GetSHA()
+		release.CommitTime = metav1.NewTime(commit.GetCommit().GetCommitter().GetDate())
+	}
+}
+
+// needsCommits reports whether any of the given ImagePolicies which follow the
+// GitHubRepository uses a VersioningPolicy which needs the commit of releases.
+// That's the case for commit versioning, and for previews which expire when
+// they're idle.
+func needsCommits(cl getClient, ghp *v1alpha1.GitHubRepository, ips []v1alpha1.ImagePolicy) bool {
+	for _, ip := range ips {
+		repo := ip.Spec.Filter.GitHub
+		if repo == nil {
+			continue
+		}
+
+		namespace := repo.Namespace
+		if namespace == "" {
+			namespace = ip.Namespace
+		}
+
+		if repo.Name != ghp.Name || namespace != ghp.Namespace {
+			continue
+		}
+
+		vp := v1alpha1.VersioningPolicy{
+			TypeMeta: metav1.TypeMeta{
+				Kind:       "VersioningPolicy",
+				APIVersion: "hlnr.io/v1alpha1",
+			},
+		}
+
+		namespace = ip.Spec.VersioningPolicy.Namespace
+		if namespace == "" {
+			namespace = ip.Namespace
+		}
+
+		if err := cl.Get(&vp, namespace, ip.Spec.VersioningPolicy.Name); err != nil {
+			log.Printf("Could not get the VersioningPolicy of ImagePolicy %s (%s): %s", ip.Name, ip.Namespace, err)
+			continue
+		}
+
+		expiry := vp.Spec.PreviewExpiry
+		if vp.Spec.Commit != nil || (expiry != nil && expiry.IdleTimeout != nil) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// commitFailures keeps track of the refs which couldn't be resolved for each
+// GitHubRepository, so they aren't looked up on every sync.
+type commitFailures struct {
+	sync.Mutex
+	refs map[string]map[string]time.Time
+}
+
+func newCommitFailures() *commitFailures {
+	return &commitFailures{refs: make(map[string]map[string]time.Time)}
+}
+
+// retry reports whether the given ref of a repository should be looked up,
+// which is when it didn't fail before or failed longer than period ago.
+func (f *commitFailures) retry(repo, ref string, now time.Time, period time.Duration) bool {
+	f.Lock()
+	defer f.Unlock()
+
+	failed, ok := f.refs[repo][ref]
+	return !ok || now.Sub(failed) >= period
+}
+
+// add records that the given ref of a repository couldn't be resolved.
+func (f *commitFailures) add(repo, ref string, now time.Time) {
+	f.Lock()
+	defer f.Unlock()
+
+	if f.refs[repo] == nil {
+		f.refs[repo] = make(map[string]time.Time)
+	}
+
+	f.refs[repo][ref] = now
+}
+
+// reset forgets the failures of a repository, so they're retried on the next
+// sync.
+func (f *commitFailures) reset(repo string) {
+	f.Lock()
+	defer f.Unlock()
+
+	delete(f.refs, repo)
+}
+
+// repositoryKey identifies a GitHubRepository in commitFailures.
+func repositoryKey(namespace, name string) string {
+	return namespace + "/" + name
+}
+
 // diffReleases logs the number of releases added or removed.
 func diffReleases(old, new []v1alpha1.GitHubRelease) {
 	diff := make(map[string]bool)
@@ -153,6 +320,8 @@ type reconcilationClient interface {
 		[]*github.RepositoryRelease, *github.Response, error)
 	ListPullRequests(ctx context.Context, owner string, repo string,
 		opt *github.PullRequestListOptions) ([]*github.PullRequest, *github.Response, error)
+	GetCommit(ctx context.Context, owner, repo, ref string) (*github.RepositoryCommit,
+		*github.Response, error)
 }
 
 type githubReconciliationClient struct {
@@ -174,6 +343,11 @@ func (gh *githubReconciliationClient) ListPullRequests(ctx context.Context, owne
 	return gh.Client.PullRequests.List(ctx, owner, repo, opt)
 }
 
+func (gh *githubReconciliationClient) GetCommit(ctx context.Context, owner, repo, ref string) (
+	*github.RepositoryCommit, *github.Response, error) {
+	return gh.Client.Repositories.GetCommit(ctx, owner, repo, ref)
+}
+
 func metaTime(t time.Time) *metav1.Time {
 	mt := metav1.NewTime(t)
 	return &mt
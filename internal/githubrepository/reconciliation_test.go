@@ -10,7 +10,11 @@ import (
 
 	"github.com/google/go-github/github"
 	"github.com/manifoldco/heighliner/apis/v1alpha1"
+	"github.com/manifoldco/heighliner/internal/tester"
+	"k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 )
 
 func TestReconciliateRepository(t *testing.T) {
@@ -90,7 +94,7 @@ func TestReconciliateRepository(t *testing.T) {
 						LastUpdate: metaTime(now.Add(-15 * time.Minute)),
 					},
 					Releases: []v1alpha1.GitHubRelease{
-						{Tag: "v1.0.0"},
+						{Tag: "v1.0.0", SHA: "123", CommitTime: metav1.NewTime(now)},
 						{Tag: "pr-sha1"},
 					},
 				},
@@ -156,6 +160,8 @@ func TestReconciliateRepository(t *testing.T) {
 					Tag:         "v1.0.0",
 					ReleaseTime: metav1.NewTime(now),
 					Level:       v1alpha1.SemVerLevelRelease,
+					SHA:         "123",
+					CommitTime:  metav1.NewTime(now),
 				},
 				{
 					Name:        "v2.0.0",
@@ -166,6 +172,7 @@ func TestReconciliateRepository(t *testing.T) {
 				{
 					Name:        "123",
 					Tag:         "456",
+					SHA:         "456",
 					ReleaseTime: metav1.NewTime(now),
 					Level:       v1alpha1.SemVerLevelPreview,
 				},
@@ -311,6 +318,153 @@ func TestReconciliateRepository(t *testing.T) {
 	}
 }
 
+func TestResolveCommits(t *testing.T) {
+	committed := time.Date(2018, time.April, 28, 13, 42, 01, 0, time.UTC)
+	known := metav1.NewTime(committed.Add(-time.Hour))
+
+	ghp := &v1alpha1.GitHubRepository{
+		ObjectMeta: metav1.ObjectMeta{Name: "heighliner", Namespace: "testing"},
+		Spec:       v1alpha1.GitHubRepositorySpec{Owner: "manifoldco", Repo: "heighliner"},
+		Status: v1alpha1.GitHubRepositoryStatus{
+			Releases: []v1alpha1.GitHubRelease{
+				{Tag: "v1.0.0", SHA: "123", CommitTime: known},
+				{Tag: "v2.0.0"},
+				{Tag: "456", SHA: "456", Level: v1alpha1.SemVerLevelPreview},
+				{Tag: "v3.0.0"},
+			},
+		},
+	}
+
+	var refs []string
+	client := &mockReconciliationClient{
+		GetCommitFn: func(ctx context.Context, owner, repo, ref string) (*github.RepositoryCommit, *github.Response, error) {
+			refs = append(refs, ref)
+			if ref == "v3.0.0" {
+				return nil, nil, errors.New("not found")
+			}
+
+			sha := "sha-" + ref
+			return &github.RepositoryCommit{
+				SHA:    &sha,
+				Commit: &github.Commit{Committer: &github.CommitAuthor{Date: &committed}},
+			}, nil, nil
+		},
+	}
+
+	failures := newCommitFailures()
+	resolveCommits(context.Background(), client, ghp, failures, time.Hour)
+
+	if expected := []string{"v2.0.0", "456", "v3.0.0"}; !reflect.DeepEqual(refs, expected) {
+		t.Errorf("Expected commits %v to be resolved, got %v", expected, refs)
+	}
+
+	expected := []v1alpha1.GitHubRelease{
+		{Tag: "v1.0.0", SHA: "123", CommitTime: known},
+		{Tag: "v2.0.0", SHA: "sha-v2.0.0", CommitTime: metav1.NewTime(committed)},
+		{Tag: "456", SHA: "sha-456", CommitTime: metav1.NewTime(committed), Level: v1alpha1.SemVerLevelPreview},
+		{Tag: "v3.0.0"},
+	}
+	if !reflect.DeepEqual(ghp.Status.Releases, expected) {
+		t.Errorf("Expected releases %+v, got %+v", expected, ghp.Status.Releases)
+	}
+
+	t.Run("skips failed commits", func(t *testing.T) {
+		refs = nil
+		resolveCommits(context.Background(), client, ghp, failures, time.Hour)
+
+		if len(refs) != 0 {
+			t.Errorf("Expected failed commits not to be resolved again, got %v", refs)
+		}
+	})
+
+	t.Run("retries failed commits after the period", func(t *testing.T) {
+		refs = nil
+		resolveCommits(context.Background(), client, ghp, failures, 0)
+
+		if expected := []string{"v3.0.0"}; !reflect.DeepEqual(refs, expected) {
+			t.Errorf("Expected commits %v to be resolved, got %v", expected, refs)
+		}
+	})
+
+	t.Run("retries failed commits after a webhook", func(t *testing.T) {
+		failures.reset(repositoryKey("testing", "heighliner"))
+
+		refs = nil
+		resolveCommits(context.Background(), client, ghp, failures, time.Hour)
+
+		if expected := []string{"v3.0.0"}; !reflect.DeepEqual(refs, expected) {
+			t.Errorf("Expected commits %v to be resolved, got %v", expected, refs)
+		}
+	})
+}
+
+func TestNeedsCommits(t *testing.T) {
+	ghp := &v1alpha1.GitHubRepository{
+		ObjectMeta: metav1.ObjectMeta{Name: "heighliner", Namespace: "testing"},
+	}
+
+	policies := map[string]v1alpha1.VersioningPolicySpec{
+		"semver": {SemVer: &v1alpha1.SemVerSource{}},
+		"commit": {Commit: &v1alpha1.CommitSource{}},
+		"idle": {
+			SemVer:        &v1alpha1.SemVerSource{},
+			PreviewExpiry: &v1alpha1.PreviewExpiry{IdleTimeout: &metav1.Duration{Duration: time.Hour}},
+		},
+	}
+
+	cl := &tester.PatchClient{
+		GetFunc: func(obj interface{}, namespace, name string) error {
+			spec, ok := policies[name]
+			if !ok || namespace != "testing" {
+				return kerrors.NewNotFound(schema.GroupResource{Resource: "versioningpolicies"}, name)
+			}
+
+			obj.(*v1alpha1.VersioningPolicy).Spec = spec
+			return nil
+		},
+	}
+
+	imagePolicy := func(namespace, repo, policy string) v1alpha1.ImagePolicy {
+		return v1alpha1.ImagePolicy{
+			ObjectMeta: metav1.ObjectMeta{Name: "hello-world", Namespace: namespace},
+			Spec: v1alpha1.ImagePolicySpec{
+				VersioningPolicy: v1.ObjectReference{Name: policy, Namespace: "testing"},
+				Filter: v1alpha1.ImagePolicyFilter{
+					GitHub: &v1.ObjectReference{Name: repo},
+				},
+			},
+		}
+	}
+
+	testCases := []struct {
+		desc     string
+		policies []v1alpha1.ImagePolicy
+		expected bool
+	}{
+		{"without policies", nil, false},
+		{"semver", []v1alpha1.ImagePolicy{imagePolicy("testing", "heighliner", "semver")}, false},
+		{"commit", []v1alpha1.ImagePolicy{imagePolicy("testing", "heighliner", "commit")}, true},
+		{"idle previews", []v1alpha1.ImagePolicy{imagePolicy("testing", "heighliner", "idle")}, true},
+		{"other repository", []v1alpha1.ImagePolicy{imagePolicy("testing", "manifold", "commit")}, false},
+		{"other namespace", []v1alpha1.ImagePolicy{imagePolicy("default", "heighliner", "commit")}, false},
+		{"missing policy", []v1alpha1.ImagePolicy{
+			imagePolicy("testing", "heighliner", "missing"),
+			imagePolicy("testing", "heighliner", "commit"),
+		}, true},
+		{"pinned", []v1alpha1.ImagePolicy{{
+			ObjectMeta: metav1.ObjectMeta{Name: "hello-world", Namespace: "testing"},
+		}}, false},
+	}
+
+	for _, tC := range testCases {
+		t.Run(tC.desc, func(t *testing.T) {
+			if needs := needsCommits(cl, ghp, tC.policies); needs != tC.expected {
+				t.Errorf("Expected %t, got %t", tC.expected, needs)
+			}
+		})
+	}
+}
+
 type mockReconciliationClient struct {
 	GetLatestReleaseFn func(ctx context.Context, owner, repo string) (*github.RepositoryRelease,
 		*github.Response, error)
@@ -318,6 +472,8 @@ type mockReconciliationClient struct {
 		[]*github.RepositoryRelease, *github.Response, error)
 	ListPullRequestsFn func(ctx context.Context, owner string, repo string,
 		opt *github.PullRequestListOptions) ([]*github.PullRequest, *github.Response, error)
+	GetCommitFn func(ctx context.Context, owner, repo, ref string) (*github.RepositoryCommit,
+		*github.Response, error)
 }
 
 func (m *mockReconciliationClient) GetLatestRelease(ctx context.Context, owner, repo string) (
@@ -334,3 +490,8 @@ func (m *mockReconciliationClient) ListPullRequests(ctx context.Context, owner,
 	opt *github.PullRequestListOptions) ([]*github.PullRequest, *github.Response, error) {
 	return m.ListPullRequestsFn(ctx, owner, repo, opt)
 }
+
+func (m *mockReconciliationClient) GetCommit(ctx context.Context, owner, repo, ref string) (
+	*github.RepositoryCommit, *github.Response, error) {
+	return m.GetCommitFn(ctx, owner, repo, ref)
+}
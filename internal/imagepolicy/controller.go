@@ -9,6 +9,7 @@ import (
 	"os/signal"
 	"sync"
 	"syscall"
+	"time"
 
 	"github.com/manifoldco/heighliner/apis/v1alpha1"
 	"github.com/manifoldco/heighliner/internal/registry"
@@ -134,16 +135,47 @@ func (c *Controller) run(ctx context.Context) {
 func (c *Controller) syncPolicy(obj interface{}) error {
 	ip := obj.(*v1alpha1.ImagePolicy).DeepCopy()
 
+	releases, reason, err := c.getReleases(ip)
+	if err != nil && reason == "" {
+		return err
+	}
+
+	if err != nil {
+		ip.Status.Conditions.MarkDegraded(reason, err.Error())
+	} else {
+		ip.Status.Releases = releases
+		ip.Status.Conditions.MarkReady("ReleasesSynced", "")
+	}
+	ip.Status.ObservedGeneration = ip.Generation
+
+	// need to specify types again until we resolve the mapping issue
+	ip.TypeMeta = metav1.TypeMeta{
+		Kind:       "ImagePolicy",
+		APIVersion: "hlnr.io/v1alpha1",
+	}
+
+	if _, err := c.patcher.Apply(ip); err != nil {
+		c.logger.Printf("Error syncing ImagePolicy %s (%s): %s", ip.Name, ip.Namespace, err)
+		return err
+	}
+
+	return nil
+}
+
+// getReleases returns the releases which match the ImagePolicy. When they
+// can't be retrieved, the reason for the Degraded condition is returned along
+// with the error.
+func (c *Controller) getReleases(ip *v1alpha1.ImagePolicy) ([]v1alpha1.Release, string, error) {
 	registry, err := getRegistry(c.patcher, ip)
 	if err != nil {
 		c.logger.Printf("Could not retrieve registry for %s: %s", ip.Name, err)
-		return nil
+		return nil, "RegistryError", err
 	}
 
 	vp, err := getVersioningPolicy(c.patcher, ip)
 	if err != nil {
 		c.logger.Printf("Could not retrieve VersioningPolicy for %s: %s", ip.Name, err)
-		return nil
+		return nil, "VersioningPolicyError", err
 	}
 
 	switch {
@@ -151,41 +183,30 @@ func (c *Controller) syncPolicy(obj interface{}) error {
 		repo, err := getGithubRepository(c.patcher, ip)
 		if err != nil {
 			c.logger.Printf("Could not retrieve GithubRepository for %s: %s", ip.Name, err)
-			return nil
+			return nil, "GitHubRepositoryError", err
 		}
 
-		ip.Status.Releases, err = filterImages(ip.Spec.Image, ip.Spec.Match, repo, registry, vp)
+		releases, err := filterImages(ip.Spec.Image, ip.Spec.Match, repo, registry, vp)
 		if err != nil {
 			c.logger.Printf("Could not filter images for %s: %s", ip.Name, err)
-			return nil
+			return nil, "FilterError", err
 		}
 
+		return releases, "", nil
 	case ip.Spec.Filter.Pinned != nil:
 		pinned := ip.Spec.Filter.Pinned
 
-		r := v1alpha1.Release{
-			SemVer: pinned,
-			Level:  vp.Spec.SemVer.Level,
-			Image:  ip.Spec.Image + ":" + pinned.Version,
+		r, err := vp.Spec.NewRelease(pinned.Name, pinned.Version)
+		if err != nil {
+			c.logger.Printf("Could not create pinned release for %s: %s", ip.Name, err)
+			return nil, "PinnedReleaseError", err
 		}
+		r.Image = ip.Spec.Image + ":" + pinned.Version
 
-		ip.Status.Releases = []v1alpha1.Release{r}
+		return []v1alpha1.Release{*r}, "", nil
 	default:
-		return errors.New("image spec filter not defined")
-	}
-
-	// need to specify types again until we resolve the mapping issue
-	ip.TypeMeta = metav1.TypeMeta{
-		Kind:       "ImagePolicy",
-		APIVersion: "hlnr.io/v1alpha1",
-	}
-
-	if _, err := c.patcher.Apply(ip); err != nil {
-		c.logger.Printf("Error syncing ImagePolicy %s (%s): %s", ip.Name, ip.Namespace, err)
-		return err
+		return nil, "", errors.New("image spec filter not defined")
 	}
-
-	return nil
 }
 
 func getGithubRepository(cl patchClient, ip *v1alpha1.ImagePolicy) (*v1alpha1.GitHubRepository, error) {
@@ -270,10 +291,32 @@ func filterImages(image string, matcher *v1alpha1.ImagePolicyMatch, repo *v1alph
 	releases := []v1alpha1.Release{}
 	for _, release := range repo.Status.Releases {
 
-		if release.Level != vp.Spec.SemVer.Level {
+		if release.Level != vp.Spec.Level() {
 			continue
 		}
 
+		// commit releases are versioned by their commit, which needs to be
+		// resolved by the GitHubRepository first.
+		version := release.Tag
+		if vp.Spec.Commit != nil {
+			if release.SHA == "" || release.CommitTime.IsZero() {
+				log.Printf("Release %s for tag %s has no resolved commit yet", release.Name, release.Tag)
+				continue
+			}
+
+			version = release.SHA
+		}
+
+		confirmedRelease, err := vp.Spec.NewRelease(release.Name, version)
+		if err == v1alpha1.ErrNoRegexMatch {
+			log.Printf("Release %s for tag %s does not match the VersioningPolicy", release.Name, release.Tag)
+			continue
+		}
+
+		if err != nil {
+			return nil, err
+		}
+
 		tag, err := reg.TagFor(image, release.Tag, matcher)
 		if registry.IsTagNotFoundError(err) {
 			log.Printf("Release %s for tag %s is not available in the registry", release.Name, release.Tag)
@@ -284,17 +327,18 @@ func filterImages(image string, matcher *v1alpha1.ImagePolicyMatch, repo *v1alph
 			return nil, err
 		}
 
-		confirmedRelease := v1alpha1.Release{
-			SemVer: &v1alpha1.SemVerRelease{
-				Name:    release.Name,
-				Version: release.Tag,
-			},
-			Level:       release.Level,
-			ReleaseTime: release.ReleaseTime,
-			Image:       image + ":" + tag,
+		confirmedRelease.Level = release.Level
+		confirmedRelease.ReleaseTime = release.ReleaseTime
+		confirmedRelease.Image = image + ":" + tag
+		confirmedRelease.Expired = vp.Spec.PreviewExpiry.Check(release, time.Now())
+		confirmedRelease.PullRequest = release.PullRequest
+		confirmedRelease.SHA = release.SHA
+
+		if confirmedRelease.Commit != nil {
+			confirmedRelease.Commit.Time = release.CommitTime
 		}
 
-		releases = append(releases, confirmedRelease)
+		releases = append(releases, *confirmedRelease)
 	}
 
 	return releases, nil
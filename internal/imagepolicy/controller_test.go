@@ -4,9 +4,11 @@ import (
 	"bytes"
 	"errors"
 	"fmt"
+	"io/ioutil"
 	"log"
 	"reflect"
 	"testing"
+	"time"
 
 	"github.com/jelmersnoeck/kubekit/patcher"
 	"github.com/manifoldco/heighliner/apis/v1alpha1"
@@ -306,6 +308,73 @@ func TestController_SyncPolicy(t *testing.T) {
 	}
 }
 
+func TestController_SyncPolicy_Conditions(t *testing.T) {
+	run := func(t *testing.T, ip *v1alpha1.ImagePolicy) *v1alpha1.ImagePolicy {
+		var applied *v1alpha1.ImagePolicy
+		c := &Controller{
+			patcher: &mockPatchClient{
+				GetFn: func(v interface{}, ns, name string) error {
+					if vp, ok := v.(*v1alpha1.VersioningPolicy); ok {
+						vp.Spec.SemVer = &v1alpha1.SemVerSource{Level: v1alpha1.SemVerLevelRelease}
+					}
+					return nil
+				},
+				ApplyFn: func(obj runtime.Object, _ ...patcher.OptionFunc) ([]byte, error) {
+					applied = obj.(*v1alpha1.ImagePolicy)
+					return nil, nil
+				},
+			},
+			logger: log.New(ioutil.Discard, "", 0),
+		}
+
+		if err := c.syncPolicy(ip); err != nil {
+			t.Fatalf("Expected no error, got %s", err)
+		}
+
+		return applied
+	}
+
+	t.Run("marks synced policies as ready", func(t *testing.T) {
+		applied := run(t, &v1alpha1.ImagePolicy{
+			ObjectMeta: metav1.ObjectMeta{Name: "ip-test", Generation: 2},
+			Spec: v1alpha1.ImagePolicySpec{
+				Image:             "hlnr/hello-world",
+				Filter:            v1alpha1.ImagePolicyFilter{Pinned: &v1alpha1.SemVerRelease{Version: "1.2.3"}},
+				ContainerRegistry: &v1alpha1.ContainerRegistry{Name: "mock", ImagePullSecrets: []v1.LocalObjectReference{{Name: "secret"}}},
+			},
+		})
+
+		if !applied.Status.Conditions.IsTrue(v1alpha1.ConditionReady) {
+			t.Errorf("Expected the ImagePolicy to be ready, got %+v", applied.Status.Conditions)
+		}
+
+		if applied.Status.ObservedGeneration != 2 {
+			t.Errorf("Expected the observed generation to be 2, got %d", applied.Status.ObservedGeneration)
+		}
+	})
+
+	t.Run("marks failing policies as degraded", func(t *testing.T) {
+		releases := []v1alpha1.Release{{Image: "hlnr/hello-world:1.2.2"}}
+		applied := run(t, &v1alpha1.ImagePolicy{
+			ObjectMeta: metav1.ObjectMeta{Name: "ip-test"},
+			Spec: v1alpha1.ImagePolicySpec{
+				Filter:            v1alpha1.ImagePolicyFilter{GitHub: &v1.ObjectReference{Name: "manifoldco"}},
+				ContainerRegistry: &v1alpha1.ContainerRegistry{Name: "azure", ImagePullSecrets: []v1.LocalObjectReference{{Name: "secret"}}},
+			},
+			Status: v1alpha1.ImagePolicyStatus{Releases: releases},
+		})
+
+		cond := applied.Status.Conditions.Get(v1alpha1.ConditionDegraded)
+		if cond == nil || cond.Status != v1.ConditionTrue || cond.Reason != "RegistryError" {
+			t.Errorf("Expected the ImagePolicy to be degraded, got %+v", applied.Status.Conditions)
+		}
+
+		if !reflect.DeepEqual(applied.Status.Releases, releases) {
+			t.Errorf("Expected the releases to be left untouched, got %+v", applied.Status.Releases)
+		}
+	})
+}
+
 func TestFilterImages(t *testing.T) {
 
 	repo := &v1alpha1.GitHubRepository{
@@ -387,6 +456,57 @@ func TestFilterImages(t *testing.T) {
 	}
 }
 
+func TestFilterImages_Commit(t *testing.T) {
+	committed := metav1.Date(2018, time.April, 28, 13, 42, 01, 0, time.UTC)
+	repo := &v1alpha1.GitHubRepository{
+		Status: v1alpha1.GitHubRepositoryStatus{
+			Releases: []v1alpha1.GitHubRelease{
+				{
+					Name:       "heighliner",
+					Tag:        "v1.2.3",
+					Level:      v1alpha1.SemVerLevelRelease,
+					SHA:        "8d2ab3f0c1b2a3d4e5f60718293a4b5c6d7e8f90",
+					CommitTime: committed,
+				},
+				{
+					Name:  "heighliner",
+					Tag:   "v1.2.4",
+					Level: v1alpha1.SemVerLevelRelease,
+				},
+			},
+		},
+	}
+
+	vp := &v1alpha1.VersioningPolicy{
+		Spec: v1alpha1.VersioningPolicySpec{
+			Commit: &v1alpha1.CommitSource{Level: v1alpha1.SemVerLevelRelease},
+		},
+	}
+
+	releases, err := filterImages("manifoldco/heighliner", nil, repo, &mockRegistryClient{}, vp)
+	if err != nil {
+		t.Fatalf("Expected no error, got %s", err)
+	}
+
+	// the release without a resolved commit is skipped until it's resolved.
+	if len(releases) != 1 {
+		t.Fatalf("Expected 1 release, got %d", len(releases))
+	}
+
+	expected := &v1alpha1.CommitRelease{Name: "heighliner", SHA: "8d2ab3f0c1b2a3d4e5f60718293a4b5c6d7e8f90", Time: committed}
+	if !reflect.DeepEqual(releases[0].Commit, expected) {
+		t.Errorf("Expected commit %+v, got %+v", expected, releases[0].Commit)
+	}
+
+	if releases[0].Image != "manifoldco/heighliner:v1.2.3" {
+		t.Errorf("Expected the image to be tagged by the release tag, got %s", releases[0].Image)
+	}
+
+	if releases[0].SHA != expected.SHA {
+		t.Errorf("Expected the release to carry its commit, got %q", releases[0].SHA)
+	}
+}
+
 type mockRegistryClient struct{}
 
 func (c *mockRegistryClient) TagFor(image string, tag string, matcher *v1alpha1.ImagePolicyMatch) (string, error) {
@@ -403,6 +523,10 @@ func (p *mockPatchClient) Get(v interface{}, ns string, name string) error {
 }
 
 func (p *mockPatchClient) Apply(obj runtime.Object, opts ...patcher.OptionFunc) ([]byte, error) {
+	if p.ApplyFn == nil {
+		return nil, nil
+	}
+
 	return p.ApplyFn(obj, opts...)
 }
 
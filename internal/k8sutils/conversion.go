@@ -17,6 +17,11 @@ func PtrIntOrString(i intstr.IntOrString) *intstr.IntOrString {
 	return &i
 }
 
+// PtrInt32 converts a value of int32 to the pointer of that value.
+func PtrInt32(i int32) *int32 {
+	return &i
+}
+
 // PtrInt64 converts a value of int64 to the pointer of that value.
 func PtrInt64(i int64) *int64 {
 	return &i
@@ -0,0 +1,75 @@
+package k8sutils
+
+import (
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// AnnotationDeletionTimeout is the annotation which overrides how long a
+	// controller tries to clean up after an object before its finalizer is
+	// removed anyway, like "30m".
+	AnnotationDeletionTimeout = "hlnr.io/deletion-timeout"
+
+	// DefaultDeletionTimeout is how long a controller tries to clean up after
+	// an object by default.
+	DefaultDeletionTimeout = 15 * time.Minute
+)
+
+// HasFinalizer reports whether the object has the given finalizer.
+func HasFinalizer(obj metav1.Object, name string) bool {
+	for _, f := range obj.GetFinalizers() {
+		if f == name {
+			return true
+		}
+	}
+
+	return false
+}
+
+// AddFinalizer adds the given finalizer to the object, and reports whether it
+// wasn't there yet.
+func AddFinalizer(obj metav1.Object, name string) bool {
+	if HasFinalizer(obj, name) {
+		return false
+	}
+
+	obj.SetFinalizers(append(obj.GetFinalizers(), name))
+	return true
+}
+
+// RemoveFinalizer removes the given finalizer from the object, and reports
+// whether it was there.
+func RemoveFinalizer(obj metav1.Object, name string) bool {
+	var finalizers []string
+	for _, f := range obj.GetFinalizers() {
+		if f != name {
+			finalizers = append(finalizers, f)
+		}
+	}
+
+	removed := len(finalizers) != len(obj.GetFinalizers())
+	obj.SetFinalizers(finalizers)
+	return removed
+}
+
+// DeletionTimedOut reports whether the cleanup of an object which is being
+// deleted took longer than its deletion timeout. Finalizers of objects which
+// timed out are removed without cleaning up, so a broken external dependency
+// can't block a deletion forever.
+func DeletionTimedOut(obj metav1.Object, now time.Time) bool {
+	ts := obj.GetDeletionTimestamp()
+	if ts == nil {
+		return false
+	}
+
+	timeout := DefaultDeletionTimeout
+	if v, ok := obj.GetAnnotations()[AnnotationDeletionTimeout]; ok {
+		if d, err := time.ParseDuration(v); err == nil {
+			timeout = d
+		}
+	}
+
+	return now.Sub(ts.Time) > timeout
+}
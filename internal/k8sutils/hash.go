@@ -2,11 +2,16 @@ package k8sutils
 
 import (
 	"encoding/base32"
+	"fmt"
 	"strings"
 
 	"github.com/dchest/blake2b"
 )
 
+// MaxJobNameLength is the maximum length of the name of a Job, which is used
+// as the value of the job-name label of its pods.
+const MaxJobNameLength = 63
+
 var encoder = base32.HexEncoding.WithPadding(base32.NoPadding)
 
 // ShortHash creates a shortened hash from the given string. The hash is
@@ -17,3 +22,15 @@ func ShortHash(data string, len int) string {
 	b2b.Write([]byte(data))
 	return strings.ToLower(encoder.EncodeToString(b2b.Sum(nil)))
 }
+
+// TruncateName shortens a name to at most max characters. Names which are too
+// long are truncated and suffixed with a hash of the full name, so they stay
+// unique.
+func TruncateName(name string, max int) string {
+	if len(name) <= max {
+		return name
+	}
+
+	hash := ShortHash(name, 8)
+	return fmt.Sprintf("%s-%s", strings.TrimRight(name[:max-len(hash)-1], "-"), hash)
+}
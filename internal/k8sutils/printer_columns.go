@@ -0,0 +1,40 @@
+package k8sutils
+
+import (
+	"encoding/json"
+
+	"k8s.io/apiextensions-apiserver/pkg/client/clientset/clientset"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// PrinterColumn describes an additional column which `kubectl get` shows for
+// a CRD.
+type PrinterColumn struct {
+	Name        string `json:"name"`
+	Type        string `json:"type"`
+	JSONPath    string `json:"JSONPath"`
+	Description string `json:"description,omitempty"`
+	Priority    int32  `json:"priority,omitempty"`
+}
+
+// SetPrinterColumns configures the additional printer columns of the CRD with
+// the given full name, like "microservices.hlnr.io". The CRD types we build
+// against predate printer columns, so they're set through a merge patch.
+// Clusters which don't support printer columns ignore them.
+func SetPrinterColumns(cs clientset.Interface, name string, columns []PrinterColumn) error {
+	patch, err := printerColumnsPatch(columns)
+	if err != nil {
+		return err
+	}
+
+	_, err = cs.ApiextensionsV1beta1().CustomResourceDefinitions().Patch(name, types.MergePatchType, patch)
+	return err
+}
+
+func printerColumnsPatch(columns []PrinterColumn) ([]byte, error) {
+	return json.Marshal(map[string]interface{}{
+		"spec": map[string]interface{}{
+			"additionalPrinterColumns": columns,
+		},
+	})
+}
@@ -5,4 +5,13 @@ const (
 	// service key set by Heighliner. This way we always have at least one label
 	// available for LabelSelectors.
 	LabelServiceKey = "hlnr.io/service"
+
+	// LabelMicroserviceKey is used to annotate the objects of all releases of
+	// a Microservice with its name.
+	LabelMicroserviceKey = "hlnr.io/microservice.name"
+
+	// LabelLevelKey is used to annotate the pods of a release with its level.
+	// It's left out of the selectors of Deployments and Services, so it can be
+	// added to existing releases.
+	LabelLevelKey = "hlnr.io/microservice.level"
 )
@@ -40,13 +40,38 @@ func MicroserviceLabels(ms *v1alpha1.Microservice, r *v1alpha1.Release, parent m
 	labels := Labels(parent.GetLabels(), parent)
 
 	labels["hlnr.io/microservice.full_name"] = labelize(r.FullName(ms.Name))
-	labels["hlnr.io/microservice.name"] = labelize(ms.Name)
+	labels[LabelMicroserviceKey] = labelize(ms.Name)
 	labels["hlnr.io/microservice.release"] = labelize(r.Name())
 	labels["hlnr.io/microservice.version"] = labelize(r.Version())
+	labels[LabelLevelKey] = string(r.Level)
 
 	return labels
 }
 
+// MicroserviceSelector returns the labels which select the pods of all
+// releases of a Microservice.
+func MicroserviceSelector(ms *v1alpha1.Microservice) map[string]string {
+	return MicroserviceNameSelector(ms.Name)
+}
+
+// MicroserviceNameSelector returns the labels which select the pods of all
+// releases of the Microservice with the given name.
+func MicroserviceNameSelector(name string) map[string]string {
+	return map[string]string{
+		LabelMicroserviceKey: labelize(name),
+	}
+}
+
+// ReleaseSelector returns the labels which select the pods of a single release
+// of a Microservice.
+func ReleaseSelector(ms *v1alpha1.Microservice, r *v1alpha1.Release) map[string]string {
+	return map[string]string{
+		LabelMicroserviceKey:           labelize(ms.Name),
+		"hlnr.io/microservice.release": labelize(r.Name()),
+		"hlnr.io/microservice.version": labelize(r.Version()),
+	}
+}
+
 // trim a string to at most len runes from a utf8 byte sequence.
 func trim(s string, l int) string {
 	var ns string
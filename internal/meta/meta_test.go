@@ -27,6 +27,7 @@ func TestMicroserviceLabels(t *testing.T) {
 		"hlnr.io/microservice.full_name": "test-pr-ebq4dofr-svek39uq",
 		"hlnr.io/microservice.release":   "a-branch",
 		"hlnr.io/microservice.version":   "0.0.1",
+		"hlnr.io/microservice.level":     "preview",
 	}
 
 	if !reflect.DeepEqual(l, expected) {
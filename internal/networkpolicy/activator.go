@@ -0,0 +1,120 @@
+package networkpolicy
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/manifoldco/heighliner/apis/v1alpha1"
+	"github.com/manifoldco/heighliner/internal/activator"
+	"github.com/manifoldco/heighliner/internal/meta"
+
+	"github.com/jelmersnoeck/kubekit"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+// activatorPortName is the name of the single port of the activator Service.
+// The activator serves all ports of a release on it, and proxies traffic to
+// the port of the domain it was sent to.
+const activatorPortName = "activator"
+
+// usesActivator returns whether traffic for the given release should be routed
+// through the activator. Only preview releases which expose ports are scaled
+// to zero.
+func usesActivator(np *v1alpha1.NetworkPolicy, release *v1alpha1.Release) bool {
+	return np.Spec.ScaleToZero != nil && len(np.Spec.Ports) != 0 && release.Level == v1alpha1.SemVerLevelPreview
+}
+
+func activatorServiceName(ms *v1alpha1.Microservice, release *v1alpha1.Release) string {
+	return release.StreamName(ms.Name) + "-activator"
+}
+
+// buildActivatorService builds the Service which routes traffic for the
+// external release through the activator. It's an ExternalName Service, which
+// allows the Ingress in the namespace of the Microservice to reach the
+// activator in the Heighliner namespace.
+func buildActivatorService(ms *v1alpha1.Microservice, np *v1alpha1.NetworkPolicy, release *v1alpha1.Release, srv metav1.Object) (*corev1.Service, error) {
+	routes := map[string]int32{}
+	for _, record := range np.Spec.ExternalDNS {
+		domain, err := templatedDomain(ms, release, record.Domain)
+		if err != nil {
+			return nil, err
+		}
+
+		portName := "headless"
+		if record.Port != "" {
+			portName = record.Port
+		}
+
+		port, ok := networkPort(np.Spec.Ports, portName)
+		if !ok {
+			return nil, fmt.Errorf("unknown port '%s' for domain %s", portName, domain)
+		}
+
+		routes[domain] = port.Port
+	}
+
+	routesData, err := json.Marshal(routes)
+	if err != nil {
+		return nil, err
+	}
+
+	labels := map[string]string{}
+	for k, v := range meta.MicroserviceLabels(ms, release, np) {
+		labels[k] = v
+	}
+	labels[activator.LabelActivator] = "true"
+
+	annotations := meta.Annotations(nil, v1alpha1.Version, np)
+	annotations[activator.AnnotationDeployment] = release.FullName(ms.Name)
+	annotations[activator.AnnotationService] = release.StreamName(ms.Name)
+	annotations[activator.AnnotationIdleTimeout] = np.Spec.ScaleToZero.IdleTimeout.Duration.String()
+	annotations[activator.AnnotationRoutes] = string(routesData)
+
+	host := np.Spec.ScaleToZero.Activator
+	if host == "" {
+		host = activator.DefaultHost
+	}
+
+	return &corev1.Service{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       "Service",
+			APIVersion: "v1",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        activatorServiceName(ms, release),
+			Namespace:   ms.Namespace,
+			Labels:      labels,
+			Annotations: annotations,
+			OwnerReferences: []metav1.OwnerReference{
+				*metav1.NewControllerRef(
+					srv,
+					corev1.SchemeGroupVersion.WithKind(kubekit.TypeName(srv)),
+				),
+			},
+		},
+		Spec: corev1.ServiceSpec{
+			Type:         corev1.ServiceTypeExternalName,
+			ExternalName: host,
+			Ports: []corev1.ServicePort{
+				{
+					Protocol:   corev1.ProtocolTCP,
+					Name:       activatorPortName,
+					Port:       activator.Port,
+					TargetPort: intstr.FromInt(activator.Port),
+				},
+			},
+		},
+	}, nil
+}
+
+func networkPort(ports []v1alpha1.NetworkPort, name string) (v1alpha1.NetworkPort, bool) {
+	for _, port := range ports {
+		if port.Name == name {
+			return port, true
+		}
+	}
+
+	return v1alpha1.NetworkPort{}, false
+}
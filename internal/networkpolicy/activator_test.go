@@ -0,0 +1,175 @@
+package networkpolicy
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/manifoldco/heighliner/apis/v1alpha1"
+	"github.com/manifoldco/heighliner/internal/activator"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+func TestBuildActivatorService(t *testing.T) {
+	ms := &v1alpha1.Microservice{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "hello-world",
+			Namespace: "testing",
+		},
+	}
+
+	release := &v1alpha1.Release{
+		SemVer: &v1alpha1.SemVerRelease{
+			Name:    "my-branch",
+			Version: "8d2ab3f",
+		},
+		Level: v1alpha1.SemVerLevelPreview,
+	}
+
+	np := &v1alpha1.NetworkPolicy{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "hello-world",
+		},
+		Spec: v1alpha1.NetworkPolicySpec{
+			Ports: []v1alpha1.NetworkPort{
+				{Name: "headless", Port: 80, TargetPort: 8080},
+			},
+			ExternalDNS: []v1alpha1.ExternalDNS{
+				{Domain: "{{.StreamName}}.pr.hlnr.io"},
+			},
+			ScaleToZero: &v1alpha1.ScaleToZero{
+				IdleTimeout: metav1.Duration{Duration: 30 * time.Minute},
+			},
+		},
+	}
+
+	srv := &corev1.Service{
+		TypeMeta: metav1.TypeMeta{
+			Kind: "Service",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name: release.StreamName(ms.Name),
+		},
+	}
+
+	t.Run("routes previews through the activator", func(t *testing.T) {
+		if !usesActivator(np, release) {
+			t.Fatal("Expected preview release to use the activator")
+		}
+
+		actSrv, err := buildActivatorService(ms, np, release, srv)
+		if err != nil {
+			t.Fatalf("Expected no error, got %s", err)
+		}
+
+		if actSrv.Spec.Type != corev1.ServiceTypeExternalName || actSrv.Spec.ExternalName != activator.DefaultHost {
+			t.Errorf("Expected ExternalName Service for %s, got %s %s", activator.DefaultHost, actSrv.Spec.Type, actSrv.Spec.ExternalName)
+		}
+
+		if actSrv.Labels[activator.LabelActivator] != "true" {
+			t.Errorf("Expected activator label to be set")
+		}
+
+		routes, err := activator.RoutesFromService(actSrv)
+		if err != nil {
+			t.Fatalf("Expected no error parsing the routes, got %s", err)
+		}
+
+		domain := srv.Name + ".pr.hlnr.io"
+		route, ok := routes[domain]
+		if !ok {
+			t.Fatalf("Expected a route for %s, got %v", domain, routes)
+		}
+
+		expected := activator.Route{
+			Target: activator.Target{
+				Namespace:   "testing",
+				Deployment:  release.FullName(ms.Name),
+				Service:     srv.Name,
+				IdleTimeout: 30 * time.Minute,
+			},
+			Port: 80,
+		}
+		if route != expected {
+			t.Errorf("Expected route %+v, got %+v", expected, route)
+		}
+
+		ings, err := buildIngressesForRelease(ms, np, release, srv, nil)
+		if err != nil {
+			t.Fatalf("Expected no error, got %s", err)
+		}
+
+		if backend := ings[0].Spec.Rules[0].HTTP.Paths[0].Backend.ServiceName; backend != actSrv.Name {
+			t.Errorf("Expected Ingress to route to %s, got %s", actSrv.Name, backend)
+		}
+	})
+
+	t.Run("with multiple ports", func(t *testing.T) {
+		np := np.DeepCopy()
+		np.Spec.Ports = []v1alpha1.NetworkPort{
+			{Name: "headless", Port: 80, TargetPort: 8080},
+			{Name: "grpc", Port: 9090, TargetPort: 9090},
+		}
+		np.Spec.ExternalDNS = []v1alpha1.ExternalDNS{
+			{Domain: "{{.StreamName}}.pr.hlnr.io"},
+			{Domain: "{{.StreamName}}.grpc.pr.hlnr.io", Port: "grpc"},
+		}
+
+		actSrv, err := buildActivatorService(ms, np, release, srv)
+		if err != nil {
+			t.Fatalf("Expected no error, got %s", err)
+		}
+
+		// the activator proxies to the port of the domain, so it only needs a
+		// single port.
+		expectedPorts := []corev1.ServicePort{
+			{Name: "activator", Protocol: corev1.ProtocolTCP, Port: activator.Port, TargetPort: intstr.FromInt(activator.Port)},
+		}
+		if !reflect.DeepEqual(actSrv.Spec.Ports, expectedPorts) {
+			t.Errorf("Expected ports %+v, got %+v", expectedPorts, actSrv.Spec.Ports)
+		}
+
+		routes, err := activator.RoutesFromService(actSrv)
+		if err != nil {
+			t.Fatalf("Expected no error parsing the routes, got %s", err)
+		}
+
+		if port := routes[srv.Name+".grpc.pr.hlnr.io"].Port; port != 9090 {
+			t.Errorf("Expected the grpc domain to be routed to port 9090, got %d", port)
+		}
+
+		ings, err := buildIngressesForRelease(ms, np, release, srv, nil)
+		if err != nil {
+			t.Fatalf("Expected no error, got %s", err)
+		}
+
+		for _, ing := range ings {
+			for _, rule := range ing.Spec.Rules {
+				backend := rule.HTTP.Paths[0].Backend
+				if backend.ServiceName != actSrv.Name || backend.ServicePort != intstr.FromString("activator") {
+					t.Errorf("Expected %s to be routed to the activator port, got %+v", rule.Host, backend)
+				}
+			}
+		}
+	})
+
+	t.Run("with an unknown port", func(t *testing.T) {
+		np := np.DeepCopy()
+		np.Spec.ExternalDNS[0].Port = "unknown"
+
+		if _, err := buildActivatorService(ms, np, release, srv); err == nil {
+			t.Error("Expected an error for an unknown port")
+		}
+	})
+
+	t.Run("doesn't route releases through the activator", func(t *testing.T) {
+		release := release.DeepCopy()
+		release.Level = v1alpha1.SemVerLevelRelease
+
+		if usesActivator(np, release) {
+			t.Error("Didn't expect a release to use the activator")
+		}
+	})
+}
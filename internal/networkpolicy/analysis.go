@@ -0,0 +1,289 @@
+package networkpolicy
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"net/http"
+	"net/url"
+	"strconv"
+	"text/template"
+	"time"
+
+	"github.com/manifoldco/heighliner/apis/v1alpha1"
+)
+
+var (
+	// ErrNoMetricData is used when a Prometheus query didn't return any data.
+	ErrNoMetricData = errors.New("the query returned no data")
+
+	// ErrInvalidThreshold is used when a threshold of a canary metric isn't a
+	// finite number.
+	ErrInvalidThreshold = errors.New("the threshold is not a finite number")
+)
+
+// analyzer judges a canary release by comparing it to the baseline release.
+type analyzer interface {
+	// Analyze returns the result of each metric. It returns an error when
+	// the analysis couldn't be completed, in which case the canary release
+	// should neither be promoted nor rolled back.
+	Analyze(baseline, canary *v1alpha1.Release) ([]v1alpha1.CanaryMetricResult, error)
+}
+
+// prometheusAnalyzer runs the metrics of a CanaryAnalysis against Prometheus.
+type prometheusAnalyzer struct {
+	ms       *v1alpha1.Microservice
+	analysis *v1alpha1.CanaryAnalysis
+	client   *http.Client
+	now      time.Time
+}
+
+func newPrometheusAnalyzer(ms *v1alpha1.Microservice, analysis *v1alpha1.CanaryAnalysis, now time.Time) *prometheusAnalyzer {
+	return &prometheusAnalyzer{
+		ms:       ms,
+		analysis: analysis,
+		client:   &http.Client{Timeout: 10 * time.Second},
+		now:      now,
+	}
+}
+
+// Analyze queries each metric for the baseline and canary release and
+// compares them with the thresholds of the metric.
+func (a *prometheusAnalyzer) Analyze(baseline, canary *v1alpha1.Release) ([]v1alpha1.CanaryMetricResult, error) {
+	results := make([]v1alpha1.CanaryMetricResult, len(a.analysis.Metrics))
+
+	var analysisErr error
+	for i, metric := range a.analysis.Metrics {
+		result, err := a.analyzeMetric(metric, baseline, canary)
+		if err != nil {
+			result = v1alpha1.CanaryMetricResult{Name: metric.Name, Message: err.Error()}
+			if analysisErr == nil {
+				analysisErr = fmt.Errorf("metric %s: %s", metric.Name, err)
+			}
+		}
+
+		results[i] = result
+	}
+
+	return results, analysisErr
+}
+
+func (a *prometheusAnalyzer) analyzeMetric(metric v1alpha1.CanaryMetric, baseline, canary *v1alpha1.Release) (v1alpha1.CanaryMetricResult, error) {
+	result := v1alpha1.CanaryMetricResult{Name: metric.Name}
+
+	canaryValue, err := a.query(metric.Query, canary)
+	if err != nil {
+		return result, fmt.Errorf("canary: %s", err)
+	}
+
+	baselineValue, err := a.query(metric.Query, baseline)
+	if err != nil {
+		return result, fmt.Errorf("baseline: %s", err)
+	}
+
+	result.Canary = formatValue(canaryValue)
+	result.Baseline = formatValue(baselineValue)
+
+	// values which aren't finite, like the result of dividing by zero, can't
+	// be compared with the thresholds and fail the metric.
+	if !finite(canaryValue) {
+		result.Message = "canary value is not a finite number"
+		return result, nil
+	}
+
+	if metric.MaxBaselineRatio != "" && !finite(baselineValue) {
+		result.Message = "baseline value is not a finite number"
+		return result, nil
+	}
+
+	result.Passed = true
+
+	if metric.Max != "" {
+		max, err := parseThreshold(metric.Max)
+		if err != nil {
+			return result, fmt.Errorf("invalid max '%s'", metric.Max)
+		}
+
+		if canaryValue > max {
+			result.Passed = false
+			result.Message = fmt.Sprintf("canary value is above %s", metric.Max)
+			return result, nil
+		}
+	}
+
+	if metric.MaxBaselineRatio != "" {
+		ratio, err := parseThreshold(metric.MaxBaselineRatio)
+		if err != nil {
+			return result, fmt.Errorf("invalid maxBaselineRatio '%s'", metric.MaxBaselineRatio)
+		}
+
+		if canaryValue > baselineValue*ratio {
+			result.Passed = false
+			result.Message = fmt.Sprintf("canary value is above %s times the baseline value", metric.MaxBaselineRatio)
+		}
+	}
+
+	return result, nil
+}
+
+// validateAnalysis checks the thresholds of the metrics of a CanaryAnalysis,
+// so invalid thresholds are reported before a canary release is analyzed.
+func validateAnalysis(analysis *v1alpha1.CanaryAnalysis) error {
+	for _, metric := range analysis.Metrics {
+		thresholds := []struct{ name, value string }{
+			{"max", metric.Max},
+			{"maxBaselineRatio", metric.MaxBaselineRatio},
+		}
+
+		for _, threshold := range thresholds {
+			if threshold.value == "" {
+				continue
+			}
+
+			if _, err := parseThreshold(threshold.value); err != nil {
+				return fmt.Errorf("%s: %s '%s' of metric %s", ErrInvalidThreshold, threshold.name, threshold.value, metric.Name)
+			}
+		}
+	}
+
+	return nil
+}
+
+// parseThreshold parses the threshold of a metric, which has to be a finite
+// number.
+func parseThreshold(threshold string) (float64, error) {
+	value, err := strconv.ParseFloat(threshold, 64)
+	if err != nil {
+		return 0, err
+	}
+
+	if !finite(value) {
+		return 0, ErrInvalidThreshold
+	}
+
+	return value, nil
+}
+
+func finite(v float64) bool {
+	return !math.IsNaN(v) && !math.IsInf(v, 0)
+}
+
+func (a *prometheusAnalyzer) query(query string, release *v1alpha1.Release) (float64, error) {
+	promQL, err := templatedQuery(a.ms, release, query)
+	if err != nil {
+		return 0, err
+	}
+
+	u, err := url.Parse(a.analysis.Address)
+	if err != nil {
+		return 0, err
+	}
+
+	u.Path = u.Path + "/api/v1/query"
+	u.RawQuery = url.Values{
+		"query": {promQL},
+		"time":  {strconv.FormatInt(a.now.Unix(), 10)},
+	}.Encode()
+
+	resp, err := a.client.Get(u.String())
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+
+	var body struct {
+		Status string `json:"status"`
+		Error  string `json:"error"`
+		Data   struct {
+			ResultType string          `json:"resultType"`
+			Result     json.RawMessage `json:"result"`
+		} `json:"data"`
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return 0, fmt.Errorf("could not decode Prometheus response: %s", err)
+	}
+
+	if body.Status != "success" {
+		return 0, fmt.Errorf("Prometheus query failed: %s", body.Error)
+	}
+
+	return parseQueryResult(body.Data.ResultType, body.Data.Result)
+}
+
+// parseQueryResult extracts a single value from a scalar or vector result.
+func parseQueryResult(resultType string, result json.RawMessage) (float64, error) {
+	var sample []interface{}
+
+	switch resultType {
+	case "scalar":
+		if err := json.Unmarshal(result, &sample); err != nil {
+			return 0, err
+		}
+	case "vector":
+		var vector []struct {
+			Value []interface{} `json:"value"`
+		}
+		if err := json.Unmarshal(result, &vector); err != nil {
+			return 0, err
+		}
+
+		switch len(vector) {
+		case 0:
+			return 0, ErrNoMetricData
+		case 1:
+			sample = vector[0].Value
+		default:
+			return 0, fmt.Errorf("the query returned %d series instead of 1", len(vector))
+		}
+	default:
+		return 0, fmt.Errorf("unsupported result type '%s'", resultType)
+	}
+
+	if len(sample) != 2 {
+		return 0, ErrNoMetricData
+	}
+
+	value, ok := sample[1].(string)
+	if !ok {
+		return 0, fmt.Errorf("unexpected sample value %v", sample[1])
+	}
+
+	return strconv.ParseFloat(value, 64)
+}
+
+func templatedQuery(ms *v1alpha1.Microservice, release *v1alpha1.Release, query string) (string, error) {
+	tmpl, err := template.New("query").Parse(query)
+	if err != nil {
+		return "", err
+	}
+
+	data := struct {
+		Namespace  string
+		Name       string
+		Release    string
+		Version    string
+		FullName   string
+		StreamName string
+	}{
+		Namespace:  ms.Namespace,
+		Name:       ms.Name,
+		Release:    release.Name(),
+		Version:    release.Version(),
+		FullName:   release.FullName(ms.Name),
+		StreamName: release.StreamName(ms.Name),
+	}
+
+	buf := &bytes.Buffer{}
+	if err := tmpl.Execute(buf, data); err != nil {
+		return "", err
+	}
+
+	return buf.String(), nil
+}
+
+func formatValue(v float64) string {
+	return strconv.FormatFloat(v, 'g', -1, 64)
+}
@@ -0,0 +1,169 @@
+package networkpolicy
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/manifoldco/heighliner/apis/v1alpha1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestPrometheusAnalyzer(t *testing.T) {
+	values := map[string]string{
+		`errors{service="hello-world-abc"}`:  `{"resultType":"vector","result":[{"metric":{},"value":[1524990000,"0.01"]}]}`,
+		`errors{service="hello-world-def"}`:  `{"resultType":"vector","result":[{"metric":{},"value":[1524990000,"0.2"]}]}`,
+		`latency{service="hello-world-abc"}`: `{"resultType":"scalar","result":[1524990000,"0.3"]}`,
+		`latency{service="hello-world-def"}`: `{"resultType":"scalar","result":[1524990000,"0.32"]}`,
+		`missing{service="hello-world-abc"}`: `{"resultType":"vector","result":[]}`,
+		`missing{service="hello-world-def"}`: `{"resultType":"vector","result":[]}`,
+		`ratio{service="hello-world-abc"}`:   `{"resultType":"scalar","result":[1524990000,"0.1"]}`,
+		`ratio{service="hello-world-def"}`:   `{"resultType":"scalar","result":[1524990000,"NaN"]}`,
+		`idle{service="hello-world-abc"}`:    `{"resultType":"scalar","result":[1524990000,"+Inf"]}`,
+		`idle{service="hello-world-def"}`:    `{"resultType":"scalar","result":[1524990000,"0.1"]}`,
+	}
+
+	prom := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v1/query" {
+			http.NotFound(w, r)
+			return
+		}
+
+		data, ok := values[r.URL.Query().Get("query")]
+		if !ok {
+			w.WriteHeader(http.StatusBadRequest)
+			fmt.Fprint(w, `{"status":"error","error":"unknown query"}`)
+			return
+		}
+
+		fmt.Fprintf(w, `{"status":"success","data":%s}`, data)
+	}))
+	defer prom.Close()
+
+	ms := &v1alpha1.Microservice{
+		ObjectMeta: metav1.ObjectMeta{Name: "hello-world", Namespace: "testing"},
+	}
+
+	baseline := &v1alpha1.Release{
+		SemVer: &v1alpha1.SemVerRelease{Name: "hello-world", Version: "1.0.0"},
+		Level:  v1alpha1.SemVerLevelRelease,
+	}
+
+	canary := &v1alpha1.Release{
+		SemVer: &v1alpha1.SemVerRelease{Name: "hello-world", Version: "1.1.0"},
+		Level:  v1alpha1.SemVerLevelRelease,
+	}
+
+	// map the generated release names to the fake series.
+	query := func(metric string) string {
+		return metric + `{service="{{if eq .Version "1.0.0"}}hello-world-abc{{else}}hello-world-def{{end}}"}`
+	}
+
+	analyze := func(metrics ...v1alpha1.CanaryMetric) ([]v1alpha1.CanaryMetricResult, error) {
+		analysis := &v1alpha1.CanaryAnalysis{Address: prom.URL, Metrics: metrics}
+		return newPrometheusAnalyzer(ms, analysis, time.Now()).Analyze(baseline, canary)
+	}
+
+	t.Run("with passing metrics", func(t *testing.T) {
+		results, err := analyze(v1alpha1.CanaryMetric{
+			Name:             "latency",
+			Query:            query("latency"),
+			Max:              "0.5",
+			MaxBaselineRatio: "1.1",
+		})
+		if err != nil {
+			t.Fatalf("Expected no error, got '%s'", err)
+		}
+
+		expected := v1alpha1.CanaryMetricResult{Name: "latency", Canary: "0.32", Baseline: "0.3", Passed: true}
+		if results[0] != expected {
+			t.Errorf("Expected result %+v, got %+v", expected, results[0])
+		}
+	})
+
+	t.Run("above the max value", func(t *testing.T) {
+		results, err := analyze(v1alpha1.CanaryMetric{Name: "errors", Query: query("errors"), Max: "0.05"})
+		if err != nil {
+			t.Fatalf("Expected no error, got '%s'", err)
+		}
+
+		if results[0].Passed || results[0].Canary != "0.2" {
+			t.Errorf("Expected the metric to fail, got %+v", results[0])
+		}
+	})
+
+	t.Run("above the baseline ratio", func(t *testing.T) {
+		results, err := analyze(v1alpha1.CanaryMetric{Name: "errors", Query: query("errors"), MaxBaselineRatio: "2"})
+		if err != nil {
+			t.Fatalf("Expected no error, got '%s'", err)
+		}
+
+		if results[0].Passed {
+			t.Errorf("Expected the metric to fail, got %+v", results[0])
+		}
+	})
+
+	t.Run("with a canary value which isn't a number", func(t *testing.T) {
+		results, err := analyze(v1alpha1.CanaryMetric{Name: "ratio", Query: query("ratio"), Max: "0.5"})
+		if err != nil {
+			t.Fatalf("Expected no error, got '%s'", err)
+		}
+
+		if results[0].Passed || results[0].Canary != "NaN" {
+			t.Errorf("Expected the metric to fail, got %+v", results[0])
+		}
+	})
+
+	t.Run("with an infinite baseline value", func(t *testing.T) {
+		results, err := analyze(v1alpha1.CanaryMetric{Name: "idle", Query: query("idle"), MaxBaselineRatio: "2"})
+		if err != nil {
+			t.Fatalf("Expected no error, got '%s'", err)
+		}
+
+		if results[0].Passed {
+			t.Errorf("Expected the metric to fail, got %+v", results[0])
+		}
+	})
+
+	t.Run("without data", func(t *testing.T) {
+		results, err := analyze(v1alpha1.CanaryMetric{Name: "missing", Query: query("missing"), Max: "1"})
+		if err == nil {
+			t.Fatal("Expected an error for a query without data")
+		}
+
+		if results[0].Passed || results[0].Message == "" {
+			t.Errorf("Expected the error to be recorded, got %+v", results[0])
+		}
+	})
+
+	t.Run("with a failing query", func(t *testing.T) {
+		if _, err := analyze(v1alpha1.CanaryMetric{Name: "unknown", Query: "unknown"}); err == nil {
+			t.Error("Expected an error for a failing query")
+		}
+	})
+}
+
+func TestValidateAnalysis(t *testing.T) {
+	tcs := []struct {
+		name   string
+		metric v1alpha1.CanaryMetric
+		valid  bool
+	}{
+		{"valid thresholds", v1alpha1.CanaryMetric{Name: "errors", Max: "0.05", MaxBaselineRatio: "1.5"}, true},
+		{"without thresholds", v1alpha1.CanaryMetric{Name: "errors"}, true},
+		{"unparsable max", v1alpha1.CanaryMetric{Name: "errors", Max: "5%"}, false},
+		{"infinite ratio", v1alpha1.CanaryMetric{Name: "errors", MaxBaselineRatio: "Inf"}, false},
+		{"max which isn't a number", v1alpha1.CanaryMetric{Name: "errors", Max: "NaN"}, false},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			err := validateAnalysis(&v1alpha1.CanaryAnalysis{Metrics: []v1alpha1.CanaryMetric{tc.metric}})
+			if (err == nil) != tc.valid {
+				t.Errorf("Expected valid to be %t, got %v", tc.valid, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,326 @@
+package networkpolicy
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/manifoldco/heighliner/apis/v1alpha1"
+	"github.com/manifoldco/heighliner/internal/k8sutils"
+	"github.com/manifoldco/heighliner/internal/meta"
+
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	clientv1 "k8s.io/client-go/kubernetes/typed/core/v1"
+)
+
+// verifier checks a release before it receives traffic.
+type verifier interface {
+	// Verify returns true once the release passed its verification. When
+	// it hasn't passed yet, the message describes why. An error is returned
+	// when the release failed its verification.
+	Verify(release *v1alpha1.Release) (bool, string, error)
+}
+
+// BlueGreenReleaser keeps the previously selected release of a stream live
+// until the latest release has been verified.
+type BlueGreenReleaser struct {
+	// Prefix is the name of the Microservice, used to name release streams.
+	Prefix string
+
+	Timeout  time.Duration
+	Verifier verifier
+
+	// Streams is the previous status of the release streams.
+	Streams []v1alpha1.StreamStatus
+
+	Now time.Time
+}
+
+// ExternalRelease selects the previously selected release until the latest
+// release has been verified.
+func (r *BlueGreenReleaser) ExternalRelease(releases []v1alpha1.Release) (*v1alpha1.Release, string, error) {
+	selection, err := r.SelectReleases(releases)
+	if err != nil {
+		return nil, "", err
+	}
+
+	return selection.Stable, selection.Reason, nil
+}
+
+// SelectReleases verifies the latest release and switches the stream over to
+// it once it passed its verification. A release which failed its verification
+// won't be verified again.
+func (r *BlueGreenReleaser) SelectReleases(releases []v1alpha1.Release) (*Selection, error) {
+	latest, stable, previous, err := selectStableRelease(r.Prefix, r.Streams, releases)
+	if err != nil {
+		return nil, err
+	}
+
+	if stable == nil {
+		return &Selection{Stable: latest, Reason: "latest release"}, nil
+	}
+
+	var status *v1alpha1.VerificationStatus
+	if v := previous.Verification; v != nil && v.Release == latest.Name() && v.Version == latest.Version() {
+		status = v.DeepCopy()
+	}
+
+	switch {
+	case status == nil:
+		status = &v1alpha1.VerificationStatus{
+			Release:   latest.Name(),
+			Version:   latest.Version(),
+			Phase:     v1alpha1.VerificationRunning,
+			StartTime: metav1.NewTime(r.Now.Truncate(time.Second)),
+		}
+	case status.Phase == v1alpha1.VerificationFailed:
+		return r.failed(stable, latest, status), nil
+	}
+
+	verified, msg, err := r.Verifier.Verify(latest)
+	switch {
+	case err != nil:
+		status.Phase = v1alpha1.VerificationFailed
+		status.Message = err.Error()
+		return r.failed(stable, latest, status), nil
+	case verified:
+		return &Selection{
+			Stable: latest,
+			Reason: fmt.Sprintf("verified %s %s", latest.Name(), latest.Version()),
+		}, nil
+	case r.Now.Sub(status.StartTime.Time) >= r.Timeout:
+		status.Phase = v1alpha1.VerificationFailed
+		status.Message = fmt.Sprintf("not verified within %s: %s", r.Timeout, msg)
+		return r.failed(stable, latest, status), nil
+	}
+
+	status.Message = msg
+	return &Selection{
+		Stable:       stable,
+		Verification: status,
+		Reason:       fmt.Sprintf("verifying %s %s", latest.Name(), latest.Version()),
+	}, nil
+}
+
+func (r *BlueGreenReleaser) failed(stable, latest *v1alpha1.Release, status *v1alpha1.VerificationStatus) *Selection {
+	return &Selection{
+		Stable:       stable,
+		Verification: status,
+		Reason:       fmt.Sprintf("verification of %s %s failed", latest.Name(), latest.Version()),
+	}
+}
+
+// releaseVerifier verifies a release by waiting for its Service to have ready
+// endpoints, after which it runs the configured Verification.
+type releaseVerifier struct {
+	cs           clientv1.CoreV1Interface
+	cl           patchClient
+	ms           *v1alpha1.Microservice
+	np           *v1alpha1.NetworkPolicy
+	verification *v1alpha1.Verification
+	client       *http.Client
+
+	// serviceURL returns the URL of the Service of a release.
+	serviceURL func(ms *v1alpha1.Microservice, release *v1alpha1.Release, port int32) string
+}
+
+func newVerifier(cs clientv1.CoreV1Interface, cl patchClient, ms *v1alpha1.Microservice, np *v1alpha1.NetworkPolicy, verification *v1alpha1.Verification) *releaseVerifier {
+	return &releaseVerifier{
+		cs:           cs,
+		cl:           cl,
+		ms:           ms,
+		np:           np,
+		verification: verification,
+		client:       &http.Client{Timeout: 10 * time.Second},
+		serviceURL:   releaseServiceURL,
+	}
+}
+
+// Verify verifies the given release.
+func (v *releaseVerifier) Verify(release *v1alpha1.Release) (bool, string, error) {
+	// without ports, there's no Service to switch over.
+	if len(v.np.Spec.Ports) == 0 {
+		return true, "", nil
+	}
+
+	ready, err := v.ready(release)
+	if err != nil || !ready {
+		return false, "waiting for the release to become ready", err
+	}
+
+	switch {
+	case v.verification == nil:
+		return true, "", nil
+	case v.verification.HTTP != nil:
+		return v.verifyHTTP(release, v.verification.HTTP)
+	case v.verification.Job != nil:
+		return v.verifyJob(release, v.verification.Job)
+	}
+
+	return true, "", nil
+}
+
+// ready checks if the Service of the release has ready endpoints.
+func (v *releaseVerifier) ready(release *v1alpha1.Release) (bool, error) {
+	ep, err := v.cs.Endpoints(v.ms.Namespace).Get(release.FullName(v.ms.Name), metav1.GetOptions{})
+	switch {
+	case errors.IsNotFound(err):
+		return false, nil
+	case err != nil:
+		return false, err
+	}
+
+	for _, subset := range ep.Subsets {
+		if len(subset.Addresses) > 0 {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+func (v *releaseVerifier) verifyHTTP(release *v1alpha1.Release, check *v1alpha1.HTTPVerification) (bool, string, error) {
+	port, err := verificationPort(v.np, check.Port)
+	if err != nil {
+		return false, "", err
+	}
+
+	path := check.Path
+	if path == "" {
+		path = "/"
+	}
+
+	status := int(check.Status)
+	if status == 0 {
+		status = http.StatusOK
+	}
+
+	resp, err := v.client.Get(v.serviceURL(v.ms, release, port.Port) + path)
+	if err != nil {
+		return false, err.Error(), nil
+	}
+	resp.Body.Close()
+
+	if resp.StatusCode != status {
+		return false, fmt.Sprintf("expected status %d from %s, got %d", status, path, resp.StatusCode), nil
+	}
+
+	return true, "", nil
+}
+
+func (v *releaseVerifier) verifyJob(release *v1alpha1.Release, spec *batchv1.JobSpec) (bool, string, error) {
+	job := &batchv1.Job{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       "Job",
+			APIVersion: "batch/v1",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      verificationJobName(v.ms, release),
+			Namespace: v.ms.Namespace,
+		},
+	}
+
+	err := v.cl.Get(job, job.Namespace, job.Name)
+	switch {
+	case errors.IsNotFound(err):
+		job, err := buildVerificationJob(v.ms, v.np, release, spec, v.serviceURL)
+		if err != nil {
+			return false, "", err
+		}
+
+		if _, err := v.cl.Apply(job); err != nil {
+			return false, fmt.Sprintf("could not start verification Job: %s", err), nil
+		}
+
+		return false, "verification Job started", nil
+	case err != nil:
+		return false, fmt.Sprintf("could not get verification Job: %s", err), nil
+	}
+
+	if job.Status.Succeeded > 0 {
+		return true, "", nil
+	}
+
+	for _, cond := range job.Status.Conditions {
+		if cond.Type == batchv1.JobFailed && cond.Status == corev1.ConditionTrue {
+			return false, "", fmt.Errorf("verification Job %s failed: %s", job.Name, cond.Message)
+		}
+	}
+
+	return false, "verification Job running", nil
+}
+
+// verificationJobName returns the name of the verification Job of a release.
+func verificationJobName(ms *v1alpha1.Microservice, release *v1alpha1.Release) string {
+	return k8sutils.TruncateName(release.FullName(ms.Name)+"-verify", k8sutils.MaxJobNameLength)
+}
+
+func buildVerificationJob(ms *v1alpha1.Microservice, np *v1alpha1.NetworkPolicy, release *v1alpha1.Release, spec *batchv1.JobSpec, serviceURL func(*v1alpha1.Microservice, *v1alpha1.Release, int32) string) (*batchv1.Job, error) {
+	port, err := verificationPort(np, "")
+	if err != nil {
+		return nil, err
+	}
+
+	jobSpec := spec.DeepCopy()
+	if jobSpec.Template.Spec.RestartPolicy == "" {
+		jobSpec.Template.Spec.RestartPolicy = corev1.RestartPolicyNever
+	}
+
+	env := corev1.EnvVar{
+		Name:  "HLNR_SERVICE_URL",
+		Value: serviceURL(ms, release, port.Port),
+	}
+	for i := range jobSpec.Template.Spec.Containers {
+		c := &jobSpec.Template.Spec.Containers[i]
+		c.Env = append(c.Env, env)
+	}
+
+	return &batchv1.Job{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       "Job",
+			APIVersion: "batch/v1",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        verificationJobName(ms, release),
+			Namespace:   ms.Namespace,
+			Labels:      meta.MicroserviceLabels(ms, release, np),
+			Annotations: meta.Annotations(np.Annotations, v1alpha1.Version, np),
+			OwnerReferences: []metav1.OwnerReference{
+				*metav1.NewControllerRef(
+					np,
+					v1alpha1.SchemeGroupVersion.WithKind("NetworkPolicy"),
+				),
+			},
+		},
+		Spec: *jobSpec,
+	}, nil
+}
+
+// verificationPort returns the NetworkPort with the given name, which
+// defaults to headless. When no name is given and there's no headless port,
+// the first port is used.
+func verificationPort(np *v1alpha1.NetworkPolicy, name string) (v1alpha1.NetworkPort, error) {
+	lookup := name
+	if lookup == "" {
+		lookup = "headless"
+	}
+
+	for _, port := range np.Spec.Ports {
+		if port.Name == lookup {
+			return port, nil
+		}
+	}
+
+	if name == "" && len(np.Spec.Ports) > 0 {
+		return np.Spec.Ports[0], nil
+	}
+
+	return v1alpha1.NetworkPort{}, fmt.Errorf("unknown port '%s'", lookup)
+}
+
+func releaseServiceURL(ms *v1alpha1.Microservice, release *v1alpha1.Release, port int32) string {
+	return fmt.Sprintf("http://%s.%s.svc.cluster.local:%d", release.FullName(ms.Name), ms.Namespace, port)
+}
@@ -0,0 +1,306 @@
+package networkpolicy
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jelmersnoeck/kubekit/patcher"
+	"github.com/manifoldco/heighliner/apis/v1alpha1"
+	"github.com/manifoldco/heighliner/internal/k8sutils"
+	"github.com/manifoldco/heighliner/internal/tester"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+type fakeVerifier struct {
+	verified bool
+	msg      string
+	err      error
+}
+
+func (v *fakeVerifier) Verify(*v1alpha1.Release) (bool, string, error) {
+	return v.verified, v.msg, v.err
+}
+
+func TestBlueGreenReleaser(t *testing.T) {
+	releases := []v1alpha1.Release{
+		{
+			Image:       "1",
+			ReleaseTime: metav1.Date(2018, time.April, 28, 13, 42, 01, 0, time.UTC),
+			SemVer:      &v1alpha1.SemVerRelease{Name: "hello-world", Version: "1.0.0"},
+			Level:       v1alpha1.SemVerLevelRelease,
+		},
+		{
+			Image:       "2",
+			ReleaseTime: metav1.Date(2018, time.April, 29, 13, 42, 01, 0, time.UTC),
+			SemVer:      &v1alpha1.SemVerRelease{Name: "hello-world", Version: "1.1.0"},
+			Level:       v1alpha1.SemVerLevelRelease,
+		},
+	}
+
+	now := time.Date(2018, time.April, 29, 14, 0, 0, 0, time.UTC)
+	stream := v1alpha1.StreamStatus{
+		Name:    releases[0].StreamName("hello-world"),
+		Release: "hello-world",
+		Version: "1.0.0",
+	}
+
+	newReleaser := func(v verifier, streams ...v1alpha1.StreamStatus) *BlueGreenReleaser {
+		return &BlueGreenReleaser{
+			Prefix:   "hello-world",
+			Timeout:  10 * time.Minute,
+			Verifier: v,
+			Streams:  streams,
+			Now:      now,
+		}
+	}
+
+	t.Run("with a new stream", func(t *testing.T) {
+		selection, err := newReleaser(&fakeVerifier{}).SelectReleases(releases)
+		if err != nil {
+			t.Fatalf("Expected no error, got '%s'", err)
+		}
+
+		if selection.Stable.Image != "2" || selection.Verification != nil {
+			t.Errorf("Expected the latest release '2' without verification, got '%s'", selection.Stable.Image)
+		}
+	})
+
+	t.Run("while verifying", func(t *testing.T) {
+		selection, _ := newReleaser(&fakeVerifier{msg: "not ready"}, stream).SelectReleases(releases)
+		if selection.Stable.Image != "1" {
+			t.Errorf("Expected the previous release '1' to stay live, got '%s'", selection.Stable.Image)
+		}
+
+		status := selection.Verification
+		if status == nil || status.Phase != v1alpha1.VerificationRunning || status.Message != "not ready" {
+			t.Errorf("Expected a running verification, got %+v", status)
+		}
+	})
+
+	t.Run("with a verified release", func(t *testing.T) {
+		selection, _ := newReleaser(&fakeVerifier{verified: true}, stream).SelectReleases(releases)
+		if selection.Stable.Image != "2" || selection.Verification != nil {
+			t.Errorf("Expected to switch over to release '2', got '%s'", selection.Stable.Image)
+		}
+	})
+
+	t.Run("with a failed verification", func(t *testing.T) {
+		selection, _ := newReleaser(&fakeVerifier{err: errors.New("smoke test failed")}, stream).SelectReleases(releases)
+		if selection.Stable.Image != "1" {
+			t.Errorf("Expected the previous release '1' to stay live, got '%s'", selection.Stable.Image)
+		}
+
+		status := selection.Verification
+		if status == nil || status.Phase != v1alpha1.VerificationFailed || status.Message != "smoke test failed" {
+			t.Fatalf("Expected a failed verification, got %+v", status)
+		}
+
+		// a failed release isn't verified again.
+		previous := stream
+		previous.Verification = status
+		selection, _ = newReleaser(&fakeVerifier{verified: true}, previous).SelectReleases(releases)
+		if selection.Stable.Image != "1" {
+			t.Errorf("Expected the failed release not to be verified again")
+		}
+	})
+
+	t.Run("with a verification that times out", func(t *testing.T) {
+		previous := stream
+		previous.Verification = &v1alpha1.VerificationStatus{
+			Release:   "hello-world",
+			Version:   "1.1.0",
+			Phase:     v1alpha1.VerificationRunning,
+			StartTime: metav1.NewTime(now.Add(-15 * time.Minute)),
+		}
+
+		selection, _ := newReleaser(&fakeVerifier{msg: "not ready"}, previous).SelectReleases(releases)
+		if status := selection.Verification; status == nil || status.Phase != v1alpha1.VerificationFailed {
+			t.Errorf("Expected the verification to time out, got %+v", status)
+		}
+	})
+}
+
+func TestReleaseVerifier(t *testing.T) {
+	ms := &v1alpha1.Microservice{
+		ObjectMeta: metav1.ObjectMeta{Name: "hello-world", Namespace: "testing"},
+	}
+
+	release := &v1alpha1.Release{
+		SemVer: &v1alpha1.SemVerRelease{Name: "hello-world", Version: "1.1.0"},
+		Level:  v1alpha1.SemVerLevelRelease,
+	}
+
+	np := &v1alpha1.NetworkPolicy{
+		ObjectMeta: metav1.ObjectMeta{Name: "hello-world", Namespace: "testing"},
+		Spec: v1alpha1.NetworkPolicySpec{
+			Ports: []v1alpha1.NetworkPort{
+				{Name: "headless", Port: 80, TargetPort: 8080},
+			},
+		},
+	}
+
+	endpoints := &corev1.Endpoints{
+		ObjectMeta: metav1.ObjectMeta{Name: release.FullName(ms.Name), Namespace: "testing"},
+		Subsets: []corev1.EndpointSubset{
+			{Addresses: []corev1.EndpointAddress{{IP: "10.0.0.1"}}},
+		},
+	}
+
+	backend := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/_healthz" {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+	}))
+	defer backend.Close()
+
+	pc := new(tester.PatchClient)
+	newVerifier := func(verification *v1alpha1.Verification, objs ...runtime.Object) *releaseVerifier {
+		v := newVerifier(fake.NewSimpleClientset(objs...).Core(), pc, ms, np, verification)
+		v.serviceURL = func(*v1alpha1.Microservice, *v1alpha1.Release, int32) string {
+			return backend.URL
+		}
+
+		return v
+	}
+
+	t.Run("without ready endpoints", func(t *testing.T) {
+		verified, msg, err := newVerifier(nil).Verify(release)
+		if verified || err != nil || msg == "" {
+			t.Errorf("Expected to wait for the release, got %t '%s' %v", verified, msg, err)
+		}
+	})
+
+	t.Run("with ready endpoints", func(t *testing.T) {
+		if verified, _, err := newVerifier(nil, endpoints).Verify(release); !verified || err != nil {
+			t.Errorf("Expected the release to be verified, got %t %v", verified, err)
+		}
+	})
+
+	t.Run("with a passing HTTP check", func(t *testing.T) {
+		verification := &v1alpha1.Verification{HTTP: &v1alpha1.HTTPVerification{Path: "/_healthz"}}
+		if verified, msg, err := newVerifier(verification, endpoints).Verify(release); !verified || err != nil {
+			t.Errorf("Expected the release to be verified, got %t '%s' %v", verified, msg, err)
+		}
+	})
+
+	t.Run("with a failing HTTP check", func(t *testing.T) {
+		verification := &v1alpha1.Verification{HTTP: &v1alpha1.HTTPVerification{Path: "/"}}
+		verified, msg, err := newVerifier(verification, endpoints).Verify(release)
+		if verified || err != nil {
+			t.Errorf("Expected the check to be retried, got %t %v", verified, err)
+		}
+
+		if expected := "expected status 200 from /, got 503"; msg != expected {
+			t.Errorf("Expected message '%s', got '%s'", expected, msg)
+		}
+	})
+
+	t.Run("with a verification Job", func(t *testing.T) {
+		defer pc.Flush()
+
+		verification := &v1alpha1.Verification{
+			Job: &batchv1.JobSpec{
+				Template: corev1.PodTemplateSpec{
+					Spec: corev1.PodSpec{
+						Containers: []corev1.Container{{Name: "smoke", Image: "smoke-test"}},
+					},
+				},
+			},
+		}
+
+		var jobStatus *batchv1.JobStatus
+		pc.GetFunc = func(obj interface{}, namespace, name string) error {
+			if jobStatus == nil {
+				return kerrors.NewNotFound(schema.GroupResource{Group: "batch", Resource: "jobs"}, name)
+			}
+
+			obj.(*batchv1.Job).Status = *jobStatus
+			return nil
+		}
+
+		var applied *batchv1.Job
+		pc.ApplyFunc = func(obj runtime.Object, _ ...patcher.OptionFunc) ([]byte, error) {
+			applied = obj.(*batchv1.Job)
+			return nil, nil
+		}
+
+		v := newVerifier(verification, endpoints)
+		if verified, _, err := v.Verify(release); verified || err != nil {
+			t.Fatalf("Expected the Job to be started, got %t %v", verified, err)
+		}
+
+		if applied == nil {
+			t.Fatal("Expected a Job to be applied")
+		}
+
+		pod := applied.Spec.Template.Spec
+		if pod.RestartPolicy != corev1.RestartPolicyNever {
+			t.Errorf("Expected restart policy Never, got %s", pod.RestartPolicy)
+		}
+
+		if env := pod.Containers[0].Env; len(env) != 1 || env[0].Name != "HLNR_SERVICE_URL" || env[0].Value != backend.URL {
+			t.Errorf("Expected the Service URL to be passed, got %v", env)
+		}
+
+		jobStatus = &batchv1.JobStatus{Active: 1}
+		if verified, _, err := v.Verify(release); verified || err != nil {
+			t.Errorf("Expected the Job to be running, got %t %v", verified, err)
+		}
+
+		jobStatus = &batchv1.JobStatus{Succeeded: 1}
+		if verified, _, err := v.Verify(release); !verified || err != nil {
+			t.Errorf("Expected the release to be verified, got %t %v", verified, err)
+		}
+
+		jobStatus = &batchv1.JobStatus{
+			Conditions: []batchv1.JobCondition{
+				{Type: batchv1.JobFailed, Status: corev1.ConditionTrue, Message: "BackoffLimitExceeded"},
+			},
+		}
+		if _, _, err := v.Verify(release); err == nil {
+			t.Error("Expected an error for a failed Job")
+		}
+	})
+}
+
+func TestBuildVerificationJob_LongName(t *testing.T) {
+	ms := &v1alpha1.Microservice{
+		ObjectMeta: metav1.ObjectMeta{Name: "a-microservice-with-a-rather-long-descriptive-name", Namespace: "testing"},
+	}
+
+	release := &v1alpha1.Release{
+		SemVer: &v1alpha1.SemVerRelease{Name: "feature", Version: "1.1.0-feature"},
+		Level:  v1alpha1.SemVerLevelPreview,
+	}
+
+	np := &v1alpha1.NetworkPolicy{
+		ObjectMeta: metav1.ObjectMeta{Name: ms.Name, Namespace: "testing"},
+		Spec: v1alpha1.NetworkPolicySpec{
+			Ports: []v1alpha1.NetworkPort{{Name: "headless", Port: 80, TargetPort: 8080}},
+		},
+	}
+
+	serviceURL := func(*v1alpha1.Microservice, *v1alpha1.Release, int32) string { return "" }
+	job, err := buildVerificationJob(ms, np, release, &batchv1.JobSpec{}, serviceURL)
+	if err != nil {
+		t.Fatalf("Expected no error, got %s", err)
+	}
+
+	if len(job.Name) > k8sutils.MaxJobNameLength {
+		t.Errorf("Expected at most %d characters, got %d for %s", k8sutils.MaxJobNameLength, len(job.Name), job.Name)
+	}
+
+	if !strings.HasPrefix(job.Name, ms.Name) {
+		t.Errorf("Expected the name to start with the Microservice, got %s", job.Name)
+	}
+}
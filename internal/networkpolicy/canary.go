@@ -0,0 +1,63 @@
+package networkpolicy
+
+import (
+	"strconv"
+
+	"github.com/manifoldco/heighliner/apis/v1alpha1"
+
+	"k8s.io/api/extensions/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	annotationCanary       = "nginx.ingress.kubernetes.io/canary"
+	annotationCanaryWeight = "nginx.ingress.kubernetes.io/canary-weight"
+)
+
+// buildCanaryIngresses builds Ingresses which send a share of the traffic for
+// the domains of the stable release to the Service of the canary release. They
+// rely on the canary annotations of the nginx ingress controller, so domains
+// handled by other controllers aren't part of the canary. Paths to other
+// Microservices aren't part of the canary either.
+func buildCanaryIngresses(ms *v1alpha1.Microservice, np *v1alpha1.NetworkPolicy, stable, canary *v1alpha1.Release, status *v1alpha1.CanaryStatus, srv metav1.Object, services map[string]string) ([]*v1beta1.Ingress, error) {
+	ings, err := buildIngressesForRelease(ms, np, stable, srv, services)
+	if err != nil {
+		return nil, err
+	}
+
+	var canaries []*v1beta1.Ingress
+	for i, records := range groupRecords(np.Spec.ExternalDNS) {
+		if ingressController(records[0]) != v1alpha1.IngressControllerNginx {
+			continue
+		}
+
+		ing := ings[i]
+		ing.Name = canaryIngressName(ing)
+
+		// the DNS records are managed through the Ingress of the stable release.
+		delete(ing.Annotations, "external-dns.alpha.kubernetes.io/hostname")
+		delete(ing.Annotations, "external-dns.alpha.kubernetes.io/ttl")
+
+		ing.Annotations[annotationCanary] = "true"
+		ing.Annotations[annotationCanaryWeight] = strconv.Itoa(int(status.Weight))
+
+		// paths to other Microservices keep their backend.
+		for i := range ing.Spec.Rules {
+			for j, path := range ing.Spec.Rules[i].HTTP.Paths {
+				if path.Backend.ServiceName == stable.StreamName(ms.Name) {
+					ing.Spec.Rules[i].HTTP.Paths[j].Backend.ServiceName = canary.FullName(ms.Name)
+				}
+			}
+		}
+
+		canaries = append(canaries, ing)
+	}
+
+	return canaries, nil
+}
+
+// canaryIngressName returns the name of the canary Ingress for the given
+// Ingress of a stable release.
+func canaryIngressName(ing *v1beta1.Ingress) string {
+	return ing.Name + "-canary"
+}
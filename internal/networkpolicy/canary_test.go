@@ -0,0 +1,95 @@
+package networkpolicy
+
+import (
+	"testing"
+
+	"github.com/manifoldco/heighliner/apis/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestBuildCanaryIngresses(t *testing.T) {
+	ms := &v1alpha1.Microservice{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "hello-world",
+			Namespace: "testing",
+		},
+	}
+
+	stable := &v1alpha1.Release{
+		SemVer: &v1alpha1.SemVerRelease{Name: "hello-world", Version: "1.0.0"},
+		Level:  v1alpha1.SemVerLevelRelease,
+	}
+
+	canary := &v1alpha1.Release{
+		SemVer: &v1alpha1.SemVerRelease{Name: "hello-world", Version: "1.1.0"},
+		Level:  v1alpha1.SemVerLevelRelease,
+	}
+
+	np := &v1alpha1.NetworkPolicy{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "hello-world",
+		},
+		Spec: v1alpha1.NetworkPolicySpec{
+			Ports: []v1alpha1.NetworkPort{
+				{Name: "headless", Port: 80, TargetPort: 8080},
+			},
+			ExternalDNS: []v1alpha1.ExternalDNS{
+				{
+					Domain: "{{.StreamName}}.hlnr.io",
+					Paths: []v1alpha1.HTTPPath{
+						{Path: "/"},
+						{Path: "/api", Microservice: &corev1.LocalObjectReference{Name: "api"}},
+					},
+				},
+				{Domain: "{{.StreamName}}.internal.hlnr.io", IngressClass: "traefik"},
+			},
+		},
+	}
+
+	srv := &corev1.Service{
+		TypeMeta: metav1.TypeMeta{
+			Kind: "Service",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name: stable.StreamName(ms.Name),
+		},
+	}
+
+	status := &v1alpha1.CanaryStatus{Release: "hello-world", Version: "1.1.0", Weight: 25}
+
+	ings, err := buildCanaryIngresses(ms, np, stable, canary, status, srv, map[string]string{"api": "api"})
+	if err != nil {
+		t.Fatalf("Expected no error, got %s", err)
+	}
+
+	if len(ings) != 1 {
+		t.Fatalf("Expected a canary Ingress for the nginx domains only, got %d", len(ings))
+	}
+
+	ing := ings[0]
+
+	if expected := "hello-world-canary"; ing.Name != expected {
+		t.Errorf("Expected Ingress name %s, got %s", expected, ing.Name)
+	}
+
+	if ing.Annotations[annotationCanary] != "true" || ing.Annotations[annotationCanaryWeight] != "25" {
+		t.Errorf("Expected canary annotations with weight 25, got %v", ing.Annotations)
+	}
+
+	if _, ok := ing.Annotations["external-dns.alpha.kubernetes.io/hostname"]; ok {
+		t.Error("Didn't expect the canary Ingress to manage DNS records")
+	}
+
+	if host := ing.Spec.Rules[0].Host; host != "hello-world.hlnr.io" {
+		t.Errorf("Expected host of the stable release, got %s", host)
+	}
+
+	if backend := ing.Spec.Rules[0].HTTP.Paths[0].Backend.ServiceName; backend != canary.FullName(ms.Name) {
+		t.Errorf("Expected Ingress to route to %s, got %s", canary.FullName(ms.Name), backend)
+	}
+
+	if backend := ing.Spec.Rules[0].HTTP.Paths[1].Backend.ServiceName; backend != "api" {
+		t.Errorf("Expected the path to the api Microservice to keep routing to api, got %s", backend)
+	}
+}
@@ -0,0 +1,285 @@
+package networkpolicy
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/manifoldco/heighliner/apis/v1alpha1"
+	"github.com/manifoldco/heighliner/internal/meta"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+const (
+	certManagerGroupVersion = "cert-manager.io/v1"
+
+	// defaultTLSGroup is the TLS group of records which don't specify one.
+	defaultTLSGroup = "heighliner-components"
+)
+
+// tlsGroup returns the TLS group of a record.
+func tlsGroup(record v1alpha1.ExternalDNS) string {
+	if record.TLSGroup != "" {
+		return record.TLSGroup
+	}
+
+	return defaultTLSGroup
+}
+
+// tlsSecretName returns the name of the secret which stores the certificate
+// for a record. Certificates managed by the NetworkPolicy are stored in a
+// secret of their own, named after the Certificate.
+func tlsSecretName(np *v1alpha1.NetworkPolicy, record v1alpha1.ExternalDNS) string {
+	if np.Spec.CertManager == nil {
+		return tlsGroup(record)
+	}
+
+	return certificateName(np, tlsGroup(record))
+}
+
+func certificateName(np *v1alpha1.NetworkPolicy, group string) string {
+	return np.Name + "-" + group
+}
+
+// syncCertificates applies the Certificates for the TLS groups of a
+// NetworkPolicy and reports their readiness. The Certificates of TLS groups
+// which were removed are deleted. A TLS group without live releases keeps its
+// Certificate, as its release streams may only have failed to sync. When the
+// Certificates can't be synced, the previous status is returned.
+func syncCertificates(cl patchClient, ms *v1alpha1.Microservice, np *v1alpha1.NetworkPolicy, releases []*v1alpha1.Release) ([]v1alpha1.CertificateStatus, error) {
+	certs, err := buildCertificates(ms, np, releases)
+	if err != nil {
+		return np.Status.Certificates, err
+	}
+
+	var statuses []v1alpha1.CertificateStatus
+	synced := map[string]bool{}
+	for _, cert := range certs {
+		if _, err := cl.Apply(cert); err != nil {
+			return np.Status.Certificates, err
+		}
+
+		status := v1alpha1.CertificateStatus{
+			Name:     cert.GetName(),
+			TLSGroup: cert.GetLabels()["hlnr.io/tls-group"],
+		}
+		status.DNSNames, _ = unstructured.NestedStringSlice(cert.Object, "spec", "dnsNames")
+
+		current := newUnstructured(certManagerGroupVersion, "Certificate", metav1.ObjectMeta{
+			Name:      cert.GetName(),
+			Namespace: cert.GetNamespace(),
+		})
+
+		err := cl.Get(current, current.GetNamespace(), current.GetName())
+		switch {
+		case errors.IsNotFound(err):
+			status.Message = "waiting for the Certificate to be created"
+		case err != nil:
+			return np.Status.Certificates, err
+		default:
+			status.Ready, status.Message = certificateReady(current)
+		}
+
+		statuses = append(statuses, status)
+		synced[status.Name] = true
+	}
+
+	var pruneErr error
+	groups := tlsGroups(np)
+	for _, previous := range np.Status.Certificates {
+		if synced[previous.Name] {
+			continue
+		}
+
+		if groups[previous.TLSGroup] {
+			statuses = append(statuses, previous)
+			continue
+		}
+
+		cert := newUnstructured(certManagerGroupVersion, "Certificate", metav1.ObjectMeta{
+			Name:      previous.Name,
+			Namespace: ms.Namespace,
+		})
+
+		// keep track of the Certificate until it's deleted.
+		if err := cl.Delete(cert); err != nil && !errors.IsNotFound(err) {
+			statuses = append(statuses, previous)
+			pruneErr = err
+		}
+	}
+
+	sort.Slice(statuses, func(i, j int) bool {
+		return statuses[i].Name < statuses[j].Name
+	})
+
+	return statuses, pruneErr
+}
+
+// tlsGroups returns the TLS groups which have a Certificate managed by the
+// NetworkPolicy.
+func tlsGroups(np *v1alpha1.NetworkPolicy) map[string]bool {
+	groups := map[string]bool{}
+	if np.Spec.CertManager == nil {
+		return groups
+	}
+
+	for _, record := range np.Spec.ExternalDNS {
+		if !record.DisableTLS {
+			groups[tlsGroup(record)] = true
+		}
+	}
+
+	return groups
+}
+
+// certificateReady returns the Ready condition of a Certificate.
+func certificateReady(cert *unstructured.Unstructured) (bool, string) {
+	conditions, _ := unstructured.NestedSlice(cert.Object, "status", "conditions")
+	for _, c := range conditions {
+		cond, ok := c.(map[string]interface{})
+		if !ok || cond["type"] != "Ready" {
+			continue
+		}
+
+		message, _ := cond["message"].(string)
+		return cond["status"] == "True", message
+	}
+
+	return false, "waiting for the Certificate to be issued"
+}
+
+// buildCertificates builds a Certificate for each TLS group of the
+// NetworkPolicy, covering the domains of the given releases. Templated
+// domains are covered by a wildcard when the template only affects the first
+// label of the domain.
+func buildCertificates(ms *v1alpha1.Microservice, np *v1alpha1.NetworkPolicy, releases []*v1alpha1.Release) ([]*unstructured.Unstructured, error) {
+	cm := np.Spec.CertManager
+	if cm == nil {
+		return nil, nil
+	}
+
+	groups := map[string]map[string]bool{}
+	for _, record := range np.Spec.ExternalDNS {
+		if record.DisableTLS {
+			continue
+		}
+
+		group := tlsGroup(record)
+		if groups[group] == nil {
+			groups[group] = map[string]bool{}
+		}
+
+		if !cm.DisableWildcards {
+			if wildcard, ok := wildcardDomain(record.Domain); ok {
+				groups[group][wildcard] = true
+				continue
+			}
+		}
+
+		for _, release := range releases {
+			domain, err := templatedDomain(ms, release, record.Domain)
+			if err != nil {
+				return nil, err
+			}
+
+			groups[group][domain] = true
+		}
+	}
+
+	names := make([]string, 0, len(groups))
+	for group := range groups {
+		names = append(names, group)
+	}
+	sort.Strings(names)
+
+	kind := cm.IssuerRef.Kind
+	if kind == "" {
+		kind = "Issuer"
+	}
+
+	var certs []*unstructured.Unstructured
+	for _, group := range names {
+		var dnsNames []interface{}
+		for _, domain := range sortedKeys(groups[group]) {
+			dnsNames = append(dnsNames, domain)
+		}
+
+		// without live releases, there's nothing to issue a certificate for.
+		if len(dnsNames) == 0 {
+			continue
+		}
+
+		cp := np.DeepCopy()
+		labels := meta.Labels(cp.Labels, cp)
+		labels["hlnr.io/tls-group"] = group
+
+		name := certificateName(np, group)
+		cert := newUnstructured(certManagerGroupVersion, "Certificate", metav1.ObjectMeta{
+			Name:        name,
+			Namespace:   ms.Namespace,
+			Labels:      labels,
+			Annotations: meta.Annotations(cp.Annotations, v1alpha1.Version, cp),
+			OwnerReferences: []metav1.OwnerReference{
+				*metav1.NewControllerRef(
+					np,
+					v1alpha1.SchemeGroupVersion.WithKind("NetworkPolicy"),
+				),
+			},
+		})
+
+		cert.Object["spec"] = map[string]interface{}{
+			"secretName": name,
+			"dnsNames":   dnsNames,
+			"issuerRef": map[string]interface{}{
+				"name":  cm.IssuerRef.Name,
+				"kind":  kind,
+				"group": "cert-manager.io",
+			},
+		}
+
+		certs = append(certs, cert)
+	}
+
+	return certs, nil
+}
+
+// wildcardDomain returns the wildcard domain which covers all domains the
+// given template results in. This is only possible when the template is
+// limited to the first label of the domain, like `{{.StreamName}}.hlnr.io`.
+func wildcardDomain(domain string) (string, bool) {
+	if !strings.Contains(domain, "{{") {
+		return "", false
+	}
+
+	// find the end of the first label, skipping the dots within actions.
+	var inAction bool
+	for i := 0; i < len(domain); i++ {
+		switch {
+		case strings.HasPrefix(domain[i:], "{{"):
+			inAction = true
+		case strings.HasPrefix(domain[i:], "}}"):
+			inAction = false
+		case domain[i] == '.' && !inAction:
+			parent := domain[i+1:]
+			if strings.Contains(parent, "{{") || !strings.Contains(parent, ".") {
+				return "", false
+			}
+
+			return "*." + parent, true
+		}
+	}
+
+	return "", false
+}
+
+func sortedKeys(m map[string]bool) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	return keys
+}
@@ -0,0 +1,171 @@
+package networkpolicy
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/jelmersnoeck/kubekit/patcher"
+	"github.com/manifoldco/heighliner/apis/v1alpha1"
+	"github.com/manifoldco/heighliner/internal/tester"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+func TestWildcardDomain(t *testing.T) {
+	tcs := []struct {
+		domain   string
+		wildcard string
+		ok       bool
+	}{
+		{"{{.StreamName}}.pr.hlnr.io", "*.pr.hlnr.io", true},
+		{"app-{{.Name}}-{{.StreamName}}.hlnr.io", "*.hlnr.io", true},
+		{"hlnr.io", "", false},
+		{"www.hlnr.io", "", false},
+		{"app.{{.StreamName}}.hlnr.io", "", false},
+		{"{{.StreamName}}.{{.Name}}.hlnr.io", "", false},
+		{"{{.StreamName}}.io", "", false},
+	}
+
+	for _, tc := range tcs {
+		wildcard, ok := wildcardDomain(tc.domain)
+		if wildcard != tc.wildcard || ok != tc.ok {
+			t.Errorf("Expected %s to result in (%q, %t), got (%q, %t)", tc.domain, tc.wildcard, tc.ok, wildcard, ok)
+		}
+	}
+}
+
+func TestSyncCertificates(t *testing.T) {
+	ms := &v1alpha1.Microservice{ObjectMeta: metav1.ObjectMeta{Name: "hello-world", Namespace: "testing"}}
+	np := &v1alpha1.NetworkPolicy{
+		ObjectMeta: metav1.ObjectMeta{Name: "hello-world", Namespace: "testing"},
+		Spec: v1alpha1.NetworkPolicySpec{
+			ExternalDNS: []v1alpha1.ExternalDNS{
+				{Domain: "hello-world.hlnr.io"},
+				{Domain: "{{.StreamName}}.pr.hlnr.io"},
+				{Domain: "{{.Name}}.internal.hlnr.io", TLSGroup: "internal"},
+				{Domain: "insecure.hlnr.io", DisableTLS: true},
+			},
+			CertManager: &v1alpha1.CertManager{
+				IssuerRef: v1alpha1.CertIssuerReference{Name: "letsencrypt", Kind: "ClusterIssuer"},
+			},
+		},
+	}
+
+	releases := []*v1alpha1.Release{
+		{SemVer: &v1alpha1.SemVerRelease{Name: "hello-world", Version: "1.0.0"}, Level: v1alpha1.SemVerLevelRelease},
+		{SemVer: &v1alpha1.SemVerRelease{Name: "my-branch", Version: "1.1.0-my-branch"}, Level: v1alpha1.SemVerLevelPreview},
+	}
+
+	var applied []*unstructured.Unstructured
+	pc := &tester.PatchClient{
+		ApplyFunc: func(obj runtime.Object, _ ...patcher.OptionFunc) ([]byte, error) {
+			applied = append(applied, obj.(*unstructured.Unstructured))
+			return nil, nil
+		},
+		GetFunc: func(obj interface{}, namespace, name string) error {
+			if name != "hello-world-heighliner-components" {
+				return nil
+			}
+
+			unstructured.SetNestedSlice(obj.(*unstructured.Unstructured).Object, []interface{}{
+				map[string]interface{}{"type": "Ready", "status": "True", "message": "Certificate is up to date"},
+			}, "status", "conditions")
+			return nil
+		},
+	}
+
+	t.Run("builds a Certificate per TLS group", func(t *testing.T) {
+		statuses, err := syncCertificates(pc, ms, np, releases)
+		if err != nil {
+			t.Fatalf("Expected no error, got %s", err)
+		}
+
+		if len(applied) != 2 {
+			t.Fatalf("Expected 2 Certificates, got %d", len(applied))
+		}
+
+		expected := []v1alpha1.CertificateStatus{
+			{
+				Name:     "hello-world-heighliner-components",
+				TLSGroup: "heighliner-components",
+				DNSNames: []string{"*.pr.hlnr.io", "hello-world.hlnr.io"},
+				Ready:    true,
+				Message:  "Certificate is up to date",
+			},
+			{
+				Name:     "hello-world-internal",
+				TLSGroup: "internal",
+				DNSNames: []string{"*.internal.hlnr.io"},
+				Message:  "waiting for the Certificate to be issued",
+			},
+		}
+		if !reflect.DeepEqual(statuses, expected) {
+			t.Errorf("Expected %+v, got %+v", expected, statuses)
+		}
+
+		issuer, _ := unstructured.NestedStringMap(applied[0].Object, "spec", "issuerRef")
+		if issuer["kind"] != "ClusterIssuer" || issuer["name"] != "letsencrypt" {
+			t.Errorf("Expected the ClusterIssuer letsencrypt, got %v", issuer)
+		}
+	})
+
+	t.Run("prunes the Certificates of removed TLS groups", func(t *testing.T) {
+		np := np.DeepCopy()
+		np.Spec.CertManager.DisableWildcards = true
+		np.Status.Certificates = []v1alpha1.CertificateStatus{
+			{Name: "hello-world-internal", TLSGroup: "internal", DNSNames: []string{"hello-world.internal.hlnr.io"}, Ready: true},
+			{Name: "hello-world-legacy", TLSGroup: "legacy", DNSNames: []string{"legacy.hlnr.io"}, Ready: true},
+		}
+
+		var deleted []string
+		pc := &tester.PatchClient{
+			DeleteFunc: func(obj runtime.Object, _ ...patcher.OptionFunc) error {
+				deleted = append(deleted, obj.(*unstructured.Unstructured).GetName())
+				return nil
+			},
+		}
+
+		// without live releases, none of the Certificates are built.
+		np.Spec.ExternalDNS = np.Spec.ExternalDNS[2:]
+		statuses, err := syncCertificates(pc, ms, np, nil)
+		if err != nil {
+			t.Fatalf("Expected no error, got %s", err)
+		}
+
+		if !reflect.DeepEqual(deleted, []string{"hello-world-legacy"}) {
+			t.Errorf("Expected the Certificate of the legacy group to be deleted, got %v", deleted)
+		}
+
+		if !reflect.DeepEqual(statuses, np.Status.Certificates[:1]) {
+			t.Errorf("Expected the Certificate of the internal group to be kept, got %+v", statuses)
+		}
+	})
+
+	t.Run("without wildcards", func(t *testing.T) {
+		np := np.DeepCopy()
+		np.Spec.CertManager.DisableWildcards = true
+
+		certs, err := buildCertificates(ms, np, releases)
+		if err != nil {
+			t.Fatalf("Expected no error, got %s", err)
+		}
+
+		dnsNames, _ := unstructured.NestedStringSlice(certs[1].Object, "spec", "dnsNames")
+		expected := []string{"hello-world.internal.hlnr.io", "my-branch.internal.hlnr.io"}
+		if !reflect.DeepEqual(dnsNames, expected) {
+			t.Errorf("Expected %v, got %v", expected, dnsNames)
+		}
+	})
+
+	t.Run("uses the Certificate secrets", func(t *testing.T) {
+		tls, err := getIngressTLS(ms, np, releases[0], np.Spec.ExternalDNS)
+		if err != nil {
+			t.Fatalf("Expected no error, got %s", err)
+		}
+
+		if tls[0].SecretName != "hello-world-heighliner-components" || tls[2].SecretName != "hello-world-internal" {
+			t.Errorf("Expected the secrets of the Certificates, got %+v", tls)
+		}
+	})
+}
@@ -2,12 +2,16 @@ package networkpolicy
 
 import (
 	"context"
+	"fmt"
 	"log"
 	"os"
 	"os/signal"
+	"sort"
 	"syscall"
+	"time"
 
 	"github.com/manifoldco/heighliner/apis/v1alpha1"
+	"github.com/manifoldco/heighliner/internal/k8sutils"
 
 	"github.com/jelmersnoeck/kubekit"
 	"github.com/jelmersnoeck/kubekit/patcher"
@@ -30,11 +34,13 @@ type Controller struct {
 	cs        clientv1.CoreV1Interface
 	namespace string
 	patcher   patchClient
+	apis      *apiDiscovery
 }
 
 type patchClient interface {
 	Get(interface{}, string, string) error
 	Apply(runtime.Object, ...patcher.OptionFunc) ([]byte, error)
+	Delete(runtime.Object, ...patcher.OptionFunc) error
 }
 
 // NewController returns a new NetworkPolicy Controller.
@@ -49,6 +55,7 @@ func NewController(cfg *rest.Config, cs kubernetes.Interface, namespace string)
 		rc:        rc,
 		namespace: namespace,
 		patcher:   patcher.New("hlnr-network-policy", cmdutil.NewFactory(nil)),
+		apis:      &apiDiscovery{dc: cs.Discovery()},
 	}, nil
 }
 
@@ -98,35 +105,161 @@ func (c *Controller) run(ctx context.Context) {
 func (c *Controller) syncNetworking(obj interface{}) error {
 	np := obj.(*v1alpha1.NetworkPolicy)
 
+	if np.DeletionTimestamp != nil {
+		return c.finalizeNetworkPolicy(np.DeepCopy())
+	}
+
 	ms, err := getMicroservice(c.patcher, np)
 	if err != nil {
 		log.Printf("Could not retrieve Microservice for %s: %s", np.Name, err)
+
+		status := np.Status
+		status.Error = fmt.Sprintf("could not retrieve Microservice: %s", err)
+		c.updateStatus(np, status)
 		return err
 	}
 
 	releaseGroups := groupReleases(ms.Name, ms.Status.Releases)
 	if len(releaseGroups) == 0 {
 		log.Printf("No release groups to sync")
-		return nil
+
+		// the records of the last releases still need to be cleaned up.
+		status := np.Status
+		if len(np.Status.DNSRecords) != 0 {
+			c.syncDNS(ms, np, nil, nil, &status)
+		}
+		return c.updateStatus(np, status)
+	}
+
+	releaser, err := newReleaser(c.cs, c.patcher, ms, np, time.Now())
+	if err != nil {
+		log.Printf("Invalid UpdateStrategy for %s: %s", np.Name, err)
+
+		// leave the live releases untouched, but let the user know why.
+		status := np.Status
+		status.Error = err.Error()
+		return c.updateStatus(np, status)
+	}
+
+	apis := c.apis.APIs()
+	rt, err := newRouter(ms, np, apis)
+	if err != nil {
+		log.Printf("Invalid routing for %s: %s", np.Name, err)
+
+		status := np.Status
+		status.Error = err.Error()
+		return c.updateStatus(np, status)
 	}
 
-	var newDomains []v1alpha1.Domain
+	if err := validateServiceType(np); err != nil {
+		log.Printf("Invalid Service type for %s: %s", np.Name, err)
+
+		status := np.Status
+		status.Error = err.Error()
+		return c.updateStatus(np, status)
+	}
+
+	var status v1alpha1.NetworkPolicyStatus
+	var live []*v1alpha1.Release
+	var routes []streamRoute
+	failed := map[string]bool{}
 	for name, releaseGroup := range releaseGroups {
 		if err := syncReleaseGroup(c.cs, c.patcher, ms, np, releaseGroup); err != nil {
 			log.Printf("Error syncing release '%s': %s", name, err)
+			failed[name] = true
+			status.Streams = appendFailedStream(status.Streams, np, name, err)
 			continue
 		}
 
-		domains, err := syncSelectedRelease(c.cs, c.patcher, ms, np, releaseGroup)
+		route, domains, stream, err := syncSelectedRelease(c.cs, c.patcher, ms, np, releaser, rt, releaseGroup)
 		if err != nil {
 			log.Printf("Error syncing selected release '%s': %s", name, err)
+			failed[name] = true
+
+			// invalid domains can only be fixed by the user.
+			if _, ok := err.(*DomainError); ok {
+				status.Error = err.Error()
+			}
+
+			status.Streams = appendFailedStream(status.Streams, np, name, err)
 			continue
 		}
 
-		newDomains = append(newDomains, domains...)
+		live = append(live, route.Release)
+		routes = append(routes, route)
+
+		status.Domains = append(status.Domains, domains...)
+		status.Streams = append(status.Streams, stream)
+	}
+
+	if err := syncAggregateRoutes(c.patcher, rt); err != nil {
+		log.Printf("Error syncing routes for %s: %s", np.Name, err)
+	}
+
+	if err := pruneRoutes(c.patcher, ms, np, apis, routes); err != nil {
+		log.Printf("Error pruning routes for %s: %s", np.Name, err)
+	}
+
+	status.Certificates, err = syncCertificates(c.patcher, ms, np, live)
+	if err != nil {
+		log.Printf("Error syncing Certificates for %s: %s", np.Name, err)
+	}
+
+	if err := syncFirewall(c.patcher, ms, np); err != nil {
+		log.Printf("Error syncing firewall for %s: %s", np.Name, err)
+		status.Error = err.Error()
+	}
+
+	c.syncDNS(ms, np, live, failed, &status)
+
+	sort.Slice(status.Streams, func(i, j int) bool {
+		return status.Streams[i].Name < status.Streams[j].Name
+	})
+
+	return c.updateStatus(np, status)
+}
+
+// syncDNS manages the DNS records of the live releases through the
+// DNSProvider of the NetworkPolicy, if any, and reports them in the status.
+func (c *Controller) syncDNS(ms *v1alpha1.Microservice, np *v1alpha1.NetworkPolicy, live []*v1alpha1.Release, failed map[string]bool, status *v1alpha1.NetworkPolicyStatus) {
+	p, err := newDNSProvider(c.cs, np)
+	if err != nil {
+		log.Printf("Invalid DNSProvider for %s: %s", np.Name, err)
+		status.DNSRecords = np.Status.DNSRecords
+		status.DNSProvider = np.Status.DNSProvider
+		status.Error = err.Error()
+		return
+	}
+
+	if p == nil {
+		status.DNSRecords = nil
+		status.DNSProvider = nil
+		return
+	}
+
+	// the records are deleted through the previous provider once the
+	// DNSProvider is removed.
+	if np.Spec.DNSProvider == nil {
+		live, failed = nil, nil
+	}
+
+	status.DNSRecords, err = syncDNS(p, ms, np, live, failed)
+	if err != nil {
+		log.Printf("Error syncing DNS records for %s: %s", np.Name, err)
 	}
 
-	if statusDomainsEqual(np.Status.Domains, newDomains) {
+	status.DNSProvider = nil
+	if len(status.DNSRecords) > 0 {
+		status.DNSProvider = dnsProviderConfig(np).DeepCopy()
+	}
+}
+
+func (c *Controller) updateStatus(np *v1alpha1.NetworkPolicy, status v1alpha1.NetworkPolicyStatus) error {
+	np = np.DeepCopy()
+	setConditions(np, &status)
+
+	added := needsFinalizer(np) && k8sutils.AddFinalizer(np, Finalizer)
+	if !added && networkStatusEqual(np.Status, status) {
 		return nil
 	}
 
@@ -138,7 +271,7 @@ func (c *Controller) syncNetworking(obj interface{}) error {
 		APIVersion: "hlnr.io/v1alpha1",
 	}
 
-	np.Status.Domains = newDomains
+	np.Status = status
 	if _, err := c.patcher.Apply(np); err != nil {
 		log.Printf("Error syncing NetworkStatus %s: %s", np.Name, err)
 		return err
@@ -160,37 +293,114 @@ func syncReleaseGroup(cs clientv1.CoreV1Interface, cl patchClient, svc *v1alpha1
 	return nil
 }
 
-func syncSelectedRelease(cs clientv1.CoreV1Interface, cl patchClient, ms *v1alpha1.Microservice, networkPolicy *v1alpha1.NetworkPolicy, releases []v1alpha1.Release) ([]v1alpha1.Domain, error) {
+func syncSelectedRelease(cs clientv1.CoreV1Interface, cl patchClient, ms *v1alpha1.Microservice, networkPolicy *v1alpha1.NetworkPolicy, releaser Releaser, rt router, releases []v1alpha1.Release) (streamRoute, []v1alpha1.Domain, v1alpha1.StreamStatus, error) {
 	np := networkPolicy.DeepCopy()
 
-	// TODO(jelmer): this should come from a factory based on the update strategy.
-	releaser := &LatestReleaser{}
-
 	name := np.Name
 
-	externalRelease, err := releaser.ExternalRelease(releases)
+	// releases only receive traffic once their hooks succeeded.
+	releases = hookedReleases(releases)
+	if len(releases) == 0 {
+		return streamRoute{}, nil, v1alpha1.StreamStatus{}, ErrHooksPending
+	}
+
+	var (
+		stream          v1alpha1.StreamStatus
+		externalRelease *v1alpha1.Release
+		reason          string
+		selection       *Selection
+		err             error
+	)
+
+	if pr, ok := releaser.(progressiveReleaser); ok {
+		if selection, err = pr.SelectReleases(releases); err == nil {
+			externalRelease, reason = selection.Stable, selection.Reason
+		}
+	} else {
+		externalRelease, reason, err = releaser.ExternalRelease(releases)
+	}
+
 	if err != nil {
 		log.Printf("Could not get ExternalRelease for %s: %s", name, err)
-		return nil, err
+		return streamRoute{}, nil, stream, err
+	}
+
+	stream = v1alpha1.StreamStatus{
+		Name:    externalRelease.StreamName(ms.Name),
+		Release: externalRelease.Name(),
+		Version: externalRelease.Version(),
+		Reason:  reason,
 	}
 
 	srv, err := createOrReplaceService(cs, cl, ms, np, externalRelease, externalRelease.StreamName(ms.Name))
 	if err != nil {
-		return nil, err
+		return streamRoute{}, nil, stream, err
+	}
+
+	if usesActivator(np, externalRelease) {
+		actSrv, err := buildActivatorService(ms, np, externalRelease, srv)
+		if err != nil {
+			log.Printf("Error building activator Service for %s: %s", name, err)
+			return streamRoute{}, nil, stream, err
+		}
+
+		if _, err := cl.Apply(actSrv); err != nil {
+			log.Printf("Error syncing activator Service for release %s: %s", name, err)
+			return streamRoute{}, nil, stream, err
+		}
 	}
 
-	ing, err := buildIngressForRelease(ms, np, externalRelease, srv)
+	services, err := resolvePathServices(cl, np, externalRelease)
 	if err != nil {
-		log.Printf("Error building Ingress for %s: %s", name, err)
-		return nil, err
+		log.Printf("Error resolving paths for %s: %s", name, err)
+		return streamRoute{}, nil, stream, err
+	}
+
+	ings, err := buildIngressesForRelease(ms, np, externalRelease, srv, services)
+	if err != nil {
+		log.Printf("Error building Ingresses for %s: %s", name, err)
+		return streamRoute{}, nil, stream, err
+	}
+
+	matches, err := buildRouteMatches(ms, np, externalRelease)
+	if err != nil {
+		log.Printf("Error building routing rules for %s: %s", name, err)
+		return streamRoute{}, nil, stream, err
+	}
+
+	pathOptions, err := buildPathOptions(ms, np, externalRelease)
+	if err != nil {
+		log.Printf("Error building paths for %s: %s", name, err)
+		return streamRoute{}, nil, stream, err
 	}
 
-	if _, err := cl.Apply(ing); err != nil {
+	route := streamRoute{Release: externalRelease, Ingresses: ings, Service: srv, Matches: matches, PathOptions: pathOptions}
+	if selection != nil && selection.Canary != nil {
+		route.Canary = selection.Canary
+		route.CanaryWeight = selection.CanaryStatus.Weight
+
+		if len(np.Spec.Ports) != 0 {
+			route.CanaryIngresses, err = buildCanaryIngresses(ms, np, externalRelease, selection.Canary, selection.CanaryStatus, srv, services)
+			if err != nil {
+				log.Printf("Error building canary Ingresses for %s: %s", name, err)
+				return streamRoute{}, nil, stream, err
+			}
+		}
+	}
+
+	_, removeCanary := releaser.(*CanaryReleaser)
+	if err := syncRoutes(cl, rt, ms, route, removeCanary); err != nil {
 		log.Printf("Error syncing Ingress for release %s: %s", name, err)
-		return nil, err
+		return streamRoute{}, nil, stream, err
 	}
 
-	return buildNetworkStatusDomainsForRelease(ms, np, externalRelease)
+	if selection != nil {
+		stream.Canary = selection.CanaryStatus
+		stream.Verification = selection.Verification
+	}
+
+	domains, err := buildNetworkStatusDomainsForRelease(ms, np, externalRelease)
+	return route, domains, stream, err
 }
 
 // createOrReplaceService will either create a new service instance, or do a full
@@ -223,17 +433,78 @@ func createOrReplaceService(cs clientv1.CoreV1Interface, cl patchClient, svc *v1
 	}
 	srv = buildServiceForRelease(srv, svc, np, release)
 
-	if create {
+	body, err := withAppProtocols(srv, np.Spec.Ports)
+	if err != nil {
+		return nil, err
+	}
+
+	switch {
+	case body != nil:
+		// the Service type we use predates appProtocol, so the Service is
+		// written as is to keep it.
+		req := cs.RESTClient().Put().Name(srv.Name)
+		if create {
+			req = cs.RESTClient().Post()
+		}
+
+		written := &v1.Service{}
+		err = req.Namespace(srv.Namespace).Resource("services").Body(body).Do().Into(written)
+		srv = written
+	case create:
 		srv, err = cs.Services(srv.Namespace).Create(srv)
-	} else {
+	default:
 		srv, err = cs.Services(srv.Namespace).Update(srv)
 	}
+
 	if err != nil {
 		log.Printf("Error syncing service for release %s: %s", np.Name, err)
+		return srv, err
 	}
 
-	return srv, err
+	return srv, nil
 }
+
+// appendFailedStream keeps the status of a stream which failed to sync, so
+// the live release and the progress of its rollout survive until the next
+// sync. Without it, the latest release would be sent all traffic straight
+// away. The error is recorded on the stream, unless the stream is waiting for
+// the hooks of its releases.
+func appendFailedStream(streams []v1alpha1.StreamStatus, np *v1alpha1.NetworkPolicy, name string, err error) []v1alpha1.StreamStatus {
+	stream := v1alpha1.StreamStatus{Name: name}
+
+	var found bool
+	for _, previous := range np.Status.Streams {
+		if previous.Name == name {
+			stream = *previous.DeepCopy()
+			found = true
+			break
+		}
+	}
+
+	stream.Error = ""
+	if err != ErrHooksPending {
+		stream.Error = err.Error()
+	}
+
+	if !found && stream.Error == "" {
+		return streams
+	}
+
+	return append(streams, stream)
+}
+
+// hookedReleases returns the releases whose hooks succeeded.
+func hookedReleases(releases []v1alpha1.Release) []v1alpha1.Release {
+	var hooked []v1alpha1.Release
+	for _, release := range releases {
+		if release.HooksSucceeded() {
+			hooked = append(hooked, release)
+		}
+	}
+
+	return hooked
+}
+
 func groupReleases(name string, releases []v1alpha1.Release) map[string][]v1alpha1.Release {
 	grouped := map[string][]v1alpha1.Release{}
 
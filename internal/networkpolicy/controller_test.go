@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"reflect"
 	"testing"
+	"time"
 
 	"k8s.io/api/core/v1"
 	"k8s.io/api/extensions/v1beta1"
@@ -79,10 +80,20 @@ func TestController_syncNetworking(t *testing.T) {
 			return myErr
 		}
 
+		var applied *v1alpha1.NetworkPolicy
+		pc.ApplyFunc = func(obj runtime.Object, opts ...patcher.OptionFunc) ([]byte, error) {
+			applied = obj.(*v1alpha1.NetworkPolicy)
+			return nil, nil
+		}
+
 		np := &v1alpha1.NetworkPolicy{}
 		if err := ctrl.syncNetworking(np); err != myErr {
 			t.Errorf("Expected '%s', got '%s'", myErr, err)
 		}
+
+		if applied == nil || !applied.Status.Conditions.IsTrue(v1alpha1.ConditionDegraded) {
+			t.Errorf("Expected the NetworkPolicy to be degraded")
+		}
 	})
 
 	t.Run("with linked microservice", func(t *testing.T) {
@@ -119,11 +130,11 @@ func TestController_syncNetworking(t *testing.T) {
 				return fmt.Errorf("Object %T not supported", obj)
 			}
 
-			var npApplied bool
+			var applied *v1alpha1.NetworkPolicy
 			pc.ApplyFunc = func(obj runtime.Object, opts ...patcher.OptionFunc) ([]byte, error) {
-				switch obj.(type) {
+				switch obj := obj.(type) {
 				case *v1alpha1.NetworkPolicy:
-					npApplied = true
+					applied = obj
 					return nil, nil
 				}
 
@@ -134,7 +145,18 @@ func TestController_syncNetworking(t *testing.T) {
 				t.Errorf("Expected no error, got '%s'", err)
 			}
 
-			if npApplied {
+			cond := applied.Status.Conditions.Get(v1alpha1.ConditionProgressing)
+			if cond == nil || cond.Status != v1.ConditionTrue || cond.Reason != "NoReleases" {
+				t.Errorf("Expected the NetworkPolicy to wait for releases, got %+v", applied.Status.Conditions)
+			}
+
+			np.Status = applied.Status
+			applied = nil
+			if err := ctrl.syncNetworking(np); err != nil {
+				t.Errorf("Expected no error, got '%s'", err)
+			}
+
+			if applied != nil {
 				t.Errorf("Didn't expect NetworkPolicy to receive an update")
 			}
 		})
@@ -220,12 +242,25 @@ func TestController_syncNetworking(t *testing.T) {
 								SemVer: obj.Status.Domains[0].SemVer,
 							},
 						},
+						Streams: []v1alpha1.StreamStatus{
+							{
+								Name:    "unit-test",
+								Release: "unit-test",
+								Version: "1.2.3",
+								Reason:  "latest release",
+							},
+						},
+						Conditions: obj.Status.Conditions,
 					}
 
 					if !reflect.DeepEqual(obj.Status, expectedStatus) {
 						t.Errorf("Expected status \n\n%#v\n\n to be \n\n%#v\n\n", obj.Status, expectedStatus)
 					}
 
+					if !obj.Status.Conditions.IsTrue(v1alpha1.ConditionReady) {
+						t.Errorf("Expected the NetworkPolicy to be ready, got %+v", obj.Status.Conditions)
+					}
+
 					return nil, nil
 				case *v1beta1.Ingress:
 					expectedRules := v1beta1.HTTPIngressRuleValue{
@@ -254,6 +289,7 @@ func TestController_syncNetworking(t *testing.T) {
 							Namespace: "testing",
 							Labels: map[string]string{
 								"hlnr.io/microservice.version":   "1.2.3",
+								"hlnr.io/microservice.level":     "release",
 								"hlnr.io/service":                "unit-tests",
 								"hlnr.io/microservice.full_name": "unit-test-a5hpt4kc",
 								"hlnr.io/microservice.name":      "unit-test",
@@ -294,6 +330,10 @@ func TestController_syncNetworking(t *testing.T) {
 				return nil, fmt.Errorf("Object %T not supported", obj)
 			}
 
+			pc.DeleteFunc = func(obj runtime.Object, _ ...patcher.OptionFunc) error {
+				return errors.NewNotFound(schema.GroupResource{}, "")
+			}
+
 			if err := ctrl.syncNetworking(np); err != nil {
 				t.Errorf("Expected no error, got '%s'", err)
 			}
@@ -314,6 +354,7 @@ func TestController_syncNetworking(t *testing.T) {
 					Labels: map[string]string{
 						"hlnr.io/microservice.release":   "unit-test",
 						"hlnr.io/microservice.version":   "1.2.3",
+						"hlnr.io/microservice.level":     "release",
 						"hlnr.io/service":                "unit-tests",
 						"hlnr.io/microservice.full_name": "unit-test-a5hpt4kc",
 						"hlnr.io/microservice.name":      "unit-test",
@@ -366,5 +407,244 @@ func TestController_syncNetworking(t *testing.T) {
 				t.Fatalf("Expected the NetworkPolicy to have received an updated")
 			}
 		})
+
+		t.Run("with an unknown pinned release", func(t *testing.T) {
+			defer pc.Flush()
+
+			np := &v1alpha1.NetworkPolicy{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "unit-test",
+					Namespace: "testing",
+				},
+				Spec: v1alpha1.NetworkPolicySpec{
+					UpdateStrategy: v1alpha1.UpdateStrategy{
+						Manual: &v1alpha1.ManualUpdateStrategy{
+							SemVer: &v1alpha1.SemVerRelease{
+								Name:    "unit-test",
+								Version: "2.0.0",
+							},
+						},
+					},
+				},
+			}
+
+			pc.GetFunc = func(obj interface{}, namespace, name string) error {
+				switch obj := obj.(type) {
+				case *v1alpha1.Microservice:
+					obj.ObjectMeta = metav1.ObjectMeta{
+						Name:      name,
+						Namespace: namespace,
+					}
+					obj.Status.Releases = []v1alpha1.Release{
+						{
+							Level: v1alpha1.SemVerLevelRelease,
+							SemVer: &v1alpha1.SemVerRelease{
+								Name:    "unit-test",
+								Version: "1.2.3",
+							},
+						},
+					}
+					return nil
+				}
+
+				return fmt.Errorf("Object %T not supported", obj)
+			}
+
+			var npApplied bool
+			pc.ApplyFunc = func(obj runtime.Object, opts ...patcher.OptionFunc) ([]byte, error) {
+				switch obj := obj.(type) {
+				case *v1alpha1.NetworkPolicy:
+					npApplied = true
+
+					if obj.Status.Error == "" {
+						t.Errorf("Expected an error to be set in the status")
+					}
+
+					if len(obj.Status.Domains) != 0 {
+						t.Errorf("Expected no domains to be linked, got %v", obj.Status.Domains)
+					}
+
+					return nil, nil
+				}
+
+				return nil, fmt.Errorf("Object %T not supported", obj)
+			}
+
+			if err := ctrl.syncNetworking(np); err != nil {
+				t.Errorf("Expected no error, got '%s'", err)
+			}
+
+			if !npApplied {
+				t.Fatalf("Expected the NetworkPolicy to have received an updated")
+			}
+		})
+	})
+}
+
+func TestController_syncNetworking_FailedStream(t *testing.T) {
+	releases := []v1alpha1.Release{
+		{
+			Image:       "1",
+			ReleaseTime: metav1.Date(2018, time.April, 28, 13, 42, 01, 0, time.UTC),
+			SemVer:      &v1alpha1.SemVerRelease{Name: "hello-world", Version: "1.0.0"},
+			Level:       v1alpha1.SemVerLevelRelease,
+		},
+		{
+			Image:       "2",
+			ReleaseTime: metav1.Date(2018, time.April, 29, 13, 42, 01, 0, time.UTC),
+			SemVer:      &v1alpha1.SemVerRelease{Name: "hello-world", Version: "1.1.0"},
+			Level:       v1alpha1.SemVerLevelRelease,
+		},
+	}
+
+	// sync syncs the NetworkPolicy twice, failing to sync its Services the
+	// first time, and returns the status after each sync.
+	sync := func(t *testing.T, np *v1alpha1.NetworkPolicy) (v1alpha1.NetworkPolicyStatus, v1alpha1.NetworkPolicyStatus) {
+		failing := true
+		var applied *v1alpha1.NetworkPolicy
+
+		pc := &tester.PatchClient{
+			GetFunc: func(obj interface{}, namespace, name string) error {
+				switch obj := obj.(type) {
+				case *v1alpha1.Microservice:
+					obj.ObjectMeta = metav1.ObjectMeta{Name: name, Namespace: namespace}
+					obj.Status.Releases = releases
+					return nil
+				case *v1.Service:
+					if failing {
+						return fmt.Errorf("connection refused")
+					}
+				}
+
+				return errors.NewNotFound(schema.GroupResource{}, name)
+			},
+			ApplyFunc: func(obj runtime.Object, opts ...patcher.OptionFunc) ([]byte, error) {
+				if obj, ok := obj.(*v1alpha1.NetworkPolicy); ok {
+					applied = obj
+				}
+
+				return nil, nil
+			},
+			DeleteFunc: func(obj runtime.Object, _ ...patcher.OptionFunc) error {
+				return errors.NewNotFound(schema.GroupResource{}, "")
+			},
+		}
+
+		ctrl := &Controller{cs: fake.NewSimpleClientset().Core(), patcher: pc}
+		ctrl.syncNetworking(np)
+		if applied == nil {
+			t.Fatalf("Expected the NetworkPolicy to be updated")
+		}
+		first := applied.Status
+
+		failing = false
+		np.Status = first
+		if err := ctrl.syncNetworking(np); err != nil {
+			t.Fatalf("Expected no error, got '%s'", err)
+		}
+
+		return first, applied.Status
+	}
+
+	expectFailedStream := func(t *testing.T, status v1alpha1.NetworkPolicyStatus, stream v1alpha1.StreamStatus) {
+		stream.Error = "connection refused"
+		if !reflect.DeepEqual(status.Streams, []v1alpha1.StreamStatus{stream}) {
+			t.Errorf("Expected the stream to be kept after a failed sync, got %+v", status.Streams)
+		}
+
+		if cond := status.Conditions.Get(v1alpha1.ConditionDegraded); cond == nil || cond.Status != v1.ConditionTrue || cond.Reason != "StreamError" {
+			t.Errorf("Expected the NetworkPolicy to be degraded, got %+v", status.Conditions)
+		}
+	}
+
+	newNetworkPolicy := func(strategy v1alpha1.UpdateStrategy, stream v1alpha1.StreamStatus) *v1alpha1.NetworkPolicy {
+		return &v1alpha1.NetworkPolicy{
+			ObjectMeta: metav1.ObjectMeta{Name: "hello-world", Namespace: "testing"},
+			Spec: v1alpha1.NetworkPolicySpec{
+				Ports:          []v1alpha1.NetworkPort{{Name: "http", Port: 80, TargetPort: 8080}},
+				UpdateStrategy: strategy,
+			},
+			Status: v1alpha1.NetworkPolicyStatus{
+				Streams: []v1alpha1.StreamStatus{stream},
+			},
+		}
+	}
+
+	t.Run("with a canary release", func(t *testing.T) {
+		stream := v1alpha1.StreamStatus{
+			Name:    releases[0].StreamName("hello-world"),
+			Release: "hello-world",
+			Version: "1.0.0",
+			Reason:  "canary of hello-world 1.1.0 in progress",
+			Canary: &v1alpha1.CanaryStatus{
+				Release:   "hello-world",
+				Version:   "1.1.0",
+				Phase:     v1alpha1.CanaryProgressing,
+				Weight:    10,
+				StartTime: metav1.NewTime(time.Now().Add(-time.Minute).Truncate(time.Second)),
+			},
+		}
+
+		np := newNetworkPolicy(v1alpha1.UpdateStrategy{
+			Canary: &v1alpha1.CanaryUpdateStrategy{StepDuration: &metav1.Duration{Duration: time.Hour}},
+		}, stream)
+
+		failed, synced := sync(t, np)
+		expectFailedStream(t, failed, stream)
+
+		if len(synced.Streams) != 1 || synced.Streams[0].Version != "1.0.0" {
+			t.Fatalf("Expected the stable release to stay live, got %+v", synced.Streams)
+		}
+
+		if c := synced.Streams[0].Canary; c == nil || c.Version != "1.1.0" || !c.StartTime.Equal(&stream.Canary.StartTime) {
+			t.Errorf("Expected the canary release to continue, got %+v", c)
+		}
+	})
+
+	t.Run("with a blue/green release", func(t *testing.T) {
+		stream := v1alpha1.StreamStatus{
+			Name:    releases[0].StreamName("hello-world"),
+			Release: "hello-world",
+			Version: "1.0.0",
+			Reason:  "verifying hello-world 1.1.0",
+			Verification: &v1alpha1.VerificationStatus{
+				Release:   "hello-world",
+				Version:   "1.1.0",
+				Phase:     v1alpha1.VerificationRunning,
+				StartTime: metav1.NewTime(time.Now().Add(-time.Minute).Truncate(time.Second)),
+			},
+		}
+
+		np := newNetworkPolicy(v1alpha1.UpdateStrategy{
+			BlueGreen: &v1alpha1.BlueGreenUpdateStrategy{},
+		}, stream)
+
+		failed, synced := sync(t, np)
+		expectFailedStream(t, failed, stream)
+
+		// the latest release has no ready endpoints, so it isn't verified.
+		if len(synced.Streams) != 1 || synced.Streams[0].Version != "1.0.0" {
+			t.Fatalf("Expected the unverified release not to go live, got %+v", synced.Streams)
+		}
+
+		if v := synced.Streams[0].Verification; v == nil || v.Version != "1.1.0" || !v.StartTime.Equal(&stream.Verification.StartTime) {
+			t.Errorf("Expected the verification to continue, got %+v", v)
+		}
+	})
+
+	t.Run("with a new stream", func(t *testing.T) {
+		np := newNetworkPolicy(v1alpha1.UpdateStrategy{}, v1alpha1.StreamStatus{})
+		np.Status.Streams = nil
+
+		failed, synced := sync(t, np)
+		expectFailedStream(t, failed, v1alpha1.StreamStatus{Name: releases[0].StreamName("hello-world")})
+
+		if len(synced.Streams) != 1 || synced.Streams[0].Version != "1.1.0" || synced.Streams[0].Error != "" {
+			t.Errorf("Expected the latest release to go live, got %+v", synced.Streams)
+		}
+
+		if !synced.Conditions.IsTrue(v1alpha1.ConditionReady) {
+			t.Errorf("Expected the NetworkPolicy to be ready, got %+v", synced.Conditions)
+		}
 	})
 }
@@ -0,0 +1,198 @@
+package networkpolicy
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"log"
+	"sort"
+
+	"github.com/manifoldco/heighliner/apis/v1alpha1"
+	"github.com/manifoldco/heighliner/internal/dns"
+	"github.com/manifoldco/heighliner/internal/dns/rfc2136"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	clientv1 "k8s.io/client-go/kubernetes/typed/core/v1"
+)
+
+const (
+	defaultTSIGAlgorithm = "hmac-sha256"
+	defaultTTL           = 300
+)
+
+// dnsProviderConfig returns the DNSProvider of a NetworkPolicy. Once it's
+// removed from the spec, the DNSProvider the records were created with is
+// returned until they're deleted.
+func dnsProviderConfig(np *v1alpha1.NetworkPolicy) *v1alpha1.DNSProvider {
+	if np.Spec.DNSProvider != nil {
+		return np.Spec.DNSProvider
+	}
+
+	if len(np.Status.DNSRecords) == 0 {
+		return nil
+	}
+
+	return np.Status.DNSProvider
+}
+
+// ErrNoDNSProvider is used when a DNSProvider doesn't configure a provider to
+// manage the records with.
+var ErrNoDNSProvider = errors.New("the DNSProvider doesn't configure a provider")
+
+// newDNSProvider returns the provider which manages the records of a
+// NetworkPolicy, or nil if the NetworkPolicy doesn't configure one.
+func newDNSProvider(cs clientv1.CoreV1Interface, np *v1alpha1.NetworkPolicy) (dns.Provider, error) {
+	cfg := dnsProviderConfig(np)
+	if cfg == nil {
+		return nil, nil
+	}
+
+	if cfg.RFC2136 == nil {
+		return nil, ErrNoDNSProvider
+	}
+
+	p := &rfc2136.Provider{
+		Server: cfg.RFC2136.Server,
+		Zone:   cfg.RFC2136.Zone,
+	}
+
+	key := cfg.RFC2136.TSIG
+	if key == nil {
+		return p, nil
+	}
+
+	algorithm := key.Algorithm
+	if algorithm == "" {
+		algorithm = defaultTSIGAlgorithm
+	}
+
+	if _, ok := rfc2136.Algorithms[algorithm]; !ok {
+		return nil, fmt.Errorf("unsupported TSIG algorithm '%s'", algorithm)
+	}
+
+	secret, err := cs.Secrets(np.Namespace).Get(key.SecretRef.Name, metav1.GetOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	value, ok := secret.Data[key.SecretRef.Key]
+	if !ok {
+		return nil, fmt.Errorf("secret '%s' has no key '%s'", key.SecretRef.Name, key.SecretRef.Key)
+	}
+
+	decoded, err := base64.StdEncoding.DecodeString(string(value))
+	if err != nil {
+		return nil, fmt.Errorf("the TSIG secret is not base64 encoded: %s", err)
+	}
+
+	p.TSIG = &rfc2136.TSIG{
+		Name:      key.KeyName,
+		Algorithm: algorithm,
+		Secret:    decoded,
+	}
+
+	return p, nil
+}
+
+// syncDNS applies the records for the domains of the live releases and
+// deletes the records of releases which went away. The records of streams
+// which failed to sync are left as they are, so their domains keep working.
+func syncDNS(p dns.Provider, ms *v1alpha1.Microservice, np *v1alpha1.NetworkPolicy, releases []*v1alpha1.Release, failed map[string]bool) ([]v1alpha1.DNSRecordStatus, error) {
+	desired, err := buildDNSRecords(ms, np, releases)
+	if err != nil {
+		return np.Status.DNSRecords, err
+	}
+
+	owner := np.Namespace + "/" + np.Name
+
+	var statuses []v1alpha1.DNSRecordStatus
+	var syncErr error
+	wanted := map[string]bool{}
+	for _, rec := range desired {
+		wanted[rec.Name] = true
+
+		err := p.Apply(rec.Record, owner)
+		if err == dns.ErrNotOwned {
+			log.Printf("Not managing DNS record %s for %s: %s", rec.Name, np.Name, err)
+			continue
+		}
+
+		// records which failed to apply are tracked as well, they might have
+		// been created before and need to be deleted once the release goes
+		// away. Deletes are limited to the records we own.
+		if err != nil {
+			log.Printf("Error applying DNS record %s for %s: %s", rec.Name, np.Name, err)
+			syncErr = err
+		}
+
+		statuses = append(statuses, v1alpha1.DNSRecordStatus{
+			Name:   rec.Name,
+			Type:   rec.Type,
+			Target: rec.Target,
+			Stream: rec.stream,
+		})
+	}
+
+	for _, status := range np.Status.DNSRecords {
+		if wanted[status.Name] {
+			continue
+		}
+
+		if failed[status.Stream] {
+			statuses = append(statuses, status)
+			continue
+		}
+
+		rec := dns.Record{Name: status.Name, Type: status.Type, Target: status.Target}
+		if err := p.Delete(rec, owner); err != nil {
+			log.Printf("Error deleting DNS record %s for %s: %s", rec.Name, np.Name, err)
+
+			// keep track of the record so we retry the next time.
+			statuses = append(statuses, status)
+			syncErr = err
+		}
+	}
+
+	sort.Slice(statuses, func(i, j int) bool {
+		return statuses[i].Name < statuses[j].Name
+	})
+
+	return statuses, syncErr
+}
+
+// streamRecord is a DNS record for the domain of a release stream.
+type streamRecord struct {
+	dns.Record
+	stream string
+}
+
+// buildDNSRecords returns the records for the domains of the given releases.
+func buildDNSRecords(ms *v1alpha1.Microservice, np *v1alpha1.NetworkPolicy, releases []*v1alpha1.Release) ([]streamRecord, error) {
+	var records []streamRecord
+	seen := map[string]bool{}
+	for _, release := range releases {
+		for _, record := range np.Spec.ExternalDNS {
+			domain, err := templatedDomain(ms, release, record.Domain)
+			if err != nil {
+				return nil, err
+			}
+
+			if seen[domain] {
+				continue
+			}
+			seen[domain] = true
+
+			ttl := record.TTL
+			if ttl == 0 {
+				ttl = defaultTTL
+			}
+
+			records = append(records, streamRecord{
+				Record: dns.NewRecord(domain, np.Spec.DNSProvider.Target, ttl),
+				stream: release.StreamName(ms.Name),
+			})
+		}
+	}
+
+	return records, nil
+}
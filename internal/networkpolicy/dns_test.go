@@ -0,0 +1,179 @@
+package networkpolicy
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/manifoldco/heighliner/apis/v1alpha1"
+	"github.com/manifoldco/heighliner/internal/dns"
+	"github.com/manifoldco/heighliner/internal/dns/rfc2136"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+type fakeDNSProvider struct {
+	errs    map[string]error
+	applied []string
+	deleted []string
+}
+
+func (p *fakeDNSProvider) Apply(rec dns.Record, owner string) error {
+	p.applied = append(p.applied, rec.Name)
+	return p.errs[rec.Name]
+}
+
+func (p *fakeDNSProvider) Delete(rec dns.Record, owner string) error {
+	p.deleted = append(p.deleted, rec.Name)
+	return p.errs[rec.Name]
+}
+
+func TestSyncDNS(t *testing.T) {
+	ms := &v1alpha1.Microservice{ObjectMeta: metav1.ObjectMeta{Name: "hello-world", Namespace: "testing"}}
+	release := &v1alpha1.Release{
+		SemVer: &v1alpha1.SemVerRelease{Name: "feature", Version: "1.0.0"},
+		Level:  v1alpha1.SemVerLevelPreview,
+	}
+
+	np := &v1alpha1.NetworkPolicy{
+		ObjectMeta: metav1.ObjectMeta{Name: "hello-world", Namespace: "testing"},
+		Spec: v1alpha1.NetworkPolicySpec{
+			ExternalDNS: []v1alpha1.ExternalDNS{{Domain: "{{.Name}}.hlnr.io", TTL: 60}},
+			DNSProvider: &v1alpha1.DNSProvider{Target: "lb.hlnr.io"},
+		},
+		Status: v1alpha1.NetworkPolicyStatus{
+			DNSRecords: []v1alpha1.DNSRecordStatus{
+				{Name: "gone.hlnr.io", Type: "CNAME", Target: "lb.hlnr.io", Stream: "hello-world-pr-gone"},
+				{Name: "failing.hlnr.io", Type: "CNAME", Target: "lb.hlnr.io", Stream: "hello-world-pr-failing"},
+			},
+		},
+	}
+
+	t.Run("applies and cleans up records", func(t *testing.T) {
+		p := &fakeDNSProvider{}
+		statuses, err := syncDNS(p, ms, np, []*v1alpha1.Release{release}, map[string]bool{"hello-world-pr-failing": true})
+		if err != nil {
+			t.Fatalf("Expected no error, got %s", err)
+		}
+
+		if !reflect.DeepEqual(p.applied, []string{"feature.hlnr.io"}) {
+			t.Errorf("Expected feature.hlnr.io to be applied, got %v", p.applied)
+		}
+
+		if !reflect.DeepEqual(p.deleted, []string{"gone.hlnr.io"}) {
+			t.Errorf("Expected gone.hlnr.io to be deleted, got %v", p.deleted)
+		}
+
+		expected := []v1alpha1.DNSRecordStatus{
+			{Name: "failing.hlnr.io", Type: "CNAME", Target: "lb.hlnr.io", Stream: "hello-world-pr-failing"},
+			{Name: "feature.hlnr.io", Type: "CNAME", Target: "lb.hlnr.io", Stream: release.StreamName("hello-world")},
+		}
+		if !reflect.DeepEqual(statuses, expected) {
+			t.Errorf("Expected records %+v, got %+v", expected, statuses)
+		}
+	})
+
+	t.Run("skips records owned by someone else", func(t *testing.T) {
+		p := &fakeDNSProvider{errs: map[string]error{"feature.hlnr.io": dns.ErrNotOwned}}
+		statuses, err := syncDNS(p, ms, np, []*v1alpha1.Release{release}, nil)
+		if err != nil {
+			t.Fatalf("Expected no error, got %s", err)
+		}
+
+		if len(statuses) != 0 {
+			t.Errorf("Expected no records to be managed, got %+v", statuses)
+		}
+	})
+
+	t.Run("retries failed deletes", func(t *testing.T) {
+		p := &fakeDNSProvider{errs: map[string]error{"gone.hlnr.io": errors.New("timeout")}}
+		statuses, err := syncDNS(p, ms, np, nil, nil)
+		if err == nil {
+			t.Fatalf("Expected an error")
+		}
+
+		if len(statuses) != 1 || statuses[0].Name != "gone.hlnr.io" {
+			t.Errorf("Expected gone.hlnr.io to be kept, got %+v", statuses)
+		}
+	})
+}
+
+func TestNewDNSProvider(t *testing.T) {
+	np := &v1alpha1.NetworkPolicy{
+		ObjectMeta: metav1.ObjectMeta{Name: "hello-world", Namespace: "testing"},
+		Spec: v1alpha1.NetworkPolicySpec{
+			DNSProvider: &v1alpha1.DNSProvider{
+				Target: "lb.hlnr.io",
+				RFC2136: &v1alpha1.RFC2136Provider{
+					Server: "ns1.hlnr.io:53",
+					Zone:   "hlnr.io",
+					TSIG: &v1alpha1.TSIGKey{
+						KeyName: "hlnr",
+						SecretRef: corev1.SecretKeySelector{
+							LocalObjectReference: corev1.LocalObjectReference{Name: "tsig"},
+							Key:                  "secret",
+						},
+					},
+				},
+			},
+		},
+	}
+
+	t.Run("with a TSIG secret", func(t *testing.T) {
+		cs := fake.NewSimpleClientset(&corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: "tsig", Namespace: "testing"},
+			Data:       map[string][]byte{"secret": []byte("c2VjcmV0")},
+		})
+
+		if _, err := newDNSProvider(cs.CoreV1(), np); err != nil {
+			t.Errorf("Expected no error, got %s", err)
+		}
+	})
+
+	t.Run("without a TSIG secret", func(t *testing.T) {
+		if _, err := newDNSProvider(fake.NewSimpleClientset().CoreV1(), np); err == nil {
+			t.Errorf("Expected an error for a missing secret")
+		}
+	})
+
+	t.Run("with a removed provider", func(t *testing.T) {
+		np := np.DeepCopy()
+		np.Status.DNSProvider = np.Spec.DNSProvider
+		np.Status.DNSProvider.RFC2136.TSIG = nil
+		np.Spec.DNSProvider = nil
+
+		if p, err := newDNSProvider(fake.NewSimpleClientset().CoreV1(), np); err != nil || p != nil {
+			t.Errorf("Expected no provider without records, got %v, %v", p, err)
+		}
+
+		np.Status.DNSRecords = []v1alpha1.DNSRecordStatus{{Name: "feature.hlnr.io", Type: "CNAME", Target: "lb.hlnr.io"}}
+		p, err := newDNSProvider(fake.NewSimpleClientset().CoreV1(), np)
+		if err != nil {
+			t.Fatalf("Expected no error, got %s", err)
+		}
+
+		if rp, ok := p.(*rfc2136.Provider); !ok || rp.Server != "ns1.hlnr.io:53" {
+			t.Fatalf("Expected the previous provider, got %+v", p)
+		}
+
+		fp := &fakeDNSProvider{}
+		if _, err := syncDNS(fp, nil, np, nil, nil); err != nil {
+			t.Fatalf("Expected no error, got %s", err)
+		}
+
+		if !reflect.DeepEqual(fp.deleted, []string{"feature.hlnr.io"}) {
+			t.Errorf("Expected feature.hlnr.io to be deleted, got %v", fp.deleted)
+		}
+	})
+
+	t.Run("without a provider", func(t *testing.T) {
+		np := np.DeepCopy()
+		np.Spec.DNSProvider.RFC2136 = nil
+
+		if _, err := newDNSProvider(fake.NewSimpleClientset().CoreV1(), np); err != ErrNoDNSProvider {
+			t.Errorf("Expected %s, got %v", ErrNoDNSProvider, err)
+		}
+	})
+}
@@ -0,0 +1,168 @@
+package networkpolicy
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"text/template"
+
+	"github.com/manifoldco/heighliner/apis/v1alpha1"
+	"github.com/manifoldco/heighliner/internal/k8sutils"
+)
+
+const (
+	maxDomainLength = 253
+	maxLabelLength  = 63
+	shortSHALength  = 7
+)
+
+var (
+	invalidLabelChars = regexp.MustCompile(`[^a-z0-9-]+`)
+	slugChars         = regexp.MustCompile(`[^a-z0-9]+`)
+)
+
+// DomainError is used when the domain of an ExternalDNS record can't be
+// turned into a valid hostname for a release.
+type DomainError struct {
+	Domain string
+	Reason string
+}
+
+func (e *DomainError) Error() string {
+	return fmt.Sprintf("invalid domain '%s': %s", e.Domain, e.Reason)
+}
+
+// templateData is the data available to domain templates.
+type templateData struct {
+	FullName     string
+	StreamName   string
+	Name         string
+	Microservice string
+	Namespace    string
+	Level        string
+	Version      string
+
+	sha         string
+	pullRequest int
+}
+
+// ShortSHA returns the abbreviated SHA of the commit of the release. Templates
+// which use it fail for releases of which the commit isn't known.
+func (d templateData) ShortSHA() (string, error) {
+	if d.sha == "" {
+		return "", errors.New("the commit of the release isn't known")
+	}
+
+	return truncate(shortSHALength, d.sha), nil
+}
+
+// PullRequest returns the number of the pull request of the release.
+// Templates which use it fail for releases which don't come from a pull
+// request.
+func (d templateData) PullRequest() (int, error) {
+	if d.pullRequest == 0 {
+		return 0, errors.New("the release doesn't come from a pull request")
+	}
+
+	return d.pullRequest, nil
+}
+
+var templateFuncs = template.FuncMap{
+	"slugify":  slugify,
+	"truncate": truncate,
+	"hash":     hash,
+}
+
+// templatedDomain templates a domain for the given release. The result is
+// turned into a valid hostname: it's lowercased and characters which aren't
+// allowed in DNS labels are replaced by dashes.
+func templatedDomain(ms *v1alpha1.Microservice, release *v1alpha1.Release, domain string) (string, error) {
+	templated, err := templateString(ms, release, domain)
+	if err != nil {
+		return "", &DomainError{Domain: domain, Reason: err.Error()}
+	}
+
+	hostname, err := dnsSafeDomain(templated)
+	if err != nil {
+		return "", &DomainError{Domain: domain, Reason: err.Error()}
+	}
+
+	return hostname, nil
+}
+
+// templateString executes a template with the data of the given release.
+func templateString(ms *v1alpha1.Microservice, release *v1alpha1.Release, text string) (string, error) {
+	tmpl, err := template.New("domain").Funcs(templateFuncs).Parse(text)
+	if err != nil {
+		return "", err
+	}
+
+	data := templateData{
+		FullName:     release.FullName(ms.Name),
+		StreamName:   release.StreamName(ms.Name),
+		Name:         release.Name(),
+		Microservice: ms.Name,
+		Namespace:    ms.Namespace,
+		Level:        string(release.Level),
+		Version:      release.Version(),
+		sha:          release.SHA,
+		pullRequest:  release.PullRequest,
+	}
+
+	if data.sha == "" && release.Commit != nil {
+		data.sha = release.Commit.SHA
+	}
+
+	buf := bytes.NewBufferString("")
+	if err := tmpl.Execute(buf, data); err != nil {
+		return "", err
+	}
+
+	return buf.String(), nil
+}
+
+// dnsSafeDomain enforces the rules for DNS labels on a domain. Invalid
+// characters are replaced and labels which are too long are truncated, but
+// empty labels and domains which are too long can't be fixed.
+func dnsSafeDomain(domain string) (string, error) {
+	labels := strings.Split(strings.ToLower(domain), ".")
+	for i, label := range labels {
+		label = invalidLabelChars.ReplaceAllString(label, "-")
+		label = strings.Trim(truncate(maxLabelLength, strings.Trim(label, "-")), "-")
+		if label == "" {
+			return "", fmt.Errorf("label %d is empty", i+1)
+		}
+
+		labels[i] = label
+	}
+
+	hostname := strings.Join(labels, ".")
+	if len(hostname) > maxDomainLength {
+		return "", fmt.Errorf("the hostname is longer than %d characters", maxDomainLength)
+	}
+
+	return hostname, nil
+}
+
+// slugify lowercases a string and replaces everything but letters and digits
+// with dashes, so `feature/Foo_bar` becomes `feature-foo-bar`.
+func slugify(s string) string {
+	return strings.Trim(slugChars.ReplaceAllString(strings.ToLower(s), "-"), "-")
+}
+
+// truncate shortens a string to at most n characters. The length comes first
+// so it can be used in pipelines, like `{{.Name | truncate 20}}`.
+func truncate(n int, s string) string {
+	if n < 0 || len(s) <= n {
+		return s
+	}
+
+	return s[:n]
+}
+
+// hash returns a short hash of a string which is safe to use in a DNS label.
+func hash(s string) string {
+	return k8sutils.ShortHash(s, 8)
+}
@@ -0,0 +1,87 @@
+package networkpolicy
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/manifoldco/heighliner/apis/v1alpha1"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestDNSSafeDomain(t *testing.T) {
+	tcs := []struct {
+		domain   string
+		hostname string
+		valid    bool
+	}{
+		{"hello-world.hlnr.io", "hello-world.hlnr.io", true},
+		{"feature/Foo_bar.hlnr.io", "feature-foo-bar.hlnr.io", true},
+		{"-leading-.hlnr.io", "leading.hlnr.io", true},
+		{strings.Repeat("a", 70) + ".hlnr.io", strings.Repeat("a", 63) + ".hlnr.io", true},
+		{"hello..hlnr.io", "", false},
+		{"__.hlnr.io", "", false},
+		{strings.Repeat(strings.Repeat("a", 60)+".", 5) + "io", "", false},
+	}
+
+	for _, tc := range tcs {
+		hostname, err := dnsSafeDomain(tc.domain)
+		if (err == nil) != tc.valid || hostname != tc.hostname {
+			t.Errorf("Expected %s to result in (%q, valid: %t), got (%q, %v)", tc.domain, tc.hostname, tc.valid, hostname, err)
+		}
+	}
+}
+
+func TestTemplatedDomainErrors(t *testing.T) {
+	ms := &v1alpha1.Microservice{ObjectMeta: metav1.ObjectMeta{Name: "hello-world"}}
+	release := &v1alpha1.Release{
+		SemVer: &v1alpha1.SemVerRelease{Name: "feature/Foo_bar", Version: "1.0.0-feature"},
+		Level:  v1alpha1.SemVerLevelPreview,
+	}
+
+	t.Run("makes branch names DNS safe", func(t *testing.T) {
+		domain, err := templatedDomain(ms, release, "{{.Name}}.hlnr.io")
+		if err != nil || domain != "feature-foo-bar.hlnr.io" {
+			t.Errorf("Expected feature-foo-bar.hlnr.io, got %q (%v)", domain, err)
+		}
+
+		domain, err = templatedDomain(ms, release, "{{slugify .Name}}.hlnr.io")
+		if err != nil || domain != "feature-foo-bar.hlnr.io" {
+			t.Errorf("Expected feature-foo-bar.hlnr.io, got %q (%v)", domain, err)
+		}
+	})
+
+	t.Run("reports invalid hostnames", func(t *testing.T) {
+		_, err := templatedDomain(ms, release, "{{truncate 0 .Name}}.hlnr.io")
+		if err == nil {
+			t.Fatal("Expected an error for an empty label")
+		}
+
+		expected := "invalid domain '{{truncate 0 .Name}}.hlnr.io': label 1 is empty"
+		if err.Error() != expected {
+			t.Errorf("Expected error %q, got %q", expected, err)
+		}
+	})
+
+	t.Run("reports fields the release doesn't have", func(t *testing.T) {
+		for domain, reason := range map[string]string{
+			"{{.ShortSHA}}.hlnr.io":       "the commit of the release isn't known",
+			"pr-{{.PullRequest}}.hlnr.io": "the release doesn't come from a pull request",
+		} {
+			_, err := templatedDomain(ms, release, domain)
+			if _, ok := err.(*DomainError); !ok || !strings.Contains(err.Error(), reason) {
+				t.Errorf("Expected a DomainError for %s mentioning %q, got %v", domain, reason, err)
+			}
+		}
+	})
+
+	t.Run("uses the commit of any release", func(t *testing.T) {
+		release := release.DeepCopy()
+		release.SHA = "8d2ab3f0c1b2a3d4e5f60718293a4b5c6d7e8f90"
+
+		domain, err := templatedDomain(ms, release, "{{.ShortSHA}}.hlnr.io")
+		if err != nil || domain != "8d2ab3f.hlnr.io" {
+			t.Errorf("Expected 8d2ab3f.hlnr.io, got %q (%v)", domain, err)
+		}
+	})
+}
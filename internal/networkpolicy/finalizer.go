@@ -0,0 +1,89 @@
+package networkpolicy
+
+import (
+	"log"
+	"time"
+
+	"github.com/manifoldco/heighliner/apis/v1alpha1"
+	"github.com/manifoldco/heighliner/internal/k8sutils"
+
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// Finalizer makes sure the DNS records of a NetworkPolicy are deleted before
+// the NetworkPolicy itself. Other resources of a NetworkPolicy are owned by
+// it, and are cleaned up by Kubernetes.
+const Finalizer = "hlnr.io/network-policy"
+
+// needsFinalizer reports whether a NetworkPolicy manages resources outside of
+// the cluster.
+func needsFinalizer(np *v1alpha1.NetworkPolicy) bool {
+	return np.Spec.DNSProvider != nil || len(np.Status.DNSRecords) > 0
+}
+
+// finalizeNetworkPolicy deletes the DNS records of a NetworkPolicy which is
+// being deleted. The finalizer is removed once they're all gone, or once the
+// deletion timed out.
+func (c *Controller) finalizeNetworkPolicy(np *v1alpha1.NetworkPolicy) error {
+	if !k8sutils.HasFinalizer(np, Finalizer) {
+		return nil
+	}
+
+	records, err := c.deleteDNSRecords(np)
+	if err != nil {
+		log.Printf("Could not delete DNS records of NetworkPolicy %s: %s", np.Name, err)
+	}
+
+	var pending []string
+	for _, rec := range records {
+		pending = append(pending, "DNSRecord/"+rec.Name)
+	}
+
+	timedOut := k8sutils.DeletionTimedOut(np, time.Now())
+	if len(pending) == 0 || timedOut {
+		if len(pending) > 0 {
+			log.Printf("Deletion of NetworkPolicy %s timed out, leaving %v behind", np.Name, pending)
+		}
+
+		k8sutils.RemoveFinalizer(np, Finalizer)
+	}
+
+	np.Status.DNSRecords = records
+	if len(records) == 0 {
+		np.Status.DNSProvider = nil
+	}
+	np.Status.Deletion = v1alpha1.NewDeletionStatus(pending, err, timedOut)
+
+	np.TypeMeta = metav1.TypeMeta{
+		Kind:       "NetworkPolicy",
+		APIVersion: "hlnr.io/v1alpha1",
+	}
+
+	if _, err := c.patcher.Apply(np); err != nil && !kerrors.IsNotFound(err) {
+		log.Printf("Error finalizing NetworkPolicy %s: %s", np.Name, err)
+		return err
+	}
+
+	return err
+}
+
+// deleteDNSRecords deletes all DNS records of a NetworkPolicy, and returns the
+// ones which couldn't be deleted.
+func (c *Controller) deleteDNSRecords(np *v1alpha1.NetworkPolicy) ([]v1alpha1.DNSRecordStatus, error) {
+	if len(np.Status.DNSRecords) == 0 {
+		return nil, nil
+	}
+
+	p, err := newDNSProvider(c.cs, np)
+	if err != nil {
+		return np.Status.DNSRecords, err
+	}
+
+	// records of which the provider isn't known can't be deleted.
+	if p == nil {
+		return nil, nil
+	}
+
+	return syncDNS(p, nil, np, nil, nil)
+}
@@ -0,0 +1,279 @@
+package networkpolicy
+
+import (
+	"errors"
+
+	"github.com/manifoldco/heighliner/apis/v1alpha1"
+	"github.com/manifoldco/heighliner/internal/meta"
+
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+// namespaceNameLabel is the label Kubernetes sets to the name of every
+// namespace.
+const namespaceNameLabel = "kubernetes.io/metadata.name"
+
+// ErrInvalidPeer is used when a FirewallPeer combines a CIDR with a
+// Microservice or namespace, or has none of them.
+var ErrInvalidPeer = errors.New("a firewall peer needs either a cidr, or a microservice and/or namespace")
+
+// syncFirewall applies the NetworkPolicies for the Firewall of a
+// NetworkPolicy and removes the ones which are no longer needed.
+func syncFirewall(cl patchClient, ms *v1alpha1.Microservice, np *v1alpha1.NetworkPolicy) error {
+	policies, err := buildFirewallPolicies(ms, np)
+	if err != nil {
+		return err
+	}
+
+	applied := map[string]bool{}
+	for _, policy := range policies {
+		if _, err := cl.Apply(policy); err != nil {
+			return err
+		}
+
+		applied[policy.Name] = true
+	}
+
+	for _, name := range []string{firewallName(np, false), firewallName(np, true)} {
+		if applied[name] {
+			continue
+		}
+
+		policy := &networkingv1.NetworkPolicy{
+			TypeMeta: metav1.TypeMeta{
+				Kind:       "NetworkPolicy",
+				APIVersion: "networking.k8s.io/v1",
+			},
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: ms.Namespace,
+			},
+		}
+
+		if err := cl.Delete(policy); err != nil && !kerrors.IsNotFound(err) {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func firewallName(np *v1alpha1.NetworkPolicy, previews bool) string {
+	if previews {
+		return np.Name + "-previews"
+	}
+
+	return np.Name
+}
+
+// buildFirewallPolicies translates the Firewall of a NetworkPolicy into
+// networking.k8s.io/v1 NetworkPolicies. Unless preview traffic is allowed,
+// preview releases get a NetworkPolicy of their own, which only allows the
+// preview releases of other Microservices.
+func buildFirewallPolicies(ms *v1alpha1.Microservice, np *v1alpha1.NetworkPolicy) ([]*networkingv1.NetworkPolicy, error) {
+	fw := np.Spec.Firewall
+	if fw == nil || (len(fw.Ingress) == 0 && len(fw.Egress) == 0) {
+		return nil, nil
+	}
+
+	if fw.AllowPreviewTraffic {
+		policy, err := buildFirewallPolicy(ms, np, firewallName(np, false), nil)
+		if err != nil {
+			return nil, err
+		}
+
+		return []*networkingv1.NetworkPolicy{policy}, nil
+	}
+
+	var policies []*networkingv1.NetworkPolicy
+	for _, op := range []metav1.LabelSelectorOperator{metav1.LabelSelectorOpNotIn, metav1.LabelSelectorOpIn} {
+		level := &metav1.LabelSelectorRequirement{
+			Key:      meta.LabelLevelKey,
+			Operator: op,
+			Values:   []string{string(v1alpha1.SemVerLevelPreview)},
+		}
+
+		policy, err := buildFirewallPolicy(ms, np, firewallName(np, op == metav1.LabelSelectorOpIn), level)
+		if err != nil {
+			return nil, err
+		}
+
+		policies = append(policies, policy)
+	}
+
+	return policies, nil
+}
+
+// buildFirewallPolicy builds a NetworkPolicy for the pods of the Microservice.
+// When a level requirement is given, it applies to both the pods and the
+// Microservices they're allowed to talk to.
+func buildFirewallPolicy(ms *v1alpha1.Microservice, np *v1alpha1.NetworkPolicy, name string, level *metav1.LabelSelectorRequirement) (*networkingv1.NetworkPolicy, error) {
+	fw := np.Spec.Firewall
+
+	cp := np.DeepCopy()
+	policy := &networkingv1.NetworkPolicy{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       "NetworkPolicy",
+			APIVersion: "networking.k8s.io/v1",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        name,
+			Namespace:   ms.Namespace,
+			Labels:      meta.Labels(cp.Labels, cp),
+			Annotations: meta.Annotations(cp.Annotations, v1alpha1.Version, cp),
+			OwnerReferences: []metav1.OwnerReference{
+				*metav1.NewControllerRef(
+					np,
+					v1alpha1.SchemeGroupVersion.WithKind("NetworkPolicy"),
+				),
+			},
+		},
+		Spec: networkingv1.NetworkPolicySpec{
+			PodSelector: levelSelector(meta.MicroserviceSelector(ms), level),
+		},
+	}
+
+	for _, rule := range fw.Ingress {
+		peers, err := firewallPeers(rule.Peers, level)
+		if err != nil {
+			return nil, err
+		}
+
+		policy.Spec.Ingress = append(policy.Spec.Ingress, networkingv1.NetworkPolicyIngressRule{
+			From:  peers,
+			Ports: firewallPorts(np, rule.Ports, true),
+		})
+	}
+
+	for _, rule := range fw.Egress {
+		peers, err := firewallPeers(rule.Peers, level)
+		if err != nil {
+			return nil, err
+		}
+
+		policy.Spec.Egress = append(policy.Spec.Egress, networkingv1.NetworkPolicyEgressRule{
+			To:    peers,
+			Ports: firewallPorts(np, rule.Ports, false),
+		})
+	}
+
+	if len(fw.Ingress) > 0 {
+		policy.Spec.PolicyTypes = append(policy.Spec.PolicyTypes, networkingv1.PolicyTypeIngress)
+	}
+
+	if len(fw.Egress) > 0 {
+		policy.Spec.PolicyTypes = append(policy.Spec.PolicyTypes, networkingv1.PolicyTypeEgress)
+	}
+
+	return policy, nil
+}
+
+func firewallPeers(peers []v1alpha1.FirewallPeer, level *metav1.LabelSelectorRequirement) ([]networkingv1.NetworkPolicyPeer, error) {
+	var npPeers []networkingv1.NetworkPolicyPeer
+	for _, peer := range peers {
+		hasCIDR := peer.CIDR != ""
+		hasPods := peer.Microservice != "" || peer.Namespace != ""
+		if hasCIDR == hasPods {
+			return nil, ErrInvalidPeer
+		}
+
+		if hasCIDR {
+			npPeers = append(npPeers, networkingv1.NetworkPolicyPeer{
+				IPBlock: &networkingv1.IPBlock{
+					CIDR:   peer.CIDR,
+					Except: peer.Except,
+				},
+			})
+			continue
+		}
+
+		var namespaceSelector *metav1.LabelSelector
+		if peer.Namespace != "" {
+			namespaceSelector = &metav1.LabelSelector{
+				MatchLabels: map[string]string{namespaceNameLabel: peer.Namespace},
+			}
+		}
+
+		if peer.Microservice != "" {
+			selector := levelSelector(meta.MicroserviceNameSelector(peer.Microservice), level)
+			npPeers = append(npPeers, networkingv1.NetworkPolicyPeer{
+				PodSelector:       &selector,
+				NamespaceSelector: namespaceSelector,
+			})
+			continue
+		}
+
+		for _, selector := range namespacePodSelectors(level) {
+			npPeers = append(npPeers, networkingv1.NetworkPolicyPeer{
+				PodSelector:       selector,
+				NamespaceSelector: namespaceSelector,
+			})
+		}
+	}
+
+	return npPeers, nil
+}
+
+// namespacePodSelectors returns the pod selectors of a peer which allows a
+// whole namespace. The releases in the namespace are restricted to the given
+// level requirement, but pods which aren't part of a release, like those of an
+// ingress controller, are always allowed.
+func namespacePodSelectors(level *metav1.LabelSelectorRequirement) []*metav1.LabelSelector {
+	if level == nil {
+		return []*metav1.LabelSelector{nil}
+	}
+
+	selector := levelSelector(nil, level)
+	selectors := []*metav1.LabelSelector{&selector}
+
+	// NotIn already matches pods without the level label.
+	if level.Operator == metav1.LabelSelectorOpIn {
+		selectors = append(selectors, &metav1.LabelSelector{
+			MatchExpressions: []metav1.LabelSelectorRequirement{
+				{Key: level.Key, Operator: metav1.LabelSelectorOpDoesNotExist},
+			},
+		})
+	}
+
+	return selectors
+}
+
+// firewallPorts converts FirewallPorts into NetworkPolicyPorts. NetworkPolicies
+// match on the ports of containers, so for incoming traffic, the names of
+// NetworkPorts are replaced with their target ports.
+func firewallPorts(np *v1alpha1.NetworkPolicy, ports []v1alpha1.FirewallPort, ingress bool) []networkingv1.NetworkPolicyPort {
+	var npPorts []networkingv1.NetworkPolicyPort
+	for _, port := range ports {
+		protocol := port.Protocol
+		if protocol == "" {
+			protocol = corev1.ProtocolTCP
+		}
+
+		target := port.Port
+		if networkPort, ok := networkPort(np.Spec.Ports, target.StrVal); ingress && target.Type == intstr.String && ok {
+			target = intstr.FromInt(int(networkPort.TargetPort))
+		}
+
+		npPorts = append(npPorts, networkingv1.NetworkPolicyPort{
+			Protocol: &protocol,
+			Port:     &target,
+		})
+	}
+
+	return npPorts
+}
+
+// levelSelector returns a LabelSelector for the given labels, restricted to
+// the given level requirement when there is one.
+func levelSelector(labels map[string]string, level *metav1.LabelSelectorRequirement) metav1.LabelSelector {
+	selector := metav1.LabelSelector{MatchLabels: labels}
+	if level != nil {
+		selector.MatchExpressions = []metav1.LabelSelectorRequirement{*level}
+	}
+
+	return selector
+}
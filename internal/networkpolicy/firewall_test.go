@@ -0,0 +1,211 @@
+package networkpolicy
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/jelmersnoeck/kubekit/patcher"
+	"github.com/manifoldco/heighliner/apis/v1alpha1"
+	"github.com/manifoldco/heighliner/internal/tester"
+
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+func TestBuildFirewallPolicies(t *testing.T) {
+	ms := &v1alpha1.Microservice{ObjectMeta: metav1.ObjectMeta{Name: "hello-world", Namespace: "testing"}}
+	np := &v1alpha1.NetworkPolicy{
+		ObjectMeta: metav1.ObjectMeta{Name: "hello-world", Namespace: "testing"},
+		Spec: v1alpha1.NetworkPolicySpec{
+			Ports: []v1alpha1.NetworkPort{{Name: "headless", Port: 80, TargetPort: 8080}},
+			Firewall: &v1alpha1.Firewall{
+				Ingress: []v1alpha1.FirewallRule{
+					{
+						Peers: []v1alpha1.FirewallPeer{
+							{Microservice: "frontend"},
+							{Namespace: "ingress-nginx"},
+						},
+						Ports: []v1alpha1.FirewallPort{{Port: intstr.FromString("headless")}},
+					},
+				},
+				Egress: []v1alpha1.FirewallRule{
+					{
+						Peers: []v1alpha1.FirewallPeer{{CIDR: "10.0.0.0/8", Except: []string{"10.1.0.0/16"}}},
+						Ports: []v1alpha1.FirewallPort{{Port: intstr.FromInt(53), Protocol: corev1.ProtocolUDP}},
+					},
+				},
+			},
+		},
+	}
+
+	t.Run("isolates previews", func(t *testing.T) {
+		policies, err := buildFirewallPolicies(ms, np)
+		if err != nil {
+			t.Fatalf("Expected no error, got %s", err)
+		}
+
+		if len(policies) != 2 {
+			t.Fatalf("Expected a policy for previews and one for other releases, got %d", len(policies))
+		}
+
+		if policies[0].Name != "hello-world" || policies[1].Name != "hello-world-previews" {
+			t.Errorf("Expected policies hello-world and hello-world-previews, got %s and %s", policies[0].Name, policies[1].Name)
+		}
+
+		previews := policies[1]
+		level := metav1.LabelSelectorRequirement{
+			Key:      "hlnr.io/microservice.level",
+			Operator: metav1.LabelSelectorOpIn,
+			Values:   []string{"preview"},
+		}
+		expectedSelector := metav1.LabelSelector{
+			MatchLabels:      map[string]string{"hlnr.io/microservice.name": "hello-world"},
+			MatchExpressions: []metav1.LabelSelectorRequirement{level},
+		}
+		if !reflect.DeepEqual(previews.Spec.PodSelector, expectedSelector) {
+			t.Errorf("Expected pod selector %+v, got %+v", expectedSelector, previews.Spec.PodSelector)
+		}
+
+		from := previews.Spec.Ingress[0].From
+		expectedFrom := []networkingv1.NetworkPolicyPeer{
+			{
+				PodSelector: &metav1.LabelSelector{
+					MatchLabels:      map[string]string{"hlnr.io/microservice.name": "frontend"},
+					MatchExpressions: []metav1.LabelSelectorRequirement{level},
+				},
+			},
+			{
+				PodSelector: &metav1.LabelSelector{
+					MatchExpressions: []metav1.LabelSelectorRequirement{level},
+				},
+				NamespaceSelector: &metav1.LabelSelector{
+					MatchLabels: map[string]string{"kubernetes.io/metadata.name": "ingress-nginx"},
+				},
+			},
+			{
+				PodSelector: &metav1.LabelSelector{
+					MatchExpressions: []metav1.LabelSelectorRequirement{
+						{Key: "hlnr.io/microservice.level", Operator: metav1.LabelSelectorOpDoesNotExist},
+					},
+				},
+				NamespaceSelector: &metav1.LabelSelector{
+					MatchLabels: map[string]string{"kubernetes.io/metadata.name": "ingress-nginx"},
+				},
+			},
+		}
+		if !reflect.DeepEqual(from, expectedFrom) {
+			t.Errorf("Expected peers %+v, got %+v", expectedFrom, from)
+		}
+
+		from = policies[0].Spec.Ingress[0].From
+		expectedNamespacePeer := networkingv1.NetworkPolicyPeer{
+			PodSelector: &metav1.LabelSelector{
+				MatchExpressions: []metav1.LabelSelectorRequirement{{
+					Key:      "hlnr.io/microservice.level",
+					Operator: metav1.LabelSelectorOpNotIn,
+					Values:   []string{"preview"},
+				}},
+			},
+			NamespaceSelector: &metav1.LabelSelector{
+				MatchLabels: map[string]string{"kubernetes.io/metadata.name": "ingress-nginx"},
+			},
+		}
+		if len(from) != 2 || !reflect.DeepEqual(from[1], expectedNamespacePeer) {
+			t.Errorf("Expected the namespace peer %+v, got %+v", expectedNamespacePeer, from)
+		}
+
+		if port := previews.Spec.Ingress[0].Ports[0].Port; *port != intstr.FromInt(8080) {
+			t.Errorf("Expected the target port 8080, got %s", port)
+		}
+
+		if block := previews.Spec.Egress[0].To[0].IPBlock; block == nil || block.CIDR != "10.0.0.0/8" {
+			t.Errorf("Expected an IP block for 10.0.0.0/8, got %+v", block)
+		}
+
+		expectedTypes := []networkingv1.PolicyType{networkingv1.PolicyTypeIngress, networkingv1.PolicyTypeEgress}
+		if !reflect.DeepEqual(previews.Spec.PolicyTypes, expectedTypes) {
+			t.Errorf("Expected policy types %v, got %v", expectedTypes, previews.Spec.PolicyTypes)
+		}
+	})
+
+	t.Run("allows preview traffic", func(t *testing.T) {
+		np := np.DeepCopy()
+		np.Spec.Firewall.AllowPreviewTraffic = true
+
+		var applied []string
+		var deleted []string
+		pc := &tester.PatchClient{
+			ApplyFunc: func(obj runtime.Object, _ ...patcher.OptionFunc) ([]byte, error) {
+				policy := obj.(*networkingv1.NetworkPolicy)
+				if policy.Spec.PodSelector.MatchExpressions != nil {
+					t.Errorf("Expected the policy to select all releases, got %+v", policy.Spec.PodSelector)
+				}
+
+				applied = append(applied, policy.Name)
+				return nil, nil
+			},
+			DeleteFunc: func(obj runtime.Object, _ ...patcher.OptionFunc) error {
+				deleted = append(deleted, obj.(*networkingv1.NetworkPolicy).Name)
+				return errors.NewNotFound(schema.GroupResource{}, "")
+			},
+		}
+
+		if err := syncFirewall(pc, ms, np); err != nil {
+			t.Fatalf("Expected no error, got %s", err)
+		}
+
+		if !reflect.DeepEqual(applied, []string{"hello-world"}) {
+			t.Errorf("Expected a single policy, got %v", applied)
+		}
+
+		if !reflect.DeepEqual(deleted, []string{"hello-world-previews"}) {
+			t.Errorf("Expected the previews policy to be removed, got %v", deleted)
+		}
+	})
+
+	t.Run("labelizes Microservice peers", func(t *testing.T) {
+		np := np.DeepCopy()
+		np.Spec.Firewall.Ingress[0].Peers[0].Microservice = "-frontend"
+
+		policies, err := buildFirewallPolicies(ms, np)
+		if err != nil {
+			t.Fatalf("Expected no error, got %s", err)
+		}
+
+		for _, policy := range policies {
+			selector := policy.Spec.Ingress[0].From[0].PodSelector
+			if name := selector.MatchLabels["hlnr.io/microservice.name"]; name != "0-frontend" {
+				t.Errorf("Expected the peer label 0-frontend in %s, got %q", policy.Name, name)
+			}
+		}
+	})
+
+	t.Run("allows namespace peers without previews", func(t *testing.T) {
+		np := np.DeepCopy()
+		np.Spec.Firewall.AllowPreviewTraffic = true
+
+		policies, err := buildFirewallPolicies(ms, np)
+		if err != nil {
+			t.Fatalf("Expected no error, got %s", err)
+		}
+
+		from := policies[0].Spec.Ingress[0].From
+		if len(from) != 2 || from[1].PodSelector != nil {
+			t.Errorf("Expected the namespace peer to select all pods, got %+v", from)
+		}
+	})
+
+	t.Run("invalid peer", func(t *testing.T) {
+		np := np.DeepCopy()
+		np.Spec.Firewall.Ingress[0].Peers[0].CIDR = "10.0.0.0/8"
+
+		if _, err := buildFirewallPolicies(ms, np); err != ErrInvalidPeer {
+			t.Errorf("Expected %s, got %v", ErrInvalidPeer, err)
+		}
+	})
+}
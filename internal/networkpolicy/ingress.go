@@ -1,8 +1,8 @@
 package networkpolicy
 
 import (
-	"bytes"
-	"html/template"
+	"fmt"
+	"sort"
 	"strconv"
 	"strings"
 
@@ -17,45 +17,185 @@ import (
 	"k8s.io/apimachinery/pkg/util/intstr"
 )
 
-func buildIngressForRelease(ms *v1alpha1.Microservice, np *v1alpha1.NetworkPolicy, release *v1alpha1.Release, srv metav1.Object) (*v1beta1.Ingress, error) {
-	if len(np.Spec.ExternalDNS) == 0 {
-		return nil, nil
+// buildIngressesForRelease builds the Ingresses for the external domains of a
+// release. The ingress class, TTL and annotations apply to all hosts of an
+// Ingress, so records which differ in these get an Ingress of their own. The
+// first Ingress is named after the release stream, the others get their index
+// as a suffix.
+func buildIngressesForRelease(ms *v1alpha1.Microservice, np *v1alpha1.NetworkPolicy, release *v1alpha1.Release, srv metav1.Object, services map[string]string) ([]*v1beta1.Ingress, error) {
+	groups := groupRecords(np.Spec.ExternalDNS)
+
+	ings := make([]*v1beta1.Ingress, len(groups))
+	for i, records := range groups {
+		ing, err := buildIngress(ms, np, release, srv, records, services)
+		if err != nil {
+			return nil, err
+		}
+
+		ing.Name = ingressName(ing.Name, i)
+		ings[i] = ing
+	}
+
+	return ings, nil
+}
+
+// ingressName returns the name of the i-th Ingress of a release stream.
+func ingressName(stream string, i int) string {
+	if i == 0 {
+		return stream
+	}
+
+	return fmt.Sprintf("%s-%d", stream, i)
+}
+
+// groupRecords groups the records which can share an Ingress, keeping the
+// order in which they're defined.
+func groupRecords(records []v1alpha1.ExternalDNS) [][]v1alpha1.ExternalDNS {
+	var groups [][]v1alpha1.ExternalDNS
+	index := map[string]int{}
+
+	for _, record := range records {
+		key := recordGroupKey(record)
+
+		i, ok := index[key]
+		if !ok {
+			i = len(groups)
+			index[key] = i
+			groups = append(groups, nil)
+		}
+
+		groups[i] = append(groups[i], record)
+	}
+
+	return groups
+}
+
+func recordGroupKey(record v1alpha1.ExternalDNS) string {
+	keys := make([]string, 0, len(record.Annotations))
+	for k := range record.Annotations {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	annotations := make([]string, len(keys))
+	for i, k := range keys {
+		annotations[i] = k + "=" + record.Annotations[k]
+	}
+
+	return strings.Join([]string{
+		ingressClass(record),
+		string(ingressController(record)),
+		ttlValue(record.TTL),
+		strconv.FormatBool(record.DisableTLS),
+		strings.Join(annotations, ","),
+	}, "|")
+}
+
+func ingressClass(record v1alpha1.ExternalDNS) string {
+	if record.IngressClass == "" {
+		return "nginx"
 	}
 
-	// TODO (jelmer): if there's different ingress classes, this should deploy
-	// different ingress objects. For now, this will do.
-	ingressClass := np.Spec.ExternalDNS[0].IngressClass
-	if ingressClass == "" {
-		ingressClass = "nginx"
+	return record.IngressClass
+}
+
+// ingressController returns the Ingress controller of a record.
+func ingressController(record v1alpha1.ExternalDNS) v1alpha1.IngressController {
+	if record.Controller != "" {
+		return record.Controller
+	}
+
+	switch c := v1alpha1.IngressController(ingressClass(record)); c {
+	case v1alpha1.IngressControllerTraefik, v1alpha1.IngressControllerContour, v1alpha1.IngressControllerHAProxy:
+		return c
+	}
+
+	return v1alpha1.IngressControllerNginx
+}
+
+// controllerAnnotations returns the annotations which configure TLS for the
+// given Ingress controller.
+func controllerAnnotations(controller v1alpha1.IngressController, disableTLS bool) map[string]string {
+	switch controller {
+	case v1alpha1.IngressControllerTraefik:
+		if disableTLS {
+			return map[string]string{"traefik.ingress.kubernetes.io/router.entrypoints": "web"}
+		}
+
+		return map[string]string{"traefik.ingress.kubernetes.io/router.tls": "true"}
+	case v1alpha1.IngressControllerContour:
+		if disableTLS {
+			return nil
+		}
+
+		return map[string]string{"ingress.kubernetes.io/force-ssl-redirect": "true"}
+	case v1alpha1.IngressControllerHAProxy:
+		if disableTLS {
+			return map[string]string{"haproxy.org/ssl-redirect": "false"}
+		}
+
+		return nil
 	}
 
-	domains := make([]string, len(np.Spec.ExternalDNS))
-	for i, record := range np.Spec.ExternalDNS {
+	// Disable SSL redirects when we don't have TLS enabled.
+	if disableTLS {
+		return map[string]string{"nginx.ingress.kubernetes.io/ssl-redirect": "false"}
+	}
+
+	return nil
+}
+
+// buildIngress builds an Ingress for a group of records which share their
+// ingress class, TTL and annotations.
+func buildIngress(ms *v1alpha1.Microservice, np *v1alpha1.NetworkPolicy, release *v1alpha1.Release, srv metav1.Object, records []v1alpha1.ExternalDNS, services map[string]string) (*v1beta1.Ingress, error) {
+	record := records[0]
+
+	domains := make([]string, len(records))
+	for i, record := range records {
 		var err error
 		if domains[i], err = templatedDomain(ms, release, record.Domain); err != nil {
 			return nil, err
 		}
 	}
 
-	labels := meta.MicroserviceLabels(ms, release, np)
+	// the labels and annotations of the NetworkPolicy are shared between the
+	// Ingresses of a release.
+	cp := np.DeepCopy()
+	labels := meta.MicroserviceLabels(ms, release, cp)
 
-	annotations := meta.Annotations(np.Annotations, v1alpha1.Version, np)
-	annotations["kubernetes.io/ingress.class"] = ingressClass
-	annotations["external-dns.alpha.kubernetes.io/hostname"] = strings.Join(domains, ",")
-	// TODO (jelmer): different TTLs should mean different Ingresses
-	annotations["external-dns.alpha.kubernetes.io/ttl"] = ttlValue(np.Spec.ExternalDNS[0].TTL)
+	annotations := meta.Annotations(cp.Annotations, v1alpha1.Version, cp)
+	annotations["kubernetes.io/ingress.class"] = ingressClass(record)
 
-	// Disable SSL redirects when we don't have TLS enabled.
-	if np.Spec.ExternalDNS[0].DisableTLS {
-		annotations["nginx.ingress.kubernetes.io/ssl-redirect"] = "false"
+	// records are managed directly when there's a DNSProvider.
+	if np.Spec.DNSProvider == nil {
+		annotations["external-dns.alpha.kubernetes.io/hostname"] = strings.Join(domains, ",")
+		annotations["external-dns.alpha.kubernetes.io/ttl"] = ttlValue(record.TTL)
 	}
 
-	ingressTLS, err := getIngressTLS(ms, release, np.Spec.ExternalDNS)
+	for k, v := range controllerAnnotations(ingressController(record), record.DisableTLS) {
+		annotations[k] = v
+	}
+
+	for k, v := range record.Annotations {
+		annotations[k] = v
+	}
+
+	ingressTLS, err := getIngressTLS(ms, np, release, records)
 	if err != nil {
 		return nil, err
 	}
 
-	ingressRules, err := getIngressRules(ms, release, np.Spec.ExternalDNS)
+	// route traffic through the activator so releases can be scaled up on
+	// demand.
+	backend := v1beta1.IngressBackend{ServiceName: release.StreamName(ms.Name)}
+	if usesActivator(np, release) {
+		backend = v1beta1.IngressBackend{
+			ServiceName: activatorServiceName(ms, release),
+			ServicePort: intstr.FromString(activatorPortName),
+		}
+	}
+
+	ingressRules, err := getIngressRules(ms, release, records, backend, services)
 	if err != nil {
 		return nil, err
 	}
@@ -86,32 +226,42 @@ func buildIngressForRelease(ms *v1alpha1.Microservice, np *v1alpha1.NetworkPolic
 	return ing, nil
 }
 
-func getIngressRules(ms *v1alpha1.Microservice, release *v1alpha1.Release, records []v1alpha1.ExternalDNS) ([]v1beta1.IngressRule, error) {
+// getIngressRules builds the rules for the given records. Paths to other
+// Microservices are routed to the Services resolved by resolvePathServices.
+func getIngressRules(ms *v1alpha1.Microservice, release *v1alpha1.Release, records []v1alpha1.ExternalDNS, backend v1beta1.IngressBackend, services map[string]string) ([]v1beta1.IngressRule, error) {
 	rules := make([]v1beta1.IngressRule, len(records))
 	for i, r := range records {
-		servicePort := "headless"
-		if r.Port != "" {
-			servicePort = r.Port
-		}
-
 		domain, err := templatedDomain(ms, release, r.Domain)
 		if err != nil {
 			return nil, err
 		}
 
+		var paths []v1beta1.HTTPIngressPath
+		for _, path := range recordPaths(r) {
+			pathBackend := v1beta1.IngressBackend{ServiceName: backend.ServiceName, ServicePort: *path.Port}
+			switch {
+			case path.Microservice != nil:
+				service, ok := services[path.Microservice.Name]
+				if !ok {
+					return nil, fmt.Errorf("unknown Microservice '%s' for path %s", path.Microservice.Name, path.Path)
+				}
+
+				pathBackend.ServiceName = service
+			case backend.ServicePort != intstr.IntOrString{}:
+				pathBackend.ServicePort = backend.ServicePort
+			}
+
+			paths = append(paths, v1beta1.HTTPIngressPath{
+				Path:    path.Path,
+				Backend: pathBackend,
+			})
+		}
+
 		rules[i] = v1beta1.IngressRule{
 			Host: domain,
 			IngressRuleValue: v1beta1.IngressRuleValue{
 				HTTP: &v1beta1.HTTPIngressRuleValue{
-					Paths: []v1beta1.HTTPIngressPath{
-						{
-							Path: "/",
-							Backend: v1beta1.IngressBackend{
-								ServiceName: release.StreamName(ms.Name),
-								ServicePort: intstr.FromString(servicePort),
-							},
-						},
-					},
+					Paths: paths,
 				},
 			},
 		}
@@ -120,55 +270,133 @@ func getIngressRules(ms *v1alpha1.Microservice, release *v1alpha1.Release, recor
 	return rules, nil
 }
 
-func getIngressTLS(ms *v1alpha1.Microservice, release *v1alpha1.Release, records []v1alpha1.ExternalDNS) ([]v1beta1.IngressTLS, error) {
-	tls := make([]v1beta1.IngressTLS, len(records))
+// resolvePathServices finds the Services the paths to other Microservices are
+// routed to, keyed by the name of the Microservice. That's the Service of the
+// release stream of the other Microservice which matches the release, so a
+// preview release is routed to the preview release of the other Microservice
+// with the same name. When the other Microservice doesn't have a release in
+// that stream, the path is routed to its release stream instead.
+func resolvePathServices(cl patchClient, np *v1alpha1.NetworkPolicy, release *v1alpha1.Release) (map[string]string, error) {
+	services := map[string]string{}
+	for _, record := range np.Spec.ExternalDNS {
+		for _, path := range record.Paths {
+			if path.Microservice == nil {
+				continue
+			}
 
-	for i, dns := range records {
-		if dns.DisableTLS {
-			continue
+			name := path.Microservice.Name
+			if _, ok := services[name]; ok {
+				continue
+			}
+
+			other := &v1alpha1.Microservice{
+				TypeMeta: metav1.TypeMeta{
+					Kind:       "Microservice",
+					APIVersion: "hlnr.io/v1alpha1",
+				},
+			}
+
+			if err := cl.Get(other, np.Namespace, name); err != nil {
+				return nil, fmt.Errorf("could not get Microservice %s for path %s: %s", name, path.Path, err)
+			}
+
+			services[name] = name
+
+			stream := release.StreamName(name)
+			for _, r := range other.Status.Releases {
+				if r.StreamName(name) == stream {
+					services[name] = stream
+					break
+				}
+			}
 		}
+	}
+
+	return services, nil
+}
+
+// recordPaths returns the paths of a record, with their defaults set.
+func recordPaths(record v1alpha1.ExternalDNS) []v1alpha1.HTTPPath {
+	servicePort := "headless"
+	if record.Port != "" {
+		servicePort = record.Port
+	}
 
-		secretName := "heighliner-components"
-		if dns.TLSGroup != "" {
-			secretName = dns.TLSGroup
+	paths := record.Paths
+	if len(paths) == 0 {
+		paths = []v1alpha1.HTTPPath{{}}
+	}
+
+	defaulted := make([]v1alpha1.HTTPPath, len(paths))
+	for i, path := range paths {
+		path := *path.DeepCopy()
+		if path.Path == "" {
+			path.Path = "/"
 		}
 
-		domain, err := templatedDomain(ms, release, dns.Domain)
+		if path.PathType == "" {
+			path.PathType = v1alpha1.PathTypePrefix
+		}
+
+		if path.Port == nil {
+			port := intstr.FromString(servicePort)
+			path.Port = &port
+		}
+
+		defaulted[i] = path
+	}
+
+	return defaulted
+}
+
+// pathOptions are the settings of a path which extensions/v1beta1 Ingresses
+// can't express.
+type pathOptions struct {
+	Type    v1alpha1.PathType
+	Rewrite string
+}
+
+// buildPathOptions returns the pathOptions of the paths of a release, keyed by
+// their host and path.
+func buildPathOptions(ms *v1alpha1.Microservice, np *v1alpha1.NetworkPolicy, release *v1alpha1.Release) (map[string]pathOptions, error) {
+	options := map[string]pathOptions{}
+	for _, record := range np.Spec.ExternalDNS {
+		domain, err := templatedDomain(ms, release, record.Domain)
 		if err != nil {
 			return nil, err
 		}
 
-		tls[i] = v1beta1.IngressTLS{
-			Hosts:      []string{domain},
-			SecretName: secretName,
+		for _, path := range recordPaths(record) {
+			options[domain+path.Path] = pathOptions{
+				Type:    path.PathType,
+				Rewrite: path.Rewrite,
+			}
 		}
 	}
 
-	return tls, nil
+	return options, nil
 }
 
-func templatedDomain(ms *v1alpha1.Microservice, release *v1alpha1.Release, domain string) (string, error) {
-	tmpl, err := template.New("domain").Parse(domain)
-	if err != nil {
-		return "", err
-	}
+func getIngressTLS(ms *v1alpha1.Microservice, np *v1alpha1.NetworkPolicy, release *v1alpha1.Release, records []v1alpha1.ExternalDNS) ([]v1beta1.IngressTLS, error) {
+	tls := make([]v1beta1.IngressTLS, len(records))
 
-	data := struct {
-		FullName   string
-		StreamName string
-		Name       string
-	}{
+	for i, dns := range records {
+		if dns.DisableTLS {
+			continue
+		}
 
-		FullName:   release.FullName(ms.Name),
-		StreamName: release.StreamName(ms.Name),
-		Name:       release.Name(),
-	}
+		domain, err := templatedDomain(ms, release, dns.Domain)
+		if err != nil {
+			return nil, err
+		}
 
-	buf := bytes.NewBufferString("")
-	if err := tmpl.Execute(buf, data); err != nil {
-		return "", err
+		tls[i] = v1beta1.IngressTLS{
+			Hosts:      []string{domain},
+			SecretName: tlsSecretName(np, dns),
+		}
 	}
-	return buf.String(), nil
+
+	return tls, nil
 }
 
 func ttlValue(ttl int32) string {
@@ -6,8 +6,13 @@ import (
 
 	"github.com/jelmersnoeck/kubekit"
 	"github.com/manifoldco/heighliner/apis/v1alpha1"
+	"github.com/manifoldco/heighliner/internal/tester"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/api/extensions/v1beta1"
+	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/intstr"
 )
 
 func TestBuildIngressForRelease(t *testing.T) {
@@ -44,11 +49,12 @@ func TestBuildIngressForRelease(t *testing.T) {
 			},
 		}
 
-		ing, err := buildIngressForRelease(ms, np, release, srv)
+		ings, err := buildIngressesForRelease(ms, np, release, srv, nil)
 		if err != nil {
 			t.Error("Expected no err. got:", err)
 		}
 
+		ing := ings[0]
 		if len(ing.OwnerReferences) != 1 {
 			t.Error("Wrong number of owners:", len(ing.OwnerReferences))
 		}
@@ -63,17 +69,226 @@ func TestBuildIngressForRelease(t *testing.T) {
 		}
 	})
 
-	t.Run("It is nil with no external dns", func(t *testing.T) {
+	t.Run("It is empty with no external dns", func(t *testing.T) {
 
-		ing, err := buildIngressForRelease(ms, np, release, srv)
+		ings, err := buildIngressesForRelease(ms, np, release, srv, nil)
 		if err != nil {
 			t.Error("Expected no err. got:", err)
 		}
 
-		if ing != nil {
-			t.Error("Expected no ingress. got:", ing)
+		if len(ings) != 0 {
+			t.Error("Expected no ingresses. got:", ings)
 		}
 	})
+
+	t.Run("Groups records per ingress class and TTL", func(t *testing.T) {
+		np := &v1alpha1.NetworkPolicy{
+			Spec: v1alpha1.NetworkPolicySpec{
+				ExternalDNS: []v1alpha1.ExternalDNS{
+					{Domain: "public.hlnr.io"},
+					{Domain: "internal.hlnr.io", IngressClass: "traefik", TTL: 60},
+					{Domain: "www.hlnr.io"},
+					{Domain: "admin.hlnr.io", IngressClass: "traefik", TTL: 60, Annotations: map[string]string{
+						"traefik.ingress.kubernetes.io/router.middlewares": "default-auth@kubernetescrd",
+					}},
+				},
+			},
+		}
+
+		ings, err := buildIngressesForRelease(ms, np, release, srv, nil)
+		if err != nil {
+			t.Fatal("Expected no err. got:", err)
+		}
+
+		if len(ings) != 3 {
+			t.Fatal("Expected 3 ingresses. got:", len(ings))
+		}
+
+		expected := []struct {
+			name, class, hostname, ttl string
+		}{
+			{"hello-world", "nginx", "public.hlnr.io,www.hlnr.io", "300"},
+			{"hello-world-1", "traefik", "internal.hlnr.io", "60"},
+			{"hello-world-2", "traefik", "admin.hlnr.io", "60"},
+		}
+
+		for i, e := range expected {
+			ing := ings[i]
+			if ing.Name != e.name {
+				t.Errorf("Expected ingress %s. got: %s", e.name, ing.Name)
+			}
+
+			if class := ing.Annotations["kubernetes.io/ingress.class"]; class != e.class {
+				t.Errorf("Expected class %s for %s. got: %s", e.class, e.name, class)
+			}
+
+			if hostname := ing.Annotations["external-dns.alpha.kubernetes.io/hostname"]; hostname != e.hostname {
+				t.Errorf("Expected hostnames %s for %s. got: %s", e.hostname, e.name, hostname)
+			}
+
+			if ttl := ing.Annotations["external-dns.alpha.kubernetes.io/ttl"]; ttl != e.ttl {
+				t.Errorf("Expected TTL %s for %s. got: %s", e.ttl, e.name, ttl)
+			}
+		}
+
+		if ings[1].Annotations["traefik.ingress.kubernetes.io/router.tls"] != "true" {
+			t.Error("Expected the traefik TLS annotation. got:", ings[1].Annotations)
+		}
+
+		if _, ok := ings[1].Annotations["traefik.ingress.kubernetes.io/router.middlewares"]; ok {
+			t.Error("Didn't expect the annotations of another record")
+		}
+
+		if ings[2].Annotations["traefik.ingress.kubernetes.io/router.middlewares"] != "default-auth@kubernetescrd" {
+			t.Error("Expected the record annotations. got:", ings[2].Annotations)
+		}
+	})
+
+	t.Run("Routes paths to their backends", func(t *testing.T) {
+		metrics := intstr.FromInt(9090)
+		np := &v1alpha1.NetworkPolicy{
+			Spec: v1alpha1.NetworkPolicySpec{
+				ExternalDNS: []v1alpha1.ExternalDNS{
+					{
+						Domain: "hlnr.io",
+						Port:   "http",
+						Paths: []v1alpha1.HTTPPath{
+							{Path: "/"},
+							{Path: "/api", Microservice: &corev1.LocalObjectReference{Name: "api"}, Rewrite: "/"},
+							{Path: "/metrics", PathType: v1alpha1.PathTypeExact, Port: &metrics},
+						},
+					},
+				},
+			},
+		}
+
+		ings, err := buildIngressesForRelease(ms, np, release, srv, map[string]string{"api": "api-rc"})
+		if err != nil {
+			t.Fatal("Expected no err. got:", err)
+		}
+
+		expected := []v1beta1.HTTPIngressPath{
+			{Path: "/", Backend: v1beta1.IngressBackend{ServiceName: "hello-world", ServicePort: intstr.FromString("http")}},
+			{Path: "/api", Backend: v1beta1.IngressBackend{ServiceName: "api-rc", ServicePort: intstr.FromString("http")}},
+			{Path: "/metrics", Backend: v1beta1.IngressBackend{ServiceName: "hello-world", ServicePort: metrics}},
+		}
+		if paths := ings[0].Spec.Rules[0].HTTP.Paths; !reflect.DeepEqual(paths, expected) {
+			t.Errorf("Expected paths %+v. got: %+v", expected, paths)
+		}
+
+		options, err := buildPathOptions(ms, np, release)
+		if err != nil {
+			t.Fatal("Expected no err. got:", err)
+		}
+
+		if _, err := buildIngressesForRelease(ms, np, release, srv, nil); err == nil {
+			t.Error("Expected an error for a path to an unresolved Microservice")
+		}
+
+		route := streamRoute{PathOptions: options}
+		if opts := route.pathOptions("hlnr.io", "/api"); opts.Type != v1alpha1.PathTypePrefix || opts.Rewrite != "/" {
+			t.Errorf("Expected a prefix path with a rewrite. got: %+v", opts)
+		}
+
+		if opts := route.pathOptions("hlnr.io", "/metrics"); opts.Type != v1alpha1.PathTypeExact {
+			t.Errorf("Expected an exact path. got: %+v", opts)
+		}
+	})
+}
+
+func TestResolvePathServices(t *testing.T) {
+	np := &v1alpha1.NetworkPolicy{
+		ObjectMeta: metav1.ObjectMeta{Name: "hello-world", Namespace: "testing"},
+		Spec: v1alpha1.NetworkPolicySpec{
+			ExternalDNS: []v1alpha1.ExternalDNS{
+				{
+					Domain: "hlnr.io",
+					Paths: []v1alpha1.HTTPPath{
+						{Path: "/"},
+						{Path: "/api", Microservice: &corev1.LocalObjectReference{Name: "api"}},
+					},
+				},
+			},
+		},
+	}
+
+	branch := func(name string) v1alpha1.Release {
+		return v1alpha1.Release{
+			SemVer: &v1alpha1.SemVerRelease{Name: name, Version: "1.1.0-" + name},
+			Level:  v1alpha1.SemVerLevelPreview,
+		}
+	}
+
+	pc := &tester.PatchClient{
+		GetFunc: func(obj interface{}, namespace, name string) error {
+			if name != "api" {
+				return errors.NewNotFound(schema.GroupResource{}, name)
+			}
+
+			ms := obj.(*v1alpha1.Microservice)
+			ms.Name = name
+			ms.Status.Releases = []v1alpha1.Release{
+				{SemVer: &v1alpha1.SemVerRelease{Name: "api", Version: "1.0.0"}, Level: v1alpha1.SemVerLevelRelease},
+				branch("my-branch"),
+			}
+			return nil
+		},
+	}
+
+	tcs := []struct {
+		name     string
+		release  v1alpha1.Release
+		expected string
+	}{
+		{"preview with a matching preview", branch("my-branch"), branch("my-branch").StreamName("api")},
+		{"preview without a matching preview", branch("other-branch"), "api"},
+		{"release candidate without a release candidate", v1alpha1.Release{
+			SemVer: &v1alpha1.SemVerRelease{Name: "hello-world", Version: "1.1.0-rc.1"},
+			Level:  v1alpha1.SemVerLevelReleaseCandidate,
+		}, "api"},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			services, err := resolvePathServices(pc, np, &tc.release)
+			if err != nil {
+				t.Fatal("Expected no err. got:", err)
+			}
+
+			if expected := map[string]string{"api": tc.expected}; !reflect.DeepEqual(services, expected) {
+				t.Errorf("Expected %v. got: %v", expected, services)
+			}
+		})
+	}
+
+	t.Run("with an unknown Microservice", func(t *testing.T) {
+		np := np.DeepCopy()
+		np.Spec.ExternalDNS[0].Paths[1].Microservice.Name = "unknown"
+
+		if _, err := resolvePathServices(pc, np, &tcs[0].release); err == nil {
+			t.Error("Expected an error for an unknown Microservice")
+		}
+	})
+}
+
+func TestControllerAnnotations(t *testing.T) {
+	tcs := []struct {
+		record   v1alpha1.ExternalDNS
+		expected map[string]string
+	}{
+		{v1alpha1.ExternalDNS{}, nil},
+		{v1alpha1.ExternalDNS{DisableTLS: true}, map[string]string{"nginx.ingress.kubernetes.io/ssl-redirect": "false"}},
+		{v1alpha1.ExternalDNS{IngressClass: "contour"}, map[string]string{"ingress.kubernetes.io/force-ssl-redirect": "true"}},
+		{v1alpha1.ExternalDNS{IngressClass: "public", Controller: v1alpha1.IngressControllerHAProxy, DisableTLS: true}, map[string]string{"haproxy.org/ssl-redirect": "false"}},
+		{v1alpha1.ExternalDNS{IngressClass: "traefik", DisableTLS: true}, map[string]string{"traefik.ingress.kubernetes.io/router.entrypoints": "web"}},
+	}
+
+	for _, tc := range tcs {
+		annotations := controllerAnnotations(ingressController(tc.record), tc.record.DisableTLS)
+		if !reflect.DeepEqual(annotations, tc.expected) {
+			t.Errorf("Expected %v for %+v. got: %v", tc.expected, tc.record, annotations)
+		}
+	}
 }
 
 func TestTemplatedDomain(t *testing.T) {
@@ -82,12 +297,15 @@ func TestTemplatedDomain(t *testing.T) {
 			Name:    "hello-world",
 			Version: "0.0.1",
 		},
-		Level: v1alpha1.SemVerLevelPreview,
+		Level:       v1alpha1.SemVerLevelPreview,
+		PullRequest: 42,
+		SHA:         "8d2ab3f0c1b2a3d4e5f60718293a4b5c6d7e8f90",
 	}
 
 	ms := &v1alpha1.Microservice{
 		ObjectMeta: metav1.ObjectMeta{
-			Name: "hello-world",
+			Name:      "hello-world",
+			Namespace: "testing",
 		},
 	}
 
@@ -99,6 +317,10 @@ func TestTemplatedDomain(t *testing.T) {
 		{"{{.FullName}}.arigato.tools", "hello-world-pr-cmqolv9f-svek39uq.arigato.tools", nil},
 		{"{{.Name}}.arigato.tools", "hello-world.arigato.tools", nil},
 		{"{{.StreamName}}.arigato.tools", "hello-world-pr-cmqolv9f.arigato.tools", nil},
+		{"pr-{{.PullRequest}}.{{.Namespace}}.arigato.tools", "pr-42.testing.arigato.tools", nil},
+		{"{{.ShortSHA}}.arigato.tools", "8d2ab3f.arigato.tools", nil},
+		{"{{.Microservice}}-{{.Level}}-{{.Version | slugify}}.arigato.tools", "hello-world-preview-0-0-1.arigato.tools", nil},
+		{"{{.Name | truncate 5}}-{{hash .Name}}.arigato.tools", "hello-" + hash("hello-world") + ".arigato.tools", nil},
 	}
 
 	for _, item := range testData {
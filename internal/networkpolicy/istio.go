@@ -0,0 +1,322 @@
+package networkpolicy
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/manifoldco/heighliner/apis/v1alpha1"
+	"github.com/manifoldco/heighliner/internal/meta"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/api/extensions/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+// HeaderStream is the header which routes a request to the release stream it
+// names, regardless of the domain it was sent to.
+const HeaderStream = "x-hlnr-stream"
+
+// istioRouter combines the routes of all release streams of a Microservice
+// into a single VirtualService and DestinationRule. All releases are reached
+// through a mesh Service which selects the pods of every release, and each
+// release becomes a subset of that Service.
+type istioRouter struct {
+	ms       *v1alpha1.Microservice
+	np       *v1alpha1.NetworkPolicy
+	gateways []string
+
+	routes []streamRoute
+}
+
+// Routes keeps track of the route of a stream, all objects are built through
+// Objects.
+func (r *istioRouter) Routes(route streamRoute) ([]runtime.Object, error) {
+	r.routes = append(r.routes, route)
+	return nil, nil
+}
+
+func (r *istioRouter) CanaryRoute(*v1beta1.Ingress) runtime.Object {
+	return nil
+}
+
+func (r *istioRouter) IngressRoutes(*v1beta1.Ingress) []runtime.Object {
+	return nil
+}
+
+func (r *istioRouter) HostRoute(*v1beta1.Ingress, int) runtime.Object {
+	return nil
+}
+
+// Objects builds the mesh Service, DestinationRule and VirtualService for the
+// routes of all streams.
+func (r *istioRouter) Objects() ([]runtime.Object, error) {
+	if len(r.np.Spec.Ports) == 0 {
+		return nil, nil
+	}
+
+	sort.Slice(r.routes, func(i, j int) bool {
+		return r.routes[i].Release.StreamName(r.ms.Name) < r.routes[j].Release.StreamName(r.ms.Name)
+	})
+
+	vs, err := r.virtualService()
+	if err != nil {
+		return nil, err
+	}
+
+	return []runtime.Object{r.meshService(), r.destinationRule(), vs}, nil
+}
+
+func (r *istioRouter) objectMeta(name string) metav1.ObjectMeta {
+	np := r.np.DeepCopy()
+
+	return metav1.ObjectMeta{
+		Name:        name,
+		Namespace:   r.ms.Namespace,
+		Labels:      meta.Labels(np.Labels, np),
+		Annotations: meta.Annotations(np.Annotations, v1alpha1.Version, np),
+		OwnerReferences: []metav1.OwnerReference{
+			*metav1.NewControllerRef(
+				r.np,
+				v1alpha1.SchemeGroupVersion.WithKind("NetworkPolicy"),
+			),
+		},
+	}
+}
+
+func (r *istioRouter) meshServiceName() string {
+	return r.ms.Name + "-mesh"
+}
+
+func (r *istioRouter) meshHost() string {
+	return fmt.Sprintf("%s.%s.svc.cluster.local", r.meshServiceName(), r.ms.Namespace)
+}
+
+// meshService builds the Service which selects the pods of all releases.
+func (r *istioRouter) meshService() *corev1.Service {
+	return &corev1.Service{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       "Service",
+			APIVersion: "v1",
+		},
+		ObjectMeta: r.objectMeta(r.meshServiceName()),
+		Spec: corev1.ServiceSpec{
+			Type:     corev1.ServiceTypeClusterIP,
+			Ports:    getServicePorts(r.np.Spec.Ports),
+			Selector: meta.MicroserviceSelector(r.ms),
+		},
+	}
+}
+
+// destinationRule builds a DestinationRule with a subset for each release
+// which receives traffic.
+func (r *istioRouter) destinationRule() runtime.Object {
+	var subsets []interface{}
+	seen := map[string]bool{}
+
+	addSubset := func(release *v1alpha1.Release) {
+		name := release.FullName(r.ms.Name)
+		if seen[name] {
+			return
+		}
+		seen[name] = true
+
+		labels := map[string]interface{}{}
+		for k, v := range meta.ReleaseSelector(r.ms, release) {
+			labels[k] = v
+		}
+
+		subsets = append(subsets, map[string]interface{}{
+			"name":   name,
+			"labels": labels,
+		})
+	}
+
+	for _, route := range r.routes {
+		addSubset(route.Release)
+		if route.Canary != nil {
+			addSubset(route.Canary)
+		}
+	}
+
+	obj := newUnstructured(istioGroupVersion, "DestinationRule", r.objectMeta(r.ms.Name))
+	obj.Object["spec"] = map[string]interface{}{
+		"host":    r.meshHost(),
+		"subsets": subsets,
+	}
+
+	return obj
+}
+
+// virtualService builds the VirtualService which routes traffic to the
+// subsets. Requests with the stream header or matching the RoutingRules of a
+// preview stream are routed to that preview stream first. Other requests are routed based on their domain, and traffic
+// within the mesh goes to the release stream of the Microservice.
+func (r *istioRouter) virtualService() (runtime.Object, error) {
+	hosts := []interface{}{}
+	var headerRoutes, domainRoutes, defaultRoutes []interface{}
+
+	for _, route := range r.routes {
+		stream := route.Release.StreamName(r.ms.Name)
+
+		if route.Release.Level == v1alpha1.SemVerLevelPreview {
+			headerRoutes = append(headerRoutes, map[string]interface{}{
+				"name": stream + "-header",
+				"match": []interface{}{
+					map[string]interface{}{
+						"headers": map[string]interface{}{
+							HeaderStream: map[string]interface{}{"exact": stream},
+						},
+					},
+				},
+				"route": r.destinations(route, 0),
+			})
+		}
+
+		if len(route.Matches) > 0 {
+			var matches []interface{}
+			for _, match := range route.Matches {
+				matches = append(matches, istioMatch(match))
+			}
+
+			headerRoutes = append(headerRoutes, map[string]interface{}{
+				"name":  stream + "-rules",
+				"match": matches,
+				"route": r.destinations(route, 0),
+			})
+		}
+
+		if stream == r.ms.Name {
+			defaultRoutes = append(defaultRoutes, map[string]interface{}{
+				"name":  stream,
+				"route": r.destinations(route, 0),
+			})
+		}
+
+		for i, rule := range route.rules() {
+			hosts = append(hosts, rule.Host)
+
+			for j, path := range rule.HTTP.Paths {
+				port, err := portNumber(r.np, path.Backend.ServicePort)
+				if err != nil {
+					return nil, err
+				}
+
+				opts := route.pathOptions(rule.Host, path.Path)
+				uri := map[string]interface{}{"prefix": path.Path}
+				if opts.Type == v1alpha1.PathTypeExact {
+					uri = map[string]interface{}{"exact": path.Path}
+				}
+
+				destinations := r.destinations(route, port)
+				if path.Backend.ServiceName != stream {
+					// paths to other Microservices go to their Service.
+					destinations = []interface{}{
+						map[string]interface{}{
+							"destination": map[string]interface{}{
+								"host": fmt.Sprintf("%s.%s.svc.cluster.local", path.Backend.ServiceName, r.ms.Namespace),
+								"port": map[string]interface{}{"number": int64(port)},
+							},
+						},
+					}
+				}
+
+				domainRoute := map[string]interface{}{
+					"name": fmt.Sprintf("%s-%d-%d", stream, i, j),
+					"match": []interface{}{
+						map[string]interface{}{
+							"authority": map[string]interface{}{"exact": rule.Host},
+							"uri":       uri,
+						},
+					},
+					"route": destinations,
+				}
+
+				if opts.Rewrite != "" {
+					domainRoute["rewrite"] = map[string]interface{}{"uri": opts.Rewrite}
+				}
+
+				domainRoutes = append(domainRoutes, domainRoute)
+			}
+		}
+	}
+
+	hosts = append(hosts, r.meshHost())
+
+	var http []interface{}
+	http = append(http, headerRoutes...)
+	http = append(http, domainRoutes...)
+	http = append(http, defaultRoutes...)
+
+	spec := map[string]interface{}{
+		"hosts": hosts,
+		"http":  http,
+	}
+
+	if len(r.gateways) > 0 {
+		gateways := []interface{}{"mesh"}
+		for _, gw := range r.gateways {
+			gateways = append(gateways, gw)
+		}
+
+		spec["gateways"] = gateways
+	}
+
+	obj := newUnstructured(istioGroupVersion, "VirtualService", r.objectMeta(r.ms.Name))
+	obj.Object["spec"] = spec
+
+	return obj, nil
+}
+
+// destinations returns the weighted destinations of a stream. Without a port,
+// the port of the request is used.
+func (r *istioRouter) destinations(route streamRoute, port int32) []interface{} {
+	destination := func(release *v1alpha1.Release) map[string]interface{} {
+		d := map[string]interface{}{
+			"host":   r.meshHost(),
+			"subset": release.FullName(r.ms.Name),
+		}
+
+		if port != 0 {
+			d["port"] = map[string]interface{}{"number": int64(port)}
+		}
+
+		return d
+	}
+
+	if route.Canary == nil {
+		return []interface{}{
+			map[string]interface{}{"destination": destination(route.Release)},
+		}
+	}
+
+	return []interface{}{
+		map[string]interface{}{
+			"destination": destination(route.Release),
+			"weight":      int64(100 - route.CanaryWeight),
+		},
+		map[string]interface{}{
+			"destination": destination(route.Canary),
+			"weight":      int64(route.CanaryWeight),
+		},
+	}
+}
+
+// istioMatch converts a routeMatch into a VirtualService match. Istio requires
+// header names to be lowercase.
+func istioMatch(match routeMatch) map[string]interface{} {
+	if match.Cookie != "" {
+		return map[string]interface{}{
+			"headers": map[string]interface{}{
+				"cookie": map[string]interface{}{"regex": cookieRegex(match.Cookie, match.Value)},
+			},
+		}
+	}
+
+	return map[string]interface{}{
+		"headers": map[string]interface{}{
+			strings.ToLower(match.Header): map[string]interface{}{"exact": match.Value},
+		},
+	}
+}
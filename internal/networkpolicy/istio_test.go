@@ -0,0 +1,193 @@
+package networkpolicy
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/manifoldco/heighliner/apis/v1alpha1"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/api/extensions/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+func TestIstioRouter(t *testing.T) {
+	ms := &v1alpha1.Microservice{
+		ObjectMeta: metav1.ObjectMeta{Name: "hello-world", Namespace: "testing"},
+	}
+
+	np := &v1alpha1.NetworkPolicy{
+		ObjectMeta: metav1.ObjectMeta{Name: "hello-world", Namespace: "testing"},
+		Spec: v1alpha1.NetworkPolicySpec{
+			Ports: []v1alpha1.NetworkPort{{Name: "headless", Port: 80, TargetPort: 8080}},
+		},
+	}
+
+	stable := &v1alpha1.Release{
+		SemVer: &v1alpha1.SemVerRelease{Name: "hello-world", Version: "1.0.0"},
+		Level:  v1alpha1.SemVerLevelRelease,
+	}
+	canary := &v1alpha1.Release{
+		SemVer: &v1alpha1.SemVerRelease{Name: "hello-world", Version: "1.1.0"},
+		Level:  v1alpha1.SemVerLevelRelease,
+	}
+	preview := &v1alpha1.Release{
+		SemVer: &v1alpha1.SemVerRelease{Name: "feature", Version: "1.2.0-feature"},
+		Level:  v1alpha1.SemVerLevelPreview,
+	}
+
+	ing := &v1beta1.Ingress{
+		Spec: v1beta1.IngressSpec{
+			Rules: []v1beta1.IngressRule{
+				{
+					Host: "hello-world.hlnr.io",
+					IngressRuleValue: v1beta1.IngressRuleValue{
+						HTTP: &v1beta1.HTTPIngressRuleValue{
+							Paths: []v1beta1.HTTPIngressPath{
+								{
+									Path: "/",
+									Backend: v1beta1.IngressBackend{
+										ServiceName: stable.StreamName(ms.Name),
+										ServicePort: intstr.FromString("headless"),
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	rt := &istioRouter{ms: ms, np: np, gateways: []string{"istio-system/public"}}
+
+	for _, route := range []streamRoute{
+		{Release: preview},
+		{Release: stable, Ingresses: []*v1beta1.Ingress{ing}, Canary: canary, CanaryWeight: 25},
+	} {
+		objs, err := rt.Routes(route)
+		if err != nil {
+			t.Fatalf("Expected no error, got %s", err)
+		}
+
+		if len(objs) != 0 {
+			t.Errorf("Expected no objects per stream, got %d", len(objs))
+		}
+	}
+
+	objs, err := rt.Objects()
+	if err != nil {
+		t.Fatalf("Expected no error, got %s", err)
+	}
+
+	if len(objs) != 3 {
+		t.Fatalf("Expected a Service, DestinationRule and VirtualService, got %d objects", len(objs))
+	}
+
+	meshHost := "hello-world-mesh.testing.svc.cluster.local"
+
+	t.Run("mesh Service", func(t *testing.T) {
+		srv := objs[0].(*corev1.Service)
+		if srv.Name != "hello-world-mesh" {
+			t.Errorf("Expected Service hello-world-mesh, got %s", srv.Name)
+		}
+
+		expected := map[string]string{"hlnr.io/microservice.name": "hello-world"}
+		if !reflect.DeepEqual(srv.Spec.Selector, expected) {
+			t.Errorf("Expected selector %v, got %v", expected, srv.Spec.Selector)
+		}
+	})
+
+	t.Run("DestinationRule", func(t *testing.T) {
+		obj := objs[1].(*unstructured.Unstructured).DeepCopy()
+		if obj.GetKind() != "DestinationRule" || obj.GetAPIVersion() != istioGroupVersion {
+			t.Errorf("Expected a DestinationRule, got %s %s", obj.GetAPIVersion(), obj.GetKind())
+		}
+
+		host, _ := unstructured.NestedString(obj.Object, "spec", "host")
+		if host != meshHost {
+			t.Errorf("Expected host %s, got %s", meshHost, host)
+		}
+
+		subsets, _ := unstructured.NestedSlice(obj.Object, "spec", "subsets")
+		if len(subsets) != 3 {
+			t.Fatalf("Expected a subset per release, got %d", len(subsets))
+		}
+
+		subset := subsets[1].(map[string]interface{})
+		if subset["name"] != canary.FullName(ms.Name) {
+			t.Errorf("Expected subset %s, got %v", canary.FullName(ms.Name), subset["name"])
+		}
+
+		expected := map[string]interface{}{
+			"hlnr.io/microservice.name":    "hello-world",
+			"hlnr.io/microservice.release": "hello-world",
+			"hlnr.io/microservice.version": "1.1.0",
+		}
+		if !reflect.DeepEqual(subset["labels"], expected) {
+			t.Errorf("Expected labels %v, got %v", expected, subset["labels"])
+		}
+	})
+
+	t.Run("VirtualService", func(t *testing.T) {
+		obj := objs[2].(*unstructured.Unstructured).DeepCopy()
+		if obj.GetKind() != "VirtualService" || obj.GetAPIVersion() != istioGroupVersion {
+			t.Errorf("Expected a VirtualService, got %s %s", obj.GetAPIVersion(), obj.GetKind())
+		}
+
+		hosts, _ := unstructured.NestedStringSlice(obj.Object, "spec", "hosts")
+		if expected := []string{"hello-world.hlnr.io", meshHost}; !reflect.DeepEqual(hosts, expected) {
+			t.Errorf("Expected hosts %v, got %v", expected, hosts)
+		}
+
+		gateways, _ := unstructured.NestedStringSlice(obj.Object, "spec", "gateways")
+		if expected := []string{"mesh", "istio-system/public"}; !reflect.DeepEqual(gateways, expected) {
+			t.Errorf("Expected gateways %v, got %v", expected, gateways)
+		}
+
+		http, _ := unstructured.NestedSlice(obj.Object, "spec", "http")
+		if len(http) != 3 {
+			t.Fatalf("Expected a header, domain and default route, got %d", len(http))
+		}
+
+		header := http[0].(map[string]interface{})
+		match := header["match"].([]interface{})[0].(map[string]interface{})
+		expectedMatch := map[string]interface{}{
+			"headers": map[string]interface{}{
+				HeaderStream: map[string]interface{}{"exact": preview.StreamName(ms.Name)},
+			},
+		}
+		if !reflect.DeepEqual(match, expectedMatch) {
+			t.Errorf("Expected header match %v, got %v", expectedMatch, match)
+		}
+
+		domain := http[1].(map[string]interface{})
+		expectedRoute := []interface{}{
+			map[string]interface{}{
+				"destination": map[string]interface{}{
+					"host":   meshHost,
+					"subset": stable.FullName(ms.Name),
+					"port":   map[string]interface{}{"number": int64(80)},
+				},
+				"weight": int64(75),
+			},
+			map[string]interface{}{
+				"destination": map[string]interface{}{
+					"host":   meshHost,
+					"subset": canary.FullName(ms.Name),
+					"port":   map[string]interface{}{"number": int64(80)},
+				},
+				"weight": int64(25),
+			},
+		}
+		if !reflect.DeepEqual(domain["route"], expectedRoute) {
+			t.Errorf("Expected weighted route %v, got %v", expectedRoute, domain["route"])
+		}
+
+		if _, ok := http[2].(map[string]interface{})["match"]; ok {
+			t.Error("Expected the default route to match all traffic")
+		}
+	})
+}
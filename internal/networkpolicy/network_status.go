@@ -2,6 +2,8 @@ package networkpolicy
 
 import (
 	"fmt"
+	"reflect"
+	"strings"
 
 	"github.com/manifoldco/heighliner/apis/v1alpha1"
 )
@@ -10,29 +12,107 @@ func buildNetworkStatusDomainsForRelease(ms *v1alpha1.Microservice, np *v1alpha1
 	domains := []v1alpha1.Domain{}
 
 	for _, record := range np.Spec.ExternalDNS {
-		url, err := templatedDomain(ms, release, getFullURL(record))
+		domain, err := templatedDomain(ms, release, record.Domain)
 		if err != nil {
-			// XXX: handle gracefully
-			panic(err)
+			return nil, err
 		}
 
-		domain := v1alpha1.Domain{
-			URL:    url,
-			SemVer: release.SemVer,
-		}
-		domains = append(domains, domain)
+		domains = append(domains, v1alpha1.NewDomain(getFullURL(record, domain), release))
 	}
 
 	return domains, nil
 }
 
-func getFullURL(dns v1alpha1.ExternalDNS) string {
+func getFullURL(dns v1alpha1.ExternalDNS, domain string) string {
 	scheme := "https://"
 	if dns.DisableTLS {
 		scheme = "http://"
 	}
 
-	return fmt.Sprintf("%s%s", scheme, dns.Domain)
+	return fmt.Sprintf("%s%s", scheme, domain)
+}
+
+// setConditions derives the conditions of a NetworkPolicy from its new status.
+// Streams which failed to sync and rollouts which need attention, like a
+// canary release which was rolled back, mark the NetworkPolicy as degraded
+// while the stable releases keep serving.
+func setConditions(np *v1alpha1.NetworkPolicy, status *v1alpha1.NetworkPolicyStatus) {
+	status.ObservedGeneration = np.Generation
+	status.Conditions = np.Status.Conditions.DeepCopy()
+
+	if status.Error != "" {
+		status.Conditions.MarkDegraded("SyncError", status.Error)
+		return
+	}
+
+	if len(status.Streams) == 0 {
+		status.Conditions.MarkProgressing("NoReleases", "waiting for releases of the Microservice")
+		return
+	}
+
+	var progressing []string
+	for _, stream := range status.Streams {
+		switch {
+		case stream.Error != "":
+			status.Conditions.MarkDegraded("StreamError", fmt.Sprintf("stream %s: %s", stream.Name, stream.Error))
+			return
+		case stream.Canary != nil && stream.Canary.Phase == v1alpha1.CanaryRolledBack:
+			status.Conditions.MarkDegraded("CanaryRolledBack", fmt.Sprintf("canary release %s of stream %s was rolled back", stream.Canary.Version, stream.Name))
+			return
+		case stream.Verification != nil && stream.Verification.Phase == v1alpha1.VerificationFailed:
+			status.Conditions.MarkDegraded("VerificationFailed", fmt.Sprintf("release %s of stream %s failed its verification", stream.Verification.Version, stream.Name))
+			return
+		case stream.Canary != nil, stream.Verification != nil:
+			progressing = append(progressing, stream.Name)
+		}
+	}
+
+	if len(progressing) > 0 {
+		status.Conditions.MarkProgressing("RolloutInProgress", fmt.Sprintf("rolling out streams %s", strings.Join(progressing, ", ")))
+		return
+	}
+
+	status.Conditions.MarkReady("Synced", "")
+}
+
+func networkStatusEqual(old, new v1alpha1.NetworkPolicyStatus) bool {
+	if old.Error != new.Error || len(old.Streams) != len(new.Streams) {
+		return false
+	}
+
+	if len(old.Streams) != 0 && !reflect.DeepEqual(old.Streams, new.Streams) {
+		return false
+	}
+
+	if len(old.Certificates) != len(new.Certificates) {
+		return false
+	}
+
+	if len(old.Certificates) != 0 && !reflect.DeepEqual(old.Certificates, new.Certificates) {
+		return false
+	}
+
+	if len(old.DNSRecords) != len(new.DNSRecords) {
+		return false
+	}
+
+	if len(old.DNSRecords) != 0 && !reflect.DeepEqual(old.DNSRecords, new.DNSRecords) {
+		return false
+	}
+
+	if !reflect.DeepEqual(old.DNSProvider, new.DNSProvider) {
+		return false
+	}
+
+	if !reflect.DeepEqual(old.Deletion, new.Deletion) {
+		return false
+	}
+
+	if old.ObservedGeneration != new.ObservedGeneration || !reflect.DeepEqual(old.Conditions, new.Conditions) {
+		return false
+	}
+
+	return statusDomainsEqual(old.Domains, new.Domains)
 }
 
 func statusDomainsEqual(old, new []v1alpha1.Domain) bool {
@@ -47,14 +127,9 @@ oldLoop:
 				continue
 			}
 
-			if o.SemVer == nil && n.SemVer != nil ||
-				o.SemVer != nil && n.SemVer == nil {
-				continue
-			}
-
-			if o.SemVer != nil &&
-				(o.SemVer.Name != n.SemVer.Name ||
-					o.SemVer.Version != n.SemVer.Version) {
+			oName, oVersion, oOK := o.Release()
+			nName, nVersion, nOK := n.Release()
+			if oOK != nOK || oName != nName || oVersion != nVersion {
 				continue
 			}
 
@@ -73,6 +73,24 @@ func TestNetworkStatus(t *testing.T) {
 		}
 
 	})
+
+	t.Run("with an invalid domain", func(t *testing.T) {
+		np := &v1alpha1.NetworkPolicy{
+			Spec: v1alpha1.NetworkPolicySpec{
+				ExternalDNS: []v1alpha1.ExternalDNS{
+					{
+						Domain: "{{.Unknown}}.cool.domain",
+					},
+				},
+			},
+		}
+
+		if _, err := buildNetworkStatusDomainsForRelease(ms, np, release); err == nil {
+			t.Error("Expected an error for an invalid template")
+		} else if _, ok := err.(*DomainError); !ok {
+			t.Errorf("Expected a DomainError, got %T", err)
+		}
+	})
 }
 
 func TestFullDomain(t *testing.T) {
@@ -84,7 +102,7 @@ func TestFullDomain(t *testing.T) {
 			Domain: domain,
 		}
 
-		if actual := getFullURL(dns); actual != url {
+		if actual := getFullURL(dns, domain); actual != url {
 			t.Errorf("Expected url to be '%s', got '%s'", url, actual)
 		}
 	})
@@ -98,7 +116,7 @@ func TestFullDomain(t *testing.T) {
 			DisableTLS: true,
 		}
 
-		if actual := getFullURL(dns); actual != url {
+		if actual := getFullURL(dns, domain); actual != url {
 			t.Errorf("Expected url to be '%s', got '%s'", url, actual)
 		}
 	})
@@ -138,6 +156,18 @@ func TestStatusDomainsEqual(t *testing.T) {
 			false,
 		},
 
+		{"one entry (mismatched versioning scheme)",
+			[]v1alpha1.Domain{{URL: "https://fake.fake", SemVer: &v1alpha1.SemVerRelease{Name: "foo", Version: "0.1.0"}}},
+			[]v1alpha1.Domain{{URL: "https://fake.fake", Commit: &v1alpha1.CommitRelease{Name: "foo", SHA: "8d2ab3f"}}},
+			false,
+		},
+
+		{"one entry (equal with commit)",
+			[]v1alpha1.Domain{{URL: "https://fake.fake", Commit: &v1alpha1.CommitRelease{Name: "foo", SHA: "8d2ab3f"}}},
+			[]v1alpha1.Domain{{URL: "https://fake.fake", Commit: &v1alpha1.CommitRelease{Name: "foo", SHA: "8d2ab3f"}}},
+			true,
+		},
+
 		{"two entries out of order",
 			[]v1alpha1.Domain{{URL: "https://fake.fake"}, {URL: "https://other.fake"}},
 			[]v1alpha1.Domain{{URL: "https://other.fake"}, {URL: "https://fake.fake"}},
@@ -159,3 +189,69 @@ func TestStatusDomainsEqual(t *testing.T) {
 		})
 	}
 }
+
+func TestSetConditions(t *testing.T) {
+	tcs := []struct {
+		scenario string
+		status   v1alpha1.NetworkPolicyStatus
+		cond     v1alpha1.ConditionType
+		reason   string
+	}{
+		{
+			scenario: "with an error",
+			status:   v1alpha1.NetworkPolicyStatus{Error: "invalid domain"},
+			cond:     v1alpha1.ConditionDegraded,
+			reason:   "SyncError",
+		},
+		{
+			scenario: "with live releases",
+			status:   v1alpha1.NetworkPolicyStatus{Streams: []v1alpha1.StreamStatus{{Name: "hello-world"}}},
+			cond:     v1alpha1.ConditionReady,
+			reason:   "Synced",
+		},
+		{
+			scenario: "with a stream which failed to sync",
+			status: v1alpha1.NetworkPolicyStatus{Streams: []v1alpha1.StreamStatus{
+				{Name: "hello-world"},
+				{Name: "hello-world-pr", Error: "could not apply Service"},
+			}},
+			cond:   v1alpha1.ConditionDegraded,
+			reason: "StreamError",
+		},
+		{
+			scenario: "with a canary release",
+			status: v1alpha1.NetworkPolicyStatus{Streams: []v1alpha1.StreamStatus{
+				{Name: "hello-world", Canary: &v1alpha1.CanaryStatus{Phase: v1alpha1.CanaryProgressing}},
+			}},
+			cond:   v1alpha1.ConditionProgressing,
+			reason: "RolloutInProgress",
+		},
+		{
+			scenario: "with a rolled back canary release",
+			status: v1alpha1.NetworkPolicyStatus{Streams: []v1alpha1.StreamStatus{
+				{Name: "hello-world", Canary: &v1alpha1.CanaryStatus{Phase: v1alpha1.CanaryRolledBack}},
+			}},
+			cond:   v1alpha1.ConditionDegraded,
+			reason: "CanaryRolledBack",
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.scenario, func(t *testing.T) {
+			np := &v1alpha1.NetworkPolicy{}
+			np.Generation = 4
+
+			status := tc.status
+			setConditions(np, &status)
+
+			cond := status.Conditions.Get(tc.cond)
+			if !status.Conditions.IsTrue(tc.cond) || cond.Reason != tc.reason {
+				t.Errorf("Expected %s to be true because of %s, got %+v", tc.cond, tc.reason, status.Conditions)
+			}
+
+			if status.ObservedGeneration != 4 {
+				t.Errorf("Expected the observed generation to be 4, got %d", status.ObservedGeneration)
+			}
+		})
+	}
+}
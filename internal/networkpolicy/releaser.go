@@ -2,26 +2,329 @@ package networkpolicy
 
 import (
 	"errors"
+	"fmt"
+	"time"
 
 	"github.com/manifoldco/heighliner/apis/v1alpha1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	clientv1 "k8s.io/client-go/kubernetes/typed/core/v1"
 )
 
-// LatestReleaser is able to select a release based on the releasetime date.
+var (
+	// ErrNoPinnedRelease is used when a Manual UpdateStrategy doesn't specify
+	// a release.
+	ErrNoPinnedRelease = errors.New("the manual update strategy requires a release to be pinned")
+
+	// ErrPinnedReleaseNotFound is used when the release pinned by a Manual
+	// UpdateStrategy isn't available for the Microservice.
+	ErrPinnedReleaseNotFound = errors.New("the pinned release is not available")
+
+	// ErrConflictingStrategies is used when more than one UpdateStrategy is
+	// configured.
+	ErrConflictingStrategies = errors.New("only one of the manual, canary and blue/green update strategies can be used")
+
+	// ErrHooksPending is used when none of the releases of a release stream
+	// passed its hooks yet.
+	ErrHooksPending = errors.New("none of the releases passed its hooks yet")
+)
+
+// Releaser selects the release which should be linked to the external
+// Service and Ingress of a release stream.
+type Releaser interface {
+	// ExternalRelease selects the release out of the releases of a single
+	// release stream, and describes why it was selected.
+	ExternalRelease([]v1alpha1.Release) (*v1alpha1.Release, string, error)
+}
+
+// progressiveReleaser is implemented by Releasers which gradually move a
+// release stream over to its latest release.
+type progressiveReleaser interface {
+	// SelectReleases selects the release which should be linked to the
+	// external Service and Ingress of a release stream, and describes the
+	// progress towards the latest release.
+	SelectReleases([]v1alpha1.Release) (*Selection, error)
+}
+
+// Selection is the selection of a progressiveReleaser for a release stream.
+type Selection struct {
+	// Stable is the release linked to the external Service and Ingress.
+	Stable *v1alpha1.Release
+
+	// Canary is the release which receives a share of the traffic. It's nil
+	// when no canary release is in progress.
+	Canary *v1alpha1.Release
+
+	// CanaryStatus describes the progress of the canary release. It's nil
+	// when there's no canary release, or when it has been promoted.
+	CanaryStatus *v1alpha1.CanaryStatus
+
+	// Verification describes the progress of the verification of the latest
+	// release. It's nil when there's nothing to verify, or when the latest
+	// release has been verified.
+	Verification *v1alpha1.VerificationStatus
+
+	// Reason describes why the stable release was selected.
+	Reason string
+}
+
+// newReleaser returns the Releaser for the UpdateStrategy of the given
+// NetworkPolicy. It validates the strategy against the available releases of
+// the Microservice.
+func newReleaser(cs clientv1.CoreV1Interface, cl patchClient, ms *v1alpha1.Microservice, np *v1alpha1.NetworkPolicy, now time.Time) (Releaser, error) {
+	strategy := np.Spec.UpdateStrategy
+
+	var strategies int
+	for _, set := range []bool{strategy.Manual != nil, strategy.Canary != nil, strategy.BlueGreen != nil} {
+		if set {
+			strategies++
+		}
+	}
+
+	switch {
+	case strategies > 1:
+		return nil, ErrConflictingStrategies
+	case strategy.BlueGreen != nil:
+		return &BlueGreenReleaser{
+			Prefix:   ms.Name,
+			Timeout:  strategy.BlueGreen.VerificationTimeout(),
+			Verifier: newVerifier(cs, cl, ms, np.DeepCopy(), strategy.BlueGreen.Verification),
+			Streams:  np.Status.Streams,
+			Now:      now,
+		}, nil
+	case strategy.Canary != nil:
+		steps, duration := strategy.Canary.Schedule()
+		releaser := &CanaryReleaser{
+			Prefix:       ms.Name,
+			Steps:        steps,
+			StepDuration: duration,
+			Streams:      np.Status.Streams,
+			Now:          now,
+		}
+
+		if analysis := strategy.Canary.Analysis; analysis != nil {
+			if err := validateAnalysis(analysis); err != nil {
+				return nil, err
+			}
+
+			releaser.Analyzer = newPrometheusAnalyzer(ms, analysis, now)
+		}
+
+		return releaser, nil
+	case strategy.Manual == nil:
+		return &LatestReleaser{}, nil
+	}
+
+	name, version, ok := strategy.Manual.Release()
+	if !ok {
+		return nil, ErrNoPinnedRelease
+	}
+
+	for _, release := range ms.Status.Releases {
+		if release.Name() == name && release.Version() == version {
+			return &ManualReleaser{Name: name, Version: version}, nil
+		}
+	}
+
+	return nil, fmt.Errorf("%s: %s %s", ErrPinnedReleaseNotFound, name, version)
+}
+
+// LatestReleaser is able to select a release based on the ordering of the
+// versioning scheme, which is the releasetime date unless the scheme defines
+// its own ordering.
 type LatestReleaser struct{}
 
 // ExternalRelease goes over all releases and releases the latest release based
-// on the releaseTime timestamp.
-func (r *LatestReleaser) ExternalRelease(releases []v1alpha1.Release) (*v1alpha1.Release, error) {
+// on the ordering of the versioning scheme.
+func (r *LatestReleaser) ExternalRelease(releases []v1alpha1.Release) (*v1alpha1.Release, string, error) {
 	if len(releases) == 0 {
-		return nil, errors.New("Need at least one release to link to an external release")
+		return nil, "", errors.New("Need at least one release to link to an external release")
 	}
 
 	latestRelease := releases[0]
 	for _, release := range releases {
-		if latestRelease.ReleaseTime.Before(&release.ReleaseTime) {
+		if latestRelease.Before(release) {
 			latestRelease = release
 		}
 	}
 
-	return &latestRelease, nil
+	return &latestRelease, "latest release", nil
+}
+
+// ManualReleaser pins the release stream of the given release to that
+// release. Other release streams follow the latest release.
+type ManualReleaser struct {
+	Name    string
+	Version string
+}
+
+// ExternalRelease selects the pinned release if it's part of the given
+// releases, or the latest release otherwise.
+func (r *ManualReleaser) ExternalRelease(releases []v1alpha1.Release) (*v1alpha1.Release, string, error) {
+	for _, release := range releases {
+		if release.Name() == r.Name && release.Version() == r.Version {
+			return &release, fmt.Sprintf("pinned to %s %s", r.Name, r.Version), nil
+		}
+	}
+
+	release, _, err := (&LatestReleaser{}).ExternalRelease(releases)
+	return release, "latest release, the pinned release is not part of this stream", err
+}
+
+// CanaryReleaser keeps the previously selected release of a stream live while
+// the latest release receives a share of the traffic. The share steps up on a
+// schedule, after which the latest release is promoted. When an Analyzer is
+// set, the latest release needs to pass its analysis before moving on to the
+// next step, or it will be rolled back.
+type CanaryReleaser struct {
+	// Prefix is the name of the Microservice, used to name release streams.
+	Prefix string
+
+	Steps        []int32
+	StepDuration time.Duration
+	Analyzer     analyzer
+
+	// Streams is the previous status of the release streams.
+	Streams []v1alpha1.StreamStatus
+
+	Now time.Time
+}
+
+// ExternalRelease selects the previously selected release while a canary
+// release is in progress or has been rolled back, and the latest release
+// otherwise.
+func (r *CanaryReleaser) ExternalRelease(releases []v1alpha1.Release) (*v1alpha1.Release, string, error) {
+	selection, err := r.SelectReleases(releases)
+	if err != nil {
+		return nil, "", err
+	}
+
+	return selection.Stable, selection.Reason, nil
+}
+
+// SelectReleases finds the stable release, which is the release that was live
+// for the stream before, and uses the latest release as canary release until
+// it's promoted or rolled back.
+func (r *CanaryReleaser) SelectReleases(releases []v1alpha1.Release) (*Selection, error) {
+	latest, stable, previous, err := selectStableRelease(r.Prefix, r.Streams, releases)
+	if err != nil {
+		return nil, err
+	}
+
+	if stable == nil {
+		return &Selection{Stable: latest, Reason: "latest release"}, nil
+	}
+
+	var status *v1alpha1.CanaryStatus
+	if c := previous.Canary; c != nil && c.Release == latest.Name() && c.Version == latest.Version() {
+		status = c.DeepCopy()
+	}
+
+	switch {
+	case status == nil:
+		status = &v1alpha1.CanaryStatus{
+			Release:   latest.Name(),
+			Version:   latest.Version(),
+			Phase:     v1alpha1.CanaryProgressing,
+			StartTime: metav1.NewTime(r.Now.Truncate(time.Second)),
+		}
+	case status.Phase == v1alpha1.CanaryRolledBack:
+		return &Selection{
+			Stable:       stable,
+			CanaryStatus: status,
+			Reason:       fmt.Sprintf("rolled back canary of %s %s", latest.Name(), latest.Version()),
+		}, nil
+	}
+
+	step := len(r.Steps)
+	if r.StepDuration > 0 {
+		step = int(r.Now.Sub(status.StartTime.Time) / r.StepDuration)
+	}
+
+	if step < 0 {
+		step = 0
+	}
+
+	// judge the canary release before moving on to the next step.
+	if step > int(status.Step) && r.Analyzer != nil {
+		results, err := r.Analyzer.Analyze(stable, latest)
+		status.Analysis = results
+
+		if err != nil {
+			return &Selection{
+				Stable:       stable,
+				Canary:       latest,
+				CanaryStatus: status,
+				Reason:       fmt.Sprintf("canary of %s %s on hold, the analysis failed: %s", latest.Name(), latest.Version(), err),
+			}, nil
+		}
+
+		if !analysisPassed(results) {
+			status.Phase = v1alpha1.CanaryRolledBack
+			status.Weight = 0
+
+			return &Selection{
+				Stable:       stable,
+				CanaryStatus: status,
+				Reason:       fmt.Sprintf("rolled back canary of %s %s", latest.Name(), latest.Version()),
+			}, nil
+		}
+	}
+
+	if step >= len(r.Steps) {
+		return &Selection{
+			Stable: latest,
+			Reason: fmt.Sprintf("promoted canary after %d steps", len(r.Steps)),
+		}, nil
+	}
+
+	status.Step = int32(step)
+	status.Weight = r.Steps[step]
+
+	return &Selection{
+		Stable:       stable,
+		Canary:       latest,
+		CanaryStatus: status,
+		Reason:       fmt.Sprintf("canary of %s %s in progress", latest.Name(), latest.Version()),
+	}, nil
+}
+
+// selectStableRelease finds the latest release and the stable release, which
+// is the release that was live for the stream before. The stable release is
+// nil when there's nothing to protect: the stream is new, the stable release
+// is no longer available or it's the latest release already.
+func selectStableRelease(prefix string, streams []v1alpha1.StreamStatus, releases []v1alpha1.Release) (*v1alpha1.Release, *v1alpha1.Release, *v1alpha1.StreamStatus, error) {
+	latest, _, err := (&LatestReleaser{}).ExternalRelease(releases)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	var previous *v1alpha1.StreamStatus
+	for i := range streams {
+		if streams[i].Name == latest.StreamName(prefix) {
+			previous = &streams[i]
+			break
+		}
+	}
+
+	if previous == nil || (previous.Release == latest.Name() && previous.Version == latest.Version()) {
+		return latest, nil, previous, nil
+	}
+
+	for i := range releases {
+		if releases[i].Name() == previous.Release && releases[i].Version() == previous.Version {
+			return latest, &releases[i], previous, nil
+		}
+	}
+
+	return latest, nil, previous, nil
+}
+
+func analysisPassed(results []v1alpha1.CanaryMetricResult) bool {
+	for _, result := range results {
+		if !result.Passed {
+			return false
+		}
+	}
+
+	return true
 }
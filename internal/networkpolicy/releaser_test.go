@@ -1,6 +1,8 @@
 package networkpolicy
 
 import (
+	"reflect"
+	"strings"
 	"testing"
 	"time"
 
@@ -25,7 +27,7 @@ func TestLatestReleaser(t *testing.T) {
 	}
 
 	releaser := &LatestReleaser{}
-	release, err := releaser.ExternalRelease(releases)
+	release, _, err := releaser.ExternalRelease(releases)
 	if err != nil {
 		t.Errorf("Expected no error, got '%s'", err)
 	}
@@ -34,3 +36,293 @@ func TestLatestReleaser(t *testing.T) {
 		t.Errorf("Expected release to be '2', got '%s'", release.Image)
 	}
 }
+
+func TestManualReleaser(t *testing.T) {
+	releases := []v1alpha1.Release{
+		{
+			Image:       "1",
+			ReleaseTime: metav1.Date(2018, time.April, 28, 13, 42, 01, 0, time.UTC),
+			SemVer:      &v1alpha1.SemVerRelease{Name: "hello-world", Version: "1.0.0"},
+		},
+		{
+			Image:       "2",
+			ReleaseTime: metav1.Date(2018, time.April, 29, 13, 42, 01, 0, time.UTC),
+			SemVer:      &v1alpha1.SemVerRelease{Name: "hello-world", Version: "1.1.0"},
+		},
+	}
+
+	ms := &v1alpha1.Microservice{
+		ObjectMeta: metav1.ObjectMeta{Name: "hello-world"},
+		Status:     v1alpha1.MicroserviceStatus{Releases: releases},
+	}
+
+	manual := func(version string) *v1alpha1.NetworkPolicy {
+		return &v1alpha1.NetworkPolicy{
+			Spec: v1alpha1.NetworkPolicySpec{
+				UpdateStrategy: v1alpha1.UpdateStrategy{
+					Manual: &v1alpha1.ManualUpdateStrategy{
+						SemVer: &v1alpha1.SemVerRelease{Name: "hello-world", Version: version},
+					},
+				},
+			},
+		}
+	}
+
+	t.Run("without a pinned release", func(t *testing.T) {
+		np := manual("")
+		np.Spec.UpdateStrategy.Manual.SemVer = nil
+		if _, err := newReleaser(nil, nil, ms, np, time.Now()); err != ErrNoPinnedRelease {
+			t.Errorf("Expected '%s', got '%v'", ErrNoPinnedRelease, err)
+		}
+	})
+
+	t.Run("with an unknown pinned release", func(t *testing.T) {
+		if _, err := newReleaser(nil, nil, ms, manual("2.0.0"), time.Now()); err == nil {
+			t.Error("Expected an error for an unknown pinned release")
+		}
+	})
+
+	t.Run("with a pinned release", func(t *testing.T) {
+		releaser, err := newReleaser(nil, nil, ms, manual("1.0.0"), time.Now())
+		if err != nil {
+			t.Fatalf("Expected no error, got '%s'", err)
+		}
+
+		release, reason, err := releaser.ExternalRelease(releases)
+		if err != nil {
+			t.Errorf("Expected no error, got '%s'", err)
+		}
+
+		if release.Image != "1" {
+			t.Errorf("Expected release to be '1', got '%s'", release.Image)
+		}
+
+		if expected := "pinned to hello-world 1.0.0"; reason != expected {
+			t.Errorf("Expected reason '%s', got '%s'", expected, reason)
+		}
+	})
+
+	t.Run("with a stream without the pinned release", func(t *testing.T) {
+		releaser := &ManualReleaser{Name: "other", Version: "1.0.0"}
+
+		release, _, err := releaser.ExternalRelease(releases)
+		if err != nil {
+			t.Errorf("Expected no error, got '%s'", err)
+		}
+
+		if release.Image != "2" {
+			t.Errorf("Expected the latest release '2', got '%s'", release.Image)
+		}
+	})
+
+	t.Run("without a manual strategy", func(t *testing.T) {
+		releaser, err := newReleaser(nil, nil, ms, &v1alpha1.NetworkPolicy{}, time.Now())
+		if err != nil {
+			t.Fatalf("Expected no error, got '%s'", err)
+		}
+
+		if _, ok := releaser.(*LatestReleaser); !ok {
+			t.Errorf("Expected a LatestReleaser, got %T", releaser)
+		}
+	})
+
+	t.Run("combined with a canary strategy", func(t *testing.T) {
+		np := manual("1.0.0")
+		np.Spec.UpdateStrategy.Canary = &v1alpha1.CanaryUpdateStrategy{}
+		if _, err := newReleaser(nil, nil, ms, np, time.Now()); err != ErrConflictingStrategies {
+			t.Errorf("Expected '%s', got '%v'", ErrConflictingStrategies, err)
+		}
+	})
+
+	t.Run("with an invalid threshold", func(t *testing.T) {
+		np := &v1alpha1.NetworkPolicy{
+			Spec: v1alpha1.NetworkPolicySpec{
+				UpdateStrategy: v1alpha1.UpdateStrategy{
+					Canary: &v1alpha1.CanaryUpdateStrategy{
+						Analysis: &v1alpha1.CanaryAnalysis{
+							Metrics: []v1alpha1.CanaryMetric{{Name: "errors", Max: "5%"}},
+						},
+					},
+				},
+			},
+		}
+
+		if _, err := newReleaser(nil, nil, ms, np, time.Now()); err == nil || !strings.HasPrefix(err.Error(), ErrInvalidThreshold.Error()) {
+			t.Errorf("Expected an invalid threshold to be reported, got %v", err)
+		}
+	})
+}
+
+type fakeAnalyzer struct {
+	results []v1alpha1.CanaryMetricResult
+	err     error
+}
+
+func (a *fakeAnalyzer) Analyze(baseline, canary *v1alpha1.Release) ([]v1alpha1.CanaryMetricResult, error) {
+	return a.results, a.err
+}
+
+func TestCanaryReleaser(t *testing.T) {
+	releases := []v1alpha1.Release{
+		{
+			Image:       "1",
+			ReleaseTime: metav1.Date(2018, time.April, 28, 13, 42, 01, 0, time.UTC),
+			SemVer:      &v1alpha1.SemVerRelease{Name: "hello-world", Version: "1.0.0"},
+			Level:       v1alpha1.SemVerLevelRelease,
+		},
+		{
+			Image:       "2",
+			ReleaseTime: metav1.Date(2018, time.April, 29, 13, 42, 01, 0, time.UTC),
+			SemVer:      &v1alpha1.SemVerRelease{Name: "hello-world", Version: "1.1.0"},
+			Level:       v1alpha1.SemVerLevelRelease,
+		},
+	}
+
+	now := time.Date(2018, time.April, 29, 14, 0, 0, 0, time.UTC)
+	stream := releases[0].StreamName("hello-world")
+
+	newReleaser := func(streams ...v1alpha1.StreamStatus) *CanaryReleaser {
+		return &CanaryReleaser{
+			Prefix:       "hello-world",
+			Steps:        []int32{10, 50},
+			StepDuration: 5 * time.Minute,
+			Streams:      streams,
+			Now:          now,
+		}
+	}
+
+	inProgress := func(step, weight int32, started time.Duration) v1alpha1.StreamStatus {
+		return v1alpha1.StreamStatus{
+			Name:    stream,
+			Release: "hello-world",
+			Version: "1.0.0",
+			Canary: &v1alpha1.CanaryStatus{
+				Release:   "hello-world",
+				Version:   "1.1.0",
+				Phase:     v1alpha1.CanaryProgressing,
+				Step:      step,
+				Weight:    weight,
+				StartTime: metav1.NewTime(now.Add(-started)),
+			},
+		}
+	}
+
+	t.Run("with a new stream", func(t *testing.T) {
+		selection, err := newReleaser().SelectReleases(releases)
+		if err != nil {
+			t.Fatalf("Expected no error, got '%s'", err)
+		}
+
+		if selection.Stable.Image != "2" || selection.Reason != "latest release" {
+			t.Errorf("Expected the latest release '2', got '%s': %s", selection.Stable.Image, selection.Reason)
+		}
+
+		if selection.Canary != nil || selection.CanaryStatus != nil {
+			t.Errorf("Expected no canary release, got %+v", selection.CanaryStatus)
+		}
+	})
+
+	t.Run("starting a canary release", func(t *testing.T) {
+		releaser := newReleaser(v1alpha1.StreamStatus{Name: stream, Release: "hello-world", Version: "1.0.0"})
+
+		release, _, err := releaser.ExternalRelease(releases)
+		if err != nil {
+			t.Fatalf("Expected no error, got '%s'", err)
+		}
+
+		if release.Image != "1" {
+			t.Errorf("Expected the stable release '1', got '%s'", release.Image)
+		}
+
+		selection, _ := releaser.SelectReleases(releases)
+		if selection.Canary == nil || selection.Canary.Image != "2" {
+			t.Fatalf("Expected canary release '2', got %v", selection.Canary)
+		}
+
+		expected := &v1alpha1.CanaryStatus{
+			Release:   "hello-world",
+			Version:   "1.1.0",
+			Phase:     v1alpha1.CanaryProgressing,
+			Weight:    10,
+			StartTime: metav1.NewTime(now),
+		}
+		if !reflect.DeepEqual(selection.CanaryStatus, expected) {
+			t.Errorf("Expected status %+v, got %+v", expected, selection.CanaryStatus)
+		}
+	})
+
+	t.Run("stepping up a canary release", func(t *testing.T) {
+		selection, _ := newReleaser(inProgress(0, 10, 6*time.Minute)).SelectReleases(releases)
+		if status := selection.CanaryStatus; status == nil || status.Step != 1 || status.Weight != 50 {
+			t.Errorf("Expected the second step with weight 50, got %+v", status)
+		}
+	})
+
+	t.Run("promoting a canary release", func(t *testing.T) {
+		selection, err := newReleaser(inProgress(1, 50, 10*time.Minute)).SelectReleases(releases)
+		if err != nil {
+			t.Fatalf("Expected no error, got '%s'", err)
+		}
+
+		if selection.Stable.Image != "2" || selection.Reason != "promoted canary after 2 steps" {
+			t.Errorf("Expected the promoted release '2', got '%s': %s", selection.Stable.Image, selection.Reason)
+		}
+
+		if selection.Canary != nil {
+			t.Errorf("Expected no canary release after promotion, got '%s'", selection.Canary.Image)
+		}
+	})
+
+	t.Run("with a passing analysis", func(t *testing.T) {
+		releaser := newReleaser(inProgress(0, 10, 6*time.Minute))
+		releaser.Analyzer = &fakeAnalyzer{
+			results: []v1alpha1.CanaryMetricResult{{Name: "error-rate", Passed: true}},
+		}
+
+		selection, _ := releaser.SelectReleases(releases)
+		if status := selection.CanaryStatus; status == nil || status.Step != 1 || len(status.Analysis) != 1 {
+			t.Errorf("Expected the second step with analysis results, got %+v", status)
+		}
+	})
+
+	t.Run("with a failing analysis", func(t *testing.T) {
+		releaser := newReleaser(inProgress(0, 10, 6*time.Minute))
+		releaser.Analyzer = &fakeAnalyzer{
+			results: []v1alpha1.CanaryMetricResult{{Name: "error-rate", Passed: false}},
+		}
+
+		selection, _ := releaser.SelectReleases(releases)
+		if selection.Stable.Image != "1" || selection.Canary != nil {
+			t.Errorf("Expected the canary release to be rolled back")
+		}
+
+		if status := selection.CanaryStatus; status == nil || status.Phase != v1alpha1.CanaryRolledBack || status.Weight != 0 {
+			t.Fatalf("Expected a rolled back status, got %+v", status)
+		}
+
+		// the rolled back release shouldn't be tried again.
+		previous := inProgress(0, 10, 6*time.Minute)
+		previous.Canary = selection.CanaryStatus
+		releaser = newReleaser(previous)
+		releaser.Now = now.Add(time.Hour)
+
+		selection, _ = releaser.SelectReleases(releases)
+		if selection.Stable.Image != "1" || selection.Canary != nil {
+			t.Errorf("Expected the rolled back release to stay rolled back")
+		}
+	})
+
+	t.Run("with an incomplete analysis", func(t *testing.T) {
+		releaser := newReleaser(inProgress(0, 10, 6*time.Minute))
+		releaser.Analyzer = &fakeAnalyzer{err: ErrNoMetricData}
+
+		selection, _ := releaser.SelectReleases(releases)
+		if status := selection.CanaryStatus; status == nil || status.Step != 0 || status.Weight != 10 {
+			t.Errorf("Expected the canary release to be held at the first step, got %+v", status)
+		}
+
+		if selection.Canary == nil || selection.Stable.Image != "1" {
+			t.Errorf("Expected the canary release to keep receiving traffic")
+		}
+	})
+}
@@ -0,0 +1,998 @@
+package networkpolicy
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/manifoldco/heighliner/apis/v1alpha1"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/api/extensions/v1beta1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/client-go/discovery"
+)
+
+const (
+	ingressV1GroupVersion = "networking.k8s.io/v1"
+	httpRouteGroupVersion = "gateway.networking.k8s.io/v1"
+	istioGroupVersion     = "networking.istio.io/v1beta1"
+
+	// discoveryPeriod is how long the discovered APIs are cached for.
+	discoveryPeriod = 5 * time.Minute
+)
+
+var (
+	// ErrUnsupportedRouting is used when the cluster doesn't support the
+	// selected routing backend.
+	ErrUnsupportedRouting = errors.New("the routing backend is not supported by the cluster")
+
+	// ErrNoGateway is used when HTTPRoutes are used without a Gateway.
+	ErrNoGateway = errors.New("the HTTPRoute routing backend requires a gateway")
+
+	// ErrUnsupportedRewrite is used when paths have a rewrite, but the routing
+	// backend can't rewrite them.
+	ErrUnsupportedRewrite = errors.New("path rewrites require the HTTPRoute or Istio routing backend")
+
+	// ErrUnsupportedScaleToZero is used when previews are scaled to zero, but
+	// the routing backend can't route their traffic through the activator.
+	ErrUnsupportedScaleToZero = errors.New("scaling to zero requires an Ingress routing backend")
+)
+
+// streamRoute describes how traffic for a release stream is routed.
+type streamRoute struct {
+	// Release is the release linked to the stream.
+	Release *v1alpha1.Release
+
+	// Ingresses route the external domains to the Service of the stream.
+	// They're empty when no external domains are configured.
+	Ingresses []*v1beta1.Ingress
+
+	// Canary is the canary release of the stream, if any.
+	Canary *v1alpha1.Release
+
+	// CanaryIngresses route a share of the traffic of the external domains to
+	// the canary release.
+	CanaryIngresses []*v1beta1.Ingress
+
+	// CanaryWeight is the percentage of traffic sent to the canary release.
+	CanaryWeight int32
+
+	// Service is the Service of the stream, which owns its routes.
+	Service *corev1.Service
+
+	// Matches are the headers and cookies which route requests for the
+	// domains of the release stream to this preview release.
+	Matches []routeMatch
+
+	// PathOptions are the settings of the paths of the Ingresses, keyed by
+	// their host and path.
+	PathOptions map[string]pathOptions
+}
+
+// pathOptions returns the settings of the given path, which default to a
+// prefix match.
+func (r streamRoute) pathOptions(host, path string) pathOptions {
+	opts, ok := r.PathOptions[host+path]
+	if !ok || opts.Type == "" {
+		opts.Type = v1alpha1.PathTypePrefix
+	}
+
+	return opts
+}
+
+// rules returns the rules of all Ingresses of the stream.
+func (r streamRoute) rules() []v1beta1.IngressRule {
+	var rules []v1beta1.IngressRule
+	for _, ing := range r.Ingresses {
+		rules = append(rules, ing.Spec.Rules...)
+	}
+
+	return rules
+}
+
+// router converts the Ingresses Heighliner builds for a release stream into
+// objects of the routing API of a NetworkPolicy.
+type router interface {
+	// Routes converts the Ingresses of a release stream, along with its
+	// canary Ingresses if any, into the objects of the routing API.
+	Routes(streamRoute) ([]runtime.Object, error)
+
+	// CanaryRoute returns the object which routes traffic to the canary
+	// release of the given Ingress, so it can be removed once there's no
+	// canary release. It returns nil when the routing API doesn't need a
+	// separate object for this.
+	CanaryRoute(ing *v1beta1.Ingress) runtime.Object
+
+	// IngressRoutes returns the objects which route the given Ingress, apart
+	// from its additional hosts, so they can be removed once the stream no
+	// longer has the Ingress.
+	IngressRoutes(ing *v1beta1.Ingress) []runtime.Object
+
+	// HostRoute returns the object which routes the i-th host of the given
+	// Ingress, so it can be removed once the Ingress no longer has the host.
+	// It returns nil when the routing API doesn't route hosts separately.
+	HostRoute(ing *v1beta1.Ingress, i int) runtime.Object
+}
+
+// aggregateRouter is implemented by routers which combine the routes of all
+// release streams of a Microservice into a single set of objects.
+type aggregateRouter interface {
+	router
+
+	// Objects returns the objects for all routes passed to the router.
+	Objects() ([]runtime.Object, error)
+}
+
+// syncRoutes applies the routes for the Ingresses of a release stream and its
+// canary Ingresses. The routes of Ingresses and hosts the stream no longer has
+// are removed. When removeCanary is set and there are no canary Ingresses,
+// the routes for the canary release are removed.
+func syncRoutes(cl patchClient, rt router, ms *v1alpha1.Microservice, route streamRoute, removeCanary bool) error {
+	objs, err := rt.Routes(route)
+	if err != nil {
+		return err
+	}
+
+	if err := applyObjects(cl, objs); err != nil {
+		return err
+	}
+
+	if err := pruneIngressRoutes(cl, rt, ms, route); err != nil {
+		return err
+	}
+
+	if len(route.CanaryIngresses) != 0 || !removeCanary {
+		return nil
+	}
+
+	for _, ing := range route.Ingresses {
+		obj := rt.CanaryRoute(ing)
+		if obj == nil {
+			continue
+		}
+
+		if err := cl.Delete(obj); err != nil && !kerrors.IsNotFound(err) {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// pruneIngressRoutes removes the routes of the Ingresses of a stream which
+// were built for groups of records that have been removed, along with the
+// routes of hosts removed from its Ingresses. Both are numbered, so they're
+// removed from the first number which isn't used anymore, until there's
+// nothing left to remove.
+func pruneIngressRoutes(cl patchClient, rt router, ms *v1alpha1.Microservice, route streamRoute) error {
+	for _, ing := range route.Ingresses {
+		if err := pruneHostRoutes(cl, rt, ing, len(ing.Spec.Rules)); err != nil {
+			return err
+		}
+	}
+
+	for i := len(route.Ingresses); ; i++ {
+		ing := &v1beta1.Ingress{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      ingressName(route.Release.StreamName(ms.Name), i),
+				Namespace: ms.Namespace,
+			},
+		}
+
+		found, err := deleteObjects(cl, rt.IngressRoutes(ing))
+		if err != nil || !found {
+			return err
+		}
+
+		if err := pruneHostRoutes(cl, rt, ing, 1); err != nil {
+			return err
+		}
+	}
+}
+
+// pruneHostRoutes removes the routes of the hosts of an Ingress from the given
+// number onwards.
+func pruneHostRoutes(cl patchClient, rt router, ing *v1beta1.Ingress, from int) error {
+	for i := from; ; i++ {
+		obj := rt.HostRoute(ing, i)
+		if obj == nil {
+			return nil
+		}
+
+		found, err := deleteObjects(cl, []runtime.Object{obj})
+		if err != nil || !found {
+			return err
+		}
+	}
+}
+
+// deleteObjects deletes the given objects, and reports whether any of them
+// existed.
+func deleteObjects(cl patchClient, objs []runtime.Object) (bool, error) {
+	var found bool
+	for _, obj := range objs {
+		err := cl.Delete(obj)
+		switch {
+		case kerrors.IsNotFound(err):
+		case err != nil:
+			return found, err
+		default:
+			found = true
+		}
+	}
+
+	return found, nil
+}
+
+// syncAggregateRoutes applies the objects of an aggregateRouter, once the
+// routes of all release streams have been passed to it.
+func syncAggregateRoutes(cl patchClient, rt router) error {
+	ar, ok := rt.(aggregateRouter)
+	if !ok {
+		return nil
+	}
+
+	objs, err := ar.Objects()
+	if err != nil {
+		return err
+	}
+
+	return applyObjects(cl, objs)
+}
+
+func applyObjects(cl patchClient, objs []runtime.Object) error {
+	for _, obj := range objs {
+		if _, err := cl.Apply(obj); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// routingAPIs describes which routing APIs are supported by the cluster.
+type routingAPIs struct {
+	IngressV1 bool
+	HTTPRoute bool
+	Istio     bool
+}
+
+// apiDiscovery detects the routing APIs supported by the cluster, caching the
+// result for a while.
+type apiDiscovery struct {
+	dc discovery.DiscoveryInterface
+
+	mu         sync.Mutex
+	apis       routingAPIs
+	discovered time.Time
+}
+
+// APIs returns the routing APIs supported by the cluster. Without a discovery
+// client, only extensions/v1beta1 Ingresses are supported.
+func (d *apiDiscovery) APIs() routingAPIs {
+	if d == nil || d.dc == nil {
+		return routingAPIs{}
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if time.Since(d.discovered) < discoveryPeriod {
+		return d.apis
+	}
+
+	d.apis = routingAPIs{
+		IngressV1: supportsResource(d.dc, ingressV1GroupVersion, "ingresses"),
+		HTTPRoute: supportsResource(d.dc, httpRouteGroupVersion, "httproutes"),
+		Istio:     supportsResource(d.dc, istioGroupVersion, "virtualservices"),
+	}
+	d.discovered = time.Now()
+
+	return d.apis
+}
+
+func supportsResource(dc discovery.DiscoveryInterface, groupVersion, name string) bool {
+	resources, err := dc.ServerResourcesForGroupVersion(groupVersion)
+	if err != nil {
+		return false
+	}
+
+	for _, r := range resources.APIResources {
+		if r.Name == name {
+			return true
+		}
+	}
+
+	return false
+}
+
+// routingBackend returns the routing backend of the given NetworkPolicy. When
+// none is selected, it's detected from the routing APIs supported by the
+// cluster.
+func routingBackend(np *v1alpha1.NetworkPolicy, apis routingAPIs) v1alpha1.RoutingBackend {
+	var routing v1alpha1.Routing
+	if np.Spec.Routing != nil {
+		routing = *np.Spec.Routing
+	}
+
+	if routing.Backend != "" {
+		return routing.Backend
+	}
+
+	switch {
+	case routing.Istio != nil && apis.Istio:
+		return v1alpha1.RoutingIstio
+	case routing.Gateway != nil && apis.HTTPRoute:
+		return v1alpha1.RoutingHTTPRoute
+	case apis.IngressV1:
+		return v1alpha1.RoutingIngress
+	}
+
+	return v1alpha1.RoutingExtensionsIngress
+}
+
+// newRouter returns the router for the given NetworkPolicy, based on the
+// routing APIs supported by the cluster.
+func newRouter(ms *v1alpha1.Microservice, np *v1alpha1.NetworkPolicy, apis routingAPIs) (router, error) {
+	var routing v1alpha1.Routing
+	if np.Spec.Routing != nil {
+		routing = *np.Spec.Routing
+	}
+
+	switch backend := routingBackend(np, apis); backend {
+	case v1alpha1.RoutingExtensionsIngress:
+		if len(routing.Rules) > 0 {
+			return nil, ErrUnsupportedRules
+		}
+
+		if hasRewrites(np) {
+			return nil, ErrUnsupportedRewrite
+		}
+
+		return &extensionsIngressRouter{}, nil
+	case v1alpha1.RoutingIngress:
+		if !apis.IngressV1 {
+			return nil, fmt.Errorf("%s: %s", ErrUnsupportedRouting, backend)
+		}
+
+		if len(routing.Rules) > 0 {
+			return nil, ErrUnsupportedRules
+		}
+
+		if hasRewrites(np) {
+			return nil, ErrUnsupportedRewrite
+		}
+
+		return &ingressRouter{}, nil
+	case v1alpha1.RoutingHTTPRoute:
+		if !apis.HTTPRoute {
+			return nil, fmt.Errorf("%s: %s", ErrUnsupportedRouting, backend)
+		}
+
+		if routing.Gateway == nil {
+			return nil, ErrNoGateway
+		}
+
+		if np.Spec.ScaleToZero != nil {
+			return nil, ErrUnsupportedScaleToZero
+		}
+
+		return &httpRouteRouter{ms: ms, np: np, gateway: *routing.Gateway}, nil
+	case v1alpha1.RoutingIstio:
+		if !apis.Istio {
+			return nil, fmt.Errorf("%s: %s", ErrUnsupportedRouting, backend)
+		}
+
+		if np.Spec.ScaleToZero != nil {
+			return nil, ErrUnsupportedScaleToZero
+		}
+
+		var gateways []string
+		if routing.Istio != nil {
+			gateways = routing.Istio.Gateways
+		}
+
+		return &istioRouter{ms: ms, np: np, gateways: gateways}, nil
+	}
+
+	return nil, fmt.Errorf("unknown routing backend '%s'", routing.Backend)
+}
+
+// pruneRoutes removes the objects which the routing backends that aren't
+// selected would create for the given routes, so switching backends doesn't
+// leave the routes of the previous backend behind. Only the backends supported
+// by the cluster are pruned. Both kinds of Ingresses share their objects, so
+// Ingresses named like the ones of the selected backend are kept.
+func pruneRoutes(cl patchClient, ms *v1alpha1.Microservice, np *v1alpha1.NetworkPolicy, apis routingAPIs, routes []streamRoute) error {
+	routers := map[v1alpha1.RoutingBackend]router{
+		v1alpha1.RoutingExtensionsIngress: &extensionsIngressRouter{},
+	}
+
+	if apis.IngressV1 {
+		routers[v1alpha1.RoutingIngress] = &ingressRouter{}
+	}
+
+	if apis.HTTPRoute {
+		routers[v1alpha1.RoutingHTTPRoute] = &httpRouteRouter{ms: ms, np: np}
+	}
+
+	if apis.Istio {
+		routers[v1alpha1.RoutingIstio] = &istioRouter{ms: ms, np: np}
+	}
+
+	backend := routingBackend(np, apis)
+
+	keep := map[string]bool{}
+	for _, route := range routes {
+		if route.Service != nil {
+			keep["Service/"+route.Service.Name] = true
+		}
+	}
+
+	if rt, ok := routers[backend]; ok {
+		for _, obj := range routeObjects(rt, routes) {
+			keep[objectKey(obj)] = true
+		}
+	}
+
+	for b, rt := range routers {
+		if b == backend {
+			continue
+		}
+
+		for _, obj := range routeObjects(rt, routes) {
+			key := objectKey(obj)
+			if keep[key] {
+				continue
+			}
+			keep[key] = true
+
+			if err := cl.Delete(obj); err != nil && !kerrors.IsNotFound(err) {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// routeObjects returns all objects the router creates for the given routes,
+// including the routes of canary releases. Routes which can't be converted are
+// skipped.
+func routeObjects(rt router, routes []streamRoute) []runtime.Object {
+	var objs []runtime.Object
+	for _, route := range routes {
+		routeObjs, err := rt.Routes(route)
+		if err != nil {
+			continue
+		}
+
+		objs = append(objs, routeObjs...)
+
+		for _, ing := range route.Ingresses {
+			if obj := rt.CanaryRoute(ing); obj != nil {
+				objs = append(objs, obj)
+			}
+		}
+	}
+
+	if ar, ok := rt.(aggregateRouter); ok {
+		if aggregateObjs, err := ar.Objects(); err == nil {
+			objs = append(objs, aggregateObjs...)
+		}
+	}
+
+	return objs
+}
+
+// objectKey identifies an object by its kind and name.
+func objectKey(obj runtime.Object) string {
+	var name string
+	if o, err := apimeta.Accessor(obj); err == nil {
+		name = o.GetName()
+	}
+
+	return obj.GetObjectKind().GroupVersionKind().Kind + "/" + name
+}
+
+// hasRewrites reports whether any of the paths of the NetworkPolicy has a
+// rewrite.
+func hasRewrites(np *v1alpha1.NetworkPolicy) bool {
+	for _, record := range np.Spec.ExternalDNS {
+		for _, path := range record.Paths {
+			if path.Rewrite != "" {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// extensionsIngressRouter uses the extensions/v1beta1 Ingresses as is.
+type extensionsIngressRouter struct{}
+
+func (r *extensionsIngressRouter) Routes(route streamRoute) ([]runtime.Object, error) {
+	var objs []runtime.Object
+	for _, ing := range route.Ingresses {
+		objs = append(objs, ing)
+	}
+
+	for _, ing := range route.CanaryIngresses {
+		objs = append(objs, ing)
+	}
+
+	return objs, nil
+}
+
+func (r *extensionsIngressRouter) IngressRoutes(ing *v1beta1.Ingress) []runtime.Object {
+	obj := &v1beta1.Ingress{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       "Ingress",
+			APIVersion: "extensions/v1beta1",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      ing.Name,
+			Namespace: ing.Namespace,
+		},
+	}
+
+	return []runtime.Object{obj, r.CanaryRoute(ing)}
+}
+
+func (r *extensionsIngressRouter) HostRoute(*v1beta1.Ingress, int) runtime.Object {
+	return nil
+}
+
+func (r *extensionsIngressRouter) CanaryRoute(ing *v1beta1.Ingress) runtime.Object {
+	return &v1beta1.Ingress{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       "Ingress",
+			APIVersion: "extensions/v1beta1",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      canaryIngressName(ing),
+			Namespace: ing.Namespace,
+		},
+	}
+}
+
+// ingressRouter converts Ingresses to networking.k8s.io/v1 Ingresses.
+type ingressRouter struct{}
+
+func (r *ingressRouter) Routes(route streamRoute) ([]runtime.Object, error) {
+	var objs []runtime.Object
+	for _, ing := range route.Ingresses {
+		objs = append(objs, convertIngress(ing, route))
+	}
+
+	for _, ing := range route.CanaryIngresses {
+		objs = append(objs, convertIngress(ing, route))
+	}
+
+	return objs, nil
+}
+
+func (r *ingressRouter) IngressRoutes(ing *v1beta1.Ingress) []runtime.Object {
+	obj := newUnstructured(ingressV1GroupVersion, "Ingress", metav1.ObjectMeta{
+		Name:      ing.Name,
+		Namespace: ing.Namespace,
+	})
+
+	return []runtime.Object{obj, r.CanaryRoute(ing)}
+}
+
+func (r *ingressRouter) HostRoute(*v1beta1.Ingress, int) runtime.Object {
+	return nil
+}
+
+func (r *ingressRouter) CanaryRoute(ing *v1beta1.Ingress) runtime.Object {
+	obj := newUnstructured(ingressV1GroupVersion, "Ingress", metav1.ObjectMeta{
+		Name:      canaryIngressName(ing),
+		Namespace: ing.Namespace,
+	})
+
+	return obj
+}
+
+func convertIngress(ing *v1beta1.Ingress, route streamRoute) *unstructured.Unstructured {
+	objectMeta := *ing.ObjectMeta.DeepCopy()
+
+	// the ingress class is part of the spec in networking.k8s.io/v1.
+	ingressClass := objectMeta.Annotations["kubernetes.io/ingress.class"]
+	delete(objectMeta.Annotations, "kubernetes.io/ingress.class")
+
+	obj := newUnstructured(ingressV1GroupVersion, "Ingress", objectMeta)
+
+	var rules []interface{}
+	for _, rule := range ing.Spec.Rules {
+		var paths []interface{}
+		for _, path := range rule.HTTP.Paths {
+			port := map[string]interface{}{
+				"name": path.Backend.ServicePort.String(),
+			}
+
+			if path.Backend.ServicePort.Type == intstr.Int {
+				port = map[string]interface{}{
+					"number": int64(path.Backend.ServicePort.IntVal),
+				}
+			}
+
+			paths = append(paths, map[string]interface{}{
+				"path":     path.Path,
+				"pathType": string(route.pathOptions(rule.Host, path.Path).Type),
+				"backend": map[string]interface{}{
+					"service": map[string]interface{}{
+						"name": path.Backend.ServiceName,
+						"port": port,
+					},
+				},
+			})
+		}
+
+		rules = append(rules, map[string]interface{}{
+			"host": rule.Host,
+			"http": map[string]interface{}{
+				"paths": paths,
+			},
+		})
+	}
+
+	var tls []interface{}
+	for _, t := range ing.Spec.TLS {
+		// records with TLS disabled leave an empty entry behind.
+		if len(t.Hosts) == 0 {
+			continue
+		}
+
+		hosts := make([]interface{}, len(t.Hosts))
+		for i, host := range t.Hosts {
+			hosts[i] = host
+		}
+
+		tls = append(tls, map[string]interface{}{
+			"hosts":      hosts,
+			"secretName": t.SecretName,
+		})
+	}
+
+	spec := map[string]interface{}{
+		"rules": rules,
+	}
+
+	if ingressClass != "" {
+		spec["ingressClassName"] = ingressClass
+	}
+
+	if len(tls) > 0 {
+		spec["tls"] = tls
+	}
+
+	obj.Object["spec"] = spec
+	return obj
+}
+
+// httpRouteRouter converts Ingresses to Gateway API HTTPRoutes. Canary
+// releases are part of the same HTTPRoute through weighted backends. Preview
+// releases with RoutingRules get an HTTPRoute on the domains of the release
+// stream, matching their headers or cookies.
+type httpRouteRouter struct {
+	ms      *v1alpha1.Microservice
+	np      *v1alpha1.NetworkPolicy
+	gateway v1alpha1.GatewayReference
+
+	routes []streamRoute
+}
+
+func (r *httpRouteRouter) Routes(route streamRoute) ([]runtime.Object, error) {
+	r.routes = append(r.routes, route)
+
+	var objs []runtime.Object
+	for _, ing := range route.Ingresses {
+		routes, err := r.ingressRoutes(route, ing)
+		if err != nil {
+			return nil, err
+		}
+
+		objs = append(objs, routes...)
+	}
+
+	return objs, nil
+}
+
+// ingressRoutes converts a single Ingress of a stream into HTTPRoutes.
+func (r *httpRouteRouter) ingressRoutes(route streamRoute, ing *v1beta1.Ingress) ([]runtime.Object, error) {
+	var weight int64
+	if route.Canary != nil {
+		weight = int64(route.CanaryWeight)
+	}
+
+	objs := make([]runtime.Object, len(ing.Spec.Rules))
+	for i, rule := range ing.Spec.Rules {
+		objectMeta := *ing.ObjectMeta.DeepCopy()
+		delete(objectMeta.Annotations, "kubernetes.io/ingress.class")
+
+		// HTTPRoutes apply their hostnames to all of their rules, so each
+		// host needs its own HTTPRoute.
+		objectMeta.Name = hostRouteName(ing, i)
+
+		obj := newUnstructured(httpRouteGroupVersion, "HTTPRoute", objectMeta)
+
+		var rules []interface{}
+		for _, path := range rule.HTTP.Paths {
+			port, err := r.port(path.Backend.ServicePort)
+			if err != nil {
+				return nil, err
+			}
+
+			backendRefs := []interface{}{
+				map[string]interface{}{
+					"name": path.Backend.ServiceName,
+					"port": port,
+				},
+			}
+
+			// paths to other Microservices don't take part in the canary.
+			if route.Canary != nil && path.Backend.ServiceName == route.Release.StreamName(r.ms.Name) {
+				backendRefs = []interface{}{
+					map[string]interface{}{
+						"name":   path.Backend.ServiceName,
+						"port":   port,
+						"weight": 100 - weight,
+					},
+					map[string]interface{}{
+						"name":   route.Canary.FullName(r.ms.Name),
+						"port":   port,
+						"weight": weight,
+					},
+				}
+			}
+
+			opts := route.pathOptions(rule.Host, path.Path)
+			rules = append(rules, httpRouteRule([]interface{}{pathMatch(path.Path, opts)}, backendRefs, opts))
+		}
+
+		obj.Object["spec"] = map[string]interface{}{
+			"parentRefs": []interface{}{r.parentRef()},
+			"hostnames":  []interface{}{rule.Host},
+			"rules":      rules,
+		}
+
+		objs[i] = obj
+	}
+
+	return objs, nil
+}
+
+func (r *httpRouteRouter) CanaryRoute(*v1beta1.Ingress) runtime.Object {
+	return nil
+}
+
+func (r *httpRouteRouter) IngressRoutes(ing *v1beta1.Ingress) []runtime.Object {
+	return []runtime.Object{r.HostRoute(ing, 0)}
+}
+
+func (r *httpRouteRouter) HostRoute(ing *v1beta1.Ingress, i int) runtime.Object {
+	return newUnstructured(httpRouteGroupVersion, "HTTPRoute", metav1.ObjectMeta{
+		Name:      hostRouteName(ing, i),
+		Namespace: ing.Namespace,
+	})
+}
+
+// hostRouteName returns the name of the HTTPRoute for the i-th host of an
+// Ingress. The first host is routed by an HTTPRoute named after the Ingress.
+// The suffix of the others differs from the one of the Ingresses of a stream,
+// which are named after it as well.
+func hostRouteName(ing *v1beta1.Ingress, i int) string {
+	if i == 0 {
+		return ing.Name
+	}
+
+	return fmt.Sprintf("%s-h%d", ing.Name, i)
+}
+
+// Objects returns the HTTPRoutes which route requests for the domains of the
+// release stream to preview releases, based on their headers or cookies.
+func (r *httpRouteRouter) Objects() ([]runtime.Object, error) {
+	var stream streamRoute
+	for _, route := range r.routes {
+		if route.Release.StreamName(r.ms.Name) == r.ms.Name {
+			stream = route
+		}
+	}
+
+	hosts := stream.rules()
+
+	var objs []runtime.Object
+	for _, route := range r.routes {
+		if len(route.Matches) == 0 || route.Service == nil {
+			continue
+		}
+
+		for i, rule := range hosts {
+			name := route.Release.StreamName(r.ms.Name) + "-rules"
+			if i > 0 {
+				name = fmt.Sprintf("%s-h%d", name, i)
+			}
+
+			obj := newUnstructured(httpRouteGroupVersion, "HTTPRoute", metav1.ObjectMeta{
+				Name:      name,
+				Namespace: r.ms.Namespace,
+				Labels:    route.Service.Labels,
+				OwnerReferences: []metav1.OwnerReference{
+					*metav1.NewControllerRef(
+						route.Service,
+						corev1.SchemeGroupVersion.WithKind("Service"),
+					),
+				},
+			})
+
+			var rules []interface{}
+			for _, path := range rule.HTTP.Paths {
+				// paths to other Microservices aren't routed to the preview.
+				if path.Backend.ServiceName != r.ms.Name {
+					continue
+				}
+
+				port, err := r.port(path.Backend.ServicePort)
+				if err != nil {
+					return nil, err
+				}
+
+				opts := stream.pathOptions(rule.Host, path.Path)
+
+				var matches []interface{}
+				for _, match := range route.Matches {
+					m := pathMatch(path.Path, opts)
+					m["headers"] = []interface{}{headerMatch(match)}
+					matches = append(matches, m)
+				}
+
+				backendRefs := []interface{}{
+					map[string]interface{}{
+						"name": route.Release.FullName(r.ms.Name),
+						"port": port,
+					},
+				}
+
+				rules = append(rules, httpRouteRule(matches, backendRefs, opts))
+			}
+
+			obj.Object["spec"] = map[string]interface{}{
+				"parentRefs": []interface{}{r.parentRef()},
+				"hostnames":  []interface{}{rule.Host},
+				"rules":      rules,
+			}
+
+			objs = append(objs, obj)
+		}
+	}
+
+	return objs, nil
+}
+
+func (r *httpRouteRouter) parentRef() map[string]interface{} {
+	parentRef := map[string]interface{}{
+		"name": r.gateway.Name,
+	}
+
+	if r.gateway.Namespace != "" {
+		parentRef["namespace"] = r.gateway.Namespace
+	}
+
+	if r.gateway.SectionName != "" {
+		parentRef["sectionName"] = r.gateway.SectionName
+	}
+
+	return parentRef
+}
+
+// headerMatch converts a routeMatch into an HTTPRoute header match. Cookies
+// are matched through the Cookie header.
+func headerMatch(match routeMatch) map[string]interface{} {
+	if match.Cookie != "" {
+		return map[string]interface{}{
+			"type":  "RegularExpression",
+			"name":  "Cookie",
+			"value": cookieRegex(match.Cookie, match.Value),
+		}
+	}
+
+	return map[string]interface{}{
+		"type":  "Exact",
+		"name":  match.Header,
+		"value": match.Value,
+	}
+}
+
+// port finds the port number of the NetworkPort with the given name, as
+// HTTPRoutes can't refer to ports by name.
+func (r *httpRouteRouter) port(servicePort intstr.IntOrString) (int64, error) {
+	port, err := portNumber(r.np, servicePort)
+	return int64(port), err
+}
+
+// pathMatch returns the HTTPRoute match for a path.
+func pathMatch(path string, opts pathOptions) map[string]interface{} {
+	matchType := "PathPrefix"
+	if opts.Type == v1alpha1.PathTypeExact {
+		matchType = "Exact"
+	}
+
+	return map[string]interface{}{
+		"path": map[string]interface{}{
+			"type":  matchType,
+			"value": path,
+		},
+	}
+}
+
+// httpRouteRule returns an HTTPRoute rule, which rewrites the matched prefix
+// when the path has a rewrite.
+func httpRouteRule(matches, backendRefs []interface{}, opts pathOptions) map[string]interface{} {
+	rule := map[string]interface{}{
+		"matches":     matches,
+		"backendRefs": backendRefs,
+	}
+
+	if opts.Rewrite != "" {
+		rule["filters"] = []interface{}{
+			map[string]interface{}{
+				"type": "URLRewrite",
+				"urlRewrite": map[string]interface{}{
+					"path": map[string]interface{}{
+						"type":               "ReplacePrefixMatch",
+						"replacePrefixMatch": opts.Rewrite,
+					},
+				},
+			},
+		}
+	}
+
+	return rule
+}
+
+// portNumber returns the number of a port, looking named ports up in the
+// NetworkPorts of the NetworkPolicy.
+func portNumber(np *v1alpha1.NetworkPolicy, servicePort intstr.IntOrString) (int32, error) {
+	if servicePort.Type == intstr.Int {
+		return servicePort.IntVal, nil
+	}
+
+	port, ok := networkPort(np.Spec.Ports, servicePort.StrVal)
+	if !ok {
+		return 0, fmt.Errorf("unknown port '%s'", servicePort.StrVal)
+	}
+
+	return port.Port, nil
+}
+
+// newUnstructured builds an unstructured object with the given metadata.
+func newUnstructured(apiVersion, kind string, objectMeta metav1.ObjectMeta) *unstructured.Unstructured {
+	obj := &unstructured.Unstructured{Object: map[string]interface{}{}}
+	obj.SetAPIVersion(apiVersion)
+	obj.SetKind(kind)
+	obj.SetName(objectMeta.Name)
+	obj.SetNamespace(objectMeta.Namespace)
+
+	if len(objectMeta.Labels) > 0 {
+		obj.SetLabels(objectMeta.Labels)
+	}
+
+	if len(objectMeta.Annotations) > 0 {
+		obj.SetAnnotations(objectMeta.Annotations)
+	}
+
+	if len(objectMeta.OwnerReferences) > 0 {
+		obj.SetOwnerReferences(objectMeta.OwnerReferences)
+	}
+
+	return obj
+}
@@ -0,0 +1,601 @@
+package networkpolicy
+
+import (
+	"reflect"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/jelmersnoeck/kubekit/patcher"
+	"github.com/manifoldco/heighliner/apis/v1alpha1"
+	"github.com/manifoldco/heighliner/internal/tester"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/api/extensions/v1beta1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	fakediscovery "k8s.io/client-go/discovery/fake"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestAPIDiscovery(t *testing.T) {
+	dc := fake.NewSimpleClientset().Discovery().(*fakediscovery.FakeDiscovery)
+	dc.Resources = []*metav1.APIResourceList{
+		{
+			GroupVersion: ingressV1GroupVersion,
+			APIResources: []metav1.APIResource{{Name: "ingresses"}},
+		},
+	}
+
+	apis := (&apiDiscovery{dc: dc}).APIs()
+	if expected := (routingAPIs{IngressV1: true}); apis != expected {
+		t.Errorf("Expected %+v, got %+v", expected, apis)
+	}
+
+	var nilDiscovery *apiDiscovery
+	if apis := nilDiscovery.APIs(); apis != (routingAPIs{}) {
+		t.Errorf("Expected no APIs without discovery, got %+v", apis)
+	}
+}
+
+func TestNewRouter(t *testing.T) {
+	gateway := &v1alpha1.GatewayReference{Name: "public"}
+	istio := &v1alpha1.IstioRouting{Gateways: []string{"istio-system/public"}}
+	all := routingAPIs{IngressV1: true, HTTPRoute: true, Istio: true}
+
+	tcs := []struct {
+		name     string
+		routing  *v1alpha1.Routing
+		apis     routingAPIs
+		expected router
+		err      bool
+	}{
+		{"detects extensions", nil, routingAPIs{}, &extensionsIngressRouter{}, false},
+		{"detects networking", nil, all, &ingressRouter{}, false},
+		{"detects gateway", &v1alpha1.Routing{Gateway: gateway}, all, &httpRouteRouter{}, false},
+		{"selects extensions", &v1alpha1.Routing{Backend: v1alpha1.RoutingExtensionsIngress}, all, &extensionsIngressRouter{}, false},
+		{"unsupported networking", &v1alpha1.Routing{Backend: v1alpha1.RoutingIngress}, routingAPIs{}, nil, true},
+		{"unsupported gateway", &v1alpha1.Routing{Backend: v1alpha1.RoutingHTTPRoute, Gateway: gateway}, routingAPIs{IngressV1: true}, nil, true},
+		{"detects istio", &v1alpha1.Routing{Istio: istio, Gateway: gateway}, all, &istioRouter{}, false},
+		{"selects istio", &v1alpha1.Routing{Backend: v1alpha1.RoutingIstio}, all, &istioRouter{}, false},
+		{"unsupported istio", &v1alpha1.Routing{Backend: v1alpha1.RoutingIstio}, routingAPIs{IngressV1: true}, nil, true},
+		{"rules on ingresses", &v1alpha1.Routing{Rules: []v1alpha1.RoutingRule{{Header: "X-Hlnr-Release"}}}, all, nil, true},
+		{"gateway without reference", &v1alpha1.Routing{Backend: v1alpha1.RoutingHTTPRoute}, all, nil, true},
+		{"unknown backend", &v1alpha1.Routing{Backend: "Unknown"}, all, nil, true},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			np := &v1alpha1.NetworkPolicy{Spec: v1alpha1.NetworkPolicySpec{Routing: tc.routing}}
+
+			rt, err := newRouter(&v1alpha1.Microservice{}, np, tc.apis)
+			if (err != nil) != tc.err {
+				t.Fatalf("Expected error to be %t, got %v", tc.err, err)
+			}
+
+			if reflect.TypeOf(rt) != reflect.TypeOf(tc.expected) {
+				t.Errorf("Expected %T, got %T", tc.expected, rt)
+			}
+		})
+	}
+
+	t.Run("rewrites", func(t *testing.T) {
+		for backend, expected := range map[v1alpha1.RoutingBackend]error{
+			v1alpha1.RoutingExtensionsIngress: ErrUnsupportedRewrite,
+			v1alpha1.RoutingIngress:           ErrUnsupportedRewrite,
+			v1alpha1.RoutingHTTPRoute:         nil,
+		} {
+			np := &v1alpha1.NetworkPolicy{
+				Spec: v1alpha1.NetworkPolicySpec{
+					ExternalDNS: []v1alpha1.ExternalDNS{
+						{Domain: "hlnr.io", Paths: []v1alpha1.HTTPPath{{Path: "/api", Rewrite: "/"}}},
+					},
+					Routing: &v1alpha1.Routing{Backend: backend, Gateway: gateway},
+				},
+			}
+
+			if _, err := newRouter(&v1alpha1.Microservice{}, np, all); err != expected {
+				t.Errorf("Expected %v for %s, got %v", expected, backend, err)
+			}
+		}
+	})
+
+	t.Run("scale to zero", func(t *testing.T) {
+		for backend, expected := range map[v1alpha1.RoutingBackend]error{
+			v1alpha1.RoutingExtensionsIngress: nil,
+			v1alpha1.RoutingIngress:           nil,
+			v1alpha1.RoutingHTTPRoute:         ErrUnsupportedScaleToZero,
+			v1alpha1.RoutingIstio:             ErrUnsupportedScaleToZero,
+		} {
+			np := &v1alpha1.NetworkPolicy{
+				Spec: v1alpha1.NetworkPolicySpec{
+					Ports:       []v1alpha1.NetworkPort{{Name: "headless", Port: 80}},
+					ExternalDNS: []v1alpha1.ExternalDNS{{Domain: "{{.StreamName}}.pr.hlnr.io"}},
+					ScaleToZero: &v1alpha1.ScaleToZero{IdleTimeout: metav1.Duration{Duration: time.Hour}},
+					Routing:     &v1alpha1.Routing{Backend: backend, Gateway: gateway},
+				},
+			}
+
+			if _, err := newRouter(&v1alpha1.Microservice{}, np, all); err != expected {
+				t.Errorf("Expected %v for %s, got %v", expected, backend, err)
+			}
+		}
+	})
+}
+
+func TestRouters(t *testing.T) {
+	newIngress := func(name, backend string, annotations map[string]string) *v1beta1.Ingress {
+		annotations["kubernetes.io/ingress.class"] = "nginx"
+		return &v1beta1.Ingress{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        name,
+				Namespace:   "testing",
+				Annotations: annotations,
+			},
+			Spec: v1beta1.IngressSpec{
+				TLS: []v1beta1.IngressTLS{
+					{Hosts: []string{"hello-world.hlnr.io"}, SecretName: "heighliner-components"},
+				},
+				Rules: []v1beta1.IngressRule{
+					{
+						Host: "hello-world.hlnr.io",
+						IngressRuleValue: v1beta1.IngressRuleValue{
+							HTTP: &v1beta1.HTTPIngressRuleValue{
+								Paths: []v1beta1.HTTPIngressPath{
+									{
+										Path: "/",
+										Backend: v1beta1.IngressBackend{
+											ServiceName: backend,
+											ServicePort: intstr.FromString("headless"),
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		}
+	}
+
+	ing := newIngress("hello-world", "hello-world", map[string]string{})
+	canary := newIngress("hello-world-canary", "hello-world-def", map[string]string{
+		annotationCanary:       "true",
+		annotationCanaryWeight: "25",
+	})
+
+	t.Run("networking.k8s.io/v1 Ingress", func(t *testing.T) {
+		rt := &ingressRouter{}
+
+		objs, err := rt.Routes(streamRoute{
+			Ingresses:       []*v1beta1.Ingress{ing},
+			CanaryIngresses: []*v1beta1.Ingress{canary},
+			CanaryWeight:    25,
+		})
+		if err != nil {
+			t.Fatalf("Expected no error, got %s", err)
+		}
+
+		if len(objs) != 2 {
+			t.Fatalf("Expected an Ingress and a canary Ingress, got %d objects", len(objs))
+		}
+
+		obj := objs[0].(*unstructured.Unstructured).DeepCopy()
+		if obj.GetAPIVersion() != ingressV1GroupVersion || obj.GetName() != "hello-world" {
+			t.Errorf("Expected a networking.k8s.io/v1 Ingress, got %s %s", obj.GetAPIVersion(), obj.GetName())
+		}
+
+		class, _ := unstructured.NestedString(obj.Object, "spec", "ingressClassName")
+		if class != "nginx" {
+			t.Errorf("Expected ingress class nginx, got '%s'", class)
+		}
+
+		if _, ok := obj.GetAnnotations()["kubernetes.io/ingress.class"]; ok {
+			t.Error("Expected the ingress class annotation to be removed")
+		}
+
+		rules, _ := unstructured.NestedSlice(obj.Object, "spec", "rules")
+		paths := rules[0].(map[string]interface{})["http"].(map[string]interface{})["paths"].([]interface{})
+		service := paths[0].(map[string]interface{})["backend"].(map[string]interface{})["service"].(map[string]interface{})
+		if service["name"] != "hello-world" {
+			t.Errorf("Expected backend hello-world, got %v", service["name"])
+		}
+
+		if route := rt.CanaryRoute(ing).(*unstructured.Unstructured); route.GetName() != "hello-world-canary" {
+			t.Errorf("Expected canary route hello-world-canary, got %s", route.GetName())
+		}
+	})
+
+	t.Run("Gateway API HTTPRoute", func(t *testing.T) {
+		np := &v1alpha1.NetworkPolicy{
+			Spec: v1alpha1.NetworkPolicySpec{
+				Ports: []v1alpha1.NetworkPort{{Name: "headless", Port: 80, TargetPort: 8080}},
+			},
+		}
+
+		ms := &v1alpha1.Microservice{ObjectMeta: metav1.ObjectMeta{Name: "hello-world"}}
+		stableRelease := &v1alpha1.Release{
+			SemVer: &v1alpha1.SemVerRelease{Name: "hello-world", Version: "1.0.0"},
+			Level:  v1alpha1.SemVerLevelRelease,
+		}
+		canaryRelease := &v1alpha1.Release{
+			SemVer: &v1alpha1.SemVerRelease{Name: "hello-world", Version: "1.1.0"},
+			Level:  v1alpha1.SemVerLevelRelease,
+		}
+		rt := &httpRouteRouter{ms: ms, np: np, gateway: v1alpha1.GatewayReference{Name: "public", Namespace: "gateways"}}
+
+		objs, err := rt.Routes(streamRoute{
+			Release:      stableRelease,
+			Ingresses:    []*v1beta1.Ingress{ing},
+			Canary:       canaryRelease,
+			CanaryWeight: 25,
+		})
+		if err != nil {
+			t.Fatalf("Expected no error, got %s", err)
+		}
+
+		if len(objs) != 1 {
+			t.Fatalf("Expected a single HTTPRoute, got %d objects", len(objs))
+		}
+
+		obj := objs[0].(*unstructured.Unstructured).DeepCopy()
+		if obj.GetKind() != "HTTPRoute" || obj.GetAPIVersion() != httpRouteGroupVersion {
+			t.Errorf("Expected an HTTPRoute, got %s %s", obj.GetAPIVersion(), obj.GetKind())
+		}
+
+		hostnames, _ := unstructured.NestedStringSlice(obj.Object, "spec", "hostnames")
+		if !reflect.DeepEqual(hostnames, []string{"hello-world.hlnr.io"}) {
+			t.Errorf("Expected hostname hello-world.hlnr.io, got %v", hostnames)
+		}
+
+		rules, _ := unstructured.NestedSlice(obj.Object, "spec", "rules")
+		backendRefs := rules[0].(map[string]interface{})["backendRefs"].([]interface{})
+		expected := []interface{}{
+			map[string]interface{}{"name": "hello-world", "port": int64(80), "weight": int64(75)},
+			map[string]interface{}{"name": canaryRelease.FullName(ms.Name), "port": int64(80), "weight": int64(25)},
+		}
+		if !reflect.DeepEqual(backendRefs, expected) {
+			t.Errorf("Expected weighted backends %v, got %v", expected, backendRefs)
+		}
+
+		if rt.CanaryRoute(ing) != nil {
+			t.Error("Didn't expect a separate canary route")
+		}
+
+		t.Run("hosts", func(t *testing.T) {
+			ing := ing.DeepCopy()
+			rule := *ing.Spec.Rules[0].DeepCopy()
+			rule.Host = "www.hlnr.io"
+			ing.Spec.Rules = append(ing.Spec.Rules, rule)
+
+			objs, err := rt.Routes(streamRoute{Release: stableRelease, Ingresses: []*v1beta1.Ingress{ing}})
+			if err != nil {
+				t.Fatalf("Expected no error, got %s", err)
+			}
+
+			var names []string
+			for _, obj := range objs {
+				names = append(names, obj.(*unstructured.Unstructured).GetName())
+			}
+
+			// hello-world-1 is the name of the second Ingress of the stream.
+			if expected := []string{"hello-world", "hello-world-h1"}; !reflect.DeepEqual(names, expected) {
+				t.Errorf("Expected HTTPRoutes %v, got %v", expected, names)
+			}
+		})
+
+		t.Run("paths", func(t *testing.T) {
+			ing := ing.DeepCopy()
+			ing.Spec.Rules[0].HTTP.Paths = append(ing.Spec.Rules[0].HTTP.Paths, v1beta1.HTTPIngressPath{
+				Path: "/api",
+				Backend: v1beta1.IngressBackend{
+					ServiceName: "api",
+					ServicePort: intstr.FromInt(8080),
+				},
+			})
+
+			objs, err := rt.Routes(streamRoute{
+				Release:      stableRelease,
+				Ingresses:    []*v1beta1.Ingress{ing},
+				Canary:       canaryRelease,
+				CanaryWeight: 25,
+				PathOptions: map[string]pathOptions{
+					"hello-world.hlnr.io/":    {Type: v1alpha1.PathTypeExact},
+					"hello-world.hlnr.io/api": {Type: v1alpha1.PathTypePrefix, Rewrite: "/"},
+				},
+			})
+			if err != nil {
+				t.Fatalf("Expected no error, got %s", err)
+			}
+
+			rules, _ := unstructured.NestedSlice(objs[0].(*unstructured.Unstructured).Object, "spec", "rules")
+			if len(rules) != 2 {
+				t.Fatalf("Expected a rule per path, got %d", len(rules))
+			}
+
+			root := rules[0].(map[string]interface{})["matches"].([]interface{})[0]
+			expectedMatch := map[string]interface{}{
+				"path": map[string]interface{}{"type": "Exact", "value": "/"},
+			}
+			if !reflect.DeepEqual(root, expectedMatch) {
+				t.Errorf("Expected match %v, got %v", expectedMatch, root)
+			}
+
+			api := rules[1].(map[string]interface{})
+			expectedRefs := []interface{}{
+				map[string]interface{}{"name": "api", "port": int64(8080)},
+			}
+			if !reflect.DeepEqual(api["backendRefs"], expectedRefs) {
+				t.Errorf("Expected unweighted backend %v, got %v", expectedRefs, api["backendRefs"])
+			}
+
+			rewrite, _ := unstructured.NestedString(api["filters"].([]interface{})[0].(map[string]interface{}), "urlRewrite", "path", "replacePrefixMatch")
+			if rewrite != "/" {
+				t.Errorf("Expected the prefix to be rewritten to /, got '%s'", rewrite)
+			}
+		})
+	})
+
+	t.Run("Gateway API HTTPRoute rules", func(t *testing.T) {
+		ms := &v1alpha1.Microservice{ObjectMeta: metav1.ObjectMeta{Name: "hello-world", Namespace: "testing"}}
+		np := &v1alpha1.NetworkPolicy{
+			Spec: v1alpha1.NetworkPolicySpec{
+				Ports: []v1alpha1.NetworkPort{{Name: "headless", Port: 80, TargetPort: 8080}},
+			},
+		}
+
+		stable := &v1alpha1.Release{
+			SemVer: &v1alpha1.SemVerRelease{Name: "hello-world", Version: "1.0.0"},
+			Level:  v1alpha1.SemVerLevelRelease,
+		}
+		preview := &v1alpha1.Release{
+			SemVer: &v1alpha1.SemVerRelease{Name: "my-branch", Version: "1.2.0-my-branch"},
+			Level:  v1alpha1.SemVerLevelPreview,
+		}
+		srv := &corev1.Service{ObjectMeta: metav1.ObjectMeta{Name: preview.StreamName(ms.Name)}}
+
+		rt := &httpRouteRouter{ms: ms, np: np, gateway: v1alpha1.GatewayReference{Name: "public"}}
+		routes := []streamRoute{
+			{Release: preview, Service: srv, Matches: []routeMatch{
+				{Header: "X-Hlnr-Release", Value: "my-branch"},
+				{Cookie: "hlnr-release", Value: "my-branch"},
+			}},
+			{Release: stable, Ingresses: []*v1beta1.Ingress{ing}},
+		}
+		for _, route := range routes {
+			if _, err := rt.Routes(route); err != nil {
+				t.Fatalf("Expected no error, got %s", err)
+			}
+		}
+
+		objs, err := rt.Objects()
+		if err != nil {
+			t.Fatalf("Expected no error, got %s", err)
+		}
+
+		if len(objs) != 1 {
+			t.Fatalf("Expected an HTTPRoute for the preview release, got %d objects", len(objs))
+		}
+
+		obj := objs[0].(*unstructured.Unstructured).DeepCopy()
+		if name := preview.StreamName(ms.Name) + "-rules"; obj.GetName() != name {
+			t.Errorf("Expected HTTPRoute %s, got %s", name, obj.GetName())
+		}
+
+		hostnames, _ := unstructured.NestedStringSlice(obj.Object, "spec", "hostnames")
+		if !reflect.DeepEqual(hostnames, []string{"hello-world.hlnr.io"}) {
+			t.Errorf("Expected the hostname of the release stream, got %v", hostnames)
+		}
+
+		rules, _ := unstructured.NestedSlice(obj.Object, "spec", "rules")
+		rule := rules[0].(map[string]interface{})
+		matches := rule["matches"].([]interface{})
+		if len(matches) != 2 {
+			t.Fatalf("Expected a match per rule, got %d", len(matches))
+		}
+
+		headers := matches[0].(map[string]interface{})["headers"]
+		expected := []interface{}{
+			map[string]interface{}{"type": "Exact", "name": "X-Hlnr-Release", "value": "my-branch"},
+		}
+		if !reflect.DeepEqual(headers, expected) {
+			t.Errorf("Expected header match %v, got %v", expected, headers)
+		}
+
+		backendRefs := rule["backendRefs"].([]interface{})
+		expectedRefs := []interface{}{
+			map[string]interface{}{"name": preview.FullName(ms.Name), "port": int64(80)},
+		}
+		if !reflect.DeepEqual(backendRefs, expectedRefs) {
+			t.Errorf("Expected backend %v, got %v", expectedRefs, backendRefs)
+		}
+	})
+}
+
+func TestPruneRoutes(t *testing.T) {
+	ms := &v1alpha1.Microservice{ObjectMeta: metav1.ObjectMeta{Name: "hello-world", Namespace: "testing"}}
+	release := &v1alpha1.Release{
+		SemVer: &v1alpha1.SemVerRelease{Name: "hello-world", Version: "1.0.0"},
+		Level:  v1alpha1.SemVerLevelRelease,
+	}
+
+	ing := &v1beta1.Ingress{
+		TypeMeta:   metav1.TypeMeta{Kind: "Ingress", APIVersion: "extensions/v1beta1"},
+		ObjectMeta: metav1.ObjectMeta{Name: "hello-world", Namespace: "testing"},
+		Spec: v1beta1.IngressSpec{
+			Rules: []v1beta1.IngressRule{
+				{
+					Host: "hello-world.hlnr.io",
+					IngressRuleValue: v1beta1.IngressRuleValue{
+						HTTP: &v1beta1.HTTPIngressRuleValue{
+							Paths: []v1beta1.HTTPIngressPath{
+								{
+									Path: "/",
+									Backend: v1beta1.IngressBackend{
+										ServiceName: "hello-world",
+										ServicePort: intstr.FromString("headless"),
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	routes := []streamRoute{
+		{
+			Release:   release,
+			Ingresses: []*v1beta1.Ingress{ing},
+			Service:   &corev1.Service{ObjectMeta: metav1.ObjectMeta{Name: "hello-world"}},
+		},
+	}
+
+	all := routingAPIs{IngressV1: true, HTTPRoute: true, Istio: true}
+
+	tcs := []struct {
+		name     string
+		backend  v1alpha1.RoutingBackend
+		apis     routingAPIs
+		expected []string
+	}{
+		{
+			"from HTTPRoutes to Ingresses",
+			v1alpha1.RoutingIngress,
+			routingAPIs{IngressV1: true, HTTPRoute: true},
+			[]string{"HTTPRoute/hello-world"},
+		},
+		{
+			"from Ingresses to HTTPRoutes",
+			v1alpha1.RoutingHTTPRoute,
+			routingAPIs{IngressV1: true, HTTPRoute: true},
+			[]string{"Ingress/hello-world", "Ingress/hello-world-canary"},
+		},
+		{
+			"from Istio to extensions Ingresses",
+			v1alpha1.RoutingExtensionsIngress,
+			all,
+			[]string{"DestinationRule/hello-world", "HTTPRoute/hello-world", "Service/hello-world-mesh", "VirtualService/hello-world"},
+		},
+		{
+			"without other supported backends",
+			v1alpha1.RoutingExtensionsIngress,
+			routingAPIs{},
+			nil,
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			np := &v1alpha1.NetworkPolicy{
+				ObjectMeta: metav1.ObjectMeta{Name: "hello-world", Namespace: "testing"},
+				Spec: v1alpha1.NetworkPolicySpec{
+					Ports:   []v1alpha1.NetworkPort{{Name: "headless", Port: 80, TargetPort: 8080}},
+					Routing: &v1alpha1.Routing{Backend: tc.backend, Gateway: &v1alpha1.GatewayReference{Name: "public"}},
+				},
+			}
+
+			var deleted []string
+			pc := &tester.PatchClient{
+				DeleteFunc: func(obj runtime.Object, _ ...patcher.OptionFunc) error {
+					deleted = append(deleted, objectKey(obj))
+					return kerrors.NewNotFound(schema.GroupResource{}, "")
+				},
+			}
+
+			if err := pruneRoutes(pc, ms, np, tc.apis, routes); err != nil {
+				t.Fatalf("Expected no error, got %s", err)
+			}
+
+			sort.Strings(deleted)
+			if !reflect.DeepEqual(deleted, tc.expected) {
+				t.Errorf("Expected %v to be pruned, got %v", tc.expected, deleted)
+			}
+		})
+	}
+}
+
+func TestSyncRoutes_RemovedGroup(t *testing.T) {
+	ms := &v1alpha1.Microservice{ObjectMeta: metav1.ObjectMeta{Name: "hello-world", Namespace: "testing"}}
+	np := &v1alpha1.NetworkPolicy{
+		ObjectMeta: metav1.ObjectMeta{Name: "hello-world", Namespace: "testing"},
+		Spec: v1alpha1.NetworkPolicySpec{
+			Ports: []v1alpha1.NetworkPort{{Name: "headless", Port: 80, TargetPort: 8080}},
+			ExternalDNS: []v1alpha1.ExternalDNS{
+				{Domain: "hello-world.hlnr.io"},
+			},
+		},
+	}
+	release := &v1alpha1.Release{
+		SemVer: &v1alpha1.SemVerRelease{Name: "hello-world", Version: "1.0.0"},
+		Level:  v1alpha1.SemVerLevelRelease,
+	}
+	srv := &corev1.Service{ObjectMeta: metav1.ObjectMeta{Name: "hello-world", Namespace: "testing"}}
+
+	ings, err := buildIngressesForRelease(ms, np, release, srv, nil)
+	if err != nil {
+		t.Fatalf("Expected no error, got %s", err)
+	}
+
+	// the stream used to have a second group of records, and both of its
+	// Ingresses had an additional host.
+	tcs := []struct {
+		name     string
+		rt       router
+		existing []string
+		expected []string
+	}{
+		{
+			"with Ingresses",
+			&extensionsIngressRouter{},
+			[]string{"Ingress/hello-world", "Ingress/hello-world-1", "Ingress/hello-world-1-canary"},
+			[]string{"Ingress/hello-world-1", "Ingress/hello-world-1-canary"},
+		},
+		{
+			"with HTTPRoutes",
+			&httpRouteRouter{ms: ms, np: np, gateway: v1alpha1.GatewayReference{Name: "public"}},
+			[]string{"HTTPRoute/hello-world", "HTTPRoute/hello-world-h1", "HTTPRoute/hello-world-1", "HTTPRoute/hello-world-1-h1"},
+			[]string{"HTTPRoute/hello-world-1", "HTTPRoute/hello-world-1-h1", "HTTPRoute/hello-world-h1"},
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			existing := map[string]bool{}
+			for _, key := range tc.existing {
+				existing[key] = true
+			}
+
+			var deleted []string
+			pc := &tester.PatchClient{
+				ApplyFunc: func(runtime.Object, ...patcher.OptionFunc) ([]byte, error) {
+					return nil, nil
+				},
+				DeleteFunc: func(obj runtime.Object, _ ...patcher.OptionFunc) error {
+					key := objectKey(obj)
+					if !existing[key] {
+						return kerrors.NewNotFound(schema.GroupResource{}, key)
+					}
+
+					delete(existing, key)
+					deleted = append(deleted, key)
+					return nil
+				},
+			}
+
+			route := streamRoute{Release: release, Ingresses: ings, Service: srv}
+			if err := syncRoutes(pc, tc.rt, ms, route, false); err != nil {
+				t.Fatalf("Expected no error, got %s", err)
+			}
+
+			sort.Strings(deleted)
+			if !reflect.DeepEqual(deleted, tc.expected) {
+				t.Errorf("Expected %v to be removed, got %v", tc.expected, deleted)
+			}
+		})
+	}
+}
@@ -0,0 +1,70 @@
+package networkpolicy
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+
+	"github.com/manifoldco/heighliner/apis/v1alpha1"
+)
+
+// defaultRuleValue is the value a header or cookie should have when a
+// RoutingRule doesn't specify one.
+const defaultRuleValue = "{{.Name}}"
+
+var (
+	// ErrUnsupportedRules is used when RoutingRules are configured for a
+	// routing backend which can't match on headers or cookies.
+	ErrUnsupportedRules = errors.New("routing rules require the HTTPRoute or Istio routing backend")
+
+	// ErrInvalidRule is used when a RoutingRule doesn't have exactly one of
+	// header and cookie.
+	ErrInvalidRule = errors.New("a routing rule needs either a header or a cookie")
+)
+
+// routeMatch is a RoutingRule with its value templated for a release.
+type routeMatch struct {
+	Header string
+	Cookie string
+	Value  string
+}
+
+// buildRouteMatches templates the RoutingRules of a NetworkPolicy for the
+// given release. Only preview releases are routed through rules.
+func buildRouteMatches(ms *v1alpha1.Microservice, np *v1alpha1.NetworkPolicy, release *v1alpha1.Release) ([]routeMatch, error) {
+	if np.Spec.Routing == nil || release.Level != v1alpha1.SemVerLevelPreview {
+		return nil, nil
+	}
+
+	var matches []routeMatch
+	for _, rule := range np.Spec.Routing.Rules {
+		if (rule.Header == "") == (rule.Cookie == "") {
+			return nil, ErrInvalidRule
+		}
+
+		value := rule.Value
+		if value == "" {
+			value = defaultRuleValue
+		}
+
+		// rule values use the same data as domains.
+		value, err := templateString(ms, release, value)
+		if err != nil {
+			return nil, fmt.Errorf("could not template the value of rule for %s%s: %s", rule.Header, rule.Cookie, err)
+		}
+
+		matches = append(matches, routeMatch{
+			Header: rule.Header,
+			Cookie: rule.Cookie,
+			Value:  value,
+		})
+	}
+
+	return matches, nil
+}
+
+// cookieRegex returns the expression which matches a Cookie header containing
+// the given cookie and value.
+func cookieRegex(name, value string) string {
+	return fmt.Sprintf(`^(.*;\s*)?%s=%s(;.*)?$`, regexp.QuoteMeta(name), regexp.QuoteMeta(value))
+}
@@ -0,0 +1,88 @@
+package networkpolicy
+
+import (
+	"reflect"
+	"regexp"
+	"testing"
+
+	"github.com/manifoldco/heighliner/apis/v1alpha1"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestBuildRouteMatches(t *testing.T) {
+	ms := &v1alpha1.Microservice{ObjectMeta: metav1.ObjectMeta{Name: "hello-world"}}
+	preview := &v1alpha1.Release{
+		SemVer: &v1alpha1.SemVerRelease{Name: "my-branch", Version: "1.2.0-my-branch"},
+		Level:  v1alpha1.SemVerLevelPreview,
+	}
+
+	newPolicy := func(rules ...v1alpha1.RoutingRule) *v1alpha1.NetworkPolicy {
+		return &v1alpha1.NetworkPolicy{
+			Spec: v1alpha1.NetworkPolicySpec{
+				Routing: &v1alpha1.Routing{Rules: rules},
+			},
+		}
+	}
+
+	t.Run("templates the values", func(t *testing.T) {
+		np := newPolicy(
+			v1alpha1.RoutingRule{Header: "X-Hlnr-Release"},
+			v1alpha1.RoutingRule{Cookie: "hlnr-stream", Value: "{{.StreamName}}"},
+		)
+
+		matches, err := buildRouteMatches(ms, np, preview)
+		if err != nil {
+			t.Fatalf("Expected no error, got %s", err)
+		}
+
+		expected := []routeMatch{
+			{Header: "X-Hlnr-Release", Value: "my-branch"},
+			{Cookie: "hlnr-stream", Value: preview.StreamName(ms.Name)},
+		}
+		if !reflect.DeepEqual(matches, expected) {
+			t.Errorf("Expected %v, got %v", expected, matches)
+		}
+	})
+
+	t.Run("ignores other levels", func(t *testing.T) {
+		release := &v1alpha1.Release{
+			SemVer: &v1alpha1.SemVerRelease{Name: "hello-world", Version: "1.0.0"},
+			Level:  v1alpha1.SemVerLevelRelease,
+		}
+
+		matches, err := buildRouteMatches(ms, newPolicy(v1alpha1.RoutingRule{Header: "X-Hlnr-Release"}), release)
+		if err != nil || matches != nil {
+			t.Errorf("Expected no matches, got %v (%v)", matches, err)
+		}
+	})
+
+	t.Run("invalid rule", func(t *testing.T) {
+		np := newPolicy(v1alpha1.RoutingRule{Header: "X-Hlnr-Release", Cookie: "hlnr-stream"})
+		if _, err := buildRouteMatches(ms, np, preview); err != ErrInvalidRule {
+			t.Errorf("Expected %s, got %v", ErrInvalidRule, err)
+		}
+	})
+}
+
+func TestCookieRegex(t *testing.T) {
+	re := regexp.MustCompile(cookieRegex("hlnr-release", "my.branch"))
+
+	tcs := []struct {
+		header  string
+		matches bool
+	}{
+		{"hlnr-release=my.branch", true},
+		{"session=abc; hlnr-release=my.branch; theme=dark", true},
+		{"session=abc;hlnr-release=my.branch", true},
+		{"hlnr-release=myxbranch", false},
+		{"hlnr-release=my.branch-2", false},
+		{"other-hlnr-release=my.branch", false},
+	}
+
+	for _, tc := range tcs {
+		if re.MatchString(tc.header) != tc.matches {
+			t.Errorf("Expected match of %q to be %t", tc.header, tc.matches)
+		}
+	}
+}
@@ -1,6 +1,10 @@
 package networkpolicy
 
 import (
+	"encoding/json"
+	"errors"
+	"fmt"
+
 	"github.com/manifoldco/heighliner/apis/v1alpha1"
 	"github.com/manifoldco/heighliner/internal/meta"
 
@@ -8,6 +12,10 @@ import (
 	"k8s.io/apimachinery/pkg/util/intstr"
 )
 
+// ErrLoadBalancerProtocol is used when a LoadBalancer Service would expose
+// ports which don't use TCP.
+var ErrLoadBalancerProtocol = errors.New("LoadBalancer Services only support TCP ports")
+
 func buildServiceForRelease(srv *corev1.Service, svc *v1alpha1.Microservice, np *v1alpha1.NetworkPolicy, release *v1alpha1.Release) *corev1.Service {
 	labels := meta.MicroserviceLabels(svc, release, np)
 	if srv.Labels == nil {
@@ -34,15 +42,44 @@ func buildServiceForRelease(srv *corev1.Service, svc *v1alpha1.Microservice, np
 		selector[k] = v
 	}
 	delete(selector, meta.LabelServiceKey)
+	delete(selector, meta.LabelLevelKey)
 
 	sessionAffinity := corev1.ServiceAffinityNone
 	if np.Spec.SessionAffinity != nil && np.Spec.SessionAffinity.ClientIP != nil {
 		sessionAffinity = corev1.ServiceAffinityClientIP
 	}
 
+	serviceType := getServiceType(np, srv.Name == release.StreamName(svc.Name))
+	ports := getServicePorts(np.Spec.Ports)
+
+	// keep the node ports which are already allocated, so they don't change
+	// with every update. Node ports are released when the Service no longer
+	// exposes them.
+	if exposesNodePorts(srv.Spec.Type) && exposesNodePorts(serviceType) {
+		nodePorts := map[string]int32{}
+		for _, port := range srv.Spec.Ports {
+			nodePorts[port.Name] = port.NodePort
+		}
+
+		for i := range ports {
+			ports[i].NodePort = nodePorts[ports[i].Name]
+		}
+	}
+
+	// the traffic policy and health check port are only valid for Services
+	// which are reachable from outside the cluster.
+	srv.Spec.ExternalTrafficPolicy = ""
+	if exposesNodePorts(serviceType) {
+		srv.Spec.ExternalTrafficPolicy = np.Spec.ExternalTrafficPolicy
+	}
+
+	if serviceType != corev1.ServiceTypeLoadBalancer || srv.Spec.ExternalTrafficPolicy != corev1.ServiceExternalTrafficPolicyTypeLocal {
+		srv.Spec.HealthCheckNodePort = 0
+	}
+
 	srv.OwnerReferences = release.OwnerReferences
-	srv.Spec.Type = corev1.ServiceTypeNodePort
-	srv.Spec.Ports = getServicePorts(np.Spec.Ports)
+	srv.Spec.Type = serviceType
+	srv.Spec.Ports = ports
 	srv.Spec.Selector = selector
 	srv.Spec.SessionAffinity = sessionAffinity
 	srv.Spec.SessionAffinityConfig = np.Spec.SessionAffinity
@@ -50,12 +87,48 @@ func buildServiceForRelease(srv *corev1.Service, svc *v1alpha1.Microservice, np
 	return srv
 }
 
+// getServiceType returns the type of a Service. Only the Services of release
+// streams become LoadBalancers, to not provision a load balancer for every
+// release.
+func getServiceType(np *v1alpha1.NetworkPolicy, stream bool) corev1.ServiceType {
+	switch np.Spec.ServiceType {
+	case "":
+		return corev1.ServiceTypeNodePort
+	case corev1.ServiceTypeLoadBalancer:
+		if !stream {
+			return corev1.ServiceTypeNodePort
+		}
+	}
+
+	return np.Spec.ServiceType
+}
+
+func exposesNodePorts(serviceType corev1.ServiceType) bool {
+	return serviceType == corev1.ServiceTypeNodePort || serviceType == corev1.ServiceTypeLoadBalancer
+}
+
+// validateServiceType checks whether the ports of a NetworkPolicy can be
+// exposed through its ServiceType.
+func validateServiceType(np *v1alpha1.NetworkPolicy) error {
+	if np.Spec.ServiceType != corev1.ServiceTypeLoadBalancer {
+		return nil
+	}
+
+	for _, port := range np.Spec.Ports {
+		if servicePortProtocol(port) != corev1.ProtocolTCP {
+			return ErrLoadBalancerProtocol
+		}
+	}
+
+	return nil
+}
+
 func getServicePorts(networkPorts []v1alpha1.NetworkPort) []corev1.ServicePort {
 	ports := make([]corev1.ServicePort, len(networkPorts))
 
 	for i, port := range networkPorts {
 		ports[i] = corev1.ServicePort{
-			Protocol:   corev1.ProtocolTCP,
+			Protocol:   servicePortProtocol(port),
 			Name:       port.Name,
 			Port:       port.Port,
 			TargetPort: intstr.FromInt(int(port.TargetPort)),
@@ -64,3 +137,50 @@ func getServicePorts(networkPorts []v1alpha1.NetworkPort) []corev1.ServicePort {
 
 	return ports
 }
+
+func servicePortProtocol(port v1alpha1.NetworkPort) corev1.Protocol {
+	if port.Protocol == "" {
+		return corev1.ProtocolTCP
+	}
+
+	return port.Protocol
+}
+
+// withAppProtocols returns the JSON of a Service with the appProtocol of its
+// ports set. The Service type we use predates appProtocol, so writing it
+// through the typed client would drop them. It returns nil when no port has
+// an appProtocol.
+func withAppProtocols(srv *corev1.Service, networkPorts []v1alpha1.NetworkPort) ([]byte, error) {
+	appProtocols := map[string]string{}
+	for _, port := range networkPorts {
+		if port.AppProtocol != "" {
+			appProtocols[fmt.Sprintf("%d/%s", port.Port, servicePortProtocol(port))] = port.AppProtocol
+		}
+	}
+
+	if len(appProtocols) == 0 {
+		return nil, nil
+	}
+
+	data, err := json.Marshal(srv)
+	if err != nil {
+		return nil, err
+	}
+
+	var obj map[string]interface{}
+	if err := json.Unmarshal(data, &obj); err != nil {
+		return nil, err
+	}
+
+	spec, _ := obj["spec"].(map[string]interface{})
+	ports, _ := spec["ports"].([]interface{})
+	for _, p := range ports {
+		port := p.(map[string]interface{})
+		key := fmt.Sprintf("%v/%v", port["port"], port["protocol"])
+		if appProtocol, ok := appProtocols[key]; ok {
+			port["appProtocol"] = appProtocol
+		}
+	}
+
+	return json.Marshal(obj)
+}
@@ -1,6 +1,7 @@
 package networkpolicy
 
 import (
+	"encoding/json"
 	"testing"
 
 	"github.com/manifoldco/heighliner/apis/v1alpha1"
@@ -82,4 +83,115 @@ func TestBuildServiceForRelease(t *testing.T) {
 		}
 	})
 
+	t.Run("Uses the service type", func(t *testing.T) {
+		np := np.DeepCopy()
+		np.Spec.ServiceType = corev1.ServiceTypeLoadBalancer
+		np.Spec.ExternalTrafficPolicy = corev1.ServiceExternalTrafficPolicyTypeLocal
+
+		stream := buildServiceForRelease(&corev1.Service{ObjectMeta: metav1.ObjectMeta{Name: release.StreamName(ms.Name)}}, ms, np, release)
+		if stream.Spec.Type != corev1.ServiceTypeLoadBalancer {
+			t.Errorf("Expected the stream Service to be a LoadBalancer, got %s", stream.Spec.Type)
+		}
+
+		if stream.Spec.ExternalTrafficPolicy != corev1.ServiceExternalTrafficPolicyTypeLocal {
+			t.Errorf("Expected the Local traffic policy, got %s", stream.Spec.ExternalTrafficPolicy)
+		}
+
+		srv := buildServiceForRelease(&corev1.Service{ObjectMeta: metav1.ObjectMeta{Name: release.FullName(ms.Name)}}, ms, np, release)
+		if srv.Spec.Type != corev1.ServiceTypeNodePort {
+			t.Errorf("Expected the release Service to be a NodePort, got %s", srv.Spec.Type)
+		}
+	})
+
+	t.Run("Keeps allocated node ports", func(t *testing.T) {
+		srv := &corev1.Service{
+			Spec: corev1.ServiceSpec{
+				Type:  corev1.ServiceTypeNodePort,
+				Ports: []corev1.ServicePort{{Name: "headless", Port: 80, NodePort: 30080}},
+			},
+		}
+
+		obj := buildServiceForRelease(srv, ms, np, release)
+		if obj.Spec.Ports[0].NodePort != 30080 {
+			t.Errorf("Expected node port 30080 to be kept, got %d", obj.Spec.Ports[0].NodePort)
+		}
+	})
+
+	t.Run("Migrates to ClusterIP", func(t *testing.T) {
+		np := np.DeepCopy()
+		np.Spec.ServiceType = corev1.ServiceTypeClusterIP
+		np.Spec.ExternalTrafficPolicy = corev1.ServiceExternalTrafficPolicyTypeLocal
+
+		srv := &corev1.Service{
+			Spec: corev1.ServiceSpec{
+				Type:                  corev1.ServiceTypeNodePort,
+				ClusterIP:             "10.0.0.10",
+				ExternalTrafficPolicy: corev1.ServiceExternalTrafficPolicyTypeCluster,
+				Ports:                 []corev1.ServicePort{{Name: "headless", Port: 80, NodePort: 30080}},
+			},
+		}
+
+		obj := buildServiceForRelease(srv, ms, np, release)
+		if obj.Spec.Type != corev1.ServiceTypeClusterIP || obj.Spec.ClusterIP != "10.0.0.10" {
+			t.Errorf("Expected a ClusterIP Service with the same IP, got %s %s", obj.Spec.Type, obj.Spec.ClusterIP)
+		}
+
+		if obj.Spec.Ports[0].NodePort != 0 || obj.Spec.ExternalTrafficPolicy != "" {
+			t.Errorf("Expected the node ports and traffic policy to be removed, got %+v", obj.Spec)
+		}
+	})
+
+	t.Run("Sets the protocol", func(t *testing.T) {
+		np := np.DeepCopy()
+		np.Spec.Ports = append(np.Spec.Ports, v1alpha1.NetworkPort{Name: "dns", Port: 53, TargetPort: 53, Protocol: corev1.ProtocolUDP})
+
+		obj := buildServiceForRelease(&corev1.Service{}, ms, np, release)
+		if obj.Spec.Ports[0].Protocol != corev1.ProtocolTCP || obj.Spec.Ports[1].Protocol != corev1.ProtocolUDP {
+			t.Errorf("Expected TCP and UDP ports, got %+v", obj.Spec.Ports)
+		}
+
+		np.Spec.ServiceType = corev1.ServiceTypeLoadBalancer
+		if err := validateServiceType(np); err != ErrLoadBalancerProtocol {
+			t.Errorf("Expected %s, got %v", ErrLoadBalancerProtocol, err)
+		}
+	})
+}
+
+func TestWithAppProtocols(t *testing.T) {
+	ports := []v1alpha1.NetworkPort{
+		{Name: "headless", Port: 80, TargetPort: 8080},
+		{Name: "grpc", Port: 9090, TargetPort: 9090, AppProtocol: "grpc"},
+	}
+
+	srv := &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{Name: "hello-world", ResourceVersion: "42"},
+		Spec:       corev1.ServiceSpec{Ports: getServicePorts(ports)},
+	}
+
+	data, err := withAppProtocols(srv, ports)
+	if err != nil {
+		t.Fatalf("Expected no error, got %s", err)
+	}
+
+	var written struct {
+		Metadata metav1.ObjectMeta `json:"metadata"`
+		Spec     struct {
+			Ports []map[string]interface{} `json:"ports"`
+		} `json:"spec"`
+	}
+	if err := json.Unmarshal(data, &written); err != nil {
+		t.Fatalf("Expected the Service as JSON, got %s", data)
+	}
+
+	if written.Metadata.ResourceVersion != "42" {
+		t.Errorf("Expected the Service to be written as is, got %s", data)
+	}
+
+	if len(written.Spec.Ports) != 2 || written.Spec.Ports[0]["appProtocol"] != nil || written.Spec.Ports[1]["appProtocol"] != "grpc" {
+		t.Errorf("Expected the appProtocol of the grpc port to be set, got %v", written.Spec.Ports)
+	}
+
+	if data, _ := withAppProtocols(srv, ports[:1]); data != nil {
+		t.Errorf("Expected nothing without appProtocols, got %s", data)
+	}
 }
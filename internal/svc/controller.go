@@ -2,9 +2,11 @@ package svc
 
 import (
 	"context"
+	"fmt"
 	"log"
 	"os"
 	"os/signal"
+	"strings"
 	"syscall"
 
 	"github.com/manifoldco/heighliner/apis/v1alpha1"
@@ -36,6 +38,7 @@ type Controller struct {
 	cs        kubernetes.Interface
 	namespace string
 	patcher   patchClient
+	releases  releaseLister
 }
 
 // NewController returns a new Microservice Controller.
@@ -75,6 +78,9 @@ func (c *Controller) Run() error {
 }
 
 func (c *Controller) run(ctx context.Context) {
+	// the rollout status is part of every sync, wait for its caches first.
+	c.watchReleases(ctx.Done())
+
 	watcher := kubekit.NewWatcher(
 		c.rc,
 		c.namespace,
@@ -99,30 +105,43 @@ func (c *Controller) run(ctx context.Context) {
 func (c *Controller) patchMicroservice(obj interface{}) error {
 	svc := obj.(*v1alpha1.Microservice).DeepCopy()
 
+	if svc.DeletionTimestamp != nil {
+		return c.finalizeMicroservice(svc)
+	}
+
+	svc.Status.ObservedGeneration = svc.Generation
+
 	imagePolicy, err := c.getImagePolicy(svc)
 	if err != nil {
 		log.Printf("Could not get ImagePolicy for Microservice %s: %s", svc.Name, err)
-		return err
+		return c.markDegraded(svc, "ImagePolicyError", err)
 	}
 
 	var deployedReleases []v1alpha1.Release
+	var progressing, degraded []string
 	for _, release := range imagePolicy.Status.Releases {
+		if release.Expired != nil && release.Expired.Action == v1alpha1.ExpiryActionTearDown {
+			// expired releases which should be torn down aren't deployed,
+			// this makes sure they get deprecated below.
+			continue
+		}
+
 		vsvc, err := c.getVersionedMicroservice(svc, imagePolicy, &release)
 		if err != nil {
 			log.Printf("Error generating the VersionedMicroservice object error=%s", err)
-			return err
+			return c.markDegraded(svc, "InvalidRelease", fmt.Errorf("release %s: %s", release.FullName(svc.Name), err))
 		}
 
 		patch, err := c.patcher.Apply(vsvc)
 		if err != nil {
 			log.Printf("Error applying VersionedMicroservice error=%s", err)
-			return err
+			return c.markDegraded(svc, "SyncError", err)
 		}
 
 		// refresh the vsvc
 		if err := c.patcher.Get(vsvc, vsvc.Namespace, vsvc.Name); err != nil {
 			log.Printf("Error refreshing VersionedMicroservice: %s", err)
-			return err
+			return c.markDegraded(svc, "SyncError", err)
 		}
 
 		// Add OwnerReference to Release. We can use this later on to link to
@@ -133,6 +152,7 @@ func (c *Controller) patchMicroservice(obj interface{}) error {
 				v1alpha1.SchemeGroupVersion.WithKind(kubekit.TypeName(vsvc)),
 			),
 		}
+		release.Hooks = releaseHooks(svc.Spec.Hooks, vsvc.Status.Hooks)
 
 		patch, err = k8sutils.CleanupPatchAnnotations(patch, "hlnr-microservice")
 		// doesn't matter if this errors, we just won't log the change if it
@@ -141,16 +161,52 @@ func (c *Controller) patchMicroservice(obj interface{}) error {
 			log.Printf("Synced Microservice %s %s with version %s", vsvc.Name, release.FullName(svc.Name), release.Version())
 		}
 
+		switch {
+		case vsvc.Status.Conditions.IsTrue(v1alpha1.ConditionDegraded):
+			degraded = append(degraded, vsvc.Name)
+		case !vsvc.Status.Conditions.IsTrue(v1alpha1.ConditionReady):
+			progressing = append(progressing, vsvc.Name)
+		}
+
 		deployedReleases = append(deployedReleases, release)
 	}
 
-	if err := deprecateReleases(c.patcher, svc, imagePolicy.Status.Releases); err != nil {
+	if err := deprecateReleases(c.patcher, svc, deployedReleases); err != nil {
 		log.Printf("Error deprecating releases for %s: %s", svc.Name, err)
-		return err
+		return c.markDegraded(svc, "SyncError", err)
 	}
 
 	// new release objects, store them
 	svc.Status.Releases = deployedReleases
+	if err := c.setRolloutStatus(svc); err != nil {
+		log.Printf("Could not get the rollout status of Microservice %s: %s", svc.Name, err)
+	}
+
+	switch {
+	case len(degraded) > 0:
+		svc.Status.Conditions.MarkDegraded("ReleaseDegraded", fmt.Sprintf("degraded releases: %s", strings.Join(degraded, ", ")))
+	case len(deployedReleases) == 0:
+		svc.Status.Conditions.MarkProgressing("NoReleases", "waiting for releases of the ImagePolicy")
+	case len(progressing) > 0:
+		svc.Status.Conditions.MarkProgressing("RollingOut", fmt.Sprintf("rolling out releases: %s", strings.Join(progressing, ", ")))
+	default:
+		svc.Status.Conditions.MarkReady("Deployed", "")
+	}
+
+	return c.applyMicroservice(svc)
+}
+
+// markDegraded records why a Microservice couldn't be synced in its status,
+// and returns the error. The releases in the status are left untouched.
+func (c *Controller) markDegraded(svc *v1alpha1.Microservice, reason string, err error) error {
+	svc.Status.Conditions.MarkDegraded(reason, err.Error())
+	c.applyMicroservice(svc)
+	return err
+}
+
+func (c *Controller) applyMicroservice(svc *v1alpha1.Microservice) error {
+	// make sure the releases are torn down when the Microservice is deleted.
+	k8sutils.AddFinalizer(svc, Finalizer)
 
 	// need to specify types again until we resolve the mapping issue
 	svc.TypeMeta = metav1.TypeMeta{
@@ -172,11 +228,18 @@ func (c *Controller) getVersionedMicroservice(crd *v1alpha1.Microservice, ip *v1
 	// VersionedMicroservice.
 	crd = crd.DeepCopy()
 
+	// releases of a level can use different policies.
+	crd.Spec = *crd.Spec.ForLevel(release.Level)
+
 	availabilityPolicySpec, err := c.getAvailabilityPolicySpec(crd)
 	if err != nil {
 		return nil, err
 	}
 
+	if release.Expired != nil {
+		availabilityPolicySpec = scaledDownAvailabilityPolicySpec(availabilityPolicySpec)
+	}
+
 	securityPolicySpec, err := c.getSecurityPolicySpec(crd)
 	if err != nil {
 		return nil, err
@@ -192,6 +255,21 @@ func (c *Controller) getVersionedMicroservice(crd *v1alpha1.Microservice, ip *v1
 		return nil, err
 	}
 
+	sidecars, volumes, err := c.getSidecars(crd, crd.Spec.Sidecars, false)
+	if err != nil {
+		return nil, err
+	}
+	containers = append(containers, sidecars...)
+
+	initContainers, initVolumes, err := c.getSidecars(crd, crd.Spec.InitContainers, true)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := validateContainerNames(containers, initContainers); err != nil {
+		return nil, err
+	}
+
 	annotations := crd.Annotations
 	delete(annotations, "kubekit-hlnr-microservice/last-applied-configuration")
 	delete(annotations, "kubectl.kubernetes.io/last-applied-configuration")
@@ -231,11 +309,29 @@ func (c *Controller) getVersionedMicroservice(crd *v1alpha1.Microservice, ip *v1
 			Config:           configPolicySpec,
 			Security:         securityPolicySpec,
 			Containers:       containers,
+			InitContainers:   initContainers,
 			ImagePullSecrets: secrets,
+			Volumes:          mergeVolumes(volumes, initVolumes),
+			Hooks:            crd.Spec.Hooks,
 		},
 	}, nil
 }
 
+// scaledDownAvailabilityPolicySpec returns a copy of the given
+// AvailabilityPolicySpec, or the default one, with no replicas and without
+// autoscaling.
+func scaledDownAvailabilityPolicySpec(spec *v1alpha1.AvailabilityPolicySpec) *v1alpha1.AvailabilityPolicySpec {
+	if spec == nil {
+		spec = &v1alpha1.DefaultAvailabilityPolicySpec
+	}
+
+	spec = spec.DeepCopy()
+	spec.Replicas = func(i int32) *int32 { return &i }(0)
+	spec.Autoscaling = nil
+
+	return spec
+}
+
 func (c *Controller) getContainers(crd *v1alpha1.Microservice, ip *v1alpha1.ImagePolicy, release *v1alpha1.Release) ([]corev1.Container, error) {
 	ipp := corev1.PullIfNotPresent
 	if ip.Spec.ImagePullPolicy != nil {
@@ -258,10 +354,26 @@ func (c *Controller) getContainers(crd *v1alpha1.Microservice, ip *v1alpha1.Imag
 		container.LivenessProbe = healthPolicySpec.LivenessProbe
 	}
 
+	resourcePolicySpec, err := c.getResourcePolicySpec(crd)
+	if err != nil {
+		return nil, err
+	}
+
+	if resourcePolicySpec != nil {
+		container.Resources = resourcePolicySpec.ForLevel(release.Level)
+		if err := validateLimitRanges(c.cs.CoreV1(), crd.Namespace, container.Resources); err != nil {
+			return nil, err
+		}
+	}
+
 	return []corev1.Container{container}, nil
 }
 
 func (c *Controller) getImagePolicy(crd *v1alpha1.Microservice) (*v1alpha1.ImagePolicy, error) {
+	return c.getImagePolicyByName(crd.Namespace, crd.Spec.ImagePolicy.Name)
+}
+
+func (c *Controller) getImagePolicyByName(namespace, name string) (*v1alpha1.ImagePolicy, error) {
 	imagePolicy := &v1alpha1.ImagePolicy{
 		TypeMeta: metav1.TypeMeta{
 			Kind:       "ImagePolicy",
@@ -269,8 +381,7 @@ func (c *Controller) getImagePolicy(crd *v1alpha1.Microservice) (*v1alpha1.Image
 		},
 	}
 
-	ipName := crd.Spec.ImagePolicy.Name
-	if err := c.patcher.Get(imagePolicy, crd.Namespace, ipName); err != nil {
+	if err := c.patcher.Get(imagePolicy, namespace, name); err != nil {
 		return nil, err
 	}
 
@@ -303,19 +414,13 @@ func (c *Controller) getAvailabilityPolicySpec(crd *v1alpha1.Microservice) (*v1a
 }
 
 func (c *Controller) getConfigPolicySpec(crd *v1alpha1.Microservice) (*v1alpha1.ConfigPolicySpec, error) {
-	configPolicy := &v1alpha1.ConfigPolicy{
-		TypeMeta: metav1.TypeMeta{
-			Kind:       "ConfigPolicy",
-			APIVersion: "hlnr.io/v1alpha1",
-		},
-	}
-
 	apName := crd.Spec.ConfigPolicy.Name
 	if apName == "" {
 		return nil, nil
 	}
 
-	if err := c.patcher.Get(configPolicy, crd.Namespace, apName); err != nil {
+	configPolicy, err := c.getConfigPolicy(crd.Namespace, apName)
+	if err != nil {
 		return nil, err
 	}
 
@@ -329,6 +434,21 @@ func (c *Controller) getConfigPolicySpec(crd *v1alpha1.Microservice) (*v1alpha1.
 	return &configPolicy.Spec, nil
 }
 
+func (c *Controller) getConfigPolicy(namespace, name string) (*v1alpha1.ConfigPolicy, error) {
+	configPolicy := &v1alpha1.ConfigPolicy{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       "ConfigPolicy",
+			APIVersion: "hlnr.io/v1alpha1",
+		},
+	}
+
+	if err := c.patcher.Get(configPolicy, namespace, name); err != nil {
+		return nil, err
+	}
+
+	return configPolicy, nil
+}
+
 func (c *Controller) getSecurityPolicySpec(crd *v1alpha1.Microservice) (*v1alpha1.SecurityPolicySpec, error) {
 	securityPolicy := &v1alpha1.SecurityPolicy{
 		TypeMeta: metav1.TypeMeta{
@@ -355,6 +475,10 @@ func (c *Controller) getSecurityPolicySpec(crd *v1alpha1.Microservice) (*v1alpha
 }
 
 func (c *Controller) getHealthPolicySpec(crd *v1alpha1.Microservice) (*v1alpha1.HealthPolicySpec, error) {
+	return c.getHealthPolicySpecByRef(crd.Namespace, crd.Spec.HealthPolicy)
+}
+
+func (c *Controller) getHealthPolicySpecByRef(namespace string, ref corev1.ObjectReference) (*v1alpha1.HealthPolicySpec, error) {
 	healthPolicy := &v1alpha1.HealthPolicy{
 		TypeMeta: metav1.TypeMeta{
 			Kind:       "HealthPolicy",
@@ -362,14 +486,14 @@ func (c *Controller) getHealthPolicySpec(crd *v1alpha1.Microservice) (*v1alpha1.
 		},
 	}
 
-	apName := crd.Spec.HealthPolicy.Name
+	apName := ref.Name
 	if apName == "" {
 		return nil, nil
 	}
 
-	hpNamespace := crd.Spec.HealthPolicy.Namespace
+	hpNamespace := ref.Namespace
 	if hpNamespace == "" {
-		hpNamespace = crd.Namespace
+		hpNamespace = namespace
 	}
 
 	if err := c.patcher.Get(healthPolicy, hpNamespace, apName); err != nil {
@@ -379,6 +503,39 @@ func (c *Controller) getHealthPolicySpec(crd *v1alpha1.Microservice) (*v1alpha1.
 	return &healthPolicy.Spec, nil
 }
 
+// releaseHooks returns the progress of the hooks of a release. Hooks which
+// haven't been picked up by the VersionedMicroservice yet are pending, so the
+// release isn't rolled out before its hooks ran.
+func releaseHooks(hooks *v1alpha1.Hooks, statuses []v1alpha1.HookStatus) []v1alpha1.HookStatus {
+	if hooks == nil {
+		return nil
+	}
+
+	var result []v1alpha1.HookStatus
+	add := func(phase v1alpha1.HookPhase, hooks []v1alpha1.Hook) {
+	HookLoop:
+		for _, hook := range hooks {
+			for _, status := range statuses {
+				if status.Phase == phase && status.Name == hook.Name {
+					result = append(result, status)
+					continue HookLoop
+				}
+			}
+
+			result = append(result, v1alpha1.HookStatus{
+				Name:  hook.Name,
+				Phase: phase,
+				State: v1alpha1.HookStatePending,
+			})
+		}
+	}
+
+	add(v1alpha1.HookPhasePreDeploy, hooks.PreDeploy)
+	add(v1alpha1.HookPhasePostDeploy, hooks.PostDeploy)
+
+	return result
+}
+
 type deleteClient interface {
 	Delete(runtime.Object, ...patcher.OptionFunc) error
 }
@@ -293,6 +293,7 @@ func TestController_PatchMicroservice(t *testing.T) {
 								"hlnr.io/microservice.name":      "test-deploy",
 								"hlnr.io/microservice.release":   "pr-branch",
 								"hlnr.io/microservice.version":   "46ef87b86b66a301c3ac1f072d630d08bbd77420",
+								"hlnr.io/microservice.level":     "preview",
 								"hlnr.io/service":                "test-deploy",
 							},
 							OwnerReferences: vsvc.OwnerReferences, // XXX test nicely
@@ -429,6 +430,7 @@ func TestController_PatchMicroservice(t *testing.T) {
 								"hlnr.io/microservice.name":      "test-deploy",
 								"hlnr.io/microservice.release":   "pr-branch",
 								"hlnr.io/microservice.version":   "46ef87b86b66a301c3ac1f072d630d08bbd77420",
+								"hlnr.io/microservice.level":     "preview",
 								"hlnr.io/service":                "test-deploy",
 								"my-label":                       "label",
 							},
@@ -467,5 +469,266 @@ func TestController_PatchMicroservice(t *testing.T) {
 				t.Errorf("Expected no error, got %s", err)
 			}
 		})
+
+		t.Run("with level overrides", func(t *testing.T) {
+			defer cl.Flush()
+			defer func() {
+				deploy.Spec = v1alpha1.MicroserviceSpec{}
+			}()
+
+			deploy.Spec = v1alpha1.MicroserviceSpec{
+				AvailabilityPolicy: v1.ObjectReference{Name: "high-availability"},
+				Overrides: map[v1alpha1.SemVerLevel]v1alpha1.PolicyOverrides{
+					v1alpha1.SemVerLevelPreview: {
+						AvailabilityPolicy: &v1.ObjectReference{Name: "single"},
+					},
+				},
+			}
+
+			replicas := map[string]int32{"high-availability": 4, "single": 1}
+			cl.GetFunc = func(obj interface{}, namespace, name string) error {
+				switch obj := obj.(type) {
+				case *v1alpha1.ImagePolicy:
+					obj.Status = v1alpha1.ImagePolicyStatus{
+						Releases: []v1alpha1.Release{
+							{
+								Level: v1alpha1.SemVerLevelPreview,
+								SemVer: &v1alpha1.SemVerRelease{
+									Name:    "pr-branch",
+									Version: "46ef87b86b66a301c3ac1f072d630d08bbd77420",
+								},
+							},
+							{
+								Level: v1alpha1.SemVerLevelRelease,
+								SemVer: &v1alpha1.SemVerRelease{
+									Name:    "test-deploy",
+									Version: "1.0.0",
+								},
+							},
+						},
+					}
+
+					return nil
+				case *v1alpha1.AvailabilityPolicy:
+					r := replicas[name]
+					obj.Spec = v1alpha1.AvailabilityPolicySpec{Replicas: &r}
+					return nil
+				case *v1alpha1.VersionedMicroservice:
+					return nil
+				}
+
+				return fmt.Errorf("Object of type %T not supported", obj)
+			}
+
+			applied := map[string]int32{}
+			cl.ApplyFunc = func(obj runtime.Object, opts ...patcher.OptionFunc) ([]byte, error) {
+				if vsvc, ok := obj.(*v1alpha1.VersionedMicroservice); ok {
+					applied[vsvc.Labels["hlnr.io/microservice.level"]] = *vsvc.Spec.Availability.Replicas
+				}
+
+				return nil, nil
+			}
+
+			if err := ctrl.patchMicroservice(deploy); err != nil {
+				t.Errorf("Expected no error, got %s", err)
+			}
+
+			expected := map[string]int32{"preview": 1, "release": 4}
+			if !reflect.DeepEqual(applied, expected) {
+				t.Errorf("Expected replicas %v, got %v", expected, applied)
+			}
+		})
+
+		t.Run("with expired releases", func(t *testing.T) {
+			defer cl.Flush()
+
+			cl.GetFunc = func(obj interface{}, namespace, name string) error {
+				switch obj := obj.(type) {
+				case *v1alpha1.ImagePolicy:
+					obj.Status = v1alpha1.ImagePolicyStatus{
+						Releases: []v1alpha1.Release{
+							{
+								Level: v1alpha1.SemVerLevelPreview,
+								SemVer: &v1alpha1.SemVerRelease{
+									Name:    "pr-scaled",
+									Version: "46ef87b86b66a301c3ac1f072d630d08bbd77420",
+								},
+								Expired: &v1alpha1.ReleaseExpiry{
+									Reason: "idle",
+									Action: v1alpha1.ExpiryActionScaleDown,
+								},
+							},
+							{
+								Level: v1alpha1.SemVerLevelPreview,
+								SemVer: &v1alpha1.SemVerRelease{
+									Name:    "pr-torn",
+									Version: "ba69e4d3e7a5b2c1d6f3f0e8c0b0d7e8e8d8c9a1",
+								},
+								Expired: &v1alpha1.ReleaseExpiry{
+									Reason: "idle",
+									Action: v1alpha1.ExpiryActionTearDown,
+								},
+							},
+						},
+					}
+
+					return nil
+				case *v1alpha1.VersionedMicroservice:
+					return nil
+				}
+
+				return fmt.Errorf("Object of type %T not supported", obj)
+			}
+
+			var applied []string
+			cl.ApplyFunc = func(obj runtime.Object, opts ...patcher.OptionFunc) ([]byte, error) {
+				if vsvc, ok := obj.(*v1alpha1.VersionedMicroservice); ok {
+					applied = append(applied, vsvc.Labels["hlnr.io/microservice.release"])
+
+					av := vsvc.Spec.Availability
+					if av == nil || av.Replicas == nil || *av.Replicas != 0 {
+						t.Errorf("Expected expired release to be scaled down, got %v", av)
+					}
+				}
+
+				if svc, ok := obj.(*v1alpha1.Microservice); ok {
+					if l := len(svc.Status.Releases); l != 1 {
+						t.Errorf("Expected 1 release, got %d", l)
+					}
+				}
+
+				return nil, nil
+			}
+
+			err := ctrl.patchMicroservice(deploy)
+			if err != nil {
+				t.Errorf("Expected no error, got %s", err)
+			}
+
+			if !reflect.DeepEqual(applied, []string{"pr-scaled"}) {
+				t.Errorf("Expected only the scaled down release to be applied, got %v", applied)
+			}
+
+			if r := v1alpha1.DefaultAvailabilityPolicySpec.Replicas; *r != 2 {
+				t.Errorf("Expected the default availability to be unchanged, got %d replicas", *r)
+			}
+		})
+	})
+}
+
+func TestReleaseHooks(t *testing.T) {
+	hooks := &v1alpha1.Hooks{
+		PreDeploy:  []v1alpha1.Hook{{Name: "migrate"}},
+		PostDeploy: []v1alpha1.Hook{{Name: "smoke"}},
+	}
+
+	statuses := []v1alpha1.HookStatus{
+		{Name: "migrate", Phase: v1alpha1.HookPhasePreDeploy, State: v1alpha1.HookStateSucceeded, Job: "hello-world-pre-migrate"},
+		{Name: "removed", Phase: v1alpha1.HookPhasePostDeploy, State: v1alpha1.HookStateFailed},
+	}
+
+	expected := []v1alpha1.HookStatus{
+		statuses[0],
+		{Name: "smoke", Phase: v1alpha1.HookPhasePostDeploy, State: v1alpha1.HookStatePending},
+	}
+
+	if actual := releaseHooks(hooks, statuses); !reflect.DeepEqual(actual, expected) {
+		t.Errorf("Expected hooks %+v, got %+v", expected, actual)
+	}
+
+	if actual := releaseHooks(nil, statuses); actual != nil {
+		t.Errorf("Expected no hooks without hooks, got %+v", actual)
+	}
+}
+
+func TestController_PatchMicroservice_Conditions(t *testing.T) {
+	release := v1alpha1.Release{
+		Level:  v1alpha1.SemVerLevelRelease,
+		SemVer: &v1alpha1.SemVerRelease{Name: "hello-world", Version: "1.2.3"},
+	}
+
+	// run syncs a Microservice whose ImagePolicy can't be retrieved with
+	// ipErr, and whose release has the given conditions.
+	run := func(t *testing.T, ipErr error, conditions v1alpha1.Conditions) *v1alpha1.Microservice {
+		var applied *v1alpha1.Microservice
+		cl := &tester.PatchClient{
+			GetFunc: func(obj interface{}, namespace, name string) error {
+				switch obj := obj.(type) {
+				case *v1alpha1.ImagePolicy:
+					obj.Spec.Image = "hlnr/hello-world"
+					obj.Status.Releases = []v1alpha1.Release{release}
+					return ipErr
+				case *v1alpha1.VersionedMicroservice:
+					obj.Status.Conditions = conditions
+					return nil
+				}
+
+				return errors.New("Object not supported")
+			},
+			ApplyFunc: func(obj runtime.Object, _ ...patcher.OptionFunc) ([]byte, error) {
+				if obj, ok := obj.(*v1alpha1.Microservice); ok {
+					applied = obj
+				}
+
+				return nil, nil
+			},
+			DeleteFunc: func(runtime.Object, ...patcher.OptionFunc) error {
+				return nil
+			},
+		}
+
+		svc := &v1alpha1.Microservice{
+			ObjectMeta: metav1.ObjectMeta{Name: "hello-world", Namespace: "testing", Generation: 5},
+			Status:     v1alpha1.MicroserviceStatus{Releases: []v1alpha1.Release{release}},
+		}
+
+		err := (&Controller{patcher: cl}).patchMicroservice(svc)
+		if err != ipErr {
+			t.Errorf("Expected error %v, got %v", ipErr, err)
+		}
+
+		if applied.Status.ObservedGeneration != 5 {
+			t.Errorf("Expected the observed generation to be 5, got %d", applied.Status.ObservedGeneration)
+		}
+
+		return applied
+	}
+
+	expectCondition := func(t *testing.T, conditions v1alpha1.Conditions, typ v1alpha1.ConditionType, reason string) {
+		cond := conditions.Get(typ)
+		if cond == nil || cond.Status != v1.ConditionTrue || cond.Reason != reason {
+			t.Errorf("Expected %s to be true because of %s, got %+v", typ, reason, conditions)
+		}
+	}
+
+	t.Run("without an ImagePolicy", func(t *testing.T) {
+		applied := run(t, errors.New("imagepolicies.hlnr.io \"hello-world\" not found"), nil)
+
+		expectCondition(t, applied.Status.Conditions, v1alpha1.ConditionDegraded, "ImagePolicyError")
+
+		if len(applied.Status.Releases) != 1 {
+			t.Errorf("Expected the releases to be left untouched, got %+v", applied.Status.Releases)
+		}
+	})
+
+	t.Run("with a release which is rolling out", func(t *testing.T) {
+		applied := run(t, nil, nil)
+		expectCondition(t, applied.Status.Conditions, v1alpha1.ConditionProgressing, "RollingOut")
+	})
+
+	t.Run("with a degraded release", func(t *testing.T) {
+		var conditions v1alpha1.Conditions
+		conditions.MarkDegraded("HookFailed", "BackoffLimitExceeded")
+
+		applied := run(t, nil, conditions)
+		expectCondition(t, applied.Status.Conditions, v1alpha1.ConditionDegraded, "ReleaseDegraded")
+	})
+
+	t.Run("with a ready release", func(t *testing.T) {
+		var conditions v1alpha1.Conditions
+		conditions.MarkReady("Available", "")
+
+		applied := run(t, nil, conditions)
+		expectCondition(t, applied.Status.Conditions, v1alpha1.ConditionReady, "Deployed")
 	})
 }
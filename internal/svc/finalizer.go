@@ -0,0 +1,80 @@
+package svc
+
+import (
+	"log"
+	"time"
+
+	"github.com/manifoldco/heighliner/apis/v1alpha1"
+	"github.com/manifoldco/heighliner/internal/k8sutils"
+
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// Finalizer makes sure the VersionedMicroservices of a Microservice are
+// deleted before the Microservice itself.
+const Finalizer = "hlnr.io/microservice"
+
+// finalizeMicroservice tears down the releases of a Microservice which is
+// being deleted. The finalizer is removed once they're all gone, or once the
+// deletion timed out.
+func (c *Controller) finalizeMicroservice(svc *v1alpha1.Microservice) error {
+	if !k8sutils.HasFinalizer(svc, Finalizer) {
+		return nil
+	}
+
+	pending, err := deleteReleases(c.patcher, svc)
+	if err != nil {
+		log.Printf("Could not delete releases of Microservice %s: %s", svc.Name, err)
+	}
+
+	timedOut := k8sutils.DeletionTimedOut(svc, time.Now())
+	if len(pending) == 0 || timedOut {
+		if len(pending) > 0 {
+			log.Printf("Deletion of Microservice %s timed out, leaving %v behind", svc.Name, pending)
+		}
+
+		k8sutils.RemoveFinalizer(svc, Finalizer)
+	}
+
+	svc.Status.Deletion = v1alpha1.NewDeletionStatus(pending, err, timedOut)
+
+	// need to specify types again until we resolve the mapping issue
+	svc.TypeMeta = metav1.TypeMeta{
+		Kind:       "Microservice",
+		APIVersion: "hlnr.io/v1alpha1",
+	}
+
+	if _, err := c.patcher.Apply(svc); err != nil && !kerrors.IsNotFound(err) {
+		log.Printf("Error finalizing Microservice %s: %s", svc.Name, err)
+		return err
+	}
+
+	return err
+}
+
+// deleteReleases deletes the VersionedMicroservices of all releases of a
+// Microservice, and returns the ones which couldn't be deleted.
+func deleteReleases(cl deleteClient, crd *v1alpha1.Microservice) ([]string, error) {
+	var pending []string
+	var lastErr error
+	for _, release := range crd.Status.Releases {
+		vsvc := &v1alpha1.VersionedMicroservice{
+			TypeMeta: metav1.TypeMeta{
+				Kind:       "VersionedMicroservice",
+				APIVersion: "hlnr.io/v1alpha1",
+			},
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      release.FullName(crd.Name),
+				Namespace: crd.Namespace,
+			},
+		}
+
+		if err := cl.Delete(vsvc); err != nil && !kerrors.IsNotFound(err) {
+			pending = append(pending, "VersionedMicroservice/"+vsvc.Name)
+			lastErr = err
+		}
+	}
+
+	return pending, lastErr
+}
@@ -0,0 +1,99 @@
+package svc
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/jelmersnoeck/kubekit/patcher"
+	"github.com/manifoldco/heighliner/apis/v1alpha1"
+	"github.com/manifoldco/heighliner/internal/k8sutils"
+	"github.com/manifoldco/heighliner/internal/tester"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+func TestController_FinalizeMicroservice(t *testing.T) {
+	// run finalizes a Microservice which was deleted at the given time, with
+	// a client which fails to delete VersionedMicroservices with deleteErr.
+	run := func(t *testing.T, deletedAt time.Time, deleteErr error) (*v1alpha1.Microservice, []string) {
+		ts := metav1.NewTime(deletedAt)
+		svc := &v1alpha1.Microservice{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:              "hello-world",
+				Namespace:         "testing",
+				DeletionTimestamp: &ts,
+				Finalizers:        []string{Finalizer},
+			},
+			Status: v1alpha1.MicroserviceStatus{
+				Releases: []v1alpha1.Release{
+					{
+						SemVer: &v1alpha1.SemVerRelease{Name: "hello-world", Version: "1.2.3"},
+						Level:  v1alpha1.SemVerLevelRelease,
+					},
+				},
+			},
+		}
+
+		var deleted []string
+		var applied *v1alpha1.Microservice
+		cl := &tester.PatchClient{
+			DeleteFunc: func(obj runtime.Object, _ ...patcher.OptionFunc) error {
+				deleted = append(deleted, obj.(*v1alpha1.VersionedMicroservice).Name)
+				return deleteErr
+			},
+			ApplyFunc: func(obj runtime.Object, _ ...patcher.OptionFunc) ([]byte, error) {
+				applied = obj.(*v1alpha1.Microservice)
+				return nil, nil
+			},
+		}
+
+		err := (&Controller{patcher: cl}).finalizeMicroservice(svc)
+		if (err != nil) != (deleteErr != nil) {
+			t.Errorf("Expected error %v, got %v", deleteErr, err)
+		}
+
+		return applied, deleted
+	}
+
+	t.Run("removes the finalizer once the releases are deleted", func(t *testing.T) {
+		applied, deleted := run(t, time.Now(), nil)
+
+		if len(deleted) != 1 {
+			t.Errorf("Expected the release to be deleted, got %v", deleted)
+		}
+
+		if k8sutils.HasFinalizer(applied, Finalizer) {
+			t.Errorf("Expected the finalizer to be removed")
+		}
+	})
+
+	t.Run("keeps the finalizer while releases are pending", func(t *testing.T) {
+		applied, _ := run(t, time.Now(), errors.New("connection refused"))
+
+		if !k8sutils.HasFinalizer(applied, Finalizer) {
+			t.Errorf("Expected the finalizer to be kept")
+		}
+
+		expected := &v1alpha1.DeletionStatus{
+			Pending: []string{"VersionedMicroservice/" + applied.Status.Releases[0].FullName("hello-world")},
+			Message: "connection refused",
+		}
+		if !reflect.DeepEqual(applied.Status.Deletion, expected) {
+			t.Errorf("Expected deletion status %+v, got %+v", expected, applied.Status.Deletion)
+		}
+	})
+
+	t.Run("removes the finalizer once the deletion timed out", func(t *testing.T) {
+		applied, _ := run(t, time.Now().Add(-time.Hour), errors.New("connection refused"))
+
+		if k8sutils.HasFinalizer(applied, Finalizer) {
+			t.Errorf("Expected the finalizer to be removed")
+		}
+
+		if !applied.Status.Deletion.TimedOut {
+			t.Errorf("Expected the deletion to have timed out, got %+v", applied.Status.Deletion)
+		}
+	})
+}
@@ -0,0 +1,98 @@
+package svc
+
+import (
+	"fmt"
+
+	"github.com/manifoldco/heighliner/apis/v1alpha1"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	clientv1 "k8s.io/client-go/kubernetes/typed/core/v1"
+)
+
+func (c *Controller) getResourcePolicySpec(crd *v1alpha1.Microservice) (*v1alpha1.ResourcePolicySpec, error) {
+	resourcePolicy := &v1alpha1.ResourcePolicy{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       "ResourcePolicy",
+			APIVersion: "hlnr.io/v1alpha1",
+		},
+	}
+
+	rpName := crd.Spec.ResourcePolicy.Name
+	if rpName == "" {
+		return nil, nil
+	}
+
+	rpNamespace := crd.Spec.ResourcePolicy.Namespace
+	if rpNamespace == "" {
+		rpNamespace = crd.Namespace
+	}
+
+	if err := c.patcher.Get(resourcePolicy, rpNamespace, rpName); err != nil {
+		return nil, err
+	}
+
+	return &resourcePolicy.Spec, nil
+}
+
+// validateLimitRanges makes sure the resources of a container are allowed by
+// the LimitRanges of a namespace, so the pods of a release don't get rejected
+// after the Deployment is created.
+func validateLimitRanges(cs clientv1.CoreV1Interface, namespace string, req corev1.ResourceRequirements) error {
+	ranges, err := cs.LimitRanges(namespace).List(metav1.ListOptions{})
+	if err != nil {
+		return err
+	}
+
+	for _, lr := range ranges.Items {
+		for _, item := range lr.Spec.Limits {
+			if item.Type != corev1.LimitTypeContainer {
+				continue
+			}
+
+			if err := validateLimitRangeItem(item, req); err != nil {
+				return fmt.Errorf("LimitRange %s: %s", lr.Name, err)
+			}
+		}
+	}
+
+	return nil
+}
+
+func validateLimitRangeItem(item corev1.LimitRangeItem, req corev1.ResourceRequirements) error {
+	for name, min := range item.Min {
+		if q, ok := req.Requests[name]; ok && q.Cmp(min) < 0 {
+			return fmt.Errorf("%s request %s is below the minimum of %s", name, q.String(), min.String())
+		}
+
+		if q, ok := req.Limits[name]; ok && q.Cmp(min) < 0 {
+			return fmt.Errorf("%s limit %s is below the minimum of %s", name, q.String(), min.String())
+		}
+	}
+
+	for name, max := range item.Max {
+		if q, ok := req.Requests[name]; ok && q.Cmp(max) > 0 {
+			return fmt.Errorf("%s request %s is above the maximum of %s", name, q.String(), max.String())
+		}
+
+		if q, ok := req.Limits[name]; ok && q.Cmp(max) > 0 {
+			return fmt.Errorf("%s limit %s is above the maximum of %s", name, q.String(), max.String())
+		}
+	}
+
+	for name, ratio := range item.MaxLimitRequestRatio {
+		limit, ok := req.Limits[name]
+		request, rok := req.Requests[name]
+		if !ok || !rok || request.IsZero() {
+			continue
+		}
+
+		// milli units keep fractional CPU values and ratios precise.
+		max := float64(request.MilliValue()) * float64(ratio.MilliValue()) / 1000
+		if float64(limit.MilliValue()) > max {
+			return fmt.Errorf("%s limit %s is more than %s times its request of %s", name, limit.String(), ratio.String(), request.String())
+		}
+	}
+
+	return nil
+}
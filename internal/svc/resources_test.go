@@ -0,0 +1,72 @@
+package svc
+
+import (
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestValidateLimitRanges(t *testing.T) {
+	cs := fake.NewSimpleClientset(&corev1.LimitRange{
+		ObjectMeta: metav1.ObjectMeta{Name: "limits", Namespace: "testing"},
+		Spec: corev1.LimitRangeSpec{
+			Limits: []corev1.LimitRangeItem{
+				{
+					Type: corev1.LimitTypePod,
+					Max:  corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("100m")},
+				},
+				{
+					Type: corev1.LimitTypeContainer,
+					Min:  corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("50m")},
+					Max:  corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("2Gi")},
+					MaxLimitRequestRatio: corev1.ResourceList{
+						corev1.ResourceCPU: resource.MustParse("2"),
+					},
+				},
+			},
+		},
+	})
+
+	req := func(cpuRequest, cpuLimit, memoryLimit string) corev1.ResourceRequirements {
+		return corev1.ResourceRequirements{
+			Requests: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse(cpuRequest)},
+			Limits: corev1.ResourceList{
+				corev1.ResourceCPU:    resource.MustParse(cpuLimit),
+				corev1.ResourceMemory: resource.MustParse(memoryLimit),
+			},
+		}
+	}
+
+	tcs := []struct {
+		name  string
+		req   corev1.ResourceRequirements
+		valid bool
+	}{
+		{"within the limits", req("250m", "500m", "1Gi"), true},
+		{"below the minimum", req("10m", "20m", "1Gi"), false},
+		{"above the maximum", req("250m", "500m", "4Gi"), false},
+		{"above the ratio", req("250m", "750m", "1Gi"), false},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			err := validateLimitRanges(cs.CoreV1(), "testing", tc.req)
+			if tc.valid && err != nil {
+				t.Errorf("Expected no error, got %s", err)
+			}
+
+			if !tc.valid && err == nil {
+				t.Errorf("Expected an error")
+			}
+		})
+	}
+
+	t.Run("in another namespace", func(t *testing.T) {
+		if err := validateLimitRanges(cs.CoreV1(), "other", req("10m", "20m", "1Gi")); err != nil {
+			t.Errorf("Expected no error, got %s", err)
+		}
+	})
+}
@@ -0,0 +1,352 @@
+package svc
+
+import (
+	"fmt"
+	"log"
+	"reflect"
+	"time"
+
+	"github.com/manifoldco/heighliner/apis/v1alpha1"
+	"github.com/manifoldco/heighliner/internal/meta"
+	"github.com/manifoldco/heighliner/internal/networkpolicy"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/api/extensions/v1beta1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/informers"
+	corelisters "k8s.io/client-go/listers/core/v1"
+	extlisters "k8s.io/client-go/listers/extensions/v1beta1"
+	"k8s.io/client-go/tools/cache"
+)
+
+// waitingFailures are the reasons of waiting containers which won't start
+// without intervention.
+var waitingFailures = map[string]bool{
+	"CrashLoopBackOff":           true,
+	"ErrImagePull":               true,
+	"ImagePullBackOff":           true,
+	"InvalidImageName":           true,
+	"CreateContainerConfigError": true,
+	"CreateContainerError":       true,
+	"RunContainerError":          true,
+}
+
+// releaseLister lists the objects which make up the releases of a
+// Microservice.
+type releaseLister interface {
+	Deployment(namespace, name string) (*v1beta1.Deployment, error)
+	Pods(namespace string, selector labels.Selector) ([]*corev1.Pod, error)
+	NetworkPolicies(namespace string) ([]*v1alpha1.NetworkPolicy, error)
+	HasSynced() bool
+}
+
+// informerLister lists the objects of releases from the caches of informers,
+// which are kept up to date by watching the cluster.
+type informerLister struct {
+	deployments     extlisters.DeploymentLister
+	pods            corelisters.PodLister
+	networkPolicies cache.Indexer
+	synced          []cache.InformerSynced
+}
+
+func (l *informerLister) HasSynced() bool {
+	for _, synced := range l.synced {
+		if !synced() {
+			return false
+		}
+	}
+
+	return true
+}
+
+func (l *informerLister) Deployment(namespace, name string) (*v1beta1.Deployment, error) {
+	return l.deployments.Deployments(namespace).Get(name)
+}
+
+func (l *informerLister) Pods(namespace string, selector labels.Selector) ([]*corev1.Pod, error) {
+	return l.pods.Pods(namespace).List(selector)
+}
+
+func (l *informerLister) NetworkPolicies(namespace string) ([]*v1alpha1.NetworkPolicy, error) {
+	objs, err := l.networkPolicies.ByIndex(cache.NamespaceIndex, namespace)
+	if err != nil {
+		return nil, err
+	}
+
+	nps := make([]*v1alpha1.NetworkPolicy, len(objs))
+	for i, obj := range objs {
+		nps[i] = obj.(*v1alpha1.NetworkPolicy)
+	}
+
+	return nps, nil
+}
+
+// watchReleases watches the Deployments and Pods of all releases, and the
+// NetworkPolicies which route to them. When they change, the rollout status
+// of their Microservice is updated. It blocks until the caches are synced.
+func (c *Controller) watchReleases(stop <-chan struct{}) {
+	handler := cache.ResourceEventHandlerFuncs{
+		AddFunc: func(obj interface{}) {
+			c.syncRolloutFor(obj)
+		},
+		UpdateFunc: func(old, new interface{}) {
+			c.syncRolloutFor(new)
+		},
+		DeleteFunc: func(obj interface{}) {
+			if tombstone, ok := obj.(cache.DeletedFinalStateUnknown); ok {
+				obj = tombstone.Obj
+			}
+
+			c.syncRolloutFor(obj)
+		},
+	}
+
+	// only the objects of releases are watched.
+	factory := informers.NewFilteredSharedInformerFactory(c.cs, 0, c.namespace, func(opts *metav1.ListOptions) {
+		opts.LabelSelector = meta.LabelMicroserviceKey
+	})
+
+	deployments := factory.Extensions().V1beta1().Deployments()
+	deployments.Informer().AddEventHandler(handler)
+
+	pods := factory.Core().V1().Pods()
+	pods.Informer().AddEventHandler(handler)
+
+	networkPolicies, npInformer := cache.NewIndexerInformer(
+		cache.NewListWatchFromClient(c.rc, networkpolicy.NetworkPolicyResource.Plural, c.namespace, fields.Everything()),
+		&v1alpha1.NetworkPolicy{},
+		0,
+		handler,
+		cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc},
+	)
+
+	lister := &informerLister{
+		deployments:     deployments.Lister(),
+		pods:            pods.Lister(),
+		networkPolicies: networkPolicies,
+		synced:          []cache.InformerSynced{deployments.Informer().HasSynced, pods.Informer().HasSynced, npInformer.HasSynced},
+	}
+	c.releases = lister
+
+	go factory.Start(stop)
+	go npInformer.Run(stop)
+
+	cache.WaitForCacheSync(stop, lister.synced...)
+}
+
+// syncRolloutFor updates the rollout status of the Microservice of a
+// Deployment, Pod or NetworkPolicy.
+func (c *Controller) syncRolloutFor(obj interface{}) {
+	var namespace, name string
+	switch obj := obj.(type) {
+	case *v1beta1.Deployment:
+		namespace, name = obj.Namespace, obj.Labels[meta.LabelMicroserviceKey]
+	case *corev1.Pod:
+		namespace, name = obj.Namespace, obj.Labels[meta.LabelMicroserviceKey]
+	case *v1alpha1.NetworkPolicy:
+		namespace, name = obj.Namespace, obj.Name
+		if obj.Spec.Microservice != nil {
+			name = obj.Spec.Microservice.Name
+		}
+	}
+
+	// all Microservices are synced once the caches are.
+	if name == "" || !c.releases.HasSynced() {
+		return
+	}
+
+	if err := c.syncRollout(namespace, name); err != nil {
+		log.Printf("Could not update the rollout status of Microservice %s: %s", name, err)
+	}
+}
+
+// syncRollout updates the rollout status of a Microservice, without syncing
+// its releases.
+func (c *Controller) syncRollout(namespace, name string) error {
+	svc := &v1alpha1.Microservice{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       "Microservice",
+			APIVersion: "hlnr.io/v1alpha1",
+		},
+	}
+
+	if err := c.patcher.Get(svc, namespace, name); err != nil {
+		if kerrors.IsNotFound(err) {
+			return nil
+		}
+
+		return err
+	}
+
+	if svc.DeletionTimestamp != nil {
+		return nil
+	}
+
+	status := svc.Status.DeepCopy()
+	if err := c.setRolloutStatus(svc); err != nil {
+		return err
+	}
+
+	if reflect.DeepEqual(status, &svc.Status) {
+		return nil
+	}
+
+	return c.applyMicroservice(svc)
+}
+
+// setRolloutStatus sets the status of the releases of a Microservice from the
+// Deployments and Pods of its VersionedMicroservices and the domains of its
+// NetworkPolicies.
+func (c *Controller) setRolloutStatus(svc *v1alpha1.Microservice) error {
+	if c.releases == nil {
+		return nil
+	}
+
+	nps, err := c.releases.NetworkPolicies(svc.Namespace)
+	if err != nil {
+		return err
+	}
+
+	var ready, replicas, restarts int32
+	for i := range svc.Status.Releases {
+		release := &svc.Status.Releases[i]
+
+		dpl, err := c.releases.Deployment(svc.Namespace, release.FullName(svc.Name))
+		if err != nil && !kerrors.IsNotFound(err) {
+			return err
+		}
+
+		pods, err := c.releases.Pods(svc.Namespace, labels.SelectorFromSet(meta.ReleaseSelector(svc, release)))
+		if err != nil {
+			return err
+		}
+
+		release.Status = releaseStatus(dpl, pods, releaseURLs(svc, nps, release))
+
+		ready += release.Status.ReadyReplicas
+		replicas += release.Status.Replicas
+		restarts += release.Status.Restarts
+	}
+
+	svc.Status.Ready = fmt.Sprintf("%d/%d", ready, replicas)
+	svc.Status.Restarts = restarts
+	return nil
+}
+
+// releaseStatus sums up the rollout and health of the pods of a release. The
+// Deployment is nil when it doesn't exist (yet).
+func releaseStatus(dpl *v1beta1.Deployment, pods []*corev1.Pod, urls []string) *v1alpha1.ReleaseStatus {
+	status := &v1alpha1.ReleaseStatus{
+		Rollout: v1alpha1.RolloutPending,
+		URLs:    urls,
+	}
+
+	if dpl != nil {
+		status.Replicas = 1
+		if dpl.Spec.Replicas != nil {
+			status.Replicas = *dpl.Spec.Replicas
+		}
+
+		status.ReadyReplicas = dpl.Status.ReadyReplicas
+		status.UpdatedReplicas = dpl.Status.UpdatedReplicas
+		status.Rollout, status.LastFailure = rolloutState(dpl, status.Replicas)
+	}
+
+	// failing pods are more specific than a rollout which timed out. Pods
+	// which are failing right now take precedence over earlier crashes.
+	var current, terminated string
+	var terminatedAt time.Time
+	for _, pod := range pods {
+		for _, cond := range pod.Status.Conditions {
+			if cond.Type == corev1.PodScheduled && cond.Reason == corev1.PodReasonUnschedulable {
+				current = fmt.Sprintf("pod %s: %s: %s", pod.Name, cond.Reason, cond.Message)
+			}
+		}
+
+		statuses := append([]corev1.ContainerStatus{}, pod.Status.InitContainerStatuses...)
+		for _, cs := range append(statuses, pod.Status.ContainerStatuses...) {
+			status.Restarts += cs.RestartCount
+
+			if msg := waitingFailure(cs); msg != "" {
+				current = msg
+			}
+
+			if t := cs.LastTerminationState.Terminated; t != nil && t.Reason != "Completed" && !t.FinishedAt.Time.Before(terminatedAt) {
+				terminated = fmt.Sprintf("container %s: %s (exit code %d)", cs.Name, t.Reason, t.ExitCode)
+				terminatedAt = t.FinishedAt.Time
+			}
+		}
+	}
+
+	switch {
+	case current != "":
+		status.LastFailure = current
+	case terminated != "":
+		status.LastFailure = terminated
+	}
+
+	return status
+}
+
+// rolloutState returns the progress of the rollout of a Deployment, and why
+// it failed.
+func rolloutState(dpl *v1beta1.Deployment, replicas int32) (v1alpha1.RolloutState, string) {
+	for _, cond := range dpl.Status.Conditions {
+		if cond.Type == v1beta1.DeploymentProgressing && cond.Status == corev1.ConditionFalse && cond.Reason == "ProgressDeadlineExceeded" {
+			return v1alpha1.RolloutFailed, cond.Message
+		}
+	}
+
+	status := dpl.Status
+	if status.ObservedGeneration >= dpl.Generation &&
+		status.UpdatedReplicas >= replicas &&
+		status.AvailableReplicas >= replicas &&
+		status.Replicas <= replicas {
+		return v1alpha1.RolloutComplete, ""
+	}
+
+	return v1alpha1.RolloutProgressing, ""
+}
+
+// waitingFailure describes why a container which won't start without
+// intervention is failing, if it is.
+func waitingFailure(cs corev1.ContainerStatus) string {
+	waiting := cs.State.Waiting
+	if waiting == nil || !waitingFailures[waiting.Reason] {
+		return ""
+	}
+
+	msg := fmt.Sprintf("container %s: %s", cs.Name, waiting.Reason)
+	if t := cs.LastTerminationState.Terminated; t != nil {
+		msg += fmt.Sprintf(", last terminated with %s", t.Reason)
+	}
+
+	return msg
+}
+
+// releaseURLs returns the URLs of a release from the status of the
+// NetworkPolicies of its Microservice.
+func releaseURLs(svc *v1alpha1.Microservice, nps []*v1alpha1.NetworkPolicy, release *v1alpha1.Release) []string {
+	var urls []string
+	for _, np := range nps {
+		name := np.Name
+		if np.Spec.Microservice != nil {
+			name = np.Spec.Microservice.Name
+		}
+
+		if name != svc.Name {
+			continue
+		}
+
+		for _, d := range np.Status.Domains {
+			if n, v, ok := d.Release(); ok && n == release.Name() && v == release.Version() {
+				urls = append(urls, d.URL)
+			}
+		}
+	}
+
+	return urls
+}
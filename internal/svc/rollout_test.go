@@ -0,0 +1,247 @@
+package svc
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/manifoldco/heighliner/apis/v1alpha1"
+	"github.com/manifoldco/heighliner/internal/meta"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/api/extensions/v1beta1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+func TestReleaseStatus(t *testing.T) {
+	replicas := int32(2)
+	deployment := func(status v1beta1.DeploymentStatus) *v1beta1.Deployment {
+		return &v1beta1.Deployment{
+			ObjectMeta: metav1.ObjectMeta{Generation: 3},
+			Spec:       v1beta1.DeploymentSpec{Replicas: &replicas},
+			Status:     status,
+		}
+	}
+
+	t.Run("without a Deployment", func(t *testing.T) {
+		status := releaseStatus(nil, nil, nil)
+		if status.Rollout != v1alpha1.RolloutPending {
+			t.Errorf("Expected the rollout to be pending, got %s", status.Rollout)
+		}
+	})
+
+	t.Run("with a Deployment", func(t *testing.T) {
+		tcs := []struct {
+			scenario string
+			status   v1beta1.DeploymentStatus
+			rollout  v1alpha1.RolloutState
+			failure  string
+		}{
+			{
+				scenario: "which is rolling out",
+				status:   v1beta1.DeploymentStatus{ObservedGeneration: 3, Replicas: 3, UpdatedReplicas: 1, ReadyReplicas: 2, AvailableReplicas: 2},
+				rollout:  v1alpha1.RolloutProgressing,
+			},
+			{
+				scenario: "which isn't observed yet",
+				status:   v1beta1.DeploymentStatus{ObservedGeneration: 2, Replicas: 2, UpdatedReplicas: 2, ReadyReplicas: 2, AvailableReplicas: 2},
+				rollout:  v1alpha1.RolloutProgressing,
+			},
+			{
+				scenario: "which is rolled out",
+				status:   v1beta1.DeploymentStatus{ObservedGeneration: 3, Replicas: 2, UpdatedReplicas: 2, ReadyReplicas: 2, AvailableReplicas: 2},
+				rollout:  v1alpha1.RolloutComplete,
+			},
+			{
+				scenario: "which exceeded its progress deadline",
+				status: v1beta1.DeploymentStatus{
+					ObservedGeneration: 3,
+					Conditions: []v1beta1.DeploymentCondition{
+						{
+							Type:    v1beta1.DeploymentProgressing,
+							Status:  corev1.ConditionFalse,
+							Reason:  "ProgressDeadlineExceeded",
+							Message: `ReplicaSet "hello-world-5d8f" has timed out progressing.`,
+						},
+					},
+				},
+				rollout: v1alpha1.RolloutFailed,
+				failure: `ReplicaSet "hello-world-5d8f" has timed out progressing.`,
+			},
+		}
+
+		for _, tc := range tcs {
+			t.Run(tc.scenario, func(t *testing.T) {
+				status := releaseStatus(deployment(tc.status), nil, nil)
+
+				if status.Rollout != tc.rollout {
+					t.Errorf("Expected rollout %s, got %s", tc.rollout, status.Rollout)
+				}
+
+				if status.LastFailure != tc.failure {
+					t.Errorf("Expected failure %q, got %q", tc.failure, status.LastFailure)
+				}
+
+				if status.Replicas != 2 || status.ReadyReplicas != tc.status.ReadyReplicas {
+					t.Errorf("Expected %d/2 ready replicas, got %d/%d", tc.status.ReadyReplicas, status.ReadyReplicas, status.Replicas)
+				}
+			})
+		}
+	})
+
+	t.Run("with failing pods", func(t *testing.T) {
+		crashed := func(reason string, at time.Time) corev1.ContainerState {
+			return corev1.ContainerState{
+				Terminated: &corev1.ContainerStateTerminated{Reason: reason, ExitCode: 137, FinishedAt: metav1.NewTime(at)},
+			}
+		}
+
+		now := time.Now()
+		pods := []*corev1.Pod{
+			{
+				Status: corev1.PodStatus{
+					ContainerStatuses: []corev1.ContainerStatus{
+						{Name: "hello-world", RestartCount: 2, LastTerminationState: crashed("Error", now.Add(-time.Hour))},
+						{Name: "proxy", RestartCount: 1, LastTerminationState: crashed("OOMKilled", now)},
+					},
+				},
+			},
+			{
+				Status: corev1.PodStatus{
+					InitContainerStatuses: []corev1.ContainerStatus{
+						{Name: "migrate", RestartCount: 1, LastTerminationState: crashed("Completed", now)},
+					},
+				},
+			},
+		}
+
+		status := releaseStatus(nil, pods, nil)
+		if status.Restarts != 4 {
+			t.Errorf("Expected 4 restarts, got %d", status.Restarts)
+		}
+
+		if expected := "container proxy: OOMKilled (exit code 137)"; status.LastFailure != expected {
+			t.Errorf("Expected the last crash to be reported, got %q", status.LastFailure)
+		}
+
+		pods[1].Status.ContainerStatuses = []corev1.ContainerStatus{
+			{
+				Name:                 "hello-world",
+				State:                corev1.ContainerState{Waiting: &corev1.ContainerStateWaiting{Reason: "CrashLoopBackOff"}},
+				LastTerminationState: crashed("Error", now.Add(-time.Hour)),
+			},
+		}
+
+		status = releaseStatus(nil, pods, nil)
+		if expected := "container hello-world: CrashLoopBackOff, last terminated with Error"; status.LastFailure != expected {
+			t.Errorf("Expected the crash loop to be reported, got %q", status.LastFailure)
+		}
+	})
+}
+
+func TestController_SetRolloutStatus(t *testing.T) {
+	release := v1alpha1.Release{
+		Level:  v1alpha1.SemVerLevelRelease,
+		SemVer: &v1alpha1.SemVerRelease{Name: "hello-world", Version: "1.2.3"},
+	}
+	other := v1alpha1.Release{
+		Level:  v1alpha1.SemVerLevelRelease,
+		SemVer: &v1alpha1.SemVerRelease{Name: "hello-world", Version: "1.2.4"},
+	}
+
+	svc := &v1alpha1.Microservice{
+		ObjectMeta: metav1.ObjectMeta{Name: "hello-world", Namespace: "testing"},
+		Status:     v1alpha1.MicroserviceStatus{Releases: []v1alpha1.Release{release, other}},
+	}
+
+	replicas := int32(2)
+	lister := &fakeReleaseLister{
+		deployments: map[string]*v1beta1.Deployment{
+			release.FullName(svc.Name): {
+				Spec:   v1beta1.DeploymentSpec{Replicas: &replicas},
+				Status: v1beta1.DeploymentStatus{Replicas: 2, UpdatedReplicas: 2, ReadyReplicas: 1, AvailableReplicas: 1},
+			},
+		},
+		podLabels: meta.ReleaseSelector(svc, &release),
+		pods: []*corev1.Pod{
+			{Status: corev1.PodStatus{ContainerStatuses: []corev1.ContainerStatus{{Name: "hello-world", RestartCount: 3}}}},
+		},
+		networkPolicies: []*v1alpha1.NetworkPolicy{
+			{
+				ObjectMeta: metav1.ObjectMeta{Name: "hello-world"},
+				Status: v1alpha1.NetworkPolicyStatus{
+					Domains: []v1alpha1.Domain{
+						v1alpha1.NewDomain("https://hello-world.hlnr.io", &release),
+						v1alpha1.NewDomain("https://next.hello-world.hlnr.io", &other),
+					},
+				},
+			},
+			{
+				ObjectMeta: metav1.ObjectMeta{Name: "other"},
+				Status: v1alpha1.NetworkPolicyStatus{
+					Domains: []v1alpha1.Domain{v1alpha1.NewDomain("https://other.hlnr.io", &release)},
+				},
+			},
+		},
+	}
+
+	if err := (&Controller{releases: lister}).setRolloutStatus(svc); err != nil {
+		t.Fatalf("Expected no error, got %s", err)
+	}
+
+	status := svc.Status.Releases[0].Status
+	if status.Rollout != v1alpha1.RolloutProgressing || status.ReadyReplicas != 1 {
+		t.Errorf("Expected the release to be rolling out, got %+v", status)
+	}
+
+	if !reflect.DeepEqual(status.URLs, []string{"https://hello-world.hlnr.io"}) {
+		t.Errorf("Expected the URLs of the release, got %v", status.URLs)
+	}
+
+	if pending := svc.Status.Releases[1].Status; pending.Rollout != v1alpha1.RolloutPending {
+		t.Errorf("Expected the release without a Deployment to be pending, got %+v", pending)
+	}
+
+	if svc.Status.Ready != "1/2" {
+		t.Errorf("Expected 1/2 ready pods, got %s", svc.Status.Ready)
+	}
+
+	if svc.Status.Restarts != 3 {
+		t.Errorf("Expected 3 restarts, got %d", svc.Status.Restarts)
+	}
+}
+
+type fakeReleaseLister struct {
+	deployments     map[string]*v1beta1.Deployment
+	podLabels       labels.Set
+	pods            []*corev1.Pod
+	networkPolicies []*v1alpha1.NetworkPolicy
+}
+
+func (l *fakeReleaseLister) Deployment(namespace, name string) (*v1beta1.Deployment, error) {
+	dpl, ok := l.deployments[name]
+	if !ok {
+		return nil, kerrors.NewNotFound(schema.GroupResource{Resource: "deployments"}, name)
+	}
+
+	return dpl, nil
+}
+
+func (l *fakeReleaseLister) Pods(namespace string, selector labels.Selector) ([]*corev1.Pod, error) {
+	if selector.Matches(l.podLabels) {
+		return l.pods, nil
+	}
+
+	return nil, nil
+}
+
+func (l *fakeReleaseLister) NetworkPolicies(namespace string) ([]*v1alpha1.NetworkPolicy, error) {
+	return l.networkPolicies, nil
+}
+
+func (l *fakeReleaseLister) HasSynced() bool {
+	return true
+}
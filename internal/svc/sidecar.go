@@ -0,0 +1,153 @@
+package svc
+
+import (
+	"fmt"
+
+	"github.com/manifoldco/heighliner/apis/v1alpha1"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+// getSidecars builds the sidecars or init containers of a Microservice,
+// together with the volumes of their ConfigPolicies.
+func (c *Controller) getSidecars(crd *v1alpha1.Microservice, sidecars []v1alpha1.Container, init bool) ([]corev1.Container, []corev1.Volume, error) {
+	var containers []corev1.Container
+	var volumes []corev1.Volume
+	for _, sidecar := range sidecars {
+		container := corev1.Container{
+			Name:            sidecar.Name,
+			Image:           sidecar.Image,
+			ImagePullPolicy: sidecar.ImagePullPolicy,
+			Ports:           sidecar.Ports,
+			Resources:       sidecar.Resources,
+		}
+
+		if sidecar.ImagePolicy != nil {
+			ip, err := c.getImagePolicyByName(crd.Namespace, sidecar.ImagePolicy.Name)
+			if err != nil {
+				return nil, nil, err
+			}
+
+			release, ok := latestRelease(ip.Status.Releases)
+			if !ok {
+				return nil, nil, fmt.Errorf("ImagePolicy %s of container %s has no releases", ip.Name, sidecar.Name)
+			}
+
+			container.Image = release.Image
+			if container.ImagePullPolicy == "" && ip.Spec.ImagePullPolicy != nil {
+				container.ImagePullPolicy = *ip.Spec.ImagePullPolicy
+			}
+		}
+
+		if container.Image == "" {
+			return nil, nil, fmt.Errorf("container %s has no image", sidecar.Name)
+		}
+
+		if container.ImagePullPolicy == "" {
+			container.ImagePullPolicy = corev1.PullIfNotPresent
+		}
+
+		if sidecar.ConfigPolicy != nil {
+			configPolicy, err := c.getConfigPolicy(crd.Namespace, sidecar.ConfigPolicy.Name)
+			if err != nil {
+				return nil, nil, err
+			}
+
+			applyConfig(&container, &configPolicy.Spec)
+			volumes = append(volumes, configPolicy.Spec.Volumes...)
+
+			// make sure the pods get re-deployed when the configuration of
+			// the container changes, like for the main container.
+			if crd.Annotations == nil {
+				crd.Annotations = map[string]string{}
+			}
+
+			key := "hlnr-config-policy/last-updated-time." + sidecar.Name
+			crd.Annotations[key] = configPolicy.Status.LastUpdatedTime.String()
+		}
+
+		if sidecar.HealthPolicy != nil && !init {
+			healthPolicySpec, err := c.getHealthPolicySpecByRef(crd.Namespace, *sidecar.HealthPolicy)
+			if err != nil {
+				return nil, nil, err
+			}
+
+			if healthPolicySpec != nil {
+				container.ReadinessProbe = healthPolicySpec.ReadinessProbe
+				container.LivenessProbe = healthPolicySpec.LivenessProbe
+			}
+		}
+
+		containers = append(containers, container)
+	}
+
+	return containers, volumes, nil
+}
+
+// applyConfig configures a container through a ConfigPolicy. The volumes of
+// the ConfigPolicy are added to the pod separately.
+func applyConfig(container *corev1.Container, config *v1alpha1.ConfigPolicySpec) {
+	container.VolumeMounts = config.VolumeMounts
+	container.EnvFrom = config.EnvFrom
+	container.Env = config.Env
+	container.Args = config.Args
+	container.Command = config.Command
+}
+
+// latestRelease returns the latest release of an ImagePolicy, ignoring
+// previews and release candidates when there are releases.
+func latestRelease(releases []v1alpha1.Release) (v1alpha1.Release, bool) {
+	var latest v1alpha1.Release
+	var found bool
+	for _, release := range releases {
+		if release.Expired != nil {
+			continue
+		}
+
+		switch {
+		case !found:
+		case latest.Level != v1alpha1.SemVerLevelRelease && release.Level == v1alpha1.SemVerLevelRelease:
+		case latest.Level == release.Level && latest.Before(release):
+		default:
+			continue
+		}
+
+		latest, found = release, true
+	}
+
+	return latest, found
+}
+
+// validateContainerNames makes sure all containers of a pod have a unique
+// name.
+func validateContainerNames(containers, initContainers []corev1.Container) error {
+	names := map[string]bool{}
+	for _, container := range append(append([]corev1.Container{}, containers...), initContainers...) {
+		if names[container.Name] {
+			return fmt.Errorf("there are multiple containers named %s", container.Name)
+		}
+
+		names[container.Name] = true
+	}
+
+	return nil
+}
+
+// mergeVolumes combines volumes, skipping volumes with a name which is
+// already in use. This allows containers to share ConfigPolicies.
+func mergeVolumes(sets ...[]corev1.Volume) []corev1.Volume {
+	var volumes []corev1.Volume
+	names := map[string]bool{}
+	for _, set := range sets {
+		for _, volume := range set {
+			if names[volume.Name] {
+				continue
+			}
+
+			names[volume.Name] = true
+			volumes = append(volumes, volume)
+		}
+	}
+
+	return volumes
+}
@@ -0,0 +1,160 @@
+package svc
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/manifoldco/heighliner/apis/v1alpha1"
+	"github.com/manifoldco/heighliner/internal/tester"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestGetSidecars(t *testing.T) {
+	cl := new(tester.PatchClient)
+	ctrl := &Controller{patcher: cl}
+
+	pullAlways := corev1.PullAlways
+	cl.GetFunc = func(obj interface{}, namespace, name string) error {
+		switch obj := obj.(type) {
+		case *v1alpha1.ImagePolicy:
+			obj.Name = name
+			obj.Spec.ImagePullPolicy = &pullAlways
+			obj.Status.Releases = []v1alpha1.Release{
+				{
+					Image:  "manifoldco/log-shipper:1.0.0",
+					Level:  v1alpha1.SemVerLevelRelease,
+					SemVer: &v1alpha1.SemVerRelease{Name: "log-shipper", Version: "1.0.0"},
+				},
+			}
+			return nil
+		case *v1alpha1.ConfigPolicy:
+			obj.Spec = v1alpha1.ConfigPolicySpec{
+				Args:    []string{"-instances=project:region:db"},
+				Volumes: []corev1.Volume{{Name: "credentials"}},
+			}
+			return nil
+		case *v1alpha1.HealthPolicy:
+			obj.Spec.ReadinessProbe = &corev1.Probe{InitialDelaySeconds: 5}
+			return nil
+		}
+
+		return errors.New("Object not supported")
+	}
+
+	crd := &v1alpha1.Microservice{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-deploy", Namespace: "testing"},
+	}
+
+	t.Run("with policies", func(t *testing.T) {
+		sidecars := []v1alpha1.Container{
+			{
+				Name:         "cloudsql-proxy",
+				Image:        "gcr.io/cloudsql-docker/gce-proxy:1.11",
+				ConfigPolicy: &corev1.LocalObjectReference{Name: "cloudsql"},
+				HealthPolicy: &corev1.ObjectReference{Name: "cloudsql"},
+			},
+			{
+				Name:        "log-shipper",
+				ImagePolicy: &corev1.LocalObjectReference{Name: "log-shipper"},
+			},
+		}
+
+		containers, volumes, err := ctrl.getSidecars(crd, sidecars, false)
+		if err != nil {
+			t.Fatalf("Expected no error, got %s", err)
+		}
+
+		expected := []corev1.Container{
+			{
+				Name:            "cloudsql-proxy",
+				Image:           "gcr.io/cloudsql-docker/gce-proxy:1.11",
+				ImagePullPolicy: corev1.PullIfNotPresent,
+				Args:            []string{"-instances=project:region:db"},
+				ReadinessProbe:  &corev1.Probe{InitialDelaySeconds: 5},
+			},
+			{
+				Name:            "log-shipper",
+				Image:           "manifoldco/log-shipper:1.0.0",
+				ImagePullPolicy: corev1.PullAlways,
+			},
+		}
+		if !reflect.DeepEqual(containers, expected) {
+			t.Errorf("Expected containers %+v, got %+v", expected, containers)
+		}
+
+		if len(volumes) != 1 || volumes[0].Name != "credentials" {
+			t.Errorf("Expected the volumes of the ConfigPolicy, got %+v", volumes)
+		}
+
+		if _, ok := crd.Annotations["hlnr-config-policy/last-updated-time.cloudsql-proxy"]; !ok {
+			t.Errorf("Expected the last updated time of the ConfigPolicy to be annotated")
+		}
+	})
+
+	t.Run("init containers don't get probes", func(t *testing.T) {
+		sidecars := []v1alpha1.Container{
+			{Name: "migrate", Image: "migrate", HealthPolicy: &corev1.ObjectReference{Name: "migrate"}},
+		}
+
+		containers, _, err := ctrl.getSidecars(crd, sidecars, true)
+		if err != nil {
+			t.Fatalf("Expected no error, got %s", err)
+		}
+
+		if containers[0].ReadinessProbe != nil {
+			t.Errorf("Expected no readiness probe, got %+v", containers[0].ReadinessProbe)
+		}
+	})
+
+	t.Run("without an image", func(t *testing.T) {
+		if _, _, err := ctrl.getSidecars(crd, []v1alpha1.Container{{Name: "proxy"}}, false); err == nil {
+			t.Errorf("Expected an error for a container without an image")
+		}
+	})
+}
+
+func TestLatestRelease(t *testing.T) {
+	now := time.Now()
+	release := func(image string, level v1alpha1.SemVerLevel, age time.Duration) v1alpha1.Release {
+		return v1alpha1.Release{
+			Image:       image,
+			Level:       level,
+			ReleaseTime: metav1.NewTime(now.Add(-age)),
+			SemVer:      &v1alpha1.SemVerRelease{Name: image, Version: image},
+		}
+	}
+
+	releases := []v1alpha1.Release{
+		release("old", v1alpha1.SemVerLevelRelease, 2*time.Hour),
+		release("new", v1alpha1.SemVerLevelRelease, time.Hour),
+		release("preview", v1alpha1.SemVerLevelPreview, time.Minute),
+	}
+
+	if latest, ok := latestRelease(releases); !ok || latest.Image != "new" {
+		t.Errorf("Expected the latest release to be 'new', got %q", latest.Image)
+	}
+
+	if latest, ok := latestRelease(releases[2:]); !ok || latest.Image != "preview" {
+		t.Errorf("Expected the preview without releases, got %q", latest.Image)
+	}
+
+	if _, ok := latestRelease(nil); ok {
+		t.Errorf("Expected no release without releases")
+	}
+}
+
+func TestValidateContainerNames(t *testing.T) {
+	containers := []corev1.Container{{Name: "test-deploy"}, {Name: "proxy"}}
+
+	if err := validateContainerNames(containers, []corev1.Container{{Name: "migrate"}}); err != nil {
+		t.Errorf("Expected no error, got %s", err)
+	}
+
+	if err := validateContainerNames(containers, []corev1.Container{{Name: "proxy"}}); err == nil {
+		t.Errorf("Expected an error for duplicate names")
+	}
+}
@@ -3,6 +3,7 @@ package svc
 
 import (
 	"github.com/manifoldco/heighliner/apis/v1alpha1"
+	"github.com/manifoldco/heighliner/internal/k8sutils"
 
 	"github.com/jelmersnoeck/kubekit"
 	"k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1beta1"
@@ -29,6 +30,40 @@ var (
 		Validation: v1alpha1.MicroserviceValidationSchema,
 	}
 
+	// PrinterColumns sum up the rollout of a Microservice in `kubectl get`.
+	PrinterColumns = []k8sutils.PrinterColumn{
+		{
+			Name:        "Ready",
+			Type:        "string",
+			JSONPath:    ".status.ready",
+			Description: "The ready pods out of the desired pods of all releases.",
+		},
+		{
+			Name:        "Status",
+			Type:        "string",
+			JSONPath:    `.status.conditions[?(@.type=="Ready")].reason`,
+			Description: "Why the Microservice is or isn't ready.",
+		},
+		{
+			Name:        "Restarts",
+			Type:        "integer",
+			JSONPath:    ".status.restarts",
+			Description: "The container restarts of all releases.",
+		},
+		{
+			Name:     "Age",
+			Type:     "date",
+			JSONPath: ".metadata.creationTimestamp",
+		},
+		{
+			Name:        "Message",
+			Type:        "string",
+			JSONPath:    `.status.conditions[?(@.type=="Ready")].message`,
+			Description: "Details on why the Microservice isn't ready.",
+			Priority:    1,
+		},
+	}
+
 	// AvailabilityPolicyResource describes the CRD configuration for the
 	// AvailabilityPolicy CRD.
 	AvailabilityPolicyResource = kubekit.CustomResource{
@@ -60,6 +95,19 @@ var (
 		Validation: v1alpha1.HealthPolicyValidationSchema,
 	}
 
+	// ResourcePolicyResource describes the CRD configuration for the
+	// ResourcePolicy CRD.
+	ResourcePolicyResource = kubekit.CustomResource{
+		Name:       "resourcepolicy",
+		Plural:     "resourcepolicies",
+		Group:      v1alpha1.GroupName,
+		Version:    v1alpha1.Version,
+		Scope:      v1beta1.NamespaceScoped,
+		Aliases:    []string{"rp"},
+		Object:     &v1alpha1.ResourcePolicy{},
+		Validation: v1alpha1.ResourcePolicyValidationSchema,
+	}
+
 	// SecurityPolicyResource describes the CRD configuration for the
 	// SecurityPolicy CRD.
 	SecurityPolicyResource = kubekit.CustomResource{
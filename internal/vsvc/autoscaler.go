@@ -0,0 +1,144 @@
+package vsvc
+
+import (
+	"errors"
+
+	"github.com/manifoldco/heighliner/apis/v1alpha1"
+	"github.com/manifoldco/heighliner/internal/meta"
+
+	"github.com/jelmersnoeck/kubekit"
+	"k8s.io/api/autoscaling/v2beta1"
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+var (
+	// ErrMaxReplicasTooLow is used when the maximum number of replicas of
+	// the autoscaler is lower than the minimum.
+	ErrMaxReplicasTooLow = errors.New("maxReplicas can't be lower than minReplicas")
+
+	// ErrNoAutoscalingTargets is used when autoscaling is configured without
+	// any metric to scale on.
+	ErrNoAutoscalingTargets = errors.New("autoscaling needs a CPU, memory or custom metric target")
+)
+
+// autoscaling returns the AutoscalingPolicy of a VersionedMicroservice, if
+// any.
+func autoscaling(crd *v1alpha1.VersionedMicroservice) *v1alpha1.AutoscalingPolicy {
+	if crd.Spec.Availability == nil {
+		return nil
+	}
+
+	return crd.Spec.Availability.Autoscaling
+}
+
+// minReplicas returns the lower limit of the autoscaler.
+func minReplicas(as *v1alpha1.AutoscalingPolicy) int32 {
+	if as.MinReplicas == nil {
+		return 1
+	}
+
+	return *as.MinReplicas
+}
+
+// getHorizontalPodAutoscaler creates the HorizontalPodAutoscaler for the
+// Deployment of a VersionedMicroservice.
+func getHorizontalPodAutoscaler(crd *v1alpha1.VersionedMicroservice) (runtime.Object, error) {
+	as := autoscaling(crd)
+	if as == nil {
+		return nil, nil
+	}
+
+	min := minReplicas(as)
+	if as.MaxReplicas < min {
+		return nil, ErrMaxReplicasTooLow
+	}
+
+	var metrics []v2beta1.MetricSpec
+	resources := []struct {
+		name   corev1.ResourceName
+		target *int32
+	}{
+		{corev1.ResourceCPU, as.TargetCPUUtilization},
+		{corev1.ResourceMemory, as.TargetMemoryUtilization},
+	}
+	for _, r := range resources {
+		if r.target == nil {
+			continue
+		}
+
+		metrics = append(metrics, v2beta1.MetricSpec{
+			Type: v2beta1.ResourceMetricSourceType,
+			Resource: &v2beta1.ResourceMetricSource{
+				Name:                     r.name,
+				TargetAverageUtilization: r.target,
+			},
+		})
+	}
+
+	for _, m := range as.Metrics {
+		metrics = append(metrics, v2beta1.MetricSpec{
+			Type: v2beta1.PodsMetricSourceType,
+			Pods: &v2beta1.PodsMetricSource{
+				MetricName:         m.Name,
+				TargetAverageValue: m.TargetAverageValue,
+			},
+		})
+	}
+
+	if len(metrics) == 0 {
+		return nil, ErrNoAutoscalingTargets
+	}
+
+	return &v2beta1.HorizontalPodAutoscaler{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       "HorizontalPodAutoscaler",
+			APIVersion: "autoscaling/v2beta1",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        crd.Name,
+			Namespace:   crd.Namespace,
+			Labels:      meta.Labels(crd.Labels, crd),
+			Annotations: meta.Annotations(crd.Annotations, v1alpha1.Version, crd),
+			OwnerReferences: []metav1.OwnerReference{
+				*metav1.NewControllerRef(
+					crd,
+					v1alpha1.SchemeGroupVersion.WithKind(kubekit.TypeName(crd)),
+				),
+			},
+		},
+		Spec: v2beta1.HorizontalPodAutoscalerSpec{
+			ScaleTargetRef: v2beta1.CrossVersionObjectReference{
+				Kind:       "Deployment",
+				Name:       crd.Name,
+				APIVersion: "extensions/v1beta1",
+			},
+			MinReplicas: &min,
+			MaxReplicas: as.MaxReplicas,
+			Metrics:     metrics,
+		},
+	}, nil
+}
+
+// deleteHorizontalPodAutoscaler removes the HorizontalPodAutoscaler of a
+// VersionedMicroservice which isn't autoscaled (anymore).
+func deleteHorizontalPodAutoscaler(cl patchClient, crd *v1alpha1.VersionedMicroservice) error {
+	hpa := &v2beta1.HorizontalPodAutoscaler{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       "HorizontalPodAutoscaler",
+			APIVersion: "autoscaling/v2beta1",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      crd.Name,
+			Namespace: crd.Namespace,
+		},
+	}
+
+	if err := cl.Delete(hpa); err != nil && !kerrors.IsNotFound(err) {
+		return err
+	}
+
+	return nil
+}
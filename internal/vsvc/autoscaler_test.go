@@ -0,0 +1,161 @@
+package vsvc
+
+import (
+	"testing"
+
+	"github.com/manifoldco/heighliner/apis/v1alpha1"
+	"github.com/manifoldco/heighliner/internal/activator"
+	"github.com/manifoldco/heighliner/internal/tester"
+
+	"github.com/stretchr/testify/assert"
+	"k8s.io/api/autoscaling/v2beta1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/api/extensions/v1beta1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+func TestGetHorizontalPodAutoscaler(t *testing.T) {
+	newCRD := func(as *v1alpha1.AutoscalingPolicy) *v1alpha1.VersionedMicroservice {
+		crd := &v1alpha1.VersionedMicroservice{
+			Spec: v1alpha1.VersionedMicroserviceSpec{
+				Availability: &v1alpha1.AvailabilityPolicySpec{
+					Replicas:    func(i int32) *int32 { return &i }(2),
+					Autoscaling: as,
+				},
+			},
+		}
+		crd.Name = "test-app"
+		return crd
+	}
+
+	t.Run("without autoscaling", func(t *testing.T) {
+		obj, err := getHorizontalPodAutoscaler(newCRD(nil))
+		assert.NoError(t, err)
+		assert.Nil(t, obj)
+	})
+
+	t.Run("with targets", func(t *testing.T) {
+		crd := newCRD(&v1alpha1.AutoscalingPolicy{
+			MaxReplicas:          10,
+			TargetCPUUtilization: func(i int32) *int32 { return &i }(80),
+			Metrics: []v1alpha1.AutoscalingMetric{
+				{Name: "requests_per_second", TargetAverageValue: resource.MustParse("100")},
+			},
+		})
+
+		obj, err := getHorizontalPodAutoscaler(crd)
+		assert.NoError(t, err)
+
+		hpa := obj.(*v2beta1.HorizontalPodAutoscaler)
+		assert.Equal(t, "test-app", hpa.Spec.ScaleTargetRef.Name)
+		assert.Equal(t, int32(1), *hpa.Spec.MinReplicas)
+		assert.Equal(t, int32(10), hpa.Spec.MaxReplicas)
+		assert.Len(t, hpa.Spec.Metrics, 2)
+		assert.Equal(t, corev1.ResourceCPU, hpa.Spec.Metrics[0].Resource.Name)
+		assert.Equal(t, "requests_per_second", hpa.Spec.Metrics[1].Pods.MetricName)
+
+		obj, err = getDeployment(crd)
+		assert.NoError(t, err)
+		assert.Equal(t, int32(1), *obj.(*v1beta1.Deployment).Spec.Replicas)
+	})
+
+	t.Run("with invalid bounds", func(t *testing.T) {
+		crd := newCRD(&v1alpha1.AutoscalingPolicy{
+			MinReplicas:          func(i int32) *int32 { return &i }(3),
+			MaxReplicas:          2,
+			TargetCPUUtilization: func(i int32) *int32 { return &i }(80),
+		})
+
+		_, err := getHorizontalPodAutoscaler(crd)
+		assert.Equal(t, ErrMaxReplicasTooLow, err)
+	})
+
+	t.Run("without targets", func(t *testing.T) {
+		_, err := getHorizontalPodAutoscaler(newCRD(&v1alpha1.AutoscalingPolicy{MaxReplicas: 2}))
+		assert.Equal(t, ErrNoAutoscalingTargets, err)
+	})
+}
+
+func TestCurrentReplicas(t *testing.T) {
+	crd := &v1alpha1.VersionedMicroservice{
+		Spec: v1alpha1.VersionedMicroserviceSpec{
+			Availability: &v1alpha1.AvailabilityPolicySpec{
+				Replicas: func(i int32) *int32 { return &i }(2),
+			},
+		},
+	}
+	crd.Name = "test-app"
+	crd.Namespace = "testing"
+
+	autoscaled := crd.DeepCopy()
+	autoscaled.Spec.Availability.Autoscaling = &v1alpha1.AutoscalingPolicy{
+		MinReplicas:          func(i int32) *int32 { return &i }(3),
+		MaxReplicas:          10,
+		TargetCPUUtilization: func(i int32) *int32 { return &i }(80),
+	}
+
+	replicas := func(t *testing.T, cl patchClient, crd *v1alpha1.VersionedMicroservice) int32 {
+		obj, err := currentReplicas(cl, getDeployment)(crd)
+		assert.NoError(t, err)
+		return *obj.(*v1beta1.Deployment).Spec.Replicas
+	}
+
+	scaled := func(replicas int32, annotations map[string]string) *tester.PatchClient {
+		return &tester.PatchClient{
+			GetFunc: func(obj interface{}, namespace, name string) error {
+				dpl := obj.(*v1beta1.Deployment)
+				dpl.Annotations = annotations
+				dpl.Spec.Replicas = &replicas
+				return nil
+			},
+		}
+	}
+
+	t.Run("without autoscaling", func(t *testing.T) {
+		assert.Equal(t, int32(2), replicas(t, scaled(5, nil), crd))
+	})
+
+	t.Run("when scaled down by the activator", func(t *testing.T) {
+		cl := scaled(0, map[string]string{activator.AnnotationReplicas: "2"})
+		assert.Equal(t, int32(0), replicas(t, cl, crd))
+	})
+
+	t.Run("when scaled up by the activator", func(t *testing.T) {
+		cl := scaled(3, map[string]string{activator.AnnotationReplicas: "3"})
+		assert.Equal(t, int32(3), replicas(t, cl, crd))
+	})
+
+	t.Run("when handing over to the autoscaler", func(t *testing.T) {
+		cl := &tester.PatchClient{
+			GetFunc: func(obj interface{}, namespace, name string) error {
+				obj.(*v1beta1.Deployment).Spec.Replicas = func(i int32) *int32 { return &i }(2)
+				return nil
+			},
+		}
+
+		assert.Equal(t, int32(2), replicas(t, cl, autoscaled))
+	})
+
+	t.Run("when scaled by the autoscaler", func(t *testing.T) {
+		cl := &tester.PatchClient{
+			GetFunc: func(obj interface{}, namespace, name string) error {
+				obj.(*v1beta1.Deployment).Spec.Replicas = func(i int32) *int32 { return &i }(7)
+				return nil
+			},
+		}
+
+		assert.Equal(t, int32(7), replicas(t, cl, autoscaled))
+	})
+
+	t.Run("without a Deployment", func(t *testing.T) {
+		cl := &tester.PatchClient{
+			GetFunc: func(obj interface{}, namespace, name string) error {
+				return kerrors.NewNotFound(schema.GroupResource{Resource: "deployments"}, name)
+			},
+		}
+
+		assert.Equal(t, int32(3), replicas(t, cl, autoscaled))
+	})
+}
@@ -5,6 +5,7 @@ import (
 	"log"
 	"os"
 	"os/signal"
+	"reflect"
 	"syscall"
 
 	"github.com/manifoldco/heighliner/apis/v1alpha1"
@@ -13,6 +14,7 @@ import (
 	"github.com/jelmersnoeck/kubekit"
 	"github.com/jelmersnoeck/kubekit/errors"
 	"github.com/jelmersnoeck/kubekit/patcher"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/rest"
@@ -27,7 +29,13 @@ type Controller struct {
 	rc        *rest.RESTClient
 	cs        kubernetes.Interface
 	namespace string
-	patcher   *patcher.Patcher
+	patcher   patchClient
+}
+
+type patchClient interface {
+	Apply(runtime.Object, ...patcher.OptionFunc) ([]byte, error)
+	Get(interface{}, string, string) error
+	Delete(runtime.Object, ...patcher.OptionFunc) error
 }
 
 // NewController returns a new VersionedMicroservice Controller.
@@ -90,15 +98,142 @@ func (c *Controller) run(ctx context.Context) {
 
 func (c *Controller) applyCRD(obj interface{}, opts ...patcher.OptionFunc) error {
 	vsvc := obj.(*v1alpha1.VersionedMicroservice).DeepCopy()
+	current := vsvc.Status.Hooks
 
 	// This is managed by a kubekit controller, lets remove that annotation.
 	delete(vsvc.Annotations, "kubekit-hlnr-microservice/last-applied-configuration")
 
-	if err := updateObject("Deployment", vsvc, c.patcher, getDeployment); err != nil {
+	hooks := vsvc.Spec.Hooks
+	if hooks == nil {
+		hooks = &v1alpha1.Hooks{}
+	}
+
+	// the Deployment is only applied once the pre-deploy hooks succeeded.
+	statuses, err := syncHooks(c.patcher, vsvc, v1alpha1.HookPhasePreDeploy, hooks.PreDeploy)
+	if err != nil {
+		log.Printf("Could not sync pre-deploy hooks for %s: %s", vsvc.Name, err)
+		c.updateStatus(obj, current, degraded("HookError", err))
+		return err
+	}
+
+	finished, err := hooksFinished(statuses)
+	if !finished {
+		cond := progressing("PreDeployHooks", "waiting for the pre-deploy hooks to finish")
+		if err != nil {
+			log.Printf("Not deploying %s: %s", vsvc.Name, err)
+			cond = degraded("HookFailed", err)
+		}
+
+		statuses = append(statuses, pendingHooks(v1alpha1.HookPhasePostDeploy, hooks.PostDeploy)...)
+		if err := c.updateStatus(obj, statuses, cond); err != nil {
+			return err
+		}
+
+		return err
+	}
+
+	if err := applyObjects(c.patcher, vsvc); err != nil {
+		c.updateStatus(obj, current, degraded("SyncError", err))
+		return err
+	}
+
+	// post-deploy hooks wait for the new pods to be available. Once they're
+	// started, pods becoming unavailable don't affect them anymore.
+	available, err := deploymentAvailable(c.patcher, vsvc)
+	if err != nil {
+		log.Printf("Could not get the Deployment of %s: %s", vsvc.Name, err)
+		return err
+	}
+
+	if len(hooks.PostDeploy) == 0 {
+		return c.updateStatus(obj, statuses, availability(available))
+	}
+
+	if !available && !hooksStarted(vsvc.Status.Hooks, v1alpha1.HookPhasePostDeploy) {
+		statuses = append(statuses, pendingHooks(v1alpha1.HookPhasePostDeploy, hooks.PostDeploy)...)
+		return c.updateStatus(obj, statuses, availability(available))
+	}
+
+	post, err := syncHooks(c.patcher, vsvc, v1alpha1.HookPhasePostDeploy, hooks.PostDeploy)
+	if err != nil {
+		log.Printf("Could not sync post-deploy hooks for %s: %s", vsvc.Name, err)
+		c.updateStatus(obj, current, degraded("HookError", err))
 		return err
 	}
 
-	if err := updateObject("PodDisruptionBudget", vsvc, c.patcher, getPodDisruptionBudget, patcher.WithDeleteFirst()); err != nil && !errors.IsNoObjectGiven(err) {
+	cond := availability(available)
+	finished, err = hooksFinished(post)
+	switch {
+	case err != nil:
+		log.Printf("Not releasing %s: %s", vsvc.Name, err)
+		cond = degraded("HookFailed", err)
+	case !finished:
+		cond = progressing("PostDeployHooks", "waiting for the post-deploy hooks to finish")
+	}
+
+	return c.updateStatus(obj, append(statuses, post...), cond)
+}
+
+// applyObjects applies the Deployment, PodDisruptionBudget and
+// HorizontalPodAutoscaler of a VersionedMicroservice.
+func applyObjects(cl patchClient, vsvc *v1alpha1.VersionedMicroservice) error {
+	if err := updateObject("Deployment", vsvc, cl, currentReplicas(cl, getDeployment)); err != nil {
+		return err
+	}
+
+	if err := updateObject("PodDisruptionBudget", vsvc, cl, getPodDisruptionBudget, patcher.WithDeleteFirst()); err != nil && !errors.IsNoObjectGiven(err) {
+		return err
+	}
+
+	if autoscaling(vsvc) == nil {
+		if err := deleteHorizontalPodAutoscaler(cl, vsvc); err != nil {
+			log.Printf("Could not delete HorizontalPodAutoscaler for %s: %s", vsvc.Name, err)
+			return err
+		}
+	} else if err := updateObject("HorizontalPodAutoscaler", vsvc, cl, getHorizontalPodAutoscaler); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func availability(available bool) v1alpha1.Condition {
+	if !available {
+		return progressing("RollingOut", "waiting for the pods of the release to be available")
+	}
+
+	return v1alpha1.Condition{Type: v1alpha1.ConditionReady, Reason: "Available"}
+}
+
+func progressing(reason, message string) v1alpha1.Condition {
+	return v1alpha1.Condition{Type: v1alpha1.ConditionProgressing, Reason: reason, Message: message}
+}
+
+func degraded(reason string, err error) v1alpha1.Condition {
+	return v1alpha1.Condition{Type: v1alpha1.ConditionDegraded, Reason: reason, Message: err.Error()}
+}
+
+// updateStatus records the progress of the hooks and the rollout of a
+// VersionedMicroservice. It takes the object as it was received, as building
+// the Deployment alters the spec.
+func (c *Controller) updateStatus(obj interface{}, hooks []v1alpha1.HookStatus, cond v1alpha1.Condition) error {
+	vsvc := obj.(*v1alpha1.VersionedMicroservice).DeepCopy()
+	status := vsvc.Status.DeepCopy()
+
+	vsvc.Status.Hooks = hooks
+	vsvc.Status.ObservedGeneration = vsvc.Generation
+	vsvc.Status.Conditions.Mark(cond.Type, cond.Reason, cond.Message)
+	if reflect.DeepEqual(status, &vsvc.Status) {
+		return nil
+	}
+
+	vsvc.TypeMeta = metav1.TypeMeta{
+		Kind:       "VersionedMicroservice",
+		APIVersion: "hlnr.io/v1alpha1",
+	}
+
+	if _, err := c.patcher.Apply(vsvc); err != nil {
+		log.Printf("Could not update the status of %s: %s", vsvc.Name, err)
 		return err
 	}
 
@@ -107,7 +242,7 @@ func (c *Controller) applyCRD(obj interface{}, opts ...patcher.OptionFunc) error
 
 type objectFunc func(*v1alpha1.VersionedMicroservice) (runtime.Object, error)
 
-func updateObject(name string, vsvc *v1alpha1.VersionedMicroservice, p *patcher.Patcher, f objectFunc, opts ...patcher.OptionFunc) error {
+func updateObject(name string, vsvc *v1alpha1.VersionedMicroservice, p patchClient, f objectFunc, opts ...patcher.OptionFunc) error {
 	obj, err := f(vsvc)
 	if err != nil {
 		log.Printf("Could not configure %s for %s: %s", name, vsvc.Name, err)
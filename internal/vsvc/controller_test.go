@@ -0,0 +1,136 @@
+package vsvc
+
+import (
+	"testing"
+
+	"github.com/jelmersnoeck/kubekit/patcher"
+	"github.com/manifoldco/heighliner/apis/v1alpha1"
+	"github.com/manifoldco/heighliner/internal/activator"
+	"github.com/manifoldco/heighliner/internal/tester"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/api/extensions/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+func TestController_ApplyCRD_Conditions(t *testing.T) {
+	// run applies the VersionedMicroservice with a Deployment which is
+	// available or not, and returns the conditions of the applied status.
+	run := func(t *testing.T, spec v1alpha1.VersionedMicroserviceSpec, available bool) v1alpha1.Conditions {
+		vsvc := &v1alpha1.VersionedMicroservice{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        "hello-world-1mpl3547",
+				Namespace:   "testing",
+				Generation:  2,
+				Labels:      map[string]string{},
+				Annotations: map[string]string{},
+			},
+			Spec: spec,
+		}
+
+		var applied *v1alpha1.VersionedMicroservice
+		cl := &tester.PatchClient{
+			GetFunc: func(obj interface{}, namespace, name string) error {
+				if dpl, ok := obj.(*v1beta1.Deployment); ok && available {
+					dpl.Status = v1beta1.DeploymentStatus{UpdatedReplicas: 1, AvailableReplicas: 1}
+				}
+
+				return nil
+			},
+			ApplyFunc: func(obj runtime.Object, _ ...patcher.OptionFunc) ([]byte, error) {
+				if obj, ok := obj.(*v1alpha1.VersionedMicroservice); ok {
+					applied = obj
+				}
+
+				return []byte("{}"), nil
+			},
+			DeleteFunc: func(runtime.Object, ...patcher.OptionFunc) error {
+				return nil
+			},
+		}
+
+		(&Controller{patcher: cl}).applyCRD(vsvc)
+
+		if applied.Status.ObservedGeneration != 2 {
+			t.Errorf("Expected the observed generation to be 2, got %d", applied.Status.ObservedGeneration)
+		}
+
+		return applied.Status.Conditions
+	}
+
+	containers := []corev1.Container{{Name: "hello-world", Image: "hlnr/hello-world:1.0.0"}}
+
+	t.Run("while the pods are rolled out", func(t *testing.T) {
+		conditions := run(t, v1alpha1.VersionedMicroserviceSpec{Containers: containers}, false)
+
+		if !conditions.IsTrue(v1alpha1.ConditionProgressing) || conditions.IsTrue(v1alpha1.ConditionReady) {
+			t.Errorf("Expected the release to be progressing, got %+v", conditions)
+		}
+	})
+
+	t.Run("once the pods are available", func(t *testing.T) {
+		conditions := run(t, v1alpha1.VersionedMicroserviceSpec{Containers: containers}, true)
+
+		if !conditions.IsTrue(v1alpha1.ConditionReady) {
+			t.Errorf("Expected the release to be ready, got %+v", conditions)
+		}
+	})
+
+	t.Run("with an invalid spec", func(t *testing.T) {
+		spec := v1alpha1.VersionedMicroserviceSpec{
+			Containers: containers,
+			Availability: &v1alpha1.AvailabilityPolicySpec{
+				Autoscaling: &v1alpha1.AutoscalingPolicy{MaxReplicas: 2},
+			},
+		}
+		conditions := run(t, spec, true)
+
+		cond := conditions.Get(v1alpha1.ConditionDegraded)
+		if cond == nil || cond.Status != corev1.ConditionTrue || cond.Message != ErrNoAutoscalingTargets.Error() {
+			t.Errorf("Expected the release to be degraded, got %+v", conditions)
+		}
+	})
+}
+
+func TestApplyObjects_ScaledToZero(t *testing.T) {
+	vsvc := &v1alpha1.VersionedMicroservice{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "hello-world-pr-1mpl3547",
+			Namespace:   "testing",
+			Labels:      map[string]string{},
+			Annotations: map[string]string{},
+		},
+		Spec: v1alpha1.VersionedMicroserviceSpec{
+			Containers: []corev1.Container{{Name: "hello-world", Image: "hlnr/hello-world:1.0.0"}},
+		},
+	}
+
+	var applied *v1beta1.Deployment
+	cl := &tester.PatchClient{
+		GetFunc: func(obj interface{}, namespace, name string) error {
+			dpl := obj.(*v1beta1.Deployment)
+			dpl.Annotations = map[string]string{activator.AnnotationReplicas: "1"}
+			dpl.Spec.Replicas = func(i int32) *int32 { return &i }(0)
+			return nil
+		},
+		ApplyFunc: func(obj runtime.Object, _ ...patcher.OptionFunc) ([]byte, error) {
+			if dpl, ok := obj.(*v1beta1.Deployment); ok {
+				applied = dpl
+			}
+
+			return []byte("{}"), nil
+		},
+		DeleteFunc: func(runtime.Object, ...patcher.OptionFunc) error {
+			return nil
+		},
+	}
+
+	if err := applyObjects(cl, vsvc); err != nil {
+		t.Fatalf("Expected no error, got %s", err)
+	}
+
+	if applied == nil || applied.Spec.Replicas == nil || *applied.Spec.Replicas != 0 {
+		t.Errorf("Expected the Deployment to stay scaled down, got %+v", applied)
+	}
+}
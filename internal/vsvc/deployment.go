@@ -2,12 +2,14 @@ package vsvc
 
 import (
 	"github.com/manifoldco/heighliner/apis/v1alpha1"
+	"github.com/manifoldco/heighliner/internal/activator"
 	"github.com/manifoldco/heighliner/internal/k8sutils"
 	"github.com/manifoldco/heighliner/internal/meta"
 
 	"github.com/jelmersnoeck/kubekit"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/api/extensions/v1beta1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 )
@@ -22,6 +24,12 @@ func getDeployment(crd *v1alpha1.VersionedMicroservice) (runtime.Object, error)
 	labels := meta.Labels(crd.Labels, crd)
 	annotations := meta.Annotations(crd.Annotations, v1alpha1.Version, crd)
 
+	selector := make(map[string]string)
+	for k, v := range labels {
+		selector[k] = v
+	}
+	delete(selector, meta.LabelLevelKey)
+
 	affinity := availability.Affinity
 	if affinity == nil {
 		affinity = DefaultAffinity("hlnr.io/service", crd.Name)
@@ -34,6 +42,13 @@ func getDeployment(crd *v1alpha1.VersionedMicroservice) (runtime.Object, error)
 
 	populateContainers(crd)
 
+	// the number of replicas of autoscaled releases is managed by their
+	// HorizontalPodAutoscaler, new Deployments start at its lower limit.
+	replicas := availability.Replicas
+	if as := autoscaling(crd); as != nil {
+		replicas = k8sutils.PtrInt32(minReplicas(as))
+	}
+
 	dpl := &v1beta1.Deployment{
 		TypeMeta: metav1.TypeMeta{
 			Kind:       "Deployment",
@@ -52,10 +67,10 @@ func getDeployment(crd *v1alpha1.VersionedMicroservice) (runtime.Object, error)
 			},
 		},
 		Spec: v1beta1.DeploymentSpec{
-			Replicas: availability.Replicas,
+			Replicas: replicas,
 			Strategy: availability.DeploymentStrategy,
 			Selector: &metav1.LabelSelector{
-				MatchLabels: labels,
+				MatchLabels: selector,
 			},
 			Template: corev1.PodTemplateSpec{
 				ObjectMeta: metav1.ObjectMeta{
@@ -71,6 +86,7 @@ func getDeployment(crd *v1alpha1.VersionedMicroservice) (runtime.Object, error)
 					SecurityContext:              security.SecurityContext,
 					Affinity:                     affinity,
 					RestartPolicy:                availability.RestartPolicy,
+					InitContainers:               crd.Spec.InitContainers,
 					Containers:                   crd.Spec.Containers,
 					Volumes:                      podVolumes(crd),
 				},
@@ -81,31 +97,88 @@ func getDeployment(crd *v1alpha1.VersionedMicroservice) (runtime.Object, error)
 	return dpl, nil
 }
 
+// currentReplicas keeps the number of replicas of the current Deployment when
+// it's scaled by something else than the VersionedMicroservice, so applying
+// the Deployment doesn't undo the scaling of its HorizontalPodAutoscaler or
+// the activator.
+func currentReplicas(cl patchClient, f objectFunc) objectFunc {
+	return func(crd *v1alpha1.VersionedMicroservice) (runtime.Object, error) {
+		obj, err := f(crd)
+		if err != nil {
+			return obj, err
+		}
+
+		current := &v1beta1.Deployment{
+			TypeMeta: metav1.TypeMeta{
+				Kind:       "Deployment",
+				APIVersion: "extensions/v1beta1",
+			},
+		}
+
+		err = cl.Get(current, crd.Namespace, crd.Name)
+		switch {
+		case kerrors.IsNotFound(err):
+			return obj, nil
+		case err != nil:
+			return nil, err
+		}
+
+		if current.Spec.Replicas != nil && (autoscaling(crd) != nil || scaledByActivator(current)) {
+			obj.(*v1beta1.Deployment).Spec.Replicas = current.Spec.Replicas
+		}
+
+		return obj, nil
+	}
+}
+
+// scaledByActivator returns whether the activator scales the Deployment of a
+// preview release. It marks the Deployments it scaled down to zero replicas.
+func scaledByActivator(dpl *v1beta1.Deployment) bool {
+	_, ok := dpl.Annotations[activator.AnnotationReplicas]
+	return ok
+}
+
+// populateContainers applies the ConfigPolicy to the container of the
+// Microservice, which is the first container. Sidecars are configured by
+// their own ConfigPolicy.
 func populateContainers(crd *v1alpha1.VersionedMicroservice) {
-	if crd.Spec.Config == nil {
+	if crd.Spec.Config == nil || len(crd.Spec.Containers) == 0 {
 		return
 	}
 
-	for i, container := range crd.Spec.Containers {
-		container.VolumeMounts = crd.Spec.Config.VolumeMounts
-		container.EnvFrom = crd.Spec.Config.EnvFrom
-		container.Env = crd.Spec.Config.Env
-		container.Args = crd.Spec.Config.Args
-		container.Command = crd.Spec.Config.Command
+	container := crd.Spec.Containers[0]
+	container.VolumeMounts = crd.Spec.Config.VolumeMounts
+	container.EnvFrom = crd.Spec.Config.EnvFrom
+	container.Env = crd.Spec.Config.Env
+	container.Args = crd.Spec.Config.Args
+	container.Command = crd.Spec.Config.Command
 
-		// reassign the container in the CRD
-		crd.Spec.Containers[i] = container
-	}
+	// reassign the container in the CRD
+	crd.Spec.Containers[0] = container
 
 	if crd.Spec.Security == nil {
 		crd.Spec.Security = &v1alpha1.SecurityPolicySpec{}
 	}
 }
 
+// podVolumes returns the volumes of the ConfigPolicy of the Microservice and
+// those of its sidecars. Volumes of sidecars with a name which is already in
+// use are skipped.
 func podVolumes(crd *v1alpha1.VersionedMicroservice) []corev1.Volume {
-	if crd.Spec.Config == nil {
-		return nil
+	var volumes []corev1.Volume
+	names := map[string]bool{}
+	if crd.Spec.Config != nil {
+		for _, volume := range crd.Spec.Config.Volumes {
+			names[volume.Name] = true
+			volumes = append(volumes, volume)
+		}
+	}
+
+	for _, volume := range crd.Spec.Volumes {
+		if !names[volume.Name] {
+			volumes = append(volumes, volume)
+		}
 	}
 
-	return crd.Spec.Config.Volumes
+	return volumes
 }
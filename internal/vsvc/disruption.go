@@ -55,6 +55,15 @@ func getPodDisruptionBudget(crd *v1alpha1.VersionedMicroservice) (runtime.Object
 		}
 	}
 
+	// pods of autoscaled releases can only be evicted when fewer than the
+	// minimum number of replicas have to stay available.
+	if as := autoscaling(crd); as != nil {
+		min := minReplicas(as)
+		if ma := budget.Spec.MinAvailable; ma != nil && ma.Type == intstr.Int && ma.IntVal >= min {
+			budget.Spec.MinAvailable = ptrIntOrStringFromInt(int(min) - 1)
+		}
+	}
+
 	return budget, nil
 }
 
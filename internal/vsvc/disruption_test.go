@@ -67,4 +67,21 @@ func TestGetPodDisruptionBudget(t *testing.T) {
 		_, err := getPodDisruptionBudget(crd)
 		assert.Equal(t, ErrMinMaxAvailabilitySet, err)
 	})
+
+	t.Run("with autoscaling configured", func(t *testing.T) {
+		crd := &v1alpha1.VersionedMicroservice{
+			Spec: v1alpha1.VersionedMicroserviceSpec{
+				Availability: &v1alpha1.AvailabilityPolicySpec{
+					MinAvailable: ptrIntOrStringFromInt(3),
+					Autoscaling:  &v1alpha1.AutoscalingPolicy{MaxReplicas: 5},
+				},
+			},
+		}
+		crd.Name = "autoscaled"
+
+		resultFunc(t, crd, ptrIntOrStringFromInt(0), nil)
+
+		crd.Spec.Availability.MinAvailable = ptrIntOrString(intstr.FromString("50%"))
+		resultFunc(t, crd, ptrIntOrString(intstr.FromString("50%")), nil)
+	})
 }
@@ -0,0 +1,267 @@
+package vsvc
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/manifoldco/heighliner/apis/v1alpha1"
+	"github.com/manifoldco/heighliner/internal/k8sutils"
+	"github.com/manifoldco/heighliner/internal/meta"
+
+	"github.com/jelmersnoeck/kubekit"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/api/extensions/v1beta1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// ErrNoContainers is used when a hook is built for a VersionedMicroservice
+// without containers to take the image from.
+var ErrNoContainers = errors.New("there's no container to run the hook with")
+
+// hookFailedError is used when a hook failed, which blocks the rollout of the
+// release.
+type hookFailedError struct {
+	status v1alpha1.HookStatus
+}
+
+func (e *hookFailedError) Error() string {
+	return fmt.Sprintf("%s hook %s failed: %s", e.status.Phase, e.status.Name, e.status.Message)
+}
+
+// syncHooks starts the Jobs for the hooks of a phase and reports their
+// progress. Jobs are only started once, so hooks run once per release.
+func syncHooks(cl patchClient, vsvc *v1alpha1.VersionedMicroservice, phase v1alpha1.HookPhase, hooks []v1alpha1.Hook) ([]v1alpha1.HookStatus, error) {
+	var statuses []v1alpha1.HookStatus
+	for _, hook := range hooks {
+		status := v1alpha1.HookStatus{
+			Name:  hook.Name,
+			Phase: phase,
+			State: v1alpha1.HookStateRunning,
+			Job:   hookJobName(vsvc, phase, hook),
+		}
+
+		job := &batchv1.Job{
+			TypeMeta: metav1.TypeMeta{
+				Kind:       "Job",
+				APIVersion: "batch/v1",
+			},
+		}
+
+		err := cl.Get(job, vsvc.Namespace, status.Job)
+		switch {
+		case kerrors.IsNotFound(err):
+			job, err := buildHookJob(vsvc, phase, hook)
+			if err != nil {
+				return statuses, err
+			}
+
+			if _, err := cl.Apply(job); err != nil {
+				return statuses, err
+			}
+		case err != nil:
+			return statuses, err
+		default:
+			status.State, status.Message = jobState(job)
+		}
+
+		statuses = append(statuses, status)
+	}
+
+	return statuses, nil
+}
+
+// pendingHooks returns the statuses of hooks which wait for the previous phase
+// to finish.
+func pendingHooks(phase v1alpha1.HookPhase, hooks []v1alpha1.Hook) []v1alpha1.HookStatus {
+	var statuses []v1alpha1.HookStatus
+	for _, hook := range hooks {
+		statuses = append(statuses, v1alpha1.HookStatus{
+			Name:  hook.Name,
+			Phase: phase,
+			State: v1alpha1.HookStatePending,
+		})
+	}
+
+	return statuses
+}
+
+// hooksStarted reports whether the Jobs of the hooks of a phase were started.
+func hooksStarted(statuses []v1alpha1.HookStatus, phase v1alpha1.HookPhase) bool {
+	for _, status := range statuses {
+		if status.Phase == phase && status.State != v1alpha1.HookStatePending {
+			return true
+		}
+	}
+
+	return false
+}
+
+// hooksFinished reports whether all hooks succeeded, and returns the error of
+// the first hook which failed.
+func hooksFinished(statuses []v1alpha1.HookStatus) (bool, error) {
+	finished := true
+	for _, status := range statuses {
+		switch status.State {
+		case v1alpha1.HookStateFailed:
+			return false, &hookFailedError{status: status}
+		case v1alpha1.HookStateSucceeded:
+		default:
+			finished = false
+		}
+	}
+
+	return finished, nil
+}
+
+func jobState(job *batchv1.Job) (v1alpha1.HookState, string) {
+	if job.Status.Succeeded > 0 {
+		return v1alpha1.HookStateSucceeded, ""
+	}
+
+	for _, cond := range job.Status.Conditions {
+		if cond.Type == batchv1.JobFailed && cond.Status == corev1.ConditionTrue {
+			return v1alpha1.HookStateFailed, cond.Message
+		}
+	}
+
+	return v1alpha1.HookStateRunning, ""
+}
+
+// hookJobName returns the name of the Job for a hook. Names which are too long
+// are truncated and suffixed with a hash of the full name, so they stay
+// unique.
+func hookJobName(vsvc *v1alpha1.VersionedMicroservice, phase v1alpha1.HookPhase, hook v1alpha1.Hook) string {
+	prefix := "pre"
+	if phase == v1alpha1.HookPhasePostDeploy {
+		prefix = "post"
+	}
+
+	name := fmt.Sprintf("%s-%s-%s", vsvc.Name, prefix, hook.Name)
+	return k8sutils.TruncateName(name, k8sutils.MaxJobNameLength)
+}
+
+// buildHookJob builds the Job for a hook. It runs the image and configuration
+// of the container of the Microservice, with the command and arguments of the
+// hook.
+func buildHookJob(vsvc *v1alpha1.VersionedMicroservice, phase v1alpha1.HookPhase, hook v1alpha1.Hook) (*batchv1.Job, error) {
+	cp := vsvc.DeepCopy()
+	if len(cp.Spec.Containers) == 0 {
+		return nil, ErrNoContainers
+	}
+
+	populateContainers(cp)
+	main := cp.Spec.Containers[0]
+
+	container := corev1.Container{
+		Name:            hook.Name,
+		Image:           main.Image,
+		ImagePullPolicy: main.ImagePullPolicy,
+		Command:         main.Command,
+		Args:            main.Args,
+		Env:             main.Env,
+		EnvFrom:         main.EnvFrom,
+		VolumeMounts:    main.VolumeMounts,
+		Resources:       main.Resources,
+	}
+
+	// the arguments of the ConfigPolicy belong to its command.
+	switch {
+	case len(hook.Command) > 0:
+		container.Command, container.Args = hook.Command, hook.Args
+	case len(hook.Args) > 0:
+		container.Args = hook.Args
+	}
+
+	security := cp.Spec.Security
+	if security == nil {
+		security = &v1alpha1.SecurityPolicySpec{}
+	}
+
+	backoffLimit := hook.BackoffLimit
+	if backoffLimit == nil {
+		backoffLimit = k8sutils.PtrInt32(0)
+	}
+
+	name := hookJobName(vsvc, phase, hook)
+
+	// the pods of hooks get labels of their own, so they aren't selected by
+	// the Deployment or Services of the release.
+	podLabels := map[string]string{
+		"hlnr.io/hook":       name,
+		"hlnr.io/hook.phase": strings.ToLower(string(phase)),
+	}
+
+	labels := meta.Labels(cp.Labels, cp)
+	for k, v := range podLabels {
+		labels[k] = v
+	}
+
+	return &batchv1.Job{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       "Job",
+			APIVersion: "batch/v1",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        name,
+			Namespace:   vsvc.Namespace,
+			Labels:      labels,
+			Annotations: meta.Annotations(cp.Annotations, v1alpha1.Version, cp),
+			OwnerReferences: []metav1.OwnerReference{
+				*metav1.NewControllerRef(
+					vsvc,
+					v1alpha1.SchemeGroupVersion.WithKind(kubekit.TypeName(vsvc)),
+				),
+			},
+		},
+		Spec: batchv1.JobSpec{
+			BackoffLimit:          backoffLimit,
+			ActiveDeadlineSeconds: hook.ActiveDeadlineSeconds,
+			Template: corev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels: podLabels,
+				},
+				Spec: corev1.PodSpec{
+					ImagePullSecrets:             cp.Spec.ImagePullSecrets,
+					ServiceAccountName:           security.ServiceAccountName,
+					AutomountServiceAccountToken: k8sutils.PtrBool(security.AutomountServiceAccountToken),
+					SecurityContext:              security.SecurityContext,
+					RestartPolicy:                corev1.RestartPolicyNever,
+					Containers:                   []corev1.Container{container},
+					Volumes:                      podVolumes(cp),
+				},
+			},
+		},
+	}, nil
+}
+
+// deploymentAvailable reports whether all pods of the Deployment of a
+// VersionedMicroservice are updated and available.
+func deploymentAvailable(cl patchClient, vsvc *v1alpha1.VersionedMicroservice) (bool, error) {
+	dpl := &v1beta1.Deployment{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       "Deployment",
+			APIVersion: "extensions/v1beta1",
+		},
+	}
+
+	if err := cl.Get(dpl, vsvc.Namespace, vsvc.Name); err != nil {
+		if kerrors.IsNotFound(err) {
+			return false, nil
+		}
+
+		return false, err
+	}
+
+	replicas := int32(1)
+	if dpl.Spec.Replicas != nil {
+		replicas = *dpl.Spec.Replicas
+	}
+
+	status := dpl.Status
+	return status.ObservedGeneration >= dpl.Generation &&
+		status.UpdatedReplicas >= replicas &&
+		status.AvailableReplicas >= replicas, nil
+}
@@ -0,0 +1,226 @@
+package vsvc
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/jelmersnoeck/kubekit/patcher"
+	"github.com/manifoldco/heighliner/apis/v1alpha1"
+	"github.com/manifoldco/heighliner/internal/k8sutils"
+	"github.com/manifoldco/heighliner/internal/tester"
+
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/api/extensions/v1beta1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+func TestController_ApplyCRD_Hooks(t *testing.T) {
+	vsvc := &v1alpha1.VersionedMicroservice{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "hello-world-1mpl3547",
+			Namespace:   "testing",
+			Labels:      map[string]string{},
+			Annotations: map[string]string{},
+		},
+		Spec: v1alpha1.VersionedMicroserviceSpec{
+			Containers: []corev1.Container{{Name: "hello-world", Image: "hlnr/hello-world:1.0.0"}},
+			Hooks: &v1alpha1.Hooks{
+				PreDeploy:  []v1alpha1.Hook{{Name: "migrate", Command: []string{"migrate"}}},
+				PostDeploy: []v1alpha1.Hook{{Name: "smoke"}},
+			},
+		},
+	}
+
+	// run creates a client where the given jobs exist with the given state,
+	// and returns the kinds of the applied objects and the last status.
+	run := func(t *testing.T, jobs map[string]batchv1.JobStatus, available bool) ([]string, []v1alpha1.HookStatus, error) {
+		var applied []string
+		var status []v1alpha1.HookStatus
+
+		cl := &tester.PatchClient{
+			GetFunc: func(obj interface{}, namespace, name string) error {
+				switch obj := obj.(type) {
+				case *batchv1.Job:
+					js, ok := jobs[name]
+					if !ok {
+						return kerrors.NewNotFound(schema.GroupResource{}, name)
+					}
+					obj.Status = js
+				case *v1beta1.Deployment:
+					if available {
+						obj.Status = v1beta1.DeploymentStatus{UpdatedReplicas: 1, AvailableReplicas: 1}
+					}
+				}
+
+				return nil
+			},
+			ApplyFunc: func(obj runtime.Object, _ ...patcher.OptionFunc) ([]byte, error) {
+				switch obj := obj.(type) {
+				case *batchv1.Job:
+					applied = append(applied, "Job/"+obj.Name)
+				case *v1beta1.Deployment:
+					applied = append(applied, "Deployment")
+				case *v1alpha1.VersionedMicroservice:
+					status = obj.Status.Hooks
+				}
+
+				return []byte("{}"), nil
+			},
+			DeleteFunc: func(runtime.Object, ...patcher.OptionFunc) error {
+				return nil
+			},
+		}
+
+		err := (&Controller{patcher: cl}).applyCRD(vsvc)
+		return applied, status, err
+	}
+
+	succeeded := batchv1.JobStatus{Succeeded: 1}
+
+	t.Run("starts the pre-deploy hooks", func(t *testing.T) {
+		applied, status, err := run(t, nil, false)
+		if err != nil {
+			t.Fatalf("Expected no error, got %s", err)
+		}
+
+		if !reflect.DeepEqual(applied, []string{"Job/hello-world-1mpl3547-pre-migrate"}) {
+			t.Errorf("Expected only the pre-deploy Job to be applied, got %v", applied)
+		}
+
+		expected := []v1alpha1.HookStatus{
+			{Name: "migrate", Phase: v1alpha1.HookPhasePreDeploy, State: v1alpha1.HookStateRunning, Job: "hello-world-1mpl3547-pre-migrate"},
+			{Name: "smoke", Phase: v1alpha1.HookPhasePostDeploy, State: v1alpha1.HookStatePending},
+		}
+		if !reflect.DeepEqual(status, expected) {
+			t.Errorf("Expected status %+v, got %+v", expected, status)
+		}
+	})
+
+	t.Run("blocks the rollout on failures", func(t *testing.T) {
+		failed := batchv1.JobStatus{
+			Conditions: []batchv1.JobCondition{{Type: batchv1.JobFailed, Status: corev1.ConditionTrue, Message: "BackoffLimitExceeded"}},
+		}
+
+		applied, status, err := run(t, map[string]batchv1.JobStatus{"hello-world-1mpl3547-pre-migrate": failed}, false)
+		if err == nil {
+			t.Errorf("Expected an error for the failed hook")
+		}
+
+		if len(applied) != 0 {
+			t.Errorf("Expected nothing to be applied, got %v", applied)
+		}
+
+		if status[0].State != v1alpha1.HookStateFailed || status[0].Message != "BackoffLimitExceeded" {
+			t.Errorf("Expected the hook to have failed, got %+v", status[0])
+		}
+	})
+
+	t.Run("waits for the Deployment to be available", func(t *testing.T) {
+		applied, status, err := run(t, map[string]batchv1.JobStatus{"hello-world-1mpl3547-pre-migrate": succeeded}, false)
+		if err != nil {
+			t.Fatalf("Expected no error, got %s", err)
+		}
+
+		if len(applied) == 0 || applied[0] != "Deployment" {
+			t.Errorf("Expected the Deployment to be applied, got %v", applied)
+		}
+
+		if status[1].State != v1alpha1.HookStatePending {
+			t.Errorf("Expected the post-deploy hook to be pending, got %+v", status[1])
+		}
+	})
+
+	t.Run("starts the post-deploy hooks", func(t *testing.T) {
+		applied, status, err := run(t, map[string]batchv1.JobStatus{"hello-world-1mpl3547-pre-migrate": succeeded}, true)
+		if err != nil {
+			t.Fatalf("Expected no error, got %s", err)
+		}
+
+		if last := applied[len(applied)-1]; last != "Job/hello-world-1mpl3547-post-smoke" {
+			t.Errorf("Expected the post-deploy Job to be applied, got %v", applied)
+		}
+
+		if status[1].State != v1alpha1.HookStateRunning {
+			t.Errorf("Expected the post-deploy hook to be running, got %+v", status[1])
+		}
+	})
+}
+
+func TestHookJobName(t *testing.T) {
+	vsvc := &v1alpha1.VersionedMicroservice{
+		ObjectMeta: metav1.ObjectMeta{Name: "hello-world-1mpl3547"},
+	}
+
+	t.Run("with a short name", func(t *testing.T) {
+		name := hookJobName(vsvc, v1alpha1.HookPhasePostDeploy, v1alpha1.Hook{Name: "notify"})
+		if name != "hello-world-1mpl3547-post-notify" {
+			t.Errorf("Expected hello-world-1mpl3547-post-notify, got %s", name)
+		}
+	})
+
+	t.Run("with a long name", func(t *testing.T) {
+		hook := v1alpha1.Hook{Name: "migrate-the-database-before-the-release-is-deployed"}
+		name := hookJobName(vsvc, v1alpha1.HookPhasePreDeploy, hook)
+		if len(name) > k8sutils.MaxJobNameLength {
+			t.Errorf("Expected at most %d characters, got %d for %s", k8sutils.MaxJobNameLength, len(name), name)
+		}
+
+		if !strings.HasPrefix(name, "hello-world-1mpl3547-pre-migrate-") {
+			t.Errorf("Expected the name to start with the release and hook, got %s", name)
+		}
+
+		hook.Name += "-again"
+		if other := hookJobName(vsvc, v1alpha1.HookPhasePreDeploy, hook); other == name {
+			t.Errorf("Expected different hooks to get different names, got %s for both", name)
+		}
+	})
+}
+
+func TestBuildHookJob(t *testing.T) {
+	vsvc := &v1alpha1.VersionedMicroservice{
+		ObjectMeta: metav1.ObjectMeta{Name: "hello-world-1mpl3547", Namespace: "testing"},
+		Spec: v1alpha1.VersionedMicroserviceSpec{
+			Containers: []corev1.Container{{Name: "hello-world", Image: "hlnr/hello-world:1.0.0"}},
+			Config: &v1alpha1.ConfigPolicySpec{
+				Command: []string{"server"},
+				Args:    []string{"--port=8080"},
+				Env:     []corev1.EnvVar{{Name: "DATABASE_URL", Value: "postgres://"}},
+			},
+		},
+	}
+
+	job, err := buildHookJob(vsvc, v1alpha1.HookPhasePreDeploy, v1alpha1.Hook{Name: "migrate", Args: []string{"migrate"}})
+	if err != nil {
+		t.Fatalf("Expected no error, got %s", err)
+	}
+
+	container := job.Spec.Template.Spec.Containers[0]
+	if container.Image != "hlnr/hello-world:1.0.0" {
+		t.Errorf("Expected the image of the release, got %s", container.Image)
+	}
+
+	if !reflect.DeepEqual(container.Command, []string{"server"}) || !reflect.DeepEqual(container.Args, []string{"migrate"}) {
+		t.Errorf("Expected the arguments of the hook, got %v %v", container.Command, container.Args)
+	}
+
+	if len(container.Env) != 1 {
+		t.Errorf("Expected the environment of the ConfigPolicy, got %v", container.Env)
+	}
+
+	if _, ok := job.Spec.Template.Labels["hlnr.io/service"]; ok {
+		t.Errorf("Expected the pods not to be selected by the Deployment, got labels %v", job.Spec.Template.Labels)
+	}
+
+	if *job.Spec.BackoffLimit != 0 {
+		t.Errorf("Expected no retries, got %d", *job.Spec.BackoffLimit)
+	}
+
+	if vsvc.Spec.Containers[0].Args != nil {
+		t.Errorf("Expected the VersionedMicroservice to be left untouched")
+	}
+}